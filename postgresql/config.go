@@ -3,15 +3,21 @@ package postgresql
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"fmt"
+	"log"
 	"net/url"
+	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 	"unicode"
 
 	"github.com/blang/semver"
-	_ "github.com/lib/pq" // PostgreSQL db
+	"github.com/lib/pq" // PostgreSQL db
 	"gocloud.dev/gcp"
 	"gocloud.dev/gcp/cloudsql"
 	"gocloud.dev/postgres"
@@ -20,6 +26,25 @@ import (
 	"google.golang.org/api/impersonate"
 )
 
+// Values of the reads_from provider setting.
+const (
+	readsFromPrimary = "primary"
+	readsFromAny     = "any"
+)
+
+// Values of the flavor provider setting, the kind of server the provider is connected to.
+const (
+	flavorPostgreSQL = "postgresql"
+	flavorRedshift   = "redshift"
+)
+
+// readIsolationLevels are the values of the read_isolation_level provider setting,
+// the isolation levels of the read-only transactions.
+var readIsolationLevels = map[string]sql.IsolationLevel{
+	"read committed":  sql.LevelReadCommitted,
+	"repeatable read": sql.LevelRepeatableRead,
+}
+
 type featureName uint
 
 const (
@@ -38,6 +63,7 @@ const (
 	featurePrivilegesOnSchemas
 	featureForceDropDatabase
 	featurePid
+	featurePgControl
 	featurePublishViaRoot
 	featurePubTruncate
 	featurePublication
@@ -46,8 +72,52 @@ const (
 	featureServer
 	featureCreateRoleSelfGrant
 	featureSecurityLabel
+	featureIdleSessionTimeout
+	featureRoleMembershipOptions
+	featureDBLocaleProvider
+	featureDBICURules
+	featureDBLocale
+	featureFunctionSupport
+	featureAggregate
+	featureFunctionTransform
 )
 
+// featureNames are the names of the features which can be used in the feature_overrides provider setting.
+var featureNames = map[string]featureName{
+	"create_role_with":            featureCreateRoleWith,
+	"database_owner_role":         featureDatabaseOwnerRole,
+	"db_allow_connections":        featureDBAllowConnections,
+	"db_is_template":              featureDBIsTemplate,
+	"fallback_application_name":   featureFallbackApplicationName,
+	"rls":                         featureRLS,
+	"schema_create_if_not_exists": featureSchemaCreateIfNotExist,
+	"replication":                 featureReplication,
+	"extension":                   featureExtension,
+	"privileges":                  featurePrivileges,
+	"procedure":                   featureProcedure,
+	"routine":                     featureRoutine,
+	"privileges_on_schemas":       featurePrivilegesOnSchemas,
+	"force_drop_database":         featureForceDropDatabase,
+	"pid":                         featurePid,
+	"pg_control":                  featurePgControl,
+	"publish_via_root":            featurePublishViaRoot,
+	"pub_truncate":                featurePubTruncate,
+	"publication":                 featurePublication,
+	"pub_without_truncate":        featurePubWithoutTruncate,
+	"function":                    featureFunction,
+	"server":                      featureServer,
+	"create_role_self_grant":      featureCreateRoleSelfGrant,
+	"security_label":              featureSecurityLabel,
+	"idle_session_timeout":        featureIdleSessionTimeout,
+	"role_membership_options":     featureRoleMembershipOptions,
+	"db_locale_provider":          featureDBLocaleProvider,
+	"db_icu_rules":                featureDBICURules,
+	"db_locale":                   featureDBLocale,
+	"function_support":            featureFunctionSupport,
+	"aggregate":                   featureAggregate,
+	"function_transform":          featureFunctionTransform,
+}
+
 var (
 	dbRegistryLock sync.Mutex
 	dbRegistry     map[string]*DBConnection = make(map[string]*DBConnection, 1)
@@ -99,6 +169,9 @@ var (
 		// for Postgresql >= 9.2 and above
 		featurePid: semver.MustParseRange(">=9.2.0"),
 
+		// pg_control_system() / pg_control_checkpoint()
+		featurePgControl: semver.MustParseRange(">=9.6.0"),
+
 		// attribute publish_via_partition_root for partition is supported
 		featurePublishViaRoot: semver.MustParseRange(">=13.0.0"),
 
@@ -122,6 +195,28 @@ var (
 		// https://www.postgresql.org/docs/16/release-16.html#RELEASE-16-PRIVILEGES
 		featureCreateRoleSelfGrant: semver.MustParseRange(">=16.0.0"),
 		featureSecurityLabel:       semver.MustParseRange(">=11.0.0"),
+
+		// idle_session_timeout parameter
+		featureIdleSessionTimeout: semver.MustParseRange(">=14.0.0"),
+
+		// GRANT role WITH INHERIT / SET options
+		featureRoleMembershipOptions: semver.MustParseRange(">=16.0.0"),
+
+		// CREATE DATABASE LOCALE_PROVIDER / ICU_LOCALE / COLLATION_VERSION
+		featureDBLocaleProvider: semver.MustParseRange(">=15.0.0"),
+		// CREATE DATABASE ICU_RULES
+		featureDBICURules: semver.MustParseRange(">=16.0.0"),
+		// pg_database.datlocale (daticulocale before)
+		featureDBLocale: semver.MustParseRange(">=17.0.0"),
+
+		// CREATE FUNCTION ... SUPPORT
+		featureFunctionSupport: semver.MustParseRange(">=12.0.0"),
+
+		// CREATE AGGREGATE ... COMBINEFUNC / PARALLEL
+		featureAggregate: semver.MustParseRange(">=9.6.0"),
+
+		// CREATE FUNCTION ... TRANSFORM
+		featureFunctionTransform: semver.MustParseRange(">=9.5.0"),
 	}
 )
 
@@ -133,12 +228,34 @@ type DBConnection struct {
 	// version is the version number of the database as determined by parsing the
 	// output of `SELECT VERSION()`.x
 	version semver.Version
+
+	// openedAt is when the connection pool was opened (see Client.resetConnections).
+	openedAt time.Time
+
+	// warnings are the messages returned as warning diagnostics once the operation is done,
+	// if the resource supports them (see PGResourceFuncWithWarnings).
+	warnings []string
+}
+
+// warn adds a warning to the operation using the connection.
+// A warning is only added once, e.g.: if it's added by a transaction which is retried (see withTransaction).
+func (db *DBConnection) warn(format string, args ...interface{}) {
+	warning := fmt.Sprintf(format, args...)
+	if sliceContainsStr(db.warnings, warning) {
+		return
+	}
+	log.Printf("[WARN] %s", warning)
+	db.warnings = append(db.warnings, warning)
 }
 
 // featureSupported returns true if a given feature is supported or not. This is
 // slightly different from Config's featureSupported in that here we're
 // evaluating against the fingerprinted version, not the expected version.
 func (db *DBConnection) featureSupported(name featureName) bool {
+	if supported, ok := db.client.config.FeatureOverrides[name]; ok {
+		return supported
+	}
+
 	fn, found := featureSupported[name]
 	if !found {
 		// panic'ing because this is a provider-only bug
@@ -159,12 +276,71 @@ func (db *DBConnection) isSuperuser() (bool, error) {
 	return superuser, nil
 }
 
+// parseFeatureOverrides converts the feature_overrides provider setting (feature name => supported).
+func parseFeatureOverrides(overrides map[string]interface{}) (map[featureName]bool, error) {
+	result := make(map[featureName]bool, len(overrides))
+	for name, supported := range overrides {
+		feature, ok := featureNames[name]
+		if !ok {
+			names := make([]string, 0, len(featureNames))
+			for name := range featureNames {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return nil, fmt.Errorf("unknown feature %q in feature_overrides (expected one of: %s)", name, strings.Join(names, ", "))
+		}
+		result[feature] = supported.(bool)
+	}
+	return result, nil
+}
+
+// DatabaseEndpoint overrides the host and the port used to connect to a specific database
+// (e.g.: databases routed through different pooler endpoints).
+type DatabaseEndpoint struct {
+	Host string
+	Port int
+}
+
+// ServerEndpoint is a server the resources can target with their endpoint attribute instead of the provider host
+// (e.g.: a logical replica or an instance endpoint managed with the same provider block).
+type ServerEndpoint struct {
+	Host string
+	// Port, Username and Password default to the provider ones.
+	Port     int
+	Username string
+	Password string
+}
+
+// ClientCertificateConfig is the SSL client certificate.
+// If SSLInline is set, CertificatePath and KeyPath (and Config.SSLRootCertPath) contain the PEM encoded
+// certificates and key instead of their paths.
 type ClientCertificateConfig struct {
 	CertificatePath string
 	KeyPath         string
 	SSLInline       bool
 }
 
+// inline reads the certificate and the key files so they're kept in memory.
+func (c *ClientCertificateConfig) inline() error {
+	if c.SSLInline {
+		return nil
+	}
+
+	for _, path := range []*string{&c.CertificatePath, &c.KeyPath} {
+		if *path == "" {
+			continue
+		}
+		content, err := os.ReadFile(*path)
+		if err != nil {
+			return fmt.Errorf("could not read SSL client certificate file %s: %w", *path, err)
+		}
+		*path = string(content)
+	}
+	c.SSLInline = true
+
+	return nil
+}
+
 // Config - provider config
 type Config struct {
 	Scheme                          string
@@ -178,11 +354,44 @@ type Config struct {
 	ApplicationName                 string
 	Timeout                         int
 	ConnectTimeoutSec               int
+	DDLTimeoutSec                   int
+	DMLTimeoutSec                   int
 	MaxConns                        int
+	MaxIdleConns                    int
+	ConnMaxLifetimeSec              int
+	ConnMaxIdleTimeSec              int
 	ExpectedVersion                 semver.Version
 	SSLClientCert                   *ClientCertificateConfig
 	SSLRootCertPath                 string
 	GCPIAMImpersonateServiceAccount string
+	FailoverRetries                 int
+	TransientErrorRetries           int
+	TransientErrorBackoffMs         int
+	StrictSecurityWarnings          bool
+	AllowedExtensions               []string
+	ObjectOwnerRole                 string
+	DatabaseEndpoints               map[string]DatabaseEndpoint
+	Endpoints                       map[string]ServerEndpoint
+	FeatureOverrides                map[featureName]bool
+	ReadsFrom                       string
+	ReadIsolationLevel              string
+	Replica                         *ReplicaEndpoint
+	JournalPath                     string
+	Flavor                          string
+	SSHTunnel                       *SSHTunnelConfig
+	StatementLog                    *StatementLogger
+	SQLRecorder                     *SQLRecorder
+
+	// journal records the statements executed during the apply, see operationJournal.
+	journal *operationJournal
+}
+
+// ReplicaEndpoint is the replica to which the reads are sent if ReadsFrom is readsFromAny.
+type ReplicaEndpoint struct {
+	Host string
+	Port int
+	// Password overrides the provider password if it is specific to the host (e.g.: RDS IAM token).
+	Password string
 }
 
 // Client struct holding connection string
@@ -191,20 +400,157 @@ type Client struct {
 	config Config
 
 	databaseName string
+
+	// defaultPrivileges caches the default privileges read during the run
+	defaultPrivileges *defaultPrivilegesCache
+
+	// readCache coalesces the catalog queries of the Read functions refreshing concurrently
+	readCache *readCache
+
+	// readOnly is set for the clients used by the Read and Exists functions, their transactions are READ ONLY.
+	readOnly bool
+
+	// endpoint is the name of the provider endpoint this client connects to, empty for the provider host.
+	endpoint string
+
+	// endpointCaches holds the caches of the clients of each endpoint, shared by all the copies of the client.
+	endpointCaches *endpointCaches
+}
+
+// endpointCaches holds the default privileges and read caches of each endpoint of the provider,
+// as they are read from another server than the provider host.
+type endpointCaches struct {
+	sync.Mutex
+	defaultPrivileges map[string]*defaultPrivilegesCache
+	readCaches        map[string]*readCache
+}
+
+func newEndpointCaches() *endpointCaches {
+	return &endpointCaches{
+		defaultPrivileges: map[string]*defaultPrivilegesCache{},
+		readCaches:        map[string]*readCache{},
+	}
+}
+
+// get returns the caches of the endpoint, creating them on the first call.
+func (c *endpointCaches) get(name string) (*defaultPrivilegesCache, *readCache) {
+	c.Lock()
+	defer c.Unlock()
+
+	if _, ok := c.readCaches[name]; !ok {
+		c.defaultPrivileges[name] = newDefaultPrivilegesCache()
+		c.readCaches[name] = newReadCache()
+	}
+	return c.defaultPrivileges[name], c.readCaches[name]
 }
 
 // NewClient returns client config for the specified database.
 func (c *Config) NewClient(database string) *Client {
 	return &Client{
-		config:       *c,
-		databaseName: database,
+		config:            *c,
+		databaseName:      database,
+		defaultPrivileges: newDefaultPrivilegesCache(),
+		readCache:         newReadCache(),
+		endpointCaches:    newEndpointCaches(),
 	}
 }
 
+// readOnlyClient returns a copy of the client for the Read and Exists functions.
+// The copy connects to the replica if the provider is configured to read from it.
+func (c *Client) readOnlyClient() *Client {
+	config := c.config
+	if config.ReadsFrom == readsFromAny && config.Replica != nil {
+		config.Host = config.Replica.Host
+		if config.Replica.Port != 0 {
+			config.Port = config.Replica.Port
+		}
+		if config.Replica.Password != "" {
+			config.Password = config.Replica.Password
+		}
+	}
+
+	return &Client{
+		config:            config,
+		databaseName:      c.databaseName,
+		defaultPrivileges: c.defaultPrivileges,
+		readCache:         c.readCache,
+		readOnly:          true,
+		endpoint:          c.endpoint,
+		endpointCaches:    c.endpointCaches,
+	}
+}
+
+// statementTimeout returns the statement_timeout, in seconds, of the transactions of the client:
+// the read-only transactions (i.e.: the catalog queries of Read and Exists) use the DML timeout,
+// the other ones the DDL timeout. Zero means the server setting is kept.
+func (c *Client) statementTimeout() int {
+	if c.readOnly {
+		return c.config.DMLTimeoutSec
+	}
+	return c.config.DDLTimeoutSec
+}
+
+// isolationLevel returns the isolation level of the transactions of the client: the read_isolation_level
+// of the provider for the read-only transactions, the default of the server otherwise.
+func (c *Client) isolationLevel() sql.IsolationLevel {
+	if c.readOnly {
+		if level, ok := readIsolationLevels[c.config.ReadIsolationLevel]; ok {
+			return level
+		}
+	}
+	return sql.LevelDefault
+}
+
+// endpointClient returns a copy of the client connecting to the named endpoint of the provider,
+// or the client itself if the name is empty.
+func (c *Client) endpointClient(name string) (*Client, error) {
+	if name == "" || name == c.endpoint {
+		return c, nil
+	}
+
+	endpoint, ok := c.config.Endpoints[name]
+	if !ok {
+		return nil, fmt.Errorf("endpoint %s is not defined in the provider", name)
+	}
+
+	config := c.config
+	config.Host = endpoint.Host
+	if endpoint.Port != 0 {
+		config.Port = endpoint.Port
+	}
+	if endpoint.Username != "" {
+		config.Username = endpoint.Username
+		config.DatabaseUsername = ""
+	}
+	if endpoint.Password != "" {
+		config.Password = endpoint.Password
+	}
+	// The database overrides and the replica belong to the provider host.
+	config.DatabaseEndpoints = nil
+	config.Replica = nil
+
+	// The default privileges and the relations are read from another server.
+	defaultPrivileges, readCache := c.endpointCaches.get(name)
+
+	return &Client{
+		config:            config,
+		databaseName:      c.databaseName,
+		defaultPrivileges: defaultPrivileges,
+		readCache:         readCache,
+		readOnly:          c.readOnly,
+		endpoint:          name,
+		endpointCaches:    c.endpointCaches,
+	}, nil
+}
+
 // featureSupported returns true if a given feature is supported or not.  This
 // is slightly different from Client's featureSupported in that here we're
 // evaluating against the expected version, not the fingerprinted version.
 func (c *Config) featureSupported(name featureName) bool {
+	if supported, ok := c.FeatureOverrides[name]; ok {
+		return supported
+	}
+
 	fn, found := featureSupported[name]
 	if !found {
 		// panic'ing because this is a provider-only bug
@@ -247,8 +593,23 @@ func (c *Config) connParams() []string {
 	return paramsArray
 }
 
+// endpoint returns the host and the port to connect to the database,
+// taking into account the database endpoint overrides.
+func (c *Config) endpoint(database string) (string, int) {
+	host, port := c.Host, c.Port
+	if override, ok := c.DatabaseEndpoints[database]; ok {
+		if override.Host != "" {
+			host = override.Host
+		}
+		if override.Port != 0 {
+			port = override.Port
+		}
+	}
+	return host, port
+}
+
 func (c *Config) connStr(database string) string {
-	host := c.Host
+	host, port := c.endpoint(database)
 	// For GCP, support both project/region/instance and project:region:instance
 	// (The second one allows to use the output of google_sql_database_instance as host
 	if c.Scheme == "gcppostgres" {
@@ -261,7 +622,7 @@ func (c *Config) connStr(database string) string {
 		url.PathEscape(c.Username),
 		url.PathEscape(c.Password),
 		host,
-		c.Port,
+		port,
 		database,
 		strings.Join(c.connParams(), "&"),
 	)
@@ -290,7 +651,12 @@ func (c *Client) Connect() (*DBConnection, error) {
 		var db *sql.DB
 		var err error
 		if c.config.Scheme == "postgres" {
-			db, err = sql.Open(proxyDriverName, dsn)
+			var connector driver.Connector
+			connector, err = pqConnector(dsn, c.config.SSHTunnel)
+			if err == nil {
+				connector = c.config.StatementLog.connector(connector, c.databaseName)
+				db = sql.OpenDB(c.config.SQLRecorder.connector(connector, c.databaseName))
+			}
 		} else if c.config.Scheme == "gcppostgres" && c.config.GCPIAMImpersonateServiceAccount != "" {
 			db, err = openImpersonatedGCPDBConnection(context.Background(), dsn, c.config.GCPIAMImpersonateServiceAccount)
 		} else {
@@ -302,14 +668,18 @@ func (c *Client) Connect() (*DBConnection, error) {
 		}
 		if err != nil {
 			errString := strings.Replace(err.Error(), c.config.Password, "XXXX", 2)
-			return nil, fmt.Errorf("Error connecting to PostgreSQL server %s (scheme: %s): %s", c.config.Host, c.config.Scheme, errString)
+			host, _ := c.config.endpoint(c.databaseName)
+			return nil, fmt.Errorf("Error connecting to PostgreSQL server %s (scheme: %s): %s", host, c.config.Scheme, errString)
 		}
 
-		// We don't want to retain connection
+		// By default we don't want to retain connection
 		// So when we connect on a specific database which might be managed by terraform,
-		// we don't keep opened connection in case of the db has to be dropped in the plan.
-		db.SetMaxIdleConns(0)
+		// we don't keep opened connection in case of the db has to be dropped in the plan
+		// (the pools of a database are closed before dropping it, see closeDatabaseConnections).
+		db.SetMaxIdleConns(c.config.MaxIdleConns)
 		db.SetMaxOpenConns(c.config.MaxConns)
+		db.SetConnMaxLifetime(time.Duration(c.config.ConnMaxLifetimeSec) * time.Second)
+		db.SetConnMaxIdleTime(time.Duration(c.config.ConnMaxIdleTimeSec) * time.Second)
 
 		defaultVersion, _ := semver.Parse(defaultExpectedPostgreSQLVersion)
 		version := &c.config.ExpectedVersion
@@ -323,14 +693,155 @@ func (c *Client) Connect() (*DBConnection, error) {
 		}
 
 		conn = &DBConnection{
-			db,
-			c,
-			*version,
+			DB:       db,
+			client:   c,
+			version:  *version,
+			openedAt: time.Now(),
 		}
 		dbRegistry[dsn] = conn
 	}
 
-	return conn, nil
+	// The connection pool can be shared by several clients (e.g.: the provider is configured again
+	// with the same connection settings), the copy references this client and its configuration.
+	return &DBConnection{DB: conn.DB, client: c, version: conn.version}, nil
+}
+
+// pqConnector returns the connector of the pq driver. Its connections are dialed through the SSH tunnel if it's set,
+// otherwise through the proxy of the environment (see proxyDriver).
+func pqConnector(dsn string, tunnel *SSHTunnelConfig) (driver.Connector, error) {
+	connector, err := pq.NewConnector(dsn)
+	if err != nil {
+		return nil, err
+	}
+	if tunnel != nil {
+		connector.Dialer(tunnel)
+	} else {
+		connector.Dialer(proxyDriver{})
+	}
+
+	return connector, nil
+}
+
+// failoverRetryDelay is the base delay to wait before reconnecting after a writer failover.
+// It is multiplied by the attempt number.
+var failoverRetryDelay = 5 * time.Second
+
+// isReadOnlyTransactionError returns true if the error is a "cannot execute ... in a read-only transaction" error.
+// This happens when the connection pool still targets the old writer after a failover
+// (e.g.: Aurora cluster writer endpoint during maintenance events).
+func isReadOnlyTransactionError(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "25006"
+}
+
+// transientErrorMaxBackoff caps the delay between two attempts of an operation which failed with a transient error.
+var transientErrorMaxBackoff = 30 * time.Second
+
+// transientErrorCodes are the PostgreSQL error codes after which an operation can succeed if it's retried.
+var transientErrorCodes = map[pq.ErrorCode]bool{
+	"40001": true, // serialization_failure
+	"40P01": true, // deadlock_detected
+	"53300": true, // too_many_connections
+	"57P03": true, // cannot_connect_now
+}
+
+// isTransientError returns true if the error is a PostgreSQL error after which the operation can be retried,
+// e.g.: concurrent updates of the catalog during large parallel applies.
+func isTransientError(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	// The concurrent updates of the same catalog row (e.g.: GRANT on the same object) fail with an internal error.
+	return transientErrorCodes[pqErr.Code] || (pqErr.Code == "XX000" && pqErr.Message == "tuple concurrently updated")
+}
+
+// resetConnections forgets the connection pools opened on the host of this client before the given time,
+// so the next Connect will open new connections (and resolve the host again).
+// The pools are not closed as they can be used by other operations running concurrently: they are closed
+// once they are released instead of being kept idle. The pools opened since (e.g.: by a concurrent reset) are kept.
+func (c *Client) resetConnections(before time.Time) {
+	dbRegistryLock.Lock()
+	defer dbRegistryLock.Unlock()
+
+	host, port := c.config.endpoint(c.databaseName)
+	for dsn, conn := range dbRegistry {
+		if connHost, connPort := conn.client.config.endpoint(conn.client.databaseName); connHost != host || connPort != port {
+			continue
+		}
+		if !conn.openedAt.Before(before) {
+			continue
+		}
+		conn.SetMaxIdleConns(0)
+		delete(dbRegistry, dsn)
+	}
+}
+
+// closeDatabaseConnections closes and forgets the connection pools opened by the provider on the database
+// of this server, e.g.: before dropping it as the idle connections would prevent it.
+func (c *Client) closeDatabaseConnections(database string) {
+	dbRegistryLock.Lock()
+	defer dbRegistryLock.Unlock()
+
+	host, port := c.config.endpoint(database)
+	for dsn, conn := range dbRegistry {
+		if conn.client.databaseName != database {
+			continue
+		}
+		if connHost, connPort := conn.client.config.endpoint(database); connHost != host || connPort != port {
+			continue
+		}
+		if err := conn.Close(); err != nil {
+			log.Printf("[WARN] could not close connection pool to database %s: %v", database, err)
+		}
+		delete(dbRegistry, dsn)
+	}
+}
+
+// withFailoverRetry executes fn and, if it fails because the server is now read-only
+// (i.e.: a writer failover happened), resets the connections and retries it
+// at most FailoverRetries times. It's disabled by default as the server can be read-only on purpose.
+func (c *Client) withFailoverRetry(fn func() error) error {
+	start := time.Now()
+	err := fn()
+	for attempt := 1; attempt <= c.config.FailoverRetries && isReadOnlyTransactionError(err); attempt++ {
+		log.Printf(
+			"[WARN] PostgreSQL server %s is read-only, it could be a writer failover. Reconnecting (attempt %d/%d): %v",
+			c.config.Host, attempt, c.config.FailoverRetries, err,
+		)
+		c.resetConnections(start)
+		time.Sleep(time.Duration(attempt) * failoverRetryDelay)
+		start = time.Now()
+		err = fn()
+	}
+	return err
+}
+
+// withTransientErrorRetry executes fn and retries it at most TransientErrorRetries times
+// if it fails with a transient error (see isTransientError).
+// The delay between the attempts doubles from TransientErrorBackoffMs, up to transientErrorMaxBackoff.
+func (c *Client) withTransientErrorRetry(fn func() error) error {
+	backoff := time.Duration(c.config.TransientErrorBackoffMs) * time.Millisecond
+	err := fn()
+	for attempt := 1; attempt <= c.config.TransientErrorRetries && isTransientError(err); attempt++ {
+		log.Printf(
+			"[WARN] transient error on PostgreSQL server %s, retrying in %s (attempt %d/%d): %v",
+			c.config.Host, backoff, attempt, c.config.TransientErrorRetries, err,
+		)
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > transientErrorMaxBackoff {
+			backoff = transientErrorMaxBackoff
+		}
+		err = fn()
+	}
+	return err
+}
+
+// withRetry executes fn with the retries of the provider: after a writer failover and after transient errors.
+func (c *Client) withRetry(fn func() error) error {
+	return c.withFailoverRetry(func() error {
+		return c.withTransientErrorRetry(fn)
+	})
 }
 
 // fingerprintCapabilities queries PostgreSQL to populate a local catalog of
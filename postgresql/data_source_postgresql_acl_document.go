@@ -0,0 +1,141 @@
+package postgresql
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	aclDocumentGrantAttr = "grant"
+	aclDocumentJSONAttr  = "json"
+)
+
+// aclDocumentGrant is the JSON representation of a grant in the document.
+// Sets are rendered as sorted lists so the output is stable between runs.
+type aclDocumentGrant struct {
+	Role            string   `json:"role"`
+	Database        string   `json:"database"`
+	Schema          string   `json:"schema,omitempty"`
+	ObjectType      string   `json:"object_type"`
+	Objects         []string `json:"objects"`
+	Columns         []string `json:"columns,omitempty"`
+	Privileges      []string `json:"privileges"`
+	WithGrantOption bool     `json:"with_grant_option"`
+}
+
+type aclDocument struct {
+	Grants []aclDocumentGrant `json:"grants"`
+}
+
+func dataSourcePostgreSQLACLDocument() *schema.Resource {
+	return &schema.Resource{
+		// This data source only renders its arguments, it doesn't need a connection to the database.
+		Read: dataSourcePostgreSQLACLDocumentRead,
+		Schema: map[string]*schema.Schema{
+			aclDocumentGrantAttr: {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "The grants to render in the document, with the same arguments as the postgresql_grant resource",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"role": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The name of the role to grant privileges on",
+						},
+						"database": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The database to grant privileges on for this role",
+						},
+						"schema": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The database schema to grant privileges on for this role",
+						},
+						"object_type": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice(allowedObjectTypes, false),
+							Description:  "The PostgreSQL object type to grant the privileges on (one of: " + strings.Join(allowedObjectTypes, ", ") + ")",
+						},
+						"objects": {
+							Type:        schema.TypeSet,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Set:         schema.HashString,
+							Description: "The specific objects to grant privileges on for this role (empty means all objects of the requested type)",
+						},
+						"columns": {
+							Type:        schema.TypeSet,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Set:         schema.HashString,
+							Description: "The specific columns to grant privileges on for this role",
+						},
+						"privileges": {
+							Type:        schema.TypeSet,
+							Required:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Set:         schema.HashString,
+							Description: "The list of privileges to grant",
+						},
+						"with_grant_option": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Permit the grant recipient to grant it to others",
+						},
+					},
+				},
+			},
+			aclDocumentJSONAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The JSON document describing the desired ACL state",
+			},
+		},
+	}
+}
+
+func dataSourcePostgreSQLACLDocumentRead(d *schema.ResourceData, meta interface{}) error {
+	doc := aclDocument{Grants: []aclDocumentGrant{}}
+
+	for i, raw := range d.Get(aclDocumentGrantAttr).([]interface{}) {
+		grant := raw.(map[string]interface{})
+
+		objectType := grant["object_type"].(string)
+		privileges := setToSortedSlice(grant["privileges"].(*schema.Set))
+		for _, priv := range privileges {
+			if !sliceContainsStr(allowedPrivileges[objectType], priv) {
+				return fmt.Errorf("grant %d: %s is not an allowed privilege for object type %s", i, priv, objectType)
+			}
+		}
+
+		doc.Grants = append(doc.Grants, aclDocumentGrant{
+			Role:            grant["role"].(string),
+			Database:        grant["database"].(string),
+			Schema:          grant["schema"].(string),
+			ObjectType:      objectType,
+			Objects:         setToSortedSlice(grant["objects"].(*schema.Set)),
+			Columns:         setToSortedSlice(grant["columns"].(*schema.Set)),
+			Privileges:      privileges,
+			WithGrantOption: grant["with_grant_option"].(bool),
+		})
+	}
+
+	jsonDoc, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not render ACL document: %w", err)
+	}
+
+	d.Set(aclDocumentJSONAttr, string(jsonDoc))
+	d.SetId(strconv.Itoa(schema.HashString(string(jsonDoc))))
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package postgresql
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestDataSourcePostgreSQLACLDocumentRead(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, dataSourcePostgreSQLACLDocument().Schema, map[string]interface{}{
+		"grant": []interface{}{
+			map[string]interface{}{
+				"role":        "reader",
+				"database":    "app",
+				"schema":      "public",
+				"object_type": "table",
+				"objects":     []interface{}{"t2", "t1"},
+				"privileges":  []interface{}{"SELECT"},
+			},
+			map[string]interface{}{
+				"role":              "admin",
+				"database":          "app",
+				"object_type":       "database",
+				"privileges":        []interface{}{"CONNECT", "CREATE"},
+				"with_grant_option": true,
+			},
+		},
+	})
+
+	if err := dataSourcePostgreSQLACLDocumentRead(d, nil); err != nil {
+		t.Fatalf("could not read ACL document: %v", err)
+	}
+
+	var doc aclDocument
+	if err := json.Unmarshal([]byte(d.Get("json").(string)), &doc); err != nil {
+		t.Fatalf("could not parse rendered JSON: %v", err)
+	}
+
+	expected := aclDocument{Grants: []aclDocumentGrant{
+		{
+			Role:       "reader",
+			Database:   "app",
+			Schema:     "public",
+			ObjectType: "table",
+			Objects:    []string{"t1", "t2"},
+			Privileges: []string{"SELECT"},
+		},
+		{
+			Role:            "admin",
+			Database:        "app",
+			ObjectType:      "database",
+			Objects:         []string{},
+			Privileges:      []string{"CONNECT", "CREATE"},
+			WithGrantOption: true,
+		},
+	}}
+	if !reflect.DeepEqual(doc, expected) {
+		t.Fatalf("unexpected ACL document: %#v", doc)
+	}
+	if d.Id() == "" {
+		t.Fatal("expected ID to be set")
+	}
+}
+
+func TestDataSourcePostgreSQLACLDocumentInvalidPrivilege(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, dataSourcePostgreSQLACLDocument().Schema, map[string]interface{}{
+		"grant": []interface{}{
+			map[string]interface{}{
+				"role":        "reader",
+				"database":    "app",
+				"object_type": "database",
+				"privileges":  []interface{}{"SELECT"},
+			},
+		},
+	})
+
+	if err := dataSourcePostgreSQLACLDocumentRead(d, nil); err == nil {
+		t.Fatal("expected an error for a privilege not allowed on databases")
+	}
+}
@@ -0,0 +1,85 @@
+package postgresql
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
+)
+
+func dataSourcePostgreSQLControlData() *schema.Resource {
+	return &schema.Resource{
+		Read: PGReadResourceFunc(dataSourcePostgreSQLControlDataRead),
+		Schema: map[string]*schema.Schema{
+			"expected_system_identifier": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Fail if the system identifier of the server is not this one",
+			},
+			"system_identifier": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The unique identifier of the cluster (pg_control_system), shared by its physical replicas",
+			},
+			"timeline_id": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The timeline of the cluster at the last checkpoint (pg_control_checkpoint)",
+			},
+		},
+	}
+}
+
+func dataSourcePostgreSQLControlDataRead(db *DBConnection, d *schema.ResourceData) error {
+	systemIdentifier, timelineID, err := readServerControlData(db)
+	if err != nil {
+		return err
+	}
+
+	if expected := d.Get("expected_system_identifier").(string); expected != "" && expected != systemIdentifier {
+		if systemIdentifier == "" {
+			return fmt.Errorf(
+				"could not check the system identifier of the server: it can't be read with PostgreSQL %s or the privileges of the role",
+				db.version,
+			)
+		}
+		return fmt.Errorf(
+			"the system identifier of the server is %s instead of the expected %s: the provider is not connected to the intended cluster",
+			systemIdentifier, expected,
+		)
+	}
+
+	d.Set("system_identifier", systemIdentifier)
+	d.Set("timeline_id", timelineID)
+	d.SetId(fmt.Sprintf("%s:%d", db.client.config.Host, db.client.config.Port))
+
+	return nil
+}
+
+// readServerControlData returns the system identifier and the timeline of the cluster from its control file.
+// They are left empty if the server is too old or if the role is not allowed to read them
+// (the functions are restricted on some managed platforms).
+func readServerControlData(db *DBConnection) (string, int, error) {
+	if !db.featureSupported(featurePgControl) {
+		log.Printf("[DEBUG] pg_control functions not supported by PostgreSQL %s, system_identifier not read", db.version)
+		return "", 0, nil
+	}
+
+	var systemIdentifier string
+	var timelineID int
+	err := db.QueryRow(
+		"SELECT system_identifier::text, (SELECT timeline_id FROM pg_catalog.pg_control_checkpoint()) FROM pg_catalog.pg_control_system()",
+	).Scan(&systemIdentifier, &timelineID)
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "42501" {
+		log.Printf("[WARN] could not read the pg_control data, system_identifier and timeline_id are left empty: %v", err)
+		return "", 0, nil
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("could not read the pg_control data: %w", err)
+	}
+	return systemIdentifier, timelineID, nil
+}
@@ -0,0 +1,36 @@
+package postgresql
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccPostgresqlDataSourceControlData(t *testing.T) {
+	skipIfNotAcc(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "postgresql_control_data" "current" {}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("data.postgresql_control_data.current", "system_identifier", regexp.MustCompile(`^[0-9]+$`)),
+					resource.TestCheckResourceAttrSet("data.postgresql_control_data.current", "timeline_id"),
+				),
+			},
+			{
+				Config: `
+data "postgresql_control_data" "current" {
+  expected_system_identifier = "1"
+}
+`,
+				ExpectError: regexp.MustCompile("not connected to the intended cluster"),
+			},
+		},
+	})
+}
@@ -0,0 +1,121 @@
+package postgresql
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourcePostgreSQLDatabase() *schema.Resource {
+	return &schema.Resource{
+		Read: PGReadResourceFunc(dataSourcePostgreSQLDatabaseRead),
+		Schema: map[string]*schema.Schema{
+			dbNameAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the PostgreSQL database to read",
+			},
+			dbOwnerAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The ROLE which owns the database",
+			},
+			dbOwnerOIDAttr: {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The OID of the ROLE which owns the database",
+			},
+			dbEncodingAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Character set encoding of the database",
+			},
+			dbCollationAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Collation order (LC_COLLATE) of the database",
+			},
+			dbCTypeAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Character classification (LC_CTYPE) of the database",
+			},
+			dbLocaleProviderAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The locale provider of the database: libc, icu or builtin",
+			},
+			dbICULocaleAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The ICU locale of the database if the locale provider is icu",
+			},
+			dbICURulesAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Additional collation rules of the default collation of the database",
+			},
+			dbCollationVersionAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The version of the default collation recorded in the database",
+			},
+			dbLCMessagesAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Language in which messages are displayed (LC_MESSAGES) in this database",
+			},
+			dbLCMonetaryAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Locale to use for formatting monetary amounts (LC_MONETARY) in this database",
+			},
+			dbLCNumericAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Locale to use for formatting numbers (LC_NUMERIC) in this database",
+			},
+			dbLCTimeAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Locale to use for formatting dates and times (LC_TIME) in this database",
+			},
+			dbTablespaceAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the tablespace associated with the database",
+			},
+			dbConnLimitAttr: {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "How many concurrent connections can be made to this database",
+			},
+			dbAllowConnsAttr: {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "If false then no one can connect to this database",
+			},
+			dbIsTemplateAttr: {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "If true, then this database can be cloned by any user with CREATEDB privileges",
+			},
+		},
+	}
+}
+
+func dataSourcePostgreSQLDatabaseRead(db *DBConnection, d *schema.ResourceData) error {
+	dbName := d.Get(dbNameAttr).(string)
+
+	found, err := readDatabaseProperties(db, d, dbName)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("database %s does not exist", dbName)
+	}
+
+	d.SetId(dbName)
+
+	return nil
+}
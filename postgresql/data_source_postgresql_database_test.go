@@ -0,0 +1,50 @@
+package postgresql
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccPostgresqlDataSourceDatabase(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "postgresql_database" "test" {
+	name = "%s"
+}
+`, dbName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.postgresql_database.test", "id", dbName),
+					resource.TestCheckResourceAttrSet("data.postgresql_database.test", "owner"),
+					resource.TestCheckResourceAttrSet("data.postgresql_database.test", "owner_oid"),
+					resource.TestCheckResourceAttr("data.postgresql_database.test", "tablespace_name", "pg_default"),
+					resource.TestCheckResourceAttr("data.postgresql_database.test", "connection_limit", "-1"),
+					resource.TestCheckResourceAttrSet("data.postgresql_database.test", "encoding"),
+					resource.TestCheckResourceAttrSet("data.postgresql_database.test", "lc_collate"),
+					resource.TestCheckResourceAttrSet("data.postgresql_database.test", "lc_ctype"),
+				),
+			},
+			{
+				Config: `
+data "postgresql_database" "unknown" {
+	name = "tf_tests_unknown_db"
+}
+`,
+				ExpectError: regexp.MustCompile("database tf_tests_unknown_db does not exist"),
+			},
+		},
+	})
+}
@@ -0,0 +1,131 @@
+package postgresql
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
+)
+
+func dataSourcePostgreSQLEncodings() *schema.Resource {
+	return &schema.Resource{
+		Read: PGReadResourceFunc(dataSourcePostgreSQLEncodingsRead),
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The database in which the collations are read. If not specified, the provider default database is used.",
+			},
+			"server_encoding": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The encoding of the server (server_encoding setting)",
+			},
+			"database_encoding": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The encoding of the database",
+			},
+			"encodings": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The encodings supported by the server",
+			},
+			"collations": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"schema": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"encoding": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"lc_collate": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"lc_ctype": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+				Description: "The collations usable in the database",
+			},
+		},
+	}
+}
+
+func dataSourcePostgreSQLEncodingsRead(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	// pg_encoding_to_char returns an empty string for the invalid encoding numbers.
+	var serverEncoding, databaseEncoding string
+	var encodings pq.StringArray
+	if err := txn.QueryRow(`
+SELECT current_setting('server_encoding'), pg_encoding_to_char(d.encoding),
+	ARRAY(SELECT e FROM (SELECT pg_encoding_to_char(i) AS e FROM generate_series(0, 255) i) s WHERE e <> '' ORDER BY e)
+FROM pg_catalog.pg_database d
+WHERE d.datname = current_database()
+`).Scan(&serverEncoding, &databaseEncoding, &encodings); err != nil {
+		return fmt.Errorf("could not read encodings in database %s: %w", database, err)
+	}
+
+	// Only the collations matching the database encoding (or any encoding, -1) can be used in the database.
+	// collcollate and collctype are null for the ICU collations since PostgreSQL 15.
+	rows, err := txn.Query(`
+SELECT n.nspname, c.collname, CASE WHEN c.collencoding = -1 THEN '' ELSE pg_encoding_to_char(c.collencoding) END,
+	COALESCE(c.collcollate, ''), COALESCE(c.collctype, '')
+FROM pg_catalog.pg_collation c
+JOIN pg_catalog.pg_namespace n ON n.oid = c.collnamespace
+WHERE c.collencoding IN (-1, (SELECT encoding FROM pg_catalog.pg_database WHERE datname = current_database()))
+ORDER BY n.nspname, c.collname
+`)
+	if err != nil {
+		return fmt.Errorf("could not read collations in database %s: %w", database, err)
+	}
+	defer rows.Close()
+
+	collations := make([]interface{}, 0)
+	for rows.Next() {
+		var schemaName, name, encoding, lcCollate, lcCtype string
+		if err := rows.Scan(&schemaName, &name, &encoding, &lcCollate, &lcCtype); err != nil {
+			return fmt.Errorf("could not scan collations: %w", err)
+		}
+		collations = append(collations, map[string]interface{}{
+			"schema":     schemaName,
+			"name":       name,
+			"encoding":   encoding,
+			"lc_collate": lcCollate,
+			"lc_ctype":   lcCtype,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	d.Set("database", database)
+	d.Set("server_encoding", serverEncoding)
+	d.Set("database_encoding", databaseEncoding)
+	d.Set("encodings", []string(encodings))
+	d.Set("collations", collations)
+	d.SetId(database)
+
+	return nil
+}
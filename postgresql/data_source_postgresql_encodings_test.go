@@ -0,0 +1,43 @@
+package postgresql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccPostgresqlDataSourceEncodings(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "postgresql_encodings" "test" {
+	database = "%s"
+}
+`, dbName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.postgresql_encodings.test", "id", dbName),
+					resource.TestCheckResourceAttrSet("data.postgresql_encodings.test", "server_encoding"),
+					resource.TestCheckResourceAttrSet("data.postgresql_encodings.test", "database_encoding"),
+					resource.TestCheckTypeSetElemAttr("data.postgresql_encodings.test", "encodings.*", "UTF8"),
+					resource.TestCheckTypeSetElemAttr("data.postgresql_encodings.test", "encodings.*", "LATIN1"),
+					resource.TestCheckTypeSetElemNestedAttrs("data.postgresql_encodings.test", "collations.*", map[string]string{
+						"schema":   "pg_catalog",
+						"name":     "C",
+						"encoding": "",
+					}),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,111 @@
+package postgresql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
+)
+
+func dataSourcePostgreSQLExtensionVersions() *schema.Resource {
+	return &schema.Resource{
+		Read: PGReadResourceFunc(dataSourcePostgreSQLExtensionVersionsRead),
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The database in which the installed versions are read",
+			},
+			"names": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "The extensions to read (empty means all the available extensions)",
+			},
+			"extensions": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"default_version": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"installed_version": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"versions": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+				Description: "The extensions available on the server with their default, installed and available versions",
+			},
+		},
+	}
+}
+
+func dataSourcePostgreSQLExtensionVersionsRead(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureExtension) {
+		return fmt.Errorf(
+			"postgresql_extension_versions data source is not supported for this Postgres version (%s)",
+			db.version,
+		)
+	}
+
+	database := d.Get("database").(string)
+	names := setToSortedSlice(d.Get("names").(*schema.Set))
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	// installed_version is only set for the extensions created in the database of the connection.
+	rows, err := txn.Query(`
+SELECT e.name, COALESCE(e.default_version, ''), COALESCE(e.installed_version, ''),
+	ARRAY(SELECT v.version FROM pg_catalog.pg_available_extension_versions v WHERE v.name = e.name ORDER BY v.version)
+FROM pg_catalog.pg_available_extensions e
+WHERE $1::text[] = '{}' OR e.name = ANY($1::text[])
+ORDER BY e.name
+`, pq.Array(names))
+	if err != nil {
+		return fmt.Errorf("could not read available extensions in database %s: %w", database, err)
+	}
+	defer rows.Close()
+
+	extensions := make([]interface{}, 0)
+	for rows.Next() {
+		var name, defaultVersion, installedVersion string
+		var versions pq.StringArray
+
+		if err = rows.Scan(&name, &defaultVersion, &installedVersion, &versions); err != nil {
+			return fmt.Errorf("could not scan available extensions: %w", err)
+		}
+
+		extensions = append(extensions, map[string]interface{}{
+			"name":              name,
+			"default_version":   defaultVersion,
+			"installed_version": installedVersion,
+			"versions":          []string(versions),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	d.Set("extensions", extensions)
+	d.SetId(strings.Join([]string{database, strings.Join(names, ",")}, "_"))
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package postgresql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccPostgresqlDataSourceExtensionVersions(t *testing.T) {
+	skipIfNotAcc(t)
+
+	config := getTestConfig(t)
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+	dbExecute(t, config.connStr(dbName), "CREATE EXTENSION IF NOT EXISTS pgcrypto")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureExtension)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "postgresql_extension_versions" "test" {
+	database = "%s"
+	names    = ["pgcrypto", "hstore"]
+}
+`, dbName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.postgresql_extension_versions.test", "extensions.#", "2"),
+					resource.TestCheckResourceAttr("data.postgresql_extension_versions.test", "extensions.0.name", "hstore"),
+					resource.TestCheckResourceAttrSet("data.postgresql_extension_versions.test", "extensions.0.default_version"),
+					resource.TestCheckResourceAttr("data.postgresql_extension_versions.test", "extensions.0.installed_version", ""),
+					resource.TestCheckResourceAttr("data.postgresql_extension_versions.test", "extensions.1.name", "pgcrypto"),
+					resource.TestCheckResourceAttrPair(
+						"data.postgresql_extension_versions.test", "extensions.1.installed_version",
+						"data.postgresql_extension_versions.test", "extensions.1.default_version",
+					),
+					resource.TestCheckResourceAttrSet("data.postgresql_extension_versions.test", "extensions.1.versions.0"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,59 @@
+package postgresql
+
+import (
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourcePostgreSQLGrantID() *schema.Resource {
+	return &schema.Resource{
+		// This data source only computes the ID from its arguments, it doesn't need a connection to the database.
+		Read: dataSourcePostgreSQLGrantIDRead,
+		Schema: map[string]*schema.Schema{
+			"role": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The role of the grant",
+			},
+			"database": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The database of the grant",
+			},
+			"object_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(allowedObjectTypes, false),
+				Description:  "The PostgreSQL object type of the grant (one of: " + strings.Join(allowedObjectTypes, ", ") + ")",
+			},
+			"schema": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The schema of the grant",
+			},
+			"objects": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "The objects of the grant",
+			},
+			"columns": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "The columns of the grant",
+			},
+		},
+	}
+}
+
+func dataSourcePostgreSQLGrantIDRead(d *schema.ResourceData, meta interface{}) error {
+	// The attributes have the same names as in postgresql_grant so the ID is built the same way.
+	d.SetId(generateGrantID(d))
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package postgresql
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataSourcePostgreSQLGrantIDRead(t *testing.T) {
+	cases := []map[string]interface{}{
+		{
+			"role":        "reader",
+			"database":    "app",
+			"object_type": "database",
+		},
+		{
+			"role":        "reader",
+			"database":    "app",
+			"schema":      "public",
+			"object_type": "table",
+			"objects":     []interface{}{"t2", "t1", "t3"},
+		},
+		{
+			"role":        "reader",
+			"database":    "app",
+			"schema":      "public",
+			"object_type": "column",
+			"objects":     []interface{}{"t1"},
+			"columns":     []interface{}{"c2", "c1"},
+		},
+	}
+
+	for _, c := range cases {
+		d := schema.TestResourceDataRaw(t, dataSourcePostgreSQLGrantID().Schema, c)
+		if err := dataSourcePostgreSQLGrantIDRead(d, nil); err != nil {
+			t.Fatalf("could not read grant ID: %v", err)
+		}
+
+		// The ID must be the one of the postgresql_grant resource with the same arguments.
+		grant := schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, c)
+		assert.Equal(t, generateGrantID(grant), d.Id())
+	}
+}
@@ -0,0 +1,242 @@
+package postgresql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// grantsDataSourceObjectTypes are the object types supported by the postgresql_grants data source.
+var grantsDataSourceObjectTypes = []string{
+	"database",
+	"schema",
+	"table",
+	"sequence",
+	"function",
+	"procedure",
+	"routine",
+	"type",
+	"foreign_data_wrapper",
+	"foreign_server",
+}
+
+func dataSourcePostgreSQLGrants() *schema.Resource {
+	return &schema.Resource{
+		Read: PGReadResourceFunc(dataSourcePostgreSQLGrantsRead),
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The database in which the privileges are read",
+			},
+			"role": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The role to read the privileges of (public for the privileges granted to PUBLIC)",
+			},
+			"object_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(grantsDataSourceObjectTypes, false),
+				Description:  "The PostgreSQL object type to read the privileges on (one of: " + strings.Join(grantsDataSourceObjectTypes, ", ") + ")",
+			},
+			"schema": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The schema of the objects. All the schemas are read if not specified",
+			},
+			"objects": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "The specific objects to read the privileges on (empty means all objects of the requested type)",
+			},
+			"grants": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"schema_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"object_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"privileges": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"grantable_privileges": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+				Description: "The objects on which the role has privileges, with its privileges and the ones it can grant to other roles",
+			},
+		},
+	}
+}
+
+// grantsDataSourceACLQuery returns the query exploding the ACLs of the objects of this type
+// (with the default privileges if the ACL is NULL), with the arguments it uses after the role OID.
+func grantsDataSourceACLQuery(db *DBConnection, d *schema.ResourceData) (string, []interface{}) {
+	database := d.Get("database").(string)
+	pgSchema := d.Get("schema").(string)
+
+	switch d.Get("object_type").(string) {
+	case "database":
+		return `
+SELECT '' AS nspname, datname AS name, (aclexplode(COALESCE(datacl, acldefault('d', datdba)))).*
+FROM pg_database
+WHERE datname = $2
+`, []interface{}{database}
+	case "schema":
+		return `
+SELECT '' AS nspname, nspname AS name, (aclexplode(COALESCE(nspacl, acldefault('n', nspowner)))).*
+FROM pg_namespace
+WHERE $2 = '' OR nspname = $2
+`, []interface{}{pgSchema}
+	case "table", "sequence":
+		kind := "r"
+		if d.Get("object_type").(string) == "sequence" {
+			kind = "s"
+		}
+		return `
+SELECT nspname, relname AS name, (aclexplode(COALESCE(relacl, acldefault($3::"char", relowner)))).*
+FROM pg_class
+JOIN pg_namespace ON pg_namespace.oid = relnamespace
+WHERE relkind = $4 AND ($2 = '' OR nspname = $2)
+`, []interface{}{pgSchema, kind, objectTypes[d.Get("object_type").(string)]}
+	case "function", "procedure", "routine":
+		return `
+SELECT nspname, proname AS name, (aclexplode(COALESCE(proacl, acldefault('f', proowner)))).*
+FROM pg_proc
+JOIN pg_namespace ON pg_namespace.oid = pronamespace
+WHERE ($2 = '' OR nspname = $2)` + routineKindFilter(db, d.Get("object_type").(string)), []interface{}{pgSchema}
+	case "type":
+		return `
+SELECT nspname, typname AS name, (aclexplode(COALESCE(typacl, acldefault('T', typowner)))).*
+FROM pg_type t
+JOIN pg_namespace ON pg_namespace.oid = typnamespace
+WHERE ($2 = '' OR nspname = $2) AND ` + schemaTypesFilter, []interface{}{pgSchema}
+	case "foreign_data_wrapper":
+		return `
+SELECT '' AS nspname, fdwname AS name, (aclexplode(COALESCE(fdwacl, acldefault('F', fdwowner)))).*
+FROM pg_foreign_data_wrapper
+`, nil
+	default:
+		return `
+SELECT '' AS nspname, srvname AS name, (aclexplode(COALESCE(srvacl, acldefault('S', srvowner)))).*
+FROM pg_foreign_server
+`, nil
+	}
+}
+
+// routineKindFilter returns the condition on prokind keeping only the routines of this object type:
+// the functions (including the aggregate and window functions) or the procedures.
+// Before PostgreSQL 11, which added the procedures and prokind, all the routines are functions.
+func routineKindFilter(db *DBConnection, objectType string) string {
+	if !db.featureSupported(featureProcedure) {
+		return ""
+	}
+	switch objectType {
+	case "function":
+		return " AND prokind <> 'p'"
+	case "procedure":
+		return " AND prokind = 'p'"
+	}
+	return ""
+}
+
+func dataSourcePostgreSQLGrantsRead(db *DBConnection, d *schema.ResourceData) error {
+	database := d.Get("database").(string)
+	role := d.Get("role").(string)
+	objects := d.Get("objects").(*schema.Set)
+
+	if d.Get("object_type") == "procedure" && !db.featureSupported(featureProcedure) {
+		return fmt.Errorf(
+			"object type PROCEDURE is not supported for this Postgres version (%s)",
+			db.version,
+		)
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	roleOID, err := getRoleOID(txn, role)
+	if err != nil {
+		return err
+	}
+
+	aclQuery, args := grantsDataSourceACLQuery(db, d)
+	query := fmt.Sprintf(`
+SELECT nspname, name, privilege_type, is_grantable
+FROM (%s) acls
+WHERE grantee = $1
+ORDER BY nspname, name, privilege_type
+`, aclQuery)
+
+	rows, err := txn.Query(query, append([]interface{}{roleOID}, args...)...)
+	if err != nil {
+		return fmt.Errorf("could not read privileges of role %s: %w", role, err)
+	}
+	defer rows.Close()
+
+	grants := make([]interface{}, 0)
+	var current map[string]interface{}
+	for rows.Next() {
+		var nspName, name, privilege string
+		var grantable bool
+
+		if err = rows.Scan(&nspName, &name, &privilege, &grantable); err != nil {
+			return fmt.Errorf("could not scan privileges of role %s: %w", role, err)
+		}
+
+		if objects.Len() > 0 && !objects.Contains(name) {
+			continue
+		}
+
+		if current == nil || current["schema_name"] != nspName || current["object_name"] != name {
+			current = map[string]interface{}{
+				"schema_name":          nspName,
+				"object_name":          name,
+				"privileges":           []string{},
+				"grantable_privileges": []string{},
+			}
+			grants = append(grants, current)
+		}
+		current["privileges"] = append(current["privileges"].([]string), privilege)
+		if grantable {
+			current["grantable_privileges"] = append(current["grantable_privileges"].([]string), privilege)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	d.Set("grants", grants)
+	d.SetId(generateDataSourceGrantsID(d))
+
+	return nil
+}
+
+func generateDataSourceGrantsID(d *schema.ResourceData) string {
+	return strings.Join([]string{
+		d.Get("database").(string),
+		d.Get("role").(string),
+		d.Get("object_type").(string),
+		d.Get("schema").(string),
+		strings.Join(setToSortedSlice(d.Get("objects").(*schema.Set)), ","),
+	}, "_")
+}
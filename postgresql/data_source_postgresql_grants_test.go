@@ -0,0 +1,85 @@
+package postgresql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoutineKindFilter(t *testing.T) {
+	client := (&Config{}).NewClient("postgres")
+	db := &DBConnection{client: client, version: semver.MustParse("14.0.0")}
+	assert.Equal(t, " AND prokind <> 'p'", routineKindFilter(db, "function"))
+	assert.Equal(t, " AND prokind = 'p'", routineKindFilter(db, "procedure"))
+	assert.Equal(t, "", routineKindFilter(db, "routine"))
+
+	db = &DBConnection{client: client, version: semver.MustParse("10.0.0")}
+	assert.Equal(t, "", routineKindFilter(db, "function"))
+}
+
+func TestAccPostgresqlDataSourceGrants(t *testing.T) {
+	skipIfNotAcc(t)
+
+	config := getTestConfig(t)
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	testTables := []string{"test_schema.test_table", "test_schema.test_table2"}
+	createTestTables(t, dbSuffix, testTables, "")
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+	dbExecute(t, config.connStr(dbName), fmt.Sprintf("GRANT SELECT, INSERT ON test_schema.test_table TO %s", roleName))
+	dbExecute(t, config.connStr(dbName), fmt.Sprintf("GRANT SELECT ON test_schema.test_table2 TO %s WITH GRANT OPTION", roleName))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "postgresql_grants" "tables" {
+	database    = "%[1]s"
+	role        = "%[2]s"
+	schema      = "test_schema"
+	object_type = "table"
+}
+
+data "postgresql_grants" "table2" {
+	database    = "%[1]s"
+	role        = "%[2]s"
+	schema      = "test_schema"
+	object_type = "table"
+	objects     = ["test_table2"]
+}
+
+data "postgresql_grants" "database" {
+	database    = "%[1]s"
+	role        = "public"
+	object_type = "database"
+}
+`, dbName, roleName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.postgresql_grants.tables", "grants.#", "2"),
+					resource.TestCheckResourceAttr("data.postgresql_grants.tables", "grants.0.schema_name", "test_schema"),
+					resource.TestCheckResourceAttr("data.postgresql_grants.tables", "grants.0.object_name", "test_table"),
+					resource.TestCheckResourceAttr("data.postgresql_grants.tables", "grants.0.privileges.#", "2"),
+					resource.TestCheckResourceAttr("data.postgresql_grants.tables", "grants.0.privileges.0", "INSERT"),
+					resource.TestCheckResourceAttr("data.postgresql_grants.tables", "grants.0.privileges.1", "SELECT"),
+					resource.TestCheckResourceAttr("data.postgresql_grants.tables", "grants.0.grantable_privileges.#", "0"),
+					resource.TestCheckResourceAttr("data.postgresql_grants.table2", "grants.#", "1"),
+					resource.TestCheckResourceAttr("data.postgresql_grants.table2", "grants.0.object_name", "test_table2"),
+					resource.TestCheckResourceAttr("data.postgresql_grants.table2", "grants.0.grantable_privileges.0", "SELECT"),
+					// The default privileges of PUBLIC on a database are CONNECT and TEMPORARY
+					resource.TestCheckResourceAttr("data.postgresql_grants.database", "grants.#", "1"),
+					resource.TestCheckResourceAttr("data.postgresql_grants.database", "grants.0.object_name", dbName),
+				),
+			},
+		},
+	})
+}
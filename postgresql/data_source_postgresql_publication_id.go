@@ -0,0 +1,30 @@
+package postgresql
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourcePostgreSQLPublicationID() *schema.Resource {
+	return &schema.Resource{
+		// This data source only computes the ID from its arguments, it doesn't need a connection to the database.
+		Read: dataSourcePostgreSQLPublicationIDRead,
+		Schema: map[string]*schema.Schema{
+			pubNameAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the publication",
+			},
+			pubDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The database of the publication, defaults to the provider database",
+			},
+		},
+	}
+}
+
+func dataSourcePostgreSQLPublicationIDRead(d *schema.ResourceData, meta interface{}) error {
+	d.SetId(generatePublicationID(d, getDatabaseForPublication(d, meta.(*Client).databaseName)))
+
+	return nil
+}
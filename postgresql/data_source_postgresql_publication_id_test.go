@@ -0,0 +1,25 @@
+package postgresql
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataSourcePostgreSQLPublicationIDRead(t *testing.T) {
+	client := &Client{databaseName: "postgres"}
+
+	d := schema.TestResourceDataRaw(t, dataSourcePostgreSQLPublicationID().Schema, map[string]interface{}{
+		"name": "my_publication",
+	})
+	assert.NoError(t, dataSourcePostgreSQLPublicationIDRead(d, client))
+	assert.Equal(t, "postgres.my_publication", d.Id())
+
+	d = schema.TestResourceDataRaw(t, dataSourcePostgreSQLPublicationID().Schema, map[string]interface{}{
+		"name":     "my_publication",
+		"database": "app",
+	})
+	assert.NoError(t, dataSourcePostgreSQLPublicationIDRead(d, client))
+	assert.Equal(t, "app.my_publication", d.Id())
+}
@@ -0,0 +1,30 @@
+package postgresql
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourcePostgreSQLSchemaID() *schema.Resource {
+	return &schema.Resource{
+		// This data source only computes the ID from its arguments, it doesn't need a connection to the database.
+		Read: dataSourcePostgreSQLSchemaIDRead,
+		Schema: map[string]*schema.Schema{
+			schemaNameAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the schema",
+			},
+			schemaDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The database of the schema, defaults to the provider database",
+			},
+		},
+	}
+}
+
+func dataSourcePostgreSQLSchemaIDRead(d *schema.ResourceData, meta interface{}) error {
+	d.SetId(generateSchemaID(d, meta.(*Client).databaseName))
+
+	return nil
+}
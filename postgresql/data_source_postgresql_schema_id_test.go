@@ -0,0 +1,25 @@
+package postgresql
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataSourcePostgreSQLSchemaIDRead(t *testing.T) {
+	client := &Client{databaseName: "postgres"}
+
+	d := schema.TestResourceDataRaw(t, dataSourcePostgreSQLSchemaID().Schema, map[string]interface{}{
+		"name": "my_schema",
+	})
+	assert.NoError(t, dataSourcePostgreSQLSchemaIDRead(d, client))
+	assert.Equal(t, "postgres.my_schema", d.Id())
+
+	d = schema.TestResourceDataRaw(t, dataSourcePostgreSQLSchemaID().Schema, map[string]interface{}{
+		"name":     "my.schema",
+		"database": "app",
+	})
+	assert.NoError(t, dataSourcePostgreSQLSchemaIDRead(d, client))
+	assert.Equal(t, `app."my.schema"`, d.Id())
+}
@@ -0,0 +1,187 @@
+package postgresql
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
+)
+
+func dataSourcePostgreSQLSchemaPrivileges() *schema.Resource {
+	return &schema.Resource{
+		Read: PGReadResourceFunc(dataSourcePostgreSQLSchemaPrivilegesRead),
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The database in which the privileges on the schemas are read",
+			},
+			"schemas": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "The schemas to read the privileges on. All the schemas except the system ones are read if not specified",
+			},
+			"include_system_schemas": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Determines whether to include system schemas (pg_ prefix and information_schema) if schemas is not specified",
+			},
+			"roles": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "The roles to report (public for PUBLIC). All the roles having a privilege on one of the schemas are reported if not specified",
+			},
+			"grantees": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The sorted list of the reported roles",
+			},
+			"privileges": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"schema_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"role": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"owner": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"usage": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"create": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+					},
+				},
+				Description: "The privileges of each reported role on each schema (the matrix of the schemas and the grantees), sorted by schema and role",
+			},
+		},
+	}
+}
+
+func dataSourcePostgreSQLSchemaPrivilegesRead(db *DBConnection, d *schema.ResourceData) error {
+	database := d.Get("database").(string)
+	schemas := setToSortedSlice(d.Get("schemas").(*schema.Set))
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	// As in postgresql_grants, a schema without ACL has the default privileges (i.e.: of its owner).
+	rows, err := txn.Query(`
+SELECT n.nspname, pg_get_userbyid(n.nspowner), COALESCE(r.rolname, 'public'),
+	bool_or(acl.privilege_type = 'USAGE'), bool_or(acl.privilege_type = 'CREATE')
+FROM pg_catalog.pg_namespace n
+CROSS JOIN LATERAL aclexplode(COALESCE(n.nspacl, acldefault('n', n.nspowner))) acl
+LEFT JOIN pg_catalog.pg_roles r ON r.oid = acl.grantee
+WHERE CASE
+	WHEN array_length($1::text[], 1) > 0 THEN n.nspname = ANY($1)
+	ELSE $2 OR (n.nspname NOT LIKE 'pg\_%' AND n.nspname <> 'information_schema')
+END
+GROUP BY n.nspname, n.nspowner, r.rolname
+ORDER BY n.nspname
+`, pq.Array(schemas), d.Get("include_system_schemas").(bool))
+	if err != nil {
+		return fmt.Errorf("could not read privileges on the schemas of database %s: %w", database, err)
+	}
+	defer rows.Close()
+
+	roles := d.Get("roles").(*schema.Set)
+
+	// The privileges by schema and role, to report all the roles on each schema.
+	schemaPrivileges := map[string]map[string]map[string]interface{}{}
+	schemaNames := []string{}
+	schemaOwners := map[string]string{}
+	grantees := []string{}
+	for rows.Next() {
+		var nspName, owner, role string
+		var usage, create bool
+		if err := rows.Scan(&nspName, &owner, &role, &usage, &create); err != nil {
+			return fmt.Errorf("could not scan privileges on the schemas of database %s: %w", database, err)
+		}
+
+		if _, ok := schemaPrivileges[nspName]; !ok {
+			schemaPrivileges[nspName] = map[string]map[string]interface{}{}
+			schemaNames = append(schemaNames, nspName)
+			schemaOwners[nspName] = owner
+		}
+		if roles.Len() > 0 && !roles.Contains(role) {
+			continue
+		}
+		if !sliceContainsStr(grantees, role) {
+			grantees = append(grantees, role)
+		}
+
+		schemaPrivileges[nspName][role] = map[string]interface{}{
+			"schema_name": nspName,
+			"role":        role,
+			"owner":       role == owner,
+			"usage":       usage,
+			"create":      create,
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// The requested roles are reported even if they have no privilege.
+	for _, role := range roles.List() {
+		if !sliceContainsStr(grantees, role.(string)) {
+			grantees = append(grantees, role.(string))
+		}
+	}
+	sort.Strings(grantees)
+
+	privileges := make([]interface{}, 0, len(schemaNames)*len(grantees))
+	for _, nspName := range schemaNames {
+		for _, role := range grantees {
+			privilege, ok := schemaPrivileges[nspName][role]
+			if !ok {
+				privilege = map[string]interface{}{
+					"schema_name": nspName,
+					"role":        role,
+					"owner":       role == schemaOwners[nspName],
+					"usage":       false,
+					"create":      false,
+				}
+			}
+			privileges = append(privileges, privilege)
+		}
+	}
+
+	d.Set("grantees", grantees)
+	d.Set("privileges", privileges)
+	d.SetId(generateDataSourceSchemaPrivilegesID(d, database, schemas))
+
+	return nil
+}
+
+func generateDataSourceSchemaPrivilegesID(d *schema.ResourceData, database string, schemas []string) string {
+	return strings.Join([]string{
+		database,
+		strings.Join(schemas, ","),
+		strconv.FormatBool(d.Get("include_system_schemas").(bool)),
+		strings.Join(setToSortedSlice(d.Get("roles").(*schema.Set)), ","),
+	}, "_")
+}
@@ -0,0 +1,57 @@
+package postgresql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccPostgresqlDataSourceSchemaPrivileges(t *testing.T) {
+	skipIfNotAcc(t)
+
+	config := getTestConfig(t)
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+	dbExecute(t, config.connStr(dbName), "CREATE SCHEMA test_report_schema")
+	dbExecute(t, config.connStr(dbName), fmt.Sprintf("GRANT USAGE ON SCHEMA test_report_schema TO %s", roleName))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "postgresql_schema_privileges" "report" {
+	database = "%[1]s"
+	schemas  = ["public", "test_report_schema"]
+	roles    = ["%[2]s", "public"]
+}
+`, dbName, roleName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.postgresql_schema_privileges.report", "grantees.#", "2"),
+					// Each role is reported on each schema.
+					resource.TestCheckResourceAttr("data.postgresql_schema_privileges.report", "privileges.#", "4"),
+					resource.TestCheckTypeSetElemNestedAttrs("data.postgresql_schema_privileges.report", "privileges.*", map[string]string{
+						"schema_name": "test_report_schema",
+						"role":        roleName,
+						"owner":       "false",
+						"usage":       "true",
+						"create":      "false",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("data.postgresql_schema_privileges.report", "privileges.*", map[string]string{
+						"schema_name": "test_report_schema",
+						"role":        "public",
+						"usage":       "false",
+						"create":      "false",
+					}),
+				),
+			},
+		},
+	})
+}
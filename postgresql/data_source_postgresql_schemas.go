@@ -25,7 +25,7 @@ const schemaPatternMatchingTarget = "schema_name"
 
 func dataSourcePostgreSQLDatabaseSchemas() *schema.Resource {
 	return &schema.Resource{
-		Read: PGResourceFunc(dataSourcePostgreSQLSchemasRead),
+		Read: PGReadResourceFunc(dataSourcePostgreSQLSchemasRead),
 		Schema: map[string]*schema.Schema{
 			"database": {
 				Type:        schema.TypeString,
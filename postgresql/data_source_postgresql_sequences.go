@@ -18,7 +18,7 @@ const (
 
 func dataSourcePostgreSQLDatabaseSequences() *schema.Resource {
 	return &schema.Resource{
-		Read: PGResourceFunc(dataSourcePostgreSQLSequencesRead),
+		Read: PGReadResourceFunc(dataSourcePostgreSQLSequencesRead),
 		Schema: map[string]*schema.Schema{
 			"database": {
 				Type:        schema.TypeString,
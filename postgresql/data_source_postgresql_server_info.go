@@ -0,0 +1,125 @@
+package postgresql
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Platforms detected by the postgresql_server_info data source.
+const (
+	serverPlatformAurora   = "aurora"
+	serverPlatformRDS      = "rds"
+	serverPlatformCloudSQL = "cloudsql"
+)
+
+func dataSourcePostgreSQLServerInfo() *schema.Resource {
+	return &schema.Resource{
+		Read: PGReadResourceFunc(dataSourcePostgreSQLServerInfoRead),
+		Schema: map[string]*schema.Schema{
+			"version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The PostgreSQL version used by the provider to detect the supported features",
+			},
+			"server_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The version reported by the server (server_version setting)",
+			},
+			"current_user": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The role the provider is connected as",
+			},
+			"is_superuser": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "If the connected role is a superuser",
+			},
+			"platform": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The managed platform hosting the server (aurora, rds or cloudsql), empty if not detected",
+			},
+			"max_connections": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The maximum number of concurrent connections to the server",
+			},
+			"features": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeBool},
+				Description: "The provider features (see feature_overrides) with whether they are supported by the server",
+			},
+		},
+	}
+}
+
+func dataSourcePostgreSQLServerInfoRead(db *DBConnection, d *schema.ResourceData) error {
+	currentUser, err := getCurrentUser(db)
+	if err != nil {
+		return err
+	}
+
+	superuser, err := db.isSuperuser()
+	if err != nil {
+		return err
+	}
+
+	var serverVersion string
+	var maxConnections int
+	if err := db.QueryRow(
+		"SELECT current_setting('server_version'), current_setting('max_connections')::int",
+	).Scan(&serverVersion, &maxConnections); err != nil {
+		return fmt.Errorf("could not read server settings: %w", err)
+	}
+
+	platform, err := detectServerPlatform(db)
+	if err != nil {
+		return err
+	}
+
+	features := make(map[string]interface{}, len(featureNames))
+	for name, feature := range featureNames {
+		features[name] = db.featureSupported(feature)
+	}
+
+	d.Set("version", db.version.String())
+	d.Set("server_version", serverVersion)
+	d.Set("current_user", currentUser)
+	d.Set("is_superuser", superuser)
+	d.Set("platform", platform)
+	d.Set("max_connections", maxConnections)
+	d.Set("features", features)
+	host, port := db.client.config.endpoint(db.client.databaseName)
+	d.SetId(fmt.Sprintf("%s:%d/%s", host, port, db.client.databaseName))
+
+	return nil
+}
+
+// detectServerPlatform returns the managed platform of the server from the objects they create.
+// Aurora is checked first as it also has the RDS roles.
+func detectServerPlatform(db QueryAble) (string, error) {
+	var aurora, rds, cloudSQL bool
+	err := db.QueryRow(`
+SELECT
+	EXISTS (SELECT 1 FROM pg_catalog.pg_proc WHERE proname = 'aurora_version'),
+	EXISTS (SELECT 1 FROM pg_catalog.pg_roles WHERE rolname = 'rds_superuser'),
+	EXISTS (SELECT 1 FROM pg_catalog.pg_roles WHERE rolname = 'cloudsqlsuperuser')
+`).Scan(&aurora, &rds, &cloudSQL)
+	if err != nil {
+		return "", fmt.Errorf("could not detect the server platform: %w", err)
+	}
+
+	switch {
+	case aurora:
+		return serverPlatformAurora, nil
+	case rds:
+		return serverPlatformRDS, nil
+	case cloudSQL:
+		return serverPlatformCloudSQL, nil
+	}
+	return "", nil
+}
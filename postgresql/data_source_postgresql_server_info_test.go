@@ -0,0 +1,32 @@
+package postgresql
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccPostgresqlDataSourceServerInfo(t *testing.T) {
+	skipIfNotAcc(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "postgresql_server_info" "current" {}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.postgresql_server_info.current", "version"),
+					resource.TestCheckResourceAttrSet("data.postgresql_server_info.current", "server_version"),
+					resource.TestCheckResourceAttrSet("data.postgresql_server_info.current", "current_user"),
+					resource.TestCheckResourceAttrSet("data.postgresql_server_info.current", "is_superuser"),
+					resource.TestCheckResourceAttr("data.postgresql_server_info.current", "platform", ""),
+					resource.TestCheckResourceAttrSet("data.postgresql_server_info.current", "max_connections"),
+					resource.TestCheckResourceAttr("data.postgresql_server_info.current", "features.extension", "true"),
+				),
+			},
+		},
+	})
+}
@@ -1,39 +1,242 @@
 package postgresql
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
 	"log"
 	"regexp"
+	"sort"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/lib/pq"
 )
 
 func PGResourceFunc(fn func(*DBConnection, *schema.ResourceData) error) func(*schema.ResourceData, interface{}) error {
 	return func(d *schema.ResourceData, meta interface{}) error {
-		client := meta.(*Client)
+		_, err := runResourceFunc(fn, d, meta)
+		return err
+	}
+}
+
+// runResourceFunc executes fn and returns the warnings it added to the connection (see DBConnection.warn).
+func runResourceFunc(fn func(*DBConnection, *schema.ResourceData) error, d *schema.ResourceData, meta interface{}) ([]string, error) {
+	client, err := resourceClient(d, meta)
+	if err != nil {
+		return nil, err
+	}
 
+	var warnings []string
+	run := func() error {
 		db, err := client.Connect()
 		if err != nil {
 			return err
 		}
 
-		return fn(db, d)
+		err = fn(db, d)
+		warnings = db.warnings
+		if !client.readOnly {
+			// What was read during the refresh may have been changed.
+			client.readCache.clear()
+		}
+		return err
+	}
+
+	err = client.config.SQLRecorder.run(func() error {
+		// Only the Read functions are retried as a whole, the transactions of the functions
+		// changing the database are retried individually (see withTransaction).
+		if client.readOnly {
+			return client.withRetry(run)
+		}
+		return run()
+	})
+	return warnings, err
+}
+
+// PGReadResourceFunc is like PGResourceFunc for the Read functions: the transactions are READ ONLY
+// and they are sent to the replica if the provider reads from it.
+func PGReadResourceFunc(fn func(*DBConnection, *schema.ResourceData) error) func(*schema.ResourceData, interface{}) error {
+	return func(d *schema.ResourceData, meta interface{}) error {
+		client, err := resourceClient(d, meta)
+		if err != nil {
+			return err
+		}
+		return PGResourceFunc(fn)(d, client.readOnlyClient())
 	}
 }
 
 func PGResourceExistsFunc(fn func(*DBConnection, *schema.ResourceData) (bool, error)) func(*schema.ResourceData, interface{}) (bool, error) {
 	return func(d *schema.ResourceData, meta interface{}) (bool, error) {
-		client := meta.(*Client)
-
-		db, err := client.Connect()
+		client, err := resourceClient(d, meta)
 		if err != nil {
 			return false, err
 		}
+		client = client.readOnlyClient()
 
-		return fn(db, d)
+		var exists bool
+		err = client.withRetry(func() error {
+			db, err := client.Connect()
+			if err != nil {
+				return err
+			}
+
+			exists, err = fn(db, d)
+			return err
+		})
+		return exists, err
+	}
+}
+
+// PGImportResourceFunc is like PGResourceExistsFunc for the importers which need to read the database,
+// e.g.: to check that the imported object exists and to set all its attributes.
+func PGImportResourceFunc(fn func(*DBConnection, *schema.ResourceData) ([]*schema.ResourceData, error)) schema.StateContextFunc {
+	return func(_ context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+		client, err := resourceClient(d, meta)
+		if err != nil {
+			return nil, err
+		}
+		client = client.readOnlyClient()
+
+		var result []*schema.ResourceData
+		err = client.withRetry(func() error {
+			db, err := client.Connect()
+			if err != nil {
+				return err
+			}
+
+			result, err = fn(db, d)
+			return err
+		})
+		return result, err
+	}
+}
+
+// resourceClient returns the client of the provider, or the client of the endpoint set by the resource (see endpointSchema).
+func resourceClient(d *schema.ResourceData, meta interface{}) (*Client, error) {
+	endpoint, _ := d.Get(endpointAttr).(string)
+	return meta.(*Client).endpointClient(endpoint)
+}
+
+// PGResourceFuncWithWarnings is like PGResourceFunc but the warnings added by fn to the connection
+// and, if strict_security_warnings is enabled in the provider, the messages returned by the warnings function
+// are returned as warning diagnostics once the resource has been created or updated.
+// The security warnings are also set in the security_warnings attribute if warnings is not nil
+// (see customizeDiffSecurityWarnings).
+func PGResourceFuncWithWarnings(
+	fn func(*DBConnection, *schema.ResourceData) error,
+	warnings securityWarningsFunc,
+) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		dbWarnings, err := runResourceFunc(fn, d, meta)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		var diags diag.Diagnostics
+		for _, warning := range dbWarnings {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "Warning",
+				Detail:   warning,
+			})
+		}
+		if warnings == nil {
+			return diags
+		}
+
+		security := securityWarnings(meta.(*Client).config, d.Get, warnings)
+		d.Set(securityWarningsAttr, security)
+		for _, warning := range security {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "Security warning",
+				Detail:   warning,
+			})
+		}
+		return diags
+	}
+}
+
+// securityWarningsAttr is the computed attribute listing the security warnings of the configuration of a resource
+// if strict_security_warnings is enabled. The Terraform plugin SDK doesn't allow resources to return warnings
+// during the plan, the attribute allows to show them in the plan.
+const securityWarningsAttr = "security_warnings"
+
+// securityWarningsFunc returns the security warnings of the configuration of a resource, see strict_security_warnings.
+type securityWarningsFunc func(get ResourceSchemeGetter) []string
+
+func securityWarningsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Description: "The security warnings of the configuration if strict_security_warnings is enabled in the provider",
+	}
+}
+
+// securityWarnings returns the security warnings of the resource if strict_security_warnings is enabled.
+func securityWarnings(config Config, get ResourceSchemeGetter, warnings securityWarningsFunc) []string {
+	if !config.StrictSecurityWarnings {
+		return nil
+	}
+	return warnings(get)
+}
+
+// customizeDiffSecurityWarnings plans the security_warnings attribute from the planned configuration, so the warnings
+// are shown by the plan. It's unknown until the apply if one of the attributes the warnings depend on is unknown.
+func customizeDiffSecurityWarnings(d *schema.ResourceDiff, meta interface{}, warnings securityWarningsFunc, attrs ...string) error {
+	for _, attr := range attrs {
+		if !d.NewValueKnown(attr) {
+			return d.SetNewComputed(securityWarningsAttr)
+		}
+	}
+
+	planned := securityWarnings(meta.(*Client).config, d.Get, warnings)
+	for _, warning := range planned {
+		log.Printf("[WARN] security warning: %s", warning)
+	}
+
+	current := d.Get(securityWarningsAttr).([]interface{})
+	if len(current) == len(planned) {
+		same := true
+		for i, warning := range planned {
+			same = same && current[i].(string) == warning
+		}
+		if same {
+			return nil
+		}
+	}
+	return d.SetNew(securityWarningsAttr, planned)
+}
+
+// customizeDiffSkipFinalRevoke fails the plan of the replacement (a change of one of the forceNewAttrs) of a resource
+// whose skip_final_revoke_on_destroy is set in the state: Delete cannot tell a replacement from a destroy,
+// the privileges of the replaced resource would never be revoked.
+func customizeDiffSkipFinalRevoke(d *schema.ResourceDiff, forceNewAttrs ...string) error {
+	if d.Id() == "" || !d.HasChanges(forceNewAttrs...) {
+		return nil
+	}
+	if skip, _ := d.GetChange("skip_final_revoke_on_destroy"); !skip.(bool) {
+		return nil
+	}
+	return fmt.Errorf(
+		"%s cannot be replaced while skip_final_revoke_on_destroy is set as it would not be revoked, "+
+			"apply skip_final_revoke_on_destroy = false before changing %s",
+		d.Id(), strings.Join(forceNewAttrs, ", "),
+	)
+}
+
+// readWithSecurityWarnings sets the security_warnings attribute once the resource has been read by fn,
+// e.g.: after its import.
+func readWithSecurityWarnings(fn func(*DBConnection, *schema.ResourceData) error, warnings securityWarningsFunc) func(*DBConnection, *schema.ResourceData) error {
+	return func(db *DBConnection, d *schema.ResourceData) error {
+		if err := fn(db, d); err != nil || d.Id() == "" {
+			return err
+		}
+		return d.Set(securityWarningsAttr, securityWarnings(db.client.config, d.Get, warnings))
 	}
 }
 
@@ -112,6 +315,31 @@ func grantRoleMembership(db QueryAble, role, member string) (bool, error) {
 	return true, nil
 }
 
+// withExecuteAs runs fn with SET LOCAL ROLE role so the objects created by fn are directly
+// owned by this role. The connected user is temporarily granted the role if needed (see withRolesGranted).
+// fn is executed directly if role is empty.
+func withExecuteAs(txn *sql.Tx, role string, fn func() error) error {
+	if role == "" {
+		return fn()
+	}
+
+	return withRolesGranted(txn, []string{role}, func() error {
+		if _, err := txn.Exec(fmt.Sprintf("SET LOCAL ROLE %s", pq.QuoteIdentifier(role))); err != nil {
+			return fmt.Errorf("could not set role %s: %w", role, err)
+		}
+
+		if err := fn(); err != nil {
+			return err
+		}
+
+		// The role needs to be reset before the temporary granted roles are revoked.
+		if _, err := txn.Exec("RESET ROLE"); err != nil {
+			return fmt.Errorf("could not reset role: %w", err)
+		}
+		return nil
+	})
+}
+
 // revokeRoleMembership revokes the role *role* from the user *member*.
 // It returns false if the revoke is not needed because the user is not a member of this role.
 func revokeRoleMembership(db QueryAble, role, member string) (bool, error) {
@@ -238,6 +466,41 @@ func withRolesGranted(txn *sql.Tx, roles []string, fn func() error) error {
 	return nil
 }
 
+// previousValueGetter returns a ResourceSchemeGetter which returns the previous value
+// of the attributes which have changed.
+// For the role attributes specified in roleAttrs, if the previous role does not exist anymore,
+// it has been renamed (e.g.: by a postgresql_role resource applied before) and the new
+// value is returned instead, as PostgreSQL privileges follow the role OID and not its name.
+func previousValueGetter(txn *sql.Tx, d *schema.ResourceData, roleAttrs ...string) (ResourceSchemeGetter, error) {
+	renamed := map[string]bool{}
+	for _, attr := range roleAttrs {
+		if !d.HasChange(attr) {
+			continue
+		}
+		old, _ := d.GetChange(attr)
+		if old.(string) == "" || isPublicRole(old.(string)) {
+			continue
+		}
+		exists, err := roleExists(txn, old.(string))
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			log.Printf("[DEBUG] previous role %s does not exist anymore, considering it has been renamed to %s", old, d.Get(attr))
+			renamed[attr] = true
+		}
+	}
+
+	return func(name string) interface{} {
+		if d.HasChange(name) && !renamed[name] {
+			old, _ := d.GetChange(name)
+			return old
+		}
+
+		return d.Get(name)
+	}, nil
+}
+
 func sliceContainsStr(haystack []string, needle string) bool {
 	for _, s := range haystack {
 		if s == needle {
@@ -258,9 +521,50 @@ var allowedPrivileges = map[string][]string{
 	"procedure":            {"ALL", "EXECUTE"},
 	"routine":              {"ALL", "EXECUTE"},
 	"type":                 {"ALL", "USAGE"},
+	"domain":               {"ALL", "USAGE"},
+	"large_object":         {"ALL", "SELECT", "UPDATE"},
 	"foreign_data_wrapper": {"ALL", "USAGE"},
 	"foreign_server":       {"ALL", "USAGE"},
 	"column":               {"ALL", "SELECT", "INSERT", "UPDATE", "REFERENCES"},
+	// Redshift object types, see redshiftObjectTypes.
+	"external_schema": {"USAGE"},
+	"datashare":       {"ALTER", "SHARE"},
+}
+
+// privilegeAliases are the alternative spellings of the privileges accepted by PostgreSQL.
+var privilegeAliases = map[string]string{
+	"TEMP":           "TEMPORARY",
+	"ALL PRIVILEGES": "ALL",
+}
+
+// normalizePrivilege returns the privilege as PostgreSQL reads it back (upper case, without alias)
+// so e.g. temp and TEMPORARY don't cause a diff.
+func normalizePrivilege(privilege string) string {
+	privilege = strings.ToUpper(strings.Join(strings.Fields(privilege), " "))
+	if name, ok := privilegeAliases[privilege]; ok {
+		return name
+	}
+	return privilege
+}
+
+// normalizePrivileges returns the set of the normalized privileges.
+func normalizePrivileges(privileges *schema.Set) *schema.Set {
+	normalized := schema.NewSet(hashPrivilege, nil)
+	for _, privilege := range privileges.List() {
+		normalized.Add(normalizePrivilege(privilege.(string)))
+	}
+	return normalized
+}
+
+// hashPrivilege is the hash function of the privileges sets, the configured and the read
+// privileges have to be in the same set element.
+func hashPrivilege(v interface{}) int {
+	return schema.HashString(normalizePrivilege(v.(string)))
+}
+
+// privilegesDiffSuppress suppresses the diff between two spellings of the same privilege (e.g.: temp and TEMPORARY).
+func privilegesDiffSuppress(k, old, new string, d *schema.ResourceData) bool {
+	return normalizePrivilege(old) == normalizePrivilege(new)
 }
 
 // validatePrivileges checks that privileges to apply are allowed for this object type.
@@ -274,13 +578,29 @@ func validatePrivileges(d *schema.ResourceData) error {
 	}
 
 	for _, priv := range privileges {
-		if !sliceContainsStr(allowed, priv.(string)) {
+		if !sliceContainsStr(allowed, normalizePrivilege(priv.(string))) {
 			return fmt.Errorf("%s is not an allowed privilege for object type %s", priv, objectType)
 		}
 	}
 	return nil
 }
 
+// expandPrivileges returns the list of privileges with ALL replaced by the privileges it implies
+// for this object type.
+func expandPrivileges(objectType string, privileges *schema.Set) []string {
+	if !privileges.Contains("ALL") {
+		return setToSortedSlice(privileges)
+	}
+
+	expanded := []string{}
+	for _, p := range allowedPrivileges[objectType] {
+		if p != "ALL" {
+			expanded = append(expanded, p)
+		}
+	}
+	return expanded
+}
+
 func resourcePrivilegesEqual(granted *schema.Set, d *schema.ResourceData) bool {
 	objectType := d.Get("object_type").(string)
 	wanted := d.Get("privileges").(*schema.Set)
@@ -321,6 +641,15 @@ func stringSliceToSet(slice []string) *schema.Set {
 	return schema.NewSet(schema.HashString, s)
 }
 
+func setToSortedSlice(set *schema.Set) []string {
+	values := []string{}
+	for _, v := range set.List() {
+		values = append(values, v.(string))
+	}
+	sort.Strings(values)
+	return values
+}
+
 func quoteIdentifyIdent(ident string) string {
 	// When passing a function with arguments like "test(text, char)" this will correctly parse it to "test"(text, char).
 	// If we were to add quotes around the whole ident postgres would not be able to find the function.
@@ -344,14 +673,34 @@ func quoteIdentifyIdent(ident string) string {
 func setToPgIdentList(schema string, idents *schema.Set) string {
 	quotedIdents := make([]string, idents.Len())
 	for i, ident := range idents.List() {
+		identSchema, name := splitQualifiedIdent(schema, ident.(string))
 		quotedIdents[i] = fmt.Sprintf(
 			"%s.%s",
-			pq.QuoteIdentifier(schema), quoteIdentifyIdent(ident.(string)),
+			pq.QuoteIdentifier(identSchema), quoteIdentifyIdent(name),
 		)
 	}
 	return strings.Join(quotedIdents, ",")
 }
 
+// splitQualifiedIdent returns the schema and the name of an identifier which can be qualified
+// with its schema (e.g.: other_schema.seq1 or "my.schema"."my.table"), function arguments are kept in the name.
+// Identifiers which are not qualified are in defaultSchema.
+func splitQualifiedIdent(defaultSchema, ident string) (string, string) {
+	name, args := ident, ""
+	if i := strings.Index(ident, "("); i >= 0 {
+		name, args = ident[:i], ident[i:]
+	}
+
+	parts, err := parseObjectID(name)
+	switch {
+	case err != nil || len(parts) > 2:
+		return defaultSchema, ident
+	case len(parts) == 2:
+		return parts[0], parts[1] + args
+	}
+	return defaultSchema, parts[0] + args
+}
+
 func setToPgIdentListWithoutSchema(idents *schema.Set) string {
 	quotedIdents := make([]string, idents.Len())
 	for i, ident := range idents.List() {
@@ -371,23 +720,84 @@ func setToPgIdentSimpleList(idents *schema.Set) string {
 // startTransaction starts a new DB transaction on the specified database.
 // If the database is specified and different from the one configured in the provider,
 // it will create a new connection pool if needed.
+// The transaction is READ ONLY if the client is used by a Read or Exists function.
 func startTransaction(client *Client, database string) (*sql.Tx, error) {
 	if database != "" && database != client.databaseName {
+		readOnly := client.readOnly
 		client = client.config.NewClient(database)
+		client.readOnly = readOnly
 	}
 	db, err := client.Connect()
 	if err != nil {
 		return nil, err
 	}
 
-	txn, err := db.Begin()
+	txn, err := db.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: client.readOnly, Isolation: client.isolationLevel()})
 	if err != nil {
 		return nil, fmt.Errorf("could not start transaction: %w", err)
 	}
 
+	if timeout := client.statementTimeout(); timeout > 0 {
+		if _, err := txn.Exec(fmt.Sprintf("SET LOCAL statement_timeout = %d", timeout*1000)); err != nil {
+			deferredRollback(txn)
+			return nil, fmt.Errorf("could not set statement_timeout: %w", err)
+		}
+	}
+
 	return txn, nil
 }
 
+// execWithStatementTimeout executes a statement outside of a transaction (e.g.: CREATE DATABASE, which can't run
+// in a transaction block) with the statement timeout of the transactions of the client (see ddl_timeout).
+// The timeout is set in the session of a dedicated connection and reset before it's returned to the pool.
+func execWithStatementTimeout(db *DBConnection, query string, args ...interface{}) (sql.Result, error) {
+	timeout := db.client.statementTimeout()
+	if timeout <= 0 {
+		return db.Exec(query, args...)
+	}
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf("SET statement_timeout = %d", timeout*1000)); err != nil {
+		return nil, fmt.Errorf("could not set statement_timeout: %w", err)
+	}
+	result, err := conn.ExecContext(ctx, query, args...)
+	if _, resetErr := conn.ExecContext(ctx, "RESET statement_timeout"); resetErr != nil {
+		// The connection is discarded so the setting doesn't apply to the next statements.
+		_ = conn.Raw(func(interface{}) error {
+			return driver.ErrBadConn
+		})
+	}
+	return result, err
+}
+
+// withTransaction executes fn in a new transaction on the database and commits it.
+// The transaction is retried with the retries of the provider (see Client.withRetry): fn must only change
+// the database through txn, so its work is rolled back when it fails and it can be run again.
+// The resource functions are not retried as a whole as what they committed before the error would be run again.
+func withTransaction(client *Client, database string, fn func(txn *sql.Tx) error) error {
+	return client.withRetry(func() error {
+		txn, err := startTransaction(client, database)
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		if err := fn(txn); err != nil {
+			return err
+		}
+		if err := txn.Commit(); err != nil {
+			return fmt.Errorf("could not commit transaction: %w", err)
+		}
+		return nil
+	})
+}
+
 func dbExists(db QueryAble, dbname string) (bool, error) {
 	err := db.QueryRow("SELECT datname FROM pg_database WHERE datname=$1", dbname).Scan(&dbname)
 	switch {
@@ -449,6 +859,121 @@ func deferredRollback(txn *sql.Tx) {
 	}
 }
 
+// maxBatchStatements is the maximum number of statements sent in a single Exec call by execBatch.
+const maxBatchStatements = 50
+
+// execBatch executes the statements in as few round trips as possible by sending them
+// separated by semicolons in a single Exec call.
+// This is only safe for statements without bind parameters, which are sent with the
+// simple query protocol and are executed in the current transaction.
+// Empty statements are skipped.
+func execBatch(db QueryAble, statements ...string) error {
+	batch := []string{}
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		query := strings.Join(batch, ";\n")
+		batch = batch[:0]
+		if _, err := db.Exec(query); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	for _, statement := range statements {
+		if statement == "" {
+			continue
+		}
+		batch = append(batch, statement)
+		if len(batch) == maxBatchStatements {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return flush()
+}
+
+// generateObjectID joins the parts of a resource ID with dots.
+// The parts which contain a dot or a double quote are quoted like SQL identifiers
+// (e.g.: "my.db".my_schema) so the ID can be split back with parseObjectID.
+func generateObjectID(parts ...string) string {
+	quoted := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if strings.ContainsAny(part, `."`) {
+			part = `"` + strings.ReplaceAll(part, `"`, `""`) + `"`
+		}
+		quoted = append(quoted, part)
+	}
+	return strings.Join(quoted, ".")
+}
+
+// parseObjectID splits an ID generated by generateObjectID in its parts.
+func parseObjectID(id string) ([]string, error) {
+	var parts []string
+	var part strings.Builder
+
+	for i := 0; i <= len(id); i++ {
+		if i < len(id) && id[i] == '"' && part.Len() == 0 {
+			// Quoted part, read until the closing quote (doubled quotes are escaped quotes)
+			closed := false
+			for i++; i < len(id); i++ {
+				if id[i] != '"' {
+					part.WriteByte(id[i])
+					continue
+				}
+				if i+1 < len(id) && id[i+1] == '"' {
+					part.WriteByte('"')
+					i++
+					continue
+				}
+				closed = true
+				break
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated quoted part in ID %s", id)
+			}
+			i++
+			if i < len(id) && id[i] != '.' {
+				return nil, fmt.Errorf("unexpected character after quoted part in ID %s", id)
+			}
+		}
+
+		if i == len(id) || id[i] == '.' {
+			parts = append(parts, part.String())
+			part.Reset()
+			continue
+		}
+		part.WriteByte(id[i])
+	}
+
+	return parts, nil
+}
+
+// upgradeObjectIDStateV0 replaces the ID of the version 0 of a resource, which joined its database and name
+// with a dot without quoting them, by the ID generated by generateObjectID.
+// The database is read from the ID if it's not in the state.
+func upgradeObjectIDStateV0(rawState map[string]interface{}, databaseAttr, nameAttr string) map[string]interface{} {
+	if rawState == nil {
+		return rawState
+	}
+
+	id, _ := rawState["id"].(string)
+	name, _ := rawState[nameAttr].(string)
+	database, _ := rawState[databaseAttr].(string)
+	if name == "" || !strings.HasSuffix(id, "."+name) {
+		return rawState
+	}
+	if database == "" {
+		database = strings.TrimSuffix(id, "."+name)
+	}
+
+	rawState["id"] = generateObjectID(database, name)
+	return rawState
+}
+
 func getDatabase(d *schema.ResourceData, databaseName string) string {
 	if v, ok := d.GetOk(extDatabaseAttr); ok {
 		databaseName = v.(string)
@@ -457,6 +982,44 @@ func getDatabase(d *schema.ResourceData, databaseName string) string {
 	return databaseName
 }
 
+// getObjectOwner returns the owner of the object to create: the one set in the resource
+// or the provider object_owner_role if it is not set.
+func getObjectOwner(d *schema.ResourceData, ownerAttr string, config Config) string {
+	if v, ok := d.GetOk(ownerAttr); ok {
+		return v.(string)
+	}
+	return config.ObjectOwnerRole
+}
+
+// ownerTokens are the special role names, in any case, accepted as owner of the objects:
+// they are resolved to the role they stand for when applying.
+var ownerTokens = []string{"CURRENT_USER", "CURRENT_ROLE", "SESSION_USER"}
+
+// pgDatabaseOwnerRole is the predefined role (since PostgreSQL 14) whose member is the owner of the current database.
+const pgDatabaseOwnerRole = "pg_database_owner"
+
+func isOwnerToken(owner string) bool {
+	for _, token := range ownerTokens {
+		if strings.EqualFold(owner, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveOwner returns the name of the role for an owner token, or the owner itself if it's a role name.
+func resolveOwner(db QueryAble, owner string) (string, error) {
+	if !isOwnerToken(owner) {
+		return owner, nil
+	}
+
+	var role string
+	if err := db.QueryRow("SELECT " + strings.ToUpper(owner) + "::TEXT").Scan(&role); err != nil {
+		return "", fmt.Errorf("could not resolve owner %s: %w", owner, err)
+	}
+	return role, nil
+}
+
 func getDatabaseOwner(db QueryAble, database string) (string, error) {
 	dbQueryString := "$1"
 	dbQueryValues := []interface{}{database}
@@ -526,10 +1089,111 @@ func getTablesOwner(db QueryAble, schemaName string) ([]string, error) {
 	return owners, nil
 }
 
+// schemaTypesFilter restricts a query on pg_type (aliased as t) to the types created explicitly
+// (base, composite, enum, domain, range types): the implicit array types and the row types
+// of tables, views, etc. are excluded as they can't be managed on their own.
+const schemaTypesFilter = `
+NOT EXISTS (
+	SELECT 1 FROM pg_catalog.pg_type elem WHERE elem.oid = t.typelem AND elem.typarray = t.oid
+)
+AND (
+	t.typrelid = 0 OR (SELECT relkind FROM pg_catalog.pg_class WHERE pg_class.oid = t.typrelid) = 'c'
+)
+`
+
+// schemaDomainsFilter restricts a query on pg_type (aliased as t) to the domains.
+const schemaDomainsFilter = "t.typtype = 'd'"
+
+// typesFilter returns the filter of the pg_type rows managed with this grant object type (type or domain).
+func typesFilter(objectType string) string {
+	if objectType == "domain" {
+		return schemaDomainsFilter
+	}
+	return schemaTypesFilter
+}
+
+// getSchemaTypes retrieves the names of all the types matching the filter in the specified schema.
+func getSchemaTypes(db QueryAble, schemaName, filter string) ([]string, error) {
+	rows, err := db.Query(`
+SELECT t.typname
+FROM pg_catalog.pg_type t
+JOIN pg_catalog.pg_namespace n ON n.oid = t.typnamespace
+WHERE n.nspname = $1 AND `+filter,
+		schemaName,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error while looking for types in schema '%s': %w", schemaName, err)
+	}
+	defer rows.Close()
+
+	var types []string
+	for rows.Next() {
+		var typeName string
+		if err := rows.Scan(&typeName); err != nil {
+			return nil, fmt.Errorf("could not scan type name: %w", err)
+		}
+		types = append(types, typeName)
+	}
+
+	return types, rows.Err()
+}
+
+// getTypesOwner retrieves all the owners for all the types matching the filter in the specified schema.
+func getTypesOwner(db QueryAble, schemaName, filter string) ([]string, error) {
+	rows, err := db.Query(`
+SELECT DISTINCT pg_roles.rolname
+FROM pg_catalog.pg_type t
+JOIN pg_catalog.pg_namespace n ON n.oid = t.typnamespace
+JOIN pg_catalog.pg_roles ON pg_roles.oid = t.typowner
+WHERE n.nspname = $1 AND `+filter,
+		schemaName,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error while looking for owners of types in schema '%s': %w", schemaName, err)
+	}
+	defer rows.Close()
+
+	var owners []string
+	for rows.Next() {
+		var owner string
+		if err := rows.Scan(&owner); err != nil {
+			return nil, fmt.Errorf("could not scan types owner: %w", err)
+		}
+		owners = append(owners, owner)
+	}
+
+	return owners, rows.Err()
+}
+
+// getLargeObjectsOwner retrieves all the owners of the specified large objects.
+func getLargeObjectsOwner(db QueryAble, oids []string) ([]string, error) {
+	rows, err := db.Query(`
+SELECT DISTINCT pg_roles.rolname
+FROM pg_catalog.pg_largeobject_metadata l
+JOIN pg_catalog.pg_roles ON pg_roles.oid = l.lomowner
+WHERE l.oid::text = ANY($1)
+`, pq.Array(oids))
+	if err != nil {
+		return nil, fmt.Errorf("error while looking for owners of large objects: %w", err)
+	}
+	defer rows.Close()
+
+	var owners []string
+	for rows.Next() {
+		var owner string
+		if err := rows.Scan(&owner); err != nil {
+			return nil, fmt.Errorf("could not scan large objects owner: %w", err)
+		}
+		owners = append(owners, owner)
+	}
+
+	return owners, rows.Err()
+}
+
 func resolveOwners(db QueryAble, owners []string) ([]string, error) {
 	resolvedOwners := []string{}
 	for _, owner := range owners {
-		if owner == "pg_database_owner" {
+		if owner == pgDatabaseOwnerRole {
 			var err error
 			owner, err = getDatabaseOwner(db, "")
 			if err != nil {
@@ -552,10 +1216,77 @@ func isSuperuser(db QueryAble, role string) (bool, error) {
 	return superuser, nil
 }
 
+// maxIdentifierLength is the maximum length in bytes of an identifier (NAMEDATALEN - 1),
+// PostgreSQL truncates the longer ones.
+const maxIdentifierLength = 63
+
+// checkIdentifier checks a name against the PostgreSQL identifier rules.
+// As the names are always quoted, any character except NUL is allowed but the upper case letters
+// make the name case sensitive (it's not found unquoted as PostgreSQL folds the unquoted names to lower case),
+// which is returned as a warning.
+func checkIdentifier(key, name string) (string, error) {
+	switch {
+	case name == "":
+		return "", fmt.Errorf("%s must not be empty", key)
+	case strings.ContainsRune(name, 0):
+		return "", fmt.Errorf("%s (%q) must not contain a NUL character", key, name)
+	case len(name) > maxIdentifierLength:
+		return "", fmt.Errorf(
+			"%s (%q) is %d bytes long but PostgreSQL identifiers are limited to %d bytes (the longer ones are truncated)",
+			key, name, len(name), maxIdentifierLength,
+		)
+	case name != strings.ToLower(name) && !isPublicRole(name):
+		return fmt.Sprintf(
+			"%s (%q) contains upper case letters: it's case sensitive and must be quoted in SQL (%s), unquoted it's folded to %q",
+			key, name, pq.QuoteIdentifier(name), strings.ToLower(name),
+		), nil
+	}
+	return "", nil
+}
+
+// validateIdentifier is a ValidateFunc checking a name against the PostgreSQL identifier rules (see checkIdentifier).
+func validateIdentifier(v interface{}, key string) (warnings []string, errors []error) {
+	warning, err := checkIdentifier(key, v.(string))
+	if err != nil {
+		errors = append(errors, err)
+	}
+	if warning != "" {
+		warnings = append(warnings, warning)
+	}
+	return
+}
+
+// validateOptionalIdentifier is validateIdentifier for the optional names, which can be empty.
+func validateOptionalIdentifier(v interface{}, key string) ([]string, []error) {
+	if v.(string) == "" {
+		return nil, nil
+	}
+	return validateIdentifier(v, key)
+}
+
+// validateQualifiedIdentifier checks a name optionally qualified with its schema and followed by
+// function arguments (see splitQualifiedIdent), the arguments are not checked.
+func validateQualifiedIdentifier(v interface{}, key string) (warnings []string, errors []error) {
+	schemaName, name := splitQualifiedIdent("", v.(string))
+	if i := strings.Index(name, "("); i >= 0 {
+		name = name[:i]
+	}
+	if schemaName != "" {
+		warnings, errors = validateIdentifier(schemaName, key)
+	}
+	nameWarnings, nameErrors := validateIdentifier(name, key)
+	return append(warnings, nameWarnings...), append(errors, nameErrors...)
+}
+
 const publicRole = "public"
 
+// isPublicRole returns true if the role is PUBLIC, which can be written in any case.
+func isPublicRole(role string) bool {
+	return strings.EqualFold(role, publicRole)
+}
+
 func getRoleOID(db QueryAble, role string) (uint32, error) {
-	if role == publicRole {
+	if isPublicRole(role) {
 		return 0, nil
 	}
 
@@ -566,39 +1297,97 @@ func getRoleOID(db QueryAble, role string) (uint32, error) {
 	return oid, nil
 }
 
-// Lock a role and all his members to avoid concurrent updates on some resources
-func pgLockRole(txn *sql.Tx, role string) error {
-	// Disable statement timeout for this connection otherwise the lock could fail
-	if _, err := txn.Exec("SET statement_timeout = 0"); err != nil {
-		return fmt.Errorf("could not disable statement_timeout: %w", err)
-	}
-	if _, err := txn.Exec("SELECT pg_advisory_xact_lock(oid::bigint) FROM pg_roles WHERE rolname = $1", role); err != nil {
-		return fmt.Errorf("could not get advisory lock for role %s: %w", role, err)
+// readDBRoleSettings returns the configuration parameters set for this role in this database
+// (see pg_db_role_setting). An empty role or database means the settings applying to all roles
+// or all databases (i.e.: set with ALTER DATABASE SET or ALTER ROLE SET).
+// The role and the database must exist.
+func readDBRoleSettings(db QueryAble, role, database string) (map[string]string, error) {
+	rows, err := db.Query(`
+SELECT unnest(setconfig)
+FROM pg_catalog.pg_db_role_setting
+WHERE setdatabase = CASE WHEN $1 = '' THEN 0 ELSE (SELECT oid FROM pg_catalog.pg_database WHERE datname = $1) END
+  AND setrole = CASE WHEN $2 = '' THEN 0 ELSE (SELECT oid FROM pg_catalog.pg_roles WHERE rolname = $2) END
+`, database, role)
+	if err != nil {
+		return nil, fmt.Errorf("could not read settings: %w", err)
 	}
+	defer rows.Close()
 
-	if _, err := txn.Exec(
-		"SELECT pg_advisory_xact_lock(member::bigint) FROM pg_auth_members JOIN pg_roles ON roleid = pg_roles.oid WHERE rolname = $1",
-		role,
-	); err != nil {
-		return fmt.Errorf("could not get advisory lock for members of role %s: %w", role, err)
+	settings := map[string]string{}
+	for rows.Next() {
+		var setting string
+		if err := rows.Scan(&setting); err != nil {
+			return nil, fmt.Errorf("could not scan setting: %w", err)
+		}
+		if name, value, found := strings.Cut(setting, "="); found {
+			settings[name] = value
+		}
 	}
 
-	return nil
+	return settings, rows.Err()
 }
 
-// Lock a database and all his members to avoid concurrent updates on some resources
-func pgLockDatabase(txn *sql.Tx, database string) error {
+// withoutStatementTimeout runs fn with the statement timeout disabled, e.g.: to wait for a lock.
+// The statement timeout of the transaction (see ddl_timeout) is restored for the next statements.
+func withoutStatementTimeout(txn *sql.Tx, fn func() error) error {
+	var timeout string
+	if err := txn.QueryRow("SELECT current_setting('statement_timeout')").Scan(&timeout); err != nil {
+		return fmt.Errorf("could not read statement_timeout: %w", err)
+	}
 	// Disable statement timeout for this connection otherwise the lock could fail
 	if _, err := txn.Exec("SET statement_timeout = 0"); err != nil {
 		return fmt.Errorf("could not disable statement_timeout: %w", err)
 	}
-	if _, err := txn.Exec("SELECT pg_advisory_xact_lock(oid::bigint) FROM pg_database WHERE datname = $1", database); err != nil {
-		return fmt.Errorf("could not get advisory lock for database %s: %w", database, err)
+
+	if err := fn(); err != nil {
+		return err
 	}
 
+	if timeout == "0" {
+		return nil
+	}
+	if _, err := txn.Exec("SELECT set_config('statement_timeout', $1, true)", timeout); err != nil {
+		return fmt.Errorf("could not restore statement_timeout: %w", err)
+	}
 	return nil
 }
 
+// Lock a role and all his members to avoid concurrent updates on some resources
+func pgLockRole(txn *sql.Tx, role string) error {
+	return withoutStatementTimeout(txn, func() error {
+		// PUBLIC is not in pg_roles, it is locked with its pseudo OID (0) which can't be the OID of a role.
+		if isPublicRole(role) {
+			if _, err := txn.Exec("SELECT pg_advisory_xact_lock(0)"); err != nil {
+				return fmt.Errorf("could not get advisory lock for role %s: %w", role, err)
+			}
+			return nil
+		}
+
+		if _, err := txn.Exec("SELECT pg_advisory_xact_lock(oid::bigint) FROM pg_roles WHERE rolname = $1", role); err != nil {
+			return fmt.Errorf("could not get advisory lock for role %s: %w", role, err)
+		}
+
+		if _, err := txn.Exec(
+			"SELECT pg_advisory_xact_lock(member::bigint) FROM pg_auth_members JOIN pg_roles ON roleid = pg_roles.oid WHERE rolname = $1",
+			role,
+		); err != nil {
+			return fmt.Errorf("could not get advisory lock for members of role %s: %w", role, err)
+		}
+
+		return nil
+	})
+}
+
+// Lock a database and all his members to avoid concurrent updates on some resources
+func pgLockDatabase(txn *sql.Tx, database string) error {
+	return withoutStatementTimeout(txn, func() error {
+		if _, err := txn.Exec("SELECT pg_advisory_xact_lock(oid::bigint) FROM pg_database WHERE datname = $1", database); err != nil {
+			return fmt.Errorf("could not get advisory lock for database %s: %w", database, err)
+		}
+		return nil
+	})
+}
+
 func arrayDifference(a, b []interface{}) (diff []interface{}) {
 	m := make(map[interface{}]bool)
 
@@ -1,9 +1,15 @@
 package postgresql
 
 import (
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -121,3 +127,367 @@ func buildResourceData(objectType string, t *testing.T) *schema.ResourceData {
 	m["object_type"] = objectType
 	return schema.TestResourceDataRaw(t, testSchema, m)
 }
+
+// recordingQueryAble records the queries passed to Exec.
+type recordingQueryAble struct {
+	QueryAble
+	queries []string
+}
+
+func (r *recordingQueryAble) Exec(query string, args ...interface{}) (sql.Result, error) {
+	r.queries = append(r.queries, query)
+	return nil, nil
+}
+
+func TestExecBatch(t *testing.T) {
+	db := &recordingQueryAble{}
+	assert.NoError(t, execBatch(db, "", "REVOKE ALL ON TABLE a FROM r", "", "GRANT SELECT ON TABLE a TO r"))
+	assert.Equal(t, []string{"REVOKE ALL ON TABLE a FROM r;\nGRANT SELECT ON TABLE a TO r"}, db.queries)
+
+	db = &recordingQueryAble{}
+	assert.NoError(t, execBatch(db, "", ""))
+	assert.Empty(t, db.queries)
+
+	statements := []string{}
+	for i := 0; i < maxBatchStatements+1; i++ {
+		statements = append(statements, fmt.Sprintf("GRANT SELECT ON TABLE t%d TO r", i))
+	}
+	db = &recordingQueryAble{}
+	assert.NoError(t, execBatch(db, statements...))
+	assert.Len(t, db.queries, 2)
+	assert.Equal(t, maxBatchStatements, len(strings.Split(db.queries[0], ";\n")))
+	assert.Equal(t, statements[maxBatchStatements], db.queries[1])
+}
+
+func TestAccWithTransaction(t *testing.T) {
+	skipIfNotAcc(t)
+
+	config := getTestConfig(t)
+	config.TransientErrorRetries = 2
+	config.TransientErrorBackoffMs = 1
+	dsn := config.connStr("postgres")
+	dbExecute(t, dsn, "CREATE TABLE tf_tests_with_transaction (attempt int)")
+	defer dbExecute(t, dsn, "DROP TABLE tf_tests_with_transaction")
+
+	client := config.NewClient("postgres")
+	countRows := func() int {
+		db, err := client.Connect()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var count int
+		if err := db.QueryRow("SELECT count(*) FROM tf_tests_with_transaction").Scan(&count); err != nil {
+			t.Fatal(err)
+		}
+		return count
+	}
+
+	// The failed attempt is rolled back and only the transaction is run again.
+	attempts := 0
+	err := withTransaction(client, "", func(txn *sql.Tx) error {
+		attempts++
+		if _, err := txn.Exec("INSERT INTO tf_tests_with_transaction VALUES ($1)", attempts); err != nil {
+			return err
+		}
+		if attempts == 1 {
+			return &pq.Error{Code: "40001"}
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, 1, countRows())
+
+	// The other errors are not retried.
+	attempts = 0
+	err = withTransaction(client, "", func(txn *sql.Tx) error {
+		attempts++
+		if _, err := txn.Exec("INSERT INTO tf_tests_with_transaction VALUES ($1)", attempts); err != nil {
+			return err
+		}
+		return fmt.Errorf("other error")
+	})
+	assert.EqualError(t, err, "other error")
+	assert.Equal(t, 1, attempts)
+	assert.Equal(t, 1, countRows())
+}
+
+func TestObjectID(t *testing.T) {
+	cases := []struct {
+		parts []string
+		id    string
+	}{
+		{parts: []string{"mydb", "myschema"}, id: "mydb.myschema"},
+		{parts: []string{"my.db", "myschema"}, id: `"my.db".myschema`},
+		{parts: []string{"my.db", "my.schema"}, id: `"my.db"."my.schema"`},
+		{parts: []string{`my"db`, "myschema"}, id: `"my""db".myschema`},
+		{parts: []string{"mydb", ""}, id: "mydb."},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.id, generateObjectID(c.parts...))
+
+		parsed, err := parseObjectID(c.id)
+		assert.NoError(t, err, c.id)
+		assert.Equal(t, c.parts, parsed, c.id)
+	}
+
+	for _, id := range []string{`"my.db.myschema`, `"my.db"x.myschema`} {
+		_, err := parseObjectID(id)
+		assert.Error(t, err, id)
+	}
+}
+
+func TestSplitQualifiedIdent(t *testing.T) {
+	cases := []struct {
+		ident  string
+		schema string
+		name   string
+	}{
+		{ident: "seq1", schema: "public", name: "seq1"},
+		{ident: "other_schema.seq1", schema: "other_schema", name: "seq1"},
+		{ident: `"my.table"`, schema: "public", name: "my.table"},
+		{ident: `"my.schema"."my.table"`, schema: "my.schema", name: "my.table"},
+		{ident: "func(text, pg_catalog.int4)", schema: "public", name: "func(text, pg_catalog.int4)"},
+		{ident: "other_schema.func(int)", schema: "other_schema", name: "func(int)"},
+		{ident: "a.b.c", schema: "public", name: "a.b.c"},
+	}
+
+	for _, c := range cases {
+		schemaName, name := splitQualifiedIdent("public", c.ident)
+		assert.Equal(t, c.schema, schemaName, c.ident)
+		assert.Equal(t, c.name, name, c.ident)
+	}
+}
+
+func TestNormalizePrivilege(t *testing.T) {
+	cases := map[string]string{
+		"SELECT":          "SELECT",
+		"select":          "SELECT",
+		"temp":            "TEMPORARY",
+		"Temporary":       "TEMPORARY",
+		"all privileges":  "ALL",
+		"ALL  PRIVILEGES": "ALL",
+	}
+
+	for privilege, expected := range cases {
+		assert.Equal(t, expected, normalizePrivilege(privilege), privilege)
+	}
+
+	// The configured privileges are stored normalized and the other spellings don't cause a diff.
+	d := schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+		"database":    "test_db",
+		"role":        "test_role",
+		"object_type": "database",
+		"privileges":  []interface{}{"temp", "connect"},
+	})
+	assert.True(t, normalizePrivileges(d.Get("privileges").(*schema.Set)).Equal(stringSliceToSet([]string{"CONNECT", "TEMPORARY"})))
+	assert.NoError(t, validatePrivileges(d))
+	assert.Equal(t, hashPrivilege("temp"), hashPrivilege("TEMPORARY"))
+	assert.True(t, privilegesDiffSuppress("privileges.1", "TEMPORARY", "temp", d))
+	assert.False(t, privilegesDiffSuppress("privileges.1", "TEMPORARY", "", d))
+}
+
+func TestGetObjectOwner(t *testing.T) {
+	config := Config{ObjectOwnerRole: "objects_owner"}
+
+	d := schema.TestResourceDataRaw(t, resourcePostgreSQLSchema().Schema, map[string]interface{}{
+		schemaNameAttr: "test_schema",
+	})
+	assert.Equal(t, "objects_owner", getObjectOwner(d, schemaOwnerAttr, config))
+	assert.Equal(t, "", getObjectOwner(d, schemaOwnerAttr, Config{}))
+
+	d = schema.TestResourceDataRaw(t, resourcePostgreSQLSchema().Schema, map[string]interface{}{
+		schemaNameAttr:  "test_schema",
+		schemaOwnerAttr: "schema_owner",
+	})
+	assert.Equal(t, "schema_owner", getObjectOwner(d, schemaOwnerAttr, config))
+}
+
+func TestIsOwnerToken(t *testing.T) {
+	for _, owner := range []string{"CURRENT_USER", "current_user", "Current_Role", "Session_User"} {
+		assert.True(t, isOwnerToken(owner), owner)
+	}
+	for _, owner := range []string{"", "postgres", "pg_database_owner", "CURRENT_USER2"} {
+		assert.False(t, isOwnerToken(owner), owner)
+	}
+}
+
+func TestValidateIdentifier(t *testing.T) {
+	tests := []struct {
+		name     string
+		valid    bool
+		warnings int
+	}{
+		{"app_reader", true, 0},
+		{"app-reader.v2 with spaces", true, 0},
+		{strings.Repeat("a", 63), true, 0},
+		{"PUBLIC", true, 0},
+		{"AppReader", true, 1},
+		{"", false, 0},
+		{"app\x00reader", false, 0},
+		{strings.Repeat("a", 64), false, 0},
+		{strings.Repeat("é", 32), false, 0},
+	}
+
+	for _, tt := range tests {
+		warnings, errors := validateIdentifier(tt.name, "role")
+		assert.Equal(t, tt.valid, len(errors) == 0, "%q: %v", tt.name, errors)
+		assert.Len(t, warnings, tt.warnings, tt.name)
+	}
+
+	warnings, errors := validateOptionalIdentifier("", "schema")
+	assert.Empty(t, warnings)
+	assert.Empty(t, errors)
+}
+
+func TestValidateQualifiedIdentifier(t *testing.T) {
+	tests := []struct {
+		name     string
+		valid    bool
+		warnings int
+	}{
+		{"test_table", true, 0},
+		{"test_schema.test_table", true, 0},
+		{`"test.table"`, true, 0},
+		{"test_function(integer, text)", true, 0},
+		{"16384", true, 0},
+		{"Test_Schema.Test_Table", true, 2},
+		{"test_schema." + strings.Repeat("a", 64), false, 0},
+		{"(integer)", false, 0},
+	}
+
+	for _, tt := range tests {
+		warnings, errors := validateQualifiedIdentifier(tt.name, "objects")
+		assert.Equal(t, tt.valid, len(errors) == 0, "%q: %v", tt.name, errors)
+		assert.Len(t, warnings, tt.warnings, tt.name)
+	}
+}
+
+func TestAccStartTransactionStatementTimeout(t *testing.T) {
+	skipIfNotAcc(t)
+
+	config := getTestConfig(t)
+	config.DDLTimeoutSec = 5
+	config.DMLTimeoutSec = 2
+	client := config.NewClient("postgres")
+
+	statementTimeout := func(txn *sql.Tx) string {
+		var timeout string
+		if err := txn.QueryRow("SELECT current_setting('statement_timeout')").Scan(&timeout); err != nil {
+			t.Fatal(err)
+		}
+		return timeout
+	}
+
+	txn, err := startTransaction(client, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer deferredRollback(txn)
+	assert.Equal(t, "5s", statementTimeout(txn))
+
+	// The timeout is disabled to wait for the lock only.
+	if err := pgLockRole(txn, config.Username); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "5s", statementTimeout(txn))
+
+	readTxn, err := startTransaction(client.readOnlyClient(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer deferredRollback(readTxn)
+	assert.Equal(t, "2s", statementTimeout(readTxn))
+}
+
+func TestAccExecWithStatementTimeout(t *testing.T) {
+	skipIfNotAcc(t)
+
+	config := getTestConfig(t)
+	config.DDLTimeoutSec = 1
+	config.MaxConns = 1
+	db, err := config.NewClient("postgres").Connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = execWithStatementTimeout(db, "SELECT pg_sleep(3)")
+	var pqErr *pq.Error
+	if assert.ErrorAs(t, err, &pqErr) {
+		assert.Equal(t, "57014", string(pqErr.Code))
+	}
+
+	// The setting is reset before the connection is returned to the pool.
+	var timeout string
+	if err := db.QueryRow("SELECT current_setting('statement_timeout')").Scan(&timeout); err != nil {
+		t.Fatal(err)
+	}
+	assert.NotEqual(t, "1s", timeout)
+}
+
+func TestAccStartTransactionIsolationLevel(t *testing.T) {
+	skipIfNotAcc(t)
+
+	config := getTestConfig(t)
+	config.ReadIsolationLevel = "repeatable read"
+	client := config.NewClient("postgres")
+
+	isolationLevel := func(txn *sql.Tx) string {
+		var level string
+		if err := txn.QueryRow("SELECT current_setting('transaction_isolation')").Scan(&level); err != nil {
+			t.Fatal(err)
+		}
+		return level
+	}
+
+	readTxn, err := startTransaction(client.readOnlyClient(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer deferredRollback(readTxn)
+	assert.Equal(t, "repeatable read", isolationLevel(readTxn))
+
+	txn, err := startTransaction(client, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer deferredRollback(txn)
+	assert.Equal(t, "read committed", isolationLevel(txn))
+}
+
+func TestUpgradeObjectIDStateV0(t *testing.T) {
+	cases := []struct {
+		state    map[string]interface{}
+		expected string
+	}{
+		{
+			state:    map[string]interface{}{"id": "mydb.myschema", "database": "mydb", "name": "myschema"},
+			expected: "mydb.myschema",
+		},
+		{
+			state:    map[string]interface{}{"id": "my.db.my.schema", "database": "my.db", "name": "my.schema"},
+			expected: `"my.db"."my.schema"`,
+		},
+		{
+			state:    map[string]interface{}{"id": "my.db.myschema", "name": "myschema"},
+			expected: `"my.db".myschema`,
+		},
+		{
+			state:    map[string]interface{}{"id": "unexpected", "database": "mydb", "name": "myschema"},
+			expected: "unexpected",
+		},
+	}
+
+	for _, c := range cases {
+		state, err := resourcePostgreSQLSchemaStateUpgradeV0(context.Background(), c.state, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, state["id"])
+	}
+
+	state, err := resourcePostgreSQLPublicationStateUpgradeV0(context.Background(), map[string]interface{}{
+		"id": "my.db.my.pub", "database": "my.db", "name": "my.pub",
+	}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, `"my.db"."my.pub"`, state["id"])
+}
@@ -0,0 +1,61 @@
+package postgresql
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// lookupSRV resolves the SRV records, it's replaced in the tests.
+var lookupSRV = net.LookupSRV
+
+// srvDialer returns the dialer of the connections to check the targets of a SRV record:
+// the SSH tunnel if it's set, otherwise the proxy of the environment (see pqConnector).
+func srvDialer(tunnel *SSHTunnelConfig) pq.Dialer {
+	if tunnel != nil {
+		return tunnel
+	}
+	return proxyDriver{}
+}
+
+// resolveHostSRV resolves the SRV record name (e.g.: _postgres._tcp.db.internal) and returns the host and the port
+// of the first target accepting connections, in the order of the records (by priority, randomized by weight).
+func resolveHostSRV(name string, dialer pq.Dialer, timeout time.Duration) (string, int, error) {
+	_, records, err := lookupSRV("", "", name)
+	if err != nil {
+		return "", 0, fmt.Errorf("could not resolve SRV record %s: %w", name, err)
+	}
+	if len(records) == 0 {
+		return "", 0, fmt.Errorf("SRV record %s has no target", name)
+	}
+
+	var errs []string
+	for _, record := range records {
+		host := strings.TrimSuffix(record.Target, ".")
+		address := net.JoinHostPort(host, strconv.Itoa(int(record.Port)))
+
+		var conn net.Conn
+		if timeout > 0 {
+			conn, err = dialer.DialTimeout("tcp", address, timeout)
+		} else {
+			// Zero means wait indefinitely, as connect_timeout.
+			conn, err = dialer.Dial("tcp", address)
+		}
+		if err != nil {
+			log.Printf("[WARN] could not connect to %s (SRV record %s), trying the next target: %v", address, name, err)
+			errs = append(errs, fmt.Sprintf("%s: %v", address, err))
+			continue
+		}
+		_ = conn.Close()
+
+		log.Printf("[DEBUG] using %s from SRV record %s", address, name)
+		return host, int(record.Port), nil
+	}
+
+	return "", 0, fmt.Errorf("could not connect to any target of SRV record %s: %s", name, strings.Join(errs, ", "))
+}
@@ -0,0 +1,108 @@
+package postgresql
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testSRVDialer accepts the connections to the reachable addresses and records the dialed ones.
+type testSRVDialer struct {
+	reachable map[string]bool
+	dialed    []string
+}
+
+func (d *testSRVDialer) Dial(network, address string) (net.Conn, error) {
+	return d.DialTimeout(network, address, 0)
+}
+
+func (d *testSRVDialer) DialTimeout(network, address string, timeout time.Duration) (net.Conn, error) {
+	d.dialed = append(d.dialed, address)
+	if !d.reachable[address] {
+		return nil, errors.New("connection refused")
+	}
+	client, server := net.Pipe()
+	_ = server.Close()
+	return client, nil
+}
+
+func TestResolveHostSRV(t *testing.T) {
+	defer func(lookup func(string, string, string) (string, []*net.SRV, error)) { lookupSRV = lookup }(lookupSRV)
+
+	records := []*net.SRV{
+		{Target: "pg-1.db.internal.", Port: 5432, Priority: 1},
+		{Target: "pg-2.db.internal.", Port: 5433, Priority: 2},
+	}
+	lookupSRV = func(service, proto, name string) (string, []*net.SRV, error) {
+		if name != "_postgres._tcp.db.internal" {
+			return "", nil, errors.New("no such host")
+		}
+		return name, records, nil
+	}
+
+	tests := []struct {
+		name       string
+		srvName    string
+		reachable  []string
+		wantHost   string
+		wantPort   int
+		wantDialed []string
+		wantErr    string
+	}{
+		{
+			name:       "first target",
+			srvName:    "_postgres._tcp.db.internal",
+			reachable:  []string{"pg-1.db.internal:5432", "pg-2.db.internal:5433"},
+			wantHost:   "pg-1.db.internal",
+			wantPort:   5432,
+			wantDialed: []string{"pg-1.db.internal:5432"},
+		},
+		{
+			name:       "failover to the next target",
+			srvName:    "_postgres._tcp.db.internal",
+			reachable:  []string{"pg-2.db.internal:5433"},
+			wantHost:   "pg-2.db.internal",
+			wantPort:   5433,
+			wantDialed: []string{"pg-1.db.internal:5432", "pg-2.db.internal:5433"},
+		},
+		{
+			name:       "no reachable target",
+			srvName:    "_postgres._tcp.db.internal",
+			wantDialed: []string{"pg-1.db.internal:5432", "pg-2.db.internal:5433"},
+			wantErr:    "could not connect to any target of SRV record _postgres._tcp.db.internal",
+		},
+		{
+			name:    "unknown record",
+			srvName: "_postgres._tcp.unknown.internal",
+			wantErr: "could not resolve SRV record _postgres._tcp.unknown.internal",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialer := &testSRVDialer{reachable: map[string]bool{}}
+			for _, address := range tt.reachable {
+				dialer.reachable[address] = true
+			}
+
+			host, port, err := resolveHostSRV(tt.srvName, dialer, time.Second)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if host != tt.wantHost || port != tt.wantPort {
+					t.Errorf("expected %s:%d, got %s:%d", tt.wantHost, tt.wantPort, host, port)
+				}
+			}
+			if strings.Join(dialer.dialed, ",") != strings.Join(tt.wantDialed, ",") {
+				t.Errorf("expected dialed targets %v, got %v", tt.wantDialed, dialer.dialed)
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package postgresql
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// journalRetention is how long the committed entries are kept in the journal file. An interrupted apply
+// has to be run again within this period to be reconciled with the journal.
+const journalRetention = 7 * 24 * time.Hour
+
+// operationJournal records the statements executed for the resources during the apply.
+// If an apply is interrupted after a transaction is committed but before Terraform saved the state,
+// the next run finds the committed statements in the journal and doesn't execute them again
+// if they are still applied. The journal is only written by the applies (never by a refresh):
+// the entry of a resource is replaced by its next change and removed when it's destroyed
+// (or when its ID changes). A committed entry left by a successful apply is harmless
+// as the statements are only skipped if the privileges are still in place.
+// The journal is kept in memory and, if a path is set, in a file so it survives the provider process.
+// Only the committed entries are written in the file, one record per line appended (and synced) on each
+// commit or removal. The file is compacted when the provider starts: the removed entries and the committed
+// entries older than journalRetention are dropped.
+type operationJournal struct {
+	sync.Mutex
+
+	path    string
+	entries map[string]journalEntry
+}
+
+type journalEntry struct {
+	Statements []string
+	Committed  bool
+	UpdatedAt  time.Time
+}
+
+// journalRecord is a line of the journal file: the committed statements of a resource,
+// or the removal of its entry.
+type journalRecord struct {
+	Key        string    `json:"key"`
+	Statements []string  `json:"statements,omitempty"`
+	Forget     bool      `json:"forget,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// newOperationJournal returns a journal with the committed entries of the file if it already exists
+// and compacts the file.
+func newOperationJournal(path string) (*operationJournal, error) {
+	journal := &operationJournal{
+		path:    path,
+		entries: map[string]journalEntry{},
+	}
+	if path == "" {
+		return journal, nil
+	}
+
+	content, err := os.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		return journal, nil
+	case err != nil:
+		return nil, fmt.Errorf("could not read journal file %s: %w", path, err)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(nil, len(content)+1)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var record journalRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			// The last record may have been truncated by an interruption, its commit has not been
+			// acknowledged so it can be ignored.
+			if !bytes.HasSuffix(content, []byte("\n")) && bytes.HasSuffix(content, scanner.Bytes()) {
+				log.Printf("[WARN] ignoring the truncated last record of journal file %s", path)
+				break
+			}
+			return nil, fmt.Errorf("could not parse journal file %s: %w", path, err)
+		}
+		if record.Forget {
+			delete(journal.entries, record.Key)
+			continue
+		}
+		journal.entries[record.Key] = journalEntry{Statements: record.Statements, Committed: true, UpdatedAt: record.UpdatedAt}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not parse journal file %s: %w", path, err)
+	}
+
+	expiry := time.Now().UTC().Add(-journalRetention)
+	for key, entry := range journal.entries {
+		if entry.UpdatedAt.Before(expiry) {
+			delete(journal.entries, key)
+		}
+	}
+
+	if err := journal.compact(); err != nil {
+		return nil, err
+	}
+	return journal, nil
+}
+
+// begin records the statements which are going to be executed for the resource.
+// The entry is only kept in memory until it's committed.
+func (j *operationJournal) begin(key string, statements []string) error {
+	if j == nil {
+		return nil
+	}
+
+	j.Lock()
+	defer j.Unlock()
+
+	j.entries[key] = journalEntry{Statements: statements, UpdatedAt: time.Now().UTC()}
+	return nil
+}
+
+// commit marks the statements of the resource as committed.
+func (j *operationJournal) commit(key string) error {
+	if j == nil {
+		return nil
+	}
+
+	j.Lock()
+	defer j.Unlock()
+
+	entry, ok := j.entries[key]
+	if !ok {
+		return nil
+	}
+	entry.Committed = true
+	entry.UpdatedAt = time.Now().UTC()
+	j.entries[key] = entry
+	return j.append(journalRecord{Key: key, Statements: entry.Statements, UpdatedAt: entry.UpdatedAt})
+}
+
+// committed returns true if these statements have already been committed for the resource.
+func (j *operationJournal) committed(key string, statements []string) bool {
+	if j == nil {
+		return false
+	}
+
+	j.Lock()
+	defer j.Unlock()
+
+	entry, ok := j.entries[key]
+	return ok && entry.Committed && reflect.DeepEqual(entry.Statements, statements)
+}
+
+// forget removes the entry of the resource.
+func (j *operationJournal) forget(key string) error {
+	if j == nil {
+		return nil
+	}
+
+	j.Lock()
+	defer j.Unlock()
+
+	entry, ok := j.entries[key]
+	if !ok {
+		return nil
+	}
+	delete(j.entries, key)
+	if !entry.Committed {
+		// Not written in the file.
+		return nil
+	}
+	return j.append(journalRecord{Key: key, Forget: true, UpdatedAt: time.Now().UTC()})
+}
+
+// append writes the record at the end of the file (if set) and syncs it. It must be called with the lock held.
+func (j *operationJournal) append(record journalRecord) error {
+	if j.path == "" {
+		return nil
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("could not serialize journal record: %w", err)
+	}
+
+	file, err := os.OpenFile(j.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("could not open journal file %s: %w", j.path, err)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("could not write journal file %s: %w", j.path, err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("could not sync journal file %s: %w", j.path, err)
+	}
+	return file.Close()
+}
+
+// compact rewrites the file with one record per committed entry.
+func (j *operationJournal) compact() error {
+	var content bytes.Buffer
+	for key, entry := range j.entries {
+		if !entry.Committed {
+			continue
+		}
+		line, err := json.Marshal(journalRecord{Key: key, Statements: entry.Statements, UpdatedAt: entry.UpdatedAt})
+		if err != nil {
+			return fmt.Errorf("could not serialize journal record: %w", err)
+		}
+		content.Write(line)
+		content.WriteByte('\n')
+	}
+
+	// The file is replaced atomically (and synced before) so an interruption can't leave a truncated journal.
+	tmpPath := j.path + ".tmp"
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("could not write journal file %s: %w", tmpPath, err)
+	}
+	if _, err := file.Write(content.Bytes()); err != nil {
+		file.Close()
+		return fmt.Errorf("could not write journal file %s: %w", tmpPath, err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("could not sync journal file %s: %w", tmpPath, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("could not write journal file %s: %w", tmpPath, err)
+	}
+	if err := os.Rename(tmpPath, j.path); err != nil {
+		return fmt.Errorf("could not write journal file %s: %w", j.path, err)
+	}
+
+	// Sync the directory so the rename itself is durable.
+	if dir, err := os.Open(filepath.Dir(j.path)); err == nil {
+		dir.Sync()
+		dir.Close()
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package postgresql
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperationJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.json")
+	statements := []string{"REVOKE ALL ON TABLE t FROM r", "GRANT SELECT ON TABLE t TO r"}
+
+	journal, err := newOperationJournal(path)
+	assert.NoError(t, err)
+
+	assert.NoError(t, journal.begin("grant1", statements))
+	assert.False(t, journal.committed("grant1", statements))
+
+	assert.NoError(t, journal.commit("grant1"))
+	assert.True(t, journal.committed("grant1", statements))
+	assert.False(t, journal.committed("grant1", statements[1:]))
+	assert.False(t, journal.committed("grant2", statements))
+
+	// The entries are loaded by the next run.
+	journal, err = newOperationJournal(path)
+	assert.NoError(t, err)
+	assert.True(t, journal.committed("grant1", statements))
+
+	assert.NoError(t, journal.forget("grant1"))
+	journal, err = newOperationJournal(path)
+	assert.NoError(t, err)
+	assert.False(t, journal.committed("grant1", statements))
+}
+
+func TestOperationJournalCompaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.json")
+	statements := []string{"GRANT SELECT ON TABLE t TO r"}
+
+	journal, err := newOperationJournal(path)
+	assert.NoError(t, err)
+
+	// The entries which are not committed are not written.
+	assert.NoError(t, journal.begin("grant1", statements))
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+
+	assert.NoError(t, journal.commit("grant1"))
+	assert.NoError(t, journal.begin("grant2", statements))
+	assert.NoError(t, journal.commit("grant2"))
+	assert.NoError(t, journal.forget("grant2"))
+
+	// An expired entry and a record truncated by an interruption.
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	assert.NoError(t, err)
+	expired := time.Now().UTC().Add(-journalRetention - time.Hour).Format(time.RFC3339)
+	_, err = fmt.Fprintf(file, `{"key":"grant3","statements":["GRANT SELECT ON TABLE t TO r"],"updated_at":%q}`+"\n", expired)
+	assert.NoError(t, err)
+	_, err = file.WriteString(`{"key":"grant4","statements":["GRANT`)
+	assert.NoError(t, err)
+	assert.NoError(t, file.Close())
+
+	journal, err = newOperationJournal(path)
+	assert.NoError(t, err)
+	assert.True(t, journal.committed("grant1", statements))
+	assert.False(t, journal.committed("grant2", statements))
+	assert.False(t, journal.committed("grant3", statements))
+	assert.False(t, journal.committed("grant4", statements))
+
+	// The file only contains the committed entry left.
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	assert.Len(t, lines, 1)
+	assert.Contains(t, lines[0], `"key":"grant1"`)
+}
+
+func TestOperationJournalInMemory(t *testing.T) {
+	journal, err := newOperationJournal("")
+	assert.NoError(t, err)
+
+	assert.NoError(t, journal.begin("grant1", []string{"GRANT SELECT ON TABLE t TO r"}))
+	assert.NoError(t, journal.commit("grant1"))
+	assert.True(t, journal.committed("grant1", []string{"GRANT SELECT ON TABLE t TO r"}))
+
+	// A nil journal (e.g.: provider not configured in unit tests) doesn't record anything.
+	var nilJournal *operationJournal
+	assert.NoError(t, nilJournal.begin("grant1", nil))
+	assert.NoError(t, nilJournal.commit("grant1"))
+	assert.False(t, nilJournal.committed("grant1", nil))
+	assert.NoError(t, nilJournal.forget("grant1"))
+}
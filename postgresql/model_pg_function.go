@@ -18,6 +18,12 @@ type PGFunction struct {
 	SecurityDefiner bool
 	Strict          bool
 	Volatility      string
+	Leakproof       bool
+	Support         string
+	TransformTypes  []string
+	Cost            float64
+	Rows            float64
+	Settings        map[string]string
 }
 
 type PGFunctionArg struct {
@@ -65,48 +71,70 @@ func (pgFunction *PGFunction) FromResourceData(d *schema.ResourceData) error {
 	} else {
 		pgFunction.Volatility = defaultFunctionVolatility
 	}
+	pgFunction.Leakproof = d.Get(funcLeakproofAttr).(bool)
+	pgFunction.Support = d.Get(funcSupportAttr).(string)
+	if v, ok := d.GetOk(funcTransformTypesAttr); ok {
+		pgFunction.TransformTypes = setToSortedSlice(v.(*schema.Set))
+	}
+	if v, ok := d.GetOk(funcCostAttr); ok {
+		pgFunction.Cost = v.(float64)
+	}
+	if v, ok := d.GetOk(funcRowsAttr); ok {
+		pgFunction.Rows = v.(float64)
+	}
+	if v, ok := d.GetOk(funcSettingsAttr); ok {
+		pgFunction.Settings = map[string]string{}
+		for name, value := range v.(map[string]interface{}) {
+			pgFunction.Settings[name] = value.(string)
+		}
+	}
+
+	if args, ok := d.GetOk(funcArgAttr); ok {
+		pgFunction.Args = expandFunctionArgs(args.([]interface{}))
+	}
 
 	// For the main returns if not provided
 	argOutput := "void"
+	for _, pgArg := range pgFunction.Args {
+		if strings.ToUpper(pgArg.Mode) == "OUT" {
+			argOutput = pgArg.Type
+		}
+	}
 
-	if args, ok := d.GetOk(funcArgAttr); ok {
-		args := args.([]interface{})
+	if v, ok := d.GetOk(funcReturnsAttr); ok {
+		pgFunction.Returns = v.(string)
+	} else {
+		pgFunction.Returns = argOutput
+	}
 
-		for _, arg := range args {
-			arg := arg.(map[string]interface{})
+	return nil
+}
 
-			var pgArg PGFunctionArg
+// expandFunctionArgs returns the arguments of the arg blocks of a function or an aggregate.
+func expandFunctionArgs(args []interface{}) []PGFunctionArg {
+	pgArgs := []PGFunctionArg{}
+	for _, arg := range args {
+		arg := arg.(map[string]interface{})
 
-			if v, ok := arg[funcArgModeAttr]; ok {
-				pgArg.Mode = v.(string)
-			}
+		var pgArg PGFunctionArg
 
-			if v, ok := arg[funcArgNameAttr]; ok {
-				pgArg.Name = v.(string)
-			}
+		if v, ok := arg[funcArgModeAttr]; ok {
+			pgArg.Mode = v.(string)
+		}
 
-			pgArg.Type = arg[funcArgTypeAttr].(string)
+		if v, ok := arg[funcArgNameAttr]; ok {
+			pgArg.Name = v.(string)
+		}
 
-			if v, ok := arg[funcArgDefaultAttr]; ok {
-				pgArg.Default = v.(string)
-			}
+		pgArg.Type = arg[funcArgTypeAttr].(string)
 
-			// For the main returns if not provided
-			if strings.ToUpper(pgArg.Mode) == "OUT" {
-				argOutput = pgArg.Type
-			}
-
-			pgFunction.Args = append(pgFunction.Args, pgArg)
+		if v, ok := arg[funcArgDefaultAttr]; ok {
+			pgArg.Default = v.(string)
 		}
-	}
 
-	if v, ok := d.GetOk(funcReturnsAttr); ok {
-		pgFunction.Returns = v.(string)
-	} else {
-		pgFunction.Returns = argOutput
+		pgArgs = append(pgArgs, pgArg)
 	}
-
-	return nil
+	return pgArgs
 }
 
 func (pgFunction *PGFunction) Parse(functionDefinition string) error {
@@ -176,6 +204,22 @@ func (pgFunctionArg *PGFunctionArg) Parse(functionArgDefinition string) error {
 	return nil
 }
 
+// String returns the declaration of the argument, as in the argument list of CREATE FUNCTION.
+func (pgFunctionArg PGFunctionArg) String() string {
+	b := strings.Builder{}
+	if pgFunctionArg.Mode != "" {
+		b.WriteString(pgFunctionArg.Mode + " ")
+	}
+	if pgFunctionArg.Name != "" {
+		b.WriteString(pgFunctionArg.Name + " ")
+	}
+	b.WriteString(pgFunctionArg.Type)
+	if pgFunctionArg.Default != "" {
+		b.WriteString(" DEFAULT " + pgFunctionArg.Default)
+	}
+	return b.String()
+}
+
 func normalizeFunctionBody(body string) string {
 	newBodyMap := findStringSubmatchMap(`(?si).*\$[a-zA-Z]*\$\s(?P<Body>.*)\s\$[a-zA-Z]*\$.*`, body)
 	if newBody, ok := newBodyMap["Body"]; ok {
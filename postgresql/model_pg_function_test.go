@@ -55,6 +55,34 @@ func TestFromResourceData(t *testing.T) {
 	})
 }
 
+func TestFromResourceDataWithOptions(t *testing.T) {
+	d := mockFunctionResourceData(t, PGFunction{
+		Name:           "hstore_keys",
+		Returns:        "text[]",
+		Language:       "plpython3u",
+		Body:           "return list(h.keys())",
+		Leakproof:      true,
+		Support:        "public.hstore_keys_support",
+		TransformTypes: []string{"hstore"},
+		Args: []PGFunctionArg{
+			{
+				Name: "h",
+				Type: "hstore",
+			},
+		},
+	})
+
+	var pgFunction PGFunction
+
+	err := pgFunction.FromResourceData(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, pgFunction.Leakproof)
+	assert.Equal(t, "public.hstore_keys_support", pgFunction.Support)
+	assert.Equal(t, []string{"hstore"}, pgFunction.TransformTypes)
+}
+
 func TestFromResourceDataWithArguments(t *testing.T) {
 	d := mockFunctionResourceData(t, PGFunction{
 		Name: "increment",
@@ -253,6 +281,14 @@ func mockFunctionResourceData(t *testing.T, obj PGFunction) *schema.ResourceData
 	attributes["security_definer"] = obj.SecurityDefiner
 	attributes["parallel"] = obj.Parallel
 	attributes["volatility"] = obj.Volatility
+	attributes["leakproof"] = obj.Leakproof
+	attributes["support"] = obj.Support
+
+	var transformTypes []interface{}
+	for _, transformType := range obj.TransformTypes {
+		transformTypes = append(transformTypes, transformType)
+	}
+	attributes["transform_types"] = transformTypes
 
 	var args []interface{}
 
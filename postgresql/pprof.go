@@ -0,0 +1,64 @@
+package postgresql
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"sync"
+)
+
+var (
+	// The provider can be configured several times in the same process, the listener is started once.
+	pprofOnce sync.Once
+	pprofErr  error
+)
+
+// startPprofListener serves the Go profiling endpoints (/debug/pprof/) on the address,
+// to profile the provider during a long plan or apply.
+func startPprofListener(address string) error {
+	pprofOnce.Do(func() {
+		listener, err := net.Listen("tcp", address)
+		if err != nil {
+			pprofErr = fmt.Errorf("could not start pprof listener on %s: %w", address, err)
+			return
+		}
+		log.Printf("[WARN] pprof listener started on http://%s/debug/pprof/", listener.Addr())
+
+		go func() {
+			if err := http.Serve(listener, pprofHandler()); err != nil {
+				log.Printf("[WARN] pprof listener stopped: %v", err)
+			}
+		}()
+	})
+	return pprofErr
+}
+
+func pprofHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	return mux
+}
+
+// validatePprofAddress only allows loopback addresses as the profiles expose the internals of the provider.
+func validatePprofAddress(v interface{}, key string) (warnings []string, errors []error) {
+	address := v.(string)
+	if address == "" {
+		return
+	}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		errors = append(errors, fmt.Errorf("invalid %s (%q): %w", key, address, err))
+		return
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		errors = append(errors, fmt.Errorf("%s must be a loopback address (e.g.: 127.0.0.1:6060), got %q", key, address))
+	}
+	return
+}
@@ -0,0 +1,45 @@
+package postgresql
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestValidatePprofAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		valid   bool
+	}{
+		{"", true},
+		{"127.0.0.1:6060", true},
+		{"localhost:6060", true},
+		{"[::1]:6060", true},
+		{"0.0.0.0:6060", false},
+		{":6060", false},
+		{"10.0.0.1:6060", false},
+		{"127.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		_, errors := validatePprofAddress(tt.address, "pprof_listen_address")
+		if valid := len(errors) == 0; valid != tt.valid {
+			t.Errorf("address %q: expected valid=%t, got errors %v", tt.address, tt.valid, errors)
+		}
+	}
+}
+
+func TestPprofHandler(t *testing.T) {
+	server := httptest.NewServer(pprofHandler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/debug/pprof/goroutine?debug=1")
+	if err != nil {
+		t.Fatalf("could not get goroutine profile: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+}
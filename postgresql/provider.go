@@ -2,10 +2,13 @@ package postgresql
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
@@ -25,9 +28,12 @@ const (
 	defaultExpectedPostgreSQLVersion  = "9.0.0"
 )
 
+// endpointAttr is the attribute of all the resources to target one of the provider endpoints.
+const endpointAttr = "endpoint"
+
 // Provider returns a terraform.ResourceProvider.
 func Provider() *schema.Provider {
-	return &schema.Provider{
+	provider := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"scheme": {
 				Type:     schema.TypeString,
@@ -51,6 +57,12 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("PGPORT", 5432),
 				Description: "The PostgreSQL port number to connect to at the server host, or socket file name extension for Unix-domain connections",
 			},
+			"host_srv": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"host"},
+				Description:   "DNS SRV record (e.g.: _postgres._tcp.db.internal) resolved to the host and the port of the server, the first target accepting connections is used",
+			},
 			"database": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -156,12 +168,25 @@ func Provider() *schema.Provider {
 						"cert": {
 							Type:        schema.TypeString,
 							Description: "The SSL client certificate file path. The file must contain PEM encoded data.",
-							Required:    true,
+							Optional:    true,
 						},
 						"key": {
 							Type:        schema.TypeString,
 							Description: "The SSL client certificate private key file path. The file must contain PEM encoded data.",
-							Required:    true,
+							Optional:    true,
+							Sensitive:   true,
+						},
+						"cert_pem": {
+							Type:        schema.TypeString,
+							Description: "The PEM encoded SSL client certificate, instead of cert.",
+							Optional:    true,
+							Sensitive:   true,
+						},
+						"key_pem": {
+							Type:        schema.TypeString,
+							Description: "The PEM encoded SSL client certificate private key, instead of key.",
+							Optional:    true,
+							Sensitive:   true,
 						},
 						"sslinline": {
 							Type:        schema.TypeBool,
@@ -172,10 +197,133 @@ func Provider() *schema.Provider {
 				},
 				MaxItems: 1,
 			},
+			"statement_log": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Log the SQL statements executed by the provider, with the passwords redacted.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"level": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "INFO",
+							Description:  "The log level of the statements",
+							ValidateFunc: validation.StringInSlice(statementLogLevels, false),
+						},
+						"file": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "File to which the statements are also appended",
+						},
+					},
+				},
+			},
+			"generate_sql_only": {
+				Type:         schema.TypeBool,
+				Optional:     true,
+				Default:      false,
+				Description:  "Write the statements changing the objects to generate_sql_file instead of executing them",
+				RequiredWith: []string{"generate_sql_file"},
+			},
+			"generate_sql_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The file to which the statements are written when generate_sql_only is set",
+			},
+			"ssh_tunnel": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "SSH bastion through which the connections to PostgreSQL are opened.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"host": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The SSH bastion host",
+						},
+						"port": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      22,
+							Description:  "The SSH port of the bastion",
+							ValidateFunc: validation.IntBetween(1, 65535),
+						},
+						"user": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The SSH user",
+						},
+						"private_key": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Sensitive:   true,
+							Description: "The PEM encoded SSH private key",
+						},
+						"private_key_passphrase": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Sensitive:   true,
+							Description: "The passphrase of the SSH private key",
+						},
+						"use_agent": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Authenticate with the keys of the SSH agent (SSH_AUTH_SOCK)",
+						},
+						"known_hosts_file": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The known_hosts file used to verify the host keys (defaults to ~/.ssh/known_hosts)",
+						},
+						"insecure_ignore_host_key": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Don't verify the host keys",
+						},
+						"jump_host": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Description: "The hosts to go through, in order, to reach the bastion",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"host": {
+										Type:        schema.TypeString,
+										Required:    true,
+										Description: "The jump host",
+									},
+									"port": {
+										Type:         schema.TypeInt,
+										Optional:     true,
+										Default:      22,
+										Description:  "The SSH port of the jump host",
+										ValidateFunc: validation.IntBetween(1, 65535),
+									},
+									"user": {
+										Type:        schema.TypeString,
+										Optional:    true,
+										Description: "The SSH user on the jump host (defaults to the bastion user)",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
 			"sslrootcert": {
+				Type:          schema.TypeString,
+				Description:   "The SSL server root certificate file path. The file must contain PEM encoded data.",
+				Optional:      true,
+				ConflictsWith: []string{"sslrootcert_pem"},
+			},
+			"sslrootcert_pem": {
 				Type:        schema.TypeString,
-				Description: "The SSL server root certificate file path. The file must contain PEM encoded data.",
+				Description: "The PEM encoded SSL server root certificate, instead of sslrootcert.",
 				Optional:    true,
+				Sensitive:   true,
 			},
 
 			"connect_timeout": {
@@ -185,6 +333,20 @@ func Provider() *schema.Provider {
 				Description:  "Maximum wait for connection, in seconds. Zero or not specified means wait indefinitely.",
 				ValidateFunc: validation.IntAtLeast(-1),
 			},
+			"ddl_timeout": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				Description:  "Statement timeout, in seconds, of the transactions changing the objects (create, update and delete). Zero means the server setting is kept.",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"dml_timeout": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				Description:  "Statement timeout, in seconds, of the read-only transactions reading the catalog (refresh and import). Zero means the server setting is kept.",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
 			"max_connections": {
 				Type:         schema.TypeInt,
 				Optional:     true,
@@ -192,6 +354,177 @@ func Provider() *schema.Provider {
 				Description:  "Maximum number of connections to establish to the database. Zero means unlimited.",
 				ValidateFunc: validation.IntAtLeast(-1),
 			},
+			"max_idle_connections": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				Description:  "Maximum number of idle connections kept open to each database. Zero means the connections are closed after each use.",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"connection_max_lifetime": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				Description:  "Maximum time, in seconds, a connection is reused. Zero means connections are reused forever.",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"connection_max_idle_time": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				Description:  "Maximum time, in seconds, a connection stays idle before being closed. Zero means no limit.",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"failover_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				Description:  "Number of times to reconnect and retry a transaction which failed because the server became read-only (e.g.: writer failover of an Aurora cluster)",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"transient_error_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      3,
+				Description:  "Number of times to retry a read or a transaction which failed with a transient error (e.g.: serialization failure, deadlock, too many connections)",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"transient_error_backoff": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      500,
+				Description:  "Delay in milliseconds before the first retry of an operation which failed with a transient error. It doubles for each retry",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"strict_security_warnings": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Return warnings for risky configurations (e.g.: password without expiry, superuser login roles, grants to PUBLIC)",
+			},
+			"allowed_extensions": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "The extensions which can be created with postgresql_extension (all extensions if not set)",
+			},
+			"flavor": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      flavorPostgreSQL,
+				ValidateFunc: validation.StringInSlice([]string{flavorPostgreSQL, flavorRedshift}, false),
+				Description:  "The kind of server the provider is connected to: postgresql or redshift (enables the Redshift object types of postgresql_grant)",
+			},
+			"journal_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The file in which the statements executed by the grants are journaled, to reconcile an interrupted apply in the next run",
+			},
+			// Not documented: only to profile the provider when investigating an issue.
+			"pprof_listen_address": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("PGPROVIDER_PPROF_ADDRESS", ""),
+				Description:  "Loopback address (e.g.: 127.0.0.1:6060) on which the Go profiling endpoints (/debug/pprof/) of the provider are served",
+				ValidateFunc: validatePprofAddress,
+			},
+			"object_owner_role": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The role owning the databases, schemas and publications created without an owner",
+			},
+			"database_endpoint": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Host and port overrides to connect to specific databases (e.g.: databases routed through different pooler endpoints)",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"database": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The database to which the override applies",
+						},
+						"host": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The host to connect to this database (defaults to the provider host)",
+						},
+						"port": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Description:  "The port to connect to this database (defaults to the provider port)",
+							ValidateFunc: validation.IntBetween(1, 65535),
+						},
+					},
+				},
+			},
+			"reads_from": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      readsFromPrimary,
+				ValidateFunc: validation.StringInSlice([]string{readsFromPrimary, readsFromAny}, false),
+				Description:  "Where the refresh reads are sent: primary (the host) or any (the replica_host if it is set)",
+			},
+			"read_isolation_level": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"read committed", "repeatable read"}, false),
+				Description:  "The isolation level of the read-only transactions of the refresh (read committed or repeatable read). If not set, the server default_transaction_isolation is used",
+			},
+			"replica_host": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The read replica to which the refresh reads are sent if reads_from is any",
+			},
+			"replica_port": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "The port of the read replica (defaults to the provider port)",
+				ValidateFunc: validation.IntBetween(1, 65535),
+			},
+			"endpoint": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Named servers the resources can be managed on with their endpoint attribute (e.g.: a logical replica)",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The name of the endpoint, referenced by the endpoint attribute of the resources",
+						},
+						"host": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The host of the server",
+						},
+						"port": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Description:  "The port of the server (defaults to the provider port)",
+							ValidateFunc: validation.IntBetween(1, 65535),
+						},
+						"username": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The user to connect to the server (defaults to the provider username)",
+						},
+						"password": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Sensitive:   true,
+							Description: "The password of the user (defaults to the provider password)",
+						},
+					},
+				},
+			},
+			"feature_overrides": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeBool},
+				Description: "Force-enable or disable features regardless of the PostgreSQL version (e.g.: { procedure = true, rls = false })",
+			},
 			"expected_version": {
 				Type:         schema.TypeString,
 				Optional:     true,
@@ -202,31 +535,74 @@ func Provider() *schema.Provider {
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
-			"postgresql_database":                  resourcePostgreSQLDatabase(),
-			"postgresql_default_privileges":        resourcePostgreSQLDefaultPrivileges(),
-			"postgresql_extension":                 resourcePostgreSQLExtension(),
-			"postgresql_grant":                     resourcePostgreSQLGrant(),
-			"postgresql_grant_role":                resourcePostgreSQLGrantRole(),
-			"postgresql_replication_slot":          resourcePostgreSQLReplicationSlot(),
-			"postgresql_publication":               resourcePostgreSQLPublication(),
-			"postgresql_subscription":              resourcePostgreSQLSubscription(),
-			"postgresql_physical_replication_slot": resourcePostgreSQLPhysicalReplicationSlot(),
-			"postgresql_schema":                    resourcePostgreSQLSchema(),
-			"postgresql_role":                      resourcePostgreSQLRole(),
-			"postgresql_function":                  resourcePostgreSQLFunction(),
-			"postgresql_server":                    resourcePostgreSQLServer(),
-			"postgresql_user_mapping":              resourcePostgreSQLUserMapping(),
-			"postgresql_security_label":            resourcePostgreSQLSecurityLabel(),
+			"postgresql_database":                     resourcePostgreSQLDatabase(),
+			"postgresql_default_privileges":           resourcePostgreSQLDefaultPrivileges(),
+			"postgresql_extension":                    resourcePostgreSQLExtension(),
+			"postgresql_grant":                        resourcePostgreSQLGrant(),
+			"postgresql_grant_role":                   resourcePostgreSQLGrantRole(),
+			"postgresql_replication_slot":             resourcePostgreSQLReplicationSlot(),
+			"postgresql_publication":                  resourcePostgreSQLPublication(),
+			"postgresql_subscription":                 resourcePostgreSQLSubscription(),
+			"postgresql_physical_replication_slot":    resourcePostgreSQLPhysicalReplicationSlot(),
+			"postgresql_schema":                       resourcePostgreSQLSchema(),
+			"postgresql_role":                         resourcePostgreSQLRole(),
+			"postgresql_function":                     resourcePostgreSQLFunction(),
+			"postgresql_aggregate":                    resourcePostgreSQLAggregate(),
+			"postgresql_server":                       resourcePostgreSQLServer(),
+			"postgresql_user_mapping":                 resourcePostgreSQLUserMapping(),
+			"postgresql_security_label":               resourcePostgreSQLSecurityLabel(),
+			"postgresql_wait_for":                     resourcePostgreSQLWaitFor(),
+			"postgresql_default_transaction_settings": resourcePostgreSQLDefaultTransactionSettings(),
+			"postgresql_pgaudit_role":                 resourcePostgreSQLPgauditRole(),
+			"postgresql_table":                        resourcePostgreSQLTable(),
+			"postgresql_schema_quota":                 resourcePostgreSQLSchemaQuota(),
+			"postgresql_schema_ownership":             resourcePostgreSQLSchemaOwnership(),
+			"postgresql_schema_migration_gate":        resourcePostgreSQLSchemaMigrationGate(),
+			"postgresql_anonymizer_rule":              resourcePostgreSQLAnonymizerRule(),
+			"postgresql_anonymizer_masked_role":       resourcePostgreSQLAnonymizerMaskedRole(),
+			"postgresql_conversion":                   resourcePostgreSQLConversion(),
+			"postgresql_replication_user":             resourcePostgreSQLReplicationUser(),
+			"postgresql_role_membership":              resourcePostgreSQLRoleMembership(),
+			"postgresql_acl_assertion":                resourcePostgreSQLACLAssertion(),
+			"postgresql_database_setting":             resourcePostgreSQLDatabaseSetting(),
+			"postgresql_role_database_settings":       resourcePostgreSQLRoleDatabaseSettings(),
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
-			"postgresql_schemas":   dataSourcePostgreSQLDatabaseSchemas(),
-			"postgresql_tables":    dataSourcePostgreSQLDatabaseTables(),
-			"postgresql_sequences": dataSourcePostgreSQLDatabaseSequences(),
+			"postgresql_schemas":            dataSourcePostgreSQLDatabaseSchemas(),
+			"postgresql_tables":             dataSourcePostgreSQLDatabaseTables(),
+			"postgresql_sequences":          dataSourcePostgreSQLDatabaseSequences(),
+			"postgresql_acl_document":       dataSourcePostgreSQLACLDocument(),
+			"postgresql_control_data":       dataSourcePostgreSQLControlData(),
+			"postgresql_grants":             dataSourcePostgreSQLGrants(),
+			"postgresql_schema_privileges":  dataSourcePostgreSQLSchemaPrivileges(),
+			"postgresql_database":           dataSourcePostgreSQLDatabase(),
+			"postgresql_extension_versions": dataSourcePostgreSQLExtensionVersions(),
+			"postgresql_server_info":        dataSourcePostgreSQLServerInfo(),
+			"postgresql_grant_id":           dataSourcePostgreSQLGrantID(),
+			"postgresql_schema_id":          dataSourcePostgreSQLSchemaID(),
+			"postgresql_publication_id":     dataSourcePostgreSQLPublicationID(),
+			"postgresql_encodings":          dataSourcePostgreSQLEncodings(),
 		},
 
 		ConfigureFunc: providerConfigure,
 	}
+
+	for _, resource := range provider.ResourcesMap {
+		resource.Schema[endpointAttr] = endpointSchema()
+	}
+
+	return provider
+}
+
+// endpointSchema is the schema of the endpoint attribute of the resources.
+func endpointSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		ForceNew:    true,
+		Description: "The name of the provider endpoint to manage this resource on instead of the provider host",
+	}
 }
 
 func validateExpectedVersion(v interface{}, key string) (warnings []string, errors []error) {
@@ -327,6 +703,12 @@ func acquireAzureOauthToken(tenantId string) (string, error) {
 }
 
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+	if address := d.Get("pprof_listen_address").(string); address != "" {
+		if err := startPprofListener(address); err != nil {
+			return nil, err
+		}
+	}
+
 	var sslMode string
 	if sslModeRaw, ok := d.GetOk("sslmode"); ok {
 		sslMode = sslModeRaw.(string)
@@ -343,6 +725,27 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	port := d.Get("port").(int)
 	username := d.Get("username").(string)
 
+	var sshTunnel *SSHTunnelConfig
+	if value, ok := d.GetOk("ssh_tunnel"); ok {
+		if d.Get("scheme").(string) != "postgres" {
+			return nil, fmt.Errorf("ssh_tunnel is only supported with the postgres scheme")
+		}
+		sshTunnel = expandSSHTunnel(value.([]interface{})[0].(map[string]interface{}))
+		sshTunnel.ConnectTimeout = time.Duration(d.Get("connect_timeout").(int)) * time.Second
+	}
+
+	// The SRV record is resolved first as the RDS IAM token is generated for the host.
+	if srvName := d.Get("host_srv").(string); srvName != "" {
+		if d.Get("scheme").(string) != "postgres" {
+			return nil, fmt.Errorf("host_srv is only supported with the postgres scheme")
+		}
+		var err error
+		host, port, err = resolveHostSRV(srvName, srvDialer(sshTunnel), time.Duration(d.Get("connect_timeout").(int))*time.Second)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var password string
 	if d.Get("aws_rds_iam_auth").(bool) {
 		profile := d.Get("aws_rds_iam_profile").(string)
@@ -378,21 +781,147 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		SSLMode:                         sslMode,
 		ApplicationName:                 "Terraform provider",
 		ConnectTimeoutSec:               d.Get("connect_timeout").(int),
+		DDLTimeoutSec:                   d.Get("ddl_timeout").(int),
+		DMLTimeoutSec:                   d.Get("dml_timeout").(int),
 		MaxConns:                        d.Get("max_connections").(int),
+		MaxIdleConns:                    d.Get("max_idle_connections").(int),
+		ConnMaxLifetimeSec:              d.Get("connection_max_lifetime").(int),
+		ConnMaxIdleTimeSec:              d.Get("connection_max_idle_time").(int),
 		ExpectedVersion:                 version,
 		SSLRootCertPath:                 d.Get("sslrootcert").(string),
 		GCPIAMImpersonateServiceAccount: d.Get("gcp_iam_impersonate_service_account").(string),
+		FailoverRetries:                 d.Get("failover_retries").(int),
+		TransientErrorRetries:           d.Get("transient_error_retries").(int),
+		TransientErrorBackoffMs:         d.Get("transient_error_backoff").(int),
+		StrictSecurityWarnings:          d.Get("strict_security_warnings").(bool),
+		AllowedExtensions:               setToSortedSlice(d.Get("allowed_extensions").(*schema.Set)),
+		ObjectOwnerRole:                 d.Get("object_owner_role").(string),
+		JournalPath:                     d.Get("journal_file").(string),
+		Flavor:                          d.Get("flavor").(string),
+		ReadsFrom:                       d.Get("reads_from").(string),
+		ReadIsolationLevel:              d.Get("read_isolation_level").(string),
+	}
+
+	if replicaHost := d.Get("replica_host").(string); replicaHost != "" {
+		config.Replica = &ReplicaEndpoint{
+			Host: replicaHost,
+			Port: d.Get("replica_port").(int),
+		}
+		// The RDS IAM token is only valid for the host it's generated for.
+		if d.Get("aws_rds_iam_auth").(bool) {
+			replicaPort := config.Replica.Port
+			if replicaPort == 0 {
+				replicaPort = port
+			}
+			replicaPassword, err := getRDSAuthToken(
+				d.Get("aws_rds_iam_region").(string),
+				d.Get("aws_rds_iam_profile").(string),
+				d.Get("aws_rds_iam_provider_role_arn").(string),
+				username, replicaHost, replicaPort,
+			)
+			if err != nil {
+				return nil, err
+			}
+			config.Replica.Password = replicaPassword
+		}
 	}
 
 	if value, ok := d.GetOk("clientcert"); ok {
 		if spec, ok := value.([]interface{})[0].(map[string]interface{}); ok {
-			config.SSLClientCert = &ClientCertificateConfig{
-				CertificatePath: spec["cert"].(string),
-				KeyPath:         spec["key"].(string),
-				SSLInline:       spec["sslinline"].(bool),
+			clientCert, err := expandClientCertificate(spec)
+			if err != nil {
+				return nil, err
 			}
+			config.SSLClientCert = clientCert
+		}
+	}
+	if err := inlineSSLCertificates(&config, d.Get("sslrootcert_pem").(string)); err != nil {
+		return nil, err
+	}
+
+	config.SSHTunnel = sshTunnel
+
+	if value, ok := d.GetOk("statement_log"); ok {
+		if config.Scheme != "postgres" {
+			return nil, fmt.Errorf("statement_log is only supported with the postgres scheme")
+		}
+		spec := value.([]interface{})[0].(map[string]interface{})
+		statementLog, err := newStatementLogger(spec["level"].(string), spec["file"].(string))
+		if err != nil {
+			return nil, err
+		}
+		config.StatementLog = statementLog
+	}
+
+	if d.Get("generate_sql_only").(bool) {
+		if config.Scheme != "postgres" {
+			return nil, fmt.Errorf("generate_sql_only is only supported with the postgres scheme")
 		}
+		recorder, err := newSQLRecorder(d.Get("generate_sql_file").(string))
+		if err != nil {
+			return nil, err
+		}
+		config.SQLRecorder = recorder
+	}
+
+	featureOverrides, err := parseFeatureOverrides(d.Get("feature_overrides").(map[string]interface{}))
+	if err != nil {
+		return nil, err
 	}
+	config.FeatureOverrides = featureOverrides
+
+	endpoints := d.Get("database_endpoint").([]interface{})
+	if len(endpoints) > 0 {
+		config.DatabaseEndpoints = map[string]DatabaseEndpoint{}
+		for _, raw := range endpoints {
+			spec := raw.(map[string]interface{})
+			database := spec["database"].(string)
+			if _, ok := config.DatabaseEndpoints[database]; ok {
+				return nil, fmt.Errorf("database_endpoint is set more than once for database %s", database)
+			}
+			config.DatabaseEndpoints[database] = DatabaseEndpoint{
+				Host: spec["host"].(string),
+				Port: spec["port"].(int),
+			}
+		}
+	}
+
+	serverEndpoints, err := expandEndpoints(d.Get("endpoint").([]interface{}))
+	if err != nil {
+		return nil, err
+	}
+	config.Endpoints = serverEndpoints
+	// The RDS IAM token is only valid for the host it's generated for.
+	if d.Get("aws_rds_iam_auth").(bool) {
+		for name, endpoint := range config.Endpoints {
+			if endpoint.Password != "" {
+				continue
+			}
+			endpointPort := endpoint.Port
+			if endpointPort == 0 {
+				endpointPort = port
+			}
+			endpointUsername := endpoint.Username
+			if endpointUsername == "" {
+				endpointUsername = username
+			}
+			if endpoint.Password, err = getRDSAuthToken(
+				d.Get("aws_rds_iam_region").(string),
+				d.Get("aws_rds_iam_profile").(string),
+				d.Get("aws_rds_iam_provider_role_arn").(string),
+				endpointUsername, endpoint.Host, endpointPort,
+			); err != nil {
+				return nil, err
+			}
+			config.Endpoints[name] = endpoint
+		}
+	}
+
+	journal, err := newOperationJournal(config.JournalPath)
+	if err != nil {
+		return nil, err
+	}
+	config.journal = journal
 
 	if config.Scheme == "gcppostgres" {
 		if err := createGoogleCredsFileIfNeeded(); err != nil {
@@ -403,3 +932,144 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	client := config.NewClient(d.Get("database").(string))
 	return client, nil
 }
+
+func expandEndpoints(specs []interface{}) (map[string]ServerEndpoint, error) {
+	if len(specs) == 0 {
+		return nil, nil
+	}
+
+	endpoints := map[string]ServerEndpoint{}
+	for _, raw := range specs {
+		spec := raw.(map[string]interface{})
+		name := spec["name"].(string)
+		if _, ok := endpoints[name]; ok {
+			return nil, fmt.Errorf("endpoint %s is defined more than once", name)
+		}
+		endpoints[name] = ServerEndpoint{
+			Host:     spec["host"].(string),
+			Port:     spec["port"].(int),
+			Username: spec["username"].(string),
+			Password: spec["password"].(string),
+		}
+	}
+
+	return endpoints, nil
+}
+
+// expandClientCertificate returns the client certificate of the clientcert block.
+// If cert_pem or key_pem is set, the certificate and the key are both kept in memory
+// (the file set instead of one of them is read) as lib/pq can't mix files and inline contents.
+func expandClientCertificate(spec map[string]interface{}) (*ClientCertificateConfig, error) {
+	clientCert := &ClientCertificateConfig{
+		CertificatePath: spec["cert"].(string),
+		KeyPath:         spec["key"].(string),
+		SSLInline:       spec["sslinline"].(bool),
+	}
+	certPEM := spec["cert_pem"].(string)
+	keyPEM := spec["key_pem"].(string)
+
+	if (clientCert.CertificatePath == "") == (certPEM == "") {
+		return nil, fmt.Errorf("clientcert requires exactly one of cert and cert_pem")
+	}
+	if (clientCert.KeyPath == "") == (keyPEM == "") {
+		return nil, fmt.Errorf("clientcert requires exactly one of key and key_pem")
+	}
+	if certPEM == "" && keyPEM == "" {
+		return clientCert, nil
+	}
+
+	if err := clientCert.inline(); err != nil {
+		return nil, err
+	}
+	if certPEM != "" {
+		clientCert.CertificatePath = certPEM
+	}
+	if keyPEM != "" {
+		clientCert.KeyPath = keyPEM
+	}
+
+	return clientCert, nil
+}
+
+// inlineSSLCertificates keeps all the SSL certificates in memory if one of them is inline:
+// lib/pq reads the root certificate from memory only with an inline client certificate.
+// Without client certificate, the root certificate PEM is written in a file (see writeSSLRootCertFile).
+func inlineSSLCertificates(config *Config, rootCertPEM string) error {
+	clientCertInline := config.SSLClientCert != nil && config.SSLClientCert.SSLInline
+	if rootCertPEM == "" && !clientCertInline {
+		return nil
+	}
+
+	if rootCertPEM != "" {
+		if config.SSLClientCert == nil {
+			path, err := writeSSLRootCertFile(rootCertPEM)
+			if err != nil {
+				return err
+			}
+			config.SSLRootCertPath = path
+			return nil
+		}
+		if err := config.SSLClientCert.inline(); err != nil {
+			return err
+		}
+		config.SSLRootCertPath = rootCertPEM
+		return nil
+	}
+
+	if config.SSLRootCertPath != "" {
+		content, err := os.ReadFile(config.SSLRootCertPath)
+		if err != nil {
+			return fmt.Errorf("could not read sslrootcert %s: %w", config.SSLRootCertPath, err)
+		}
+		config.SSLRootCertPath = string(content)
+	}
+	return nil
+}
+
+// writeSSLRootCertFile writes the root certificate PEM in a file of the temporary directory and returns its path.
+// The file is named after the hash of the certificate so the runs of the provider reuse it.
+func writeSSLRootCertFile(rootCertPEM string) (string, error) {
+	path := filepath.Join(os.TempDir(), fmt.Sprintf("terraform-provider-postgresql-rootcert-%x.pem", sha256.Sum256([]byte(rootCertPEM))))
+
+	// The file is replaced atomically so a concurrent run can't read a truncated certificate.
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("could not write sslrootcert_pem file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.WriteString(rootCertPEM); err != nil {
+		tmpFile.Close()
+		return "", fmt.Errorf("could not write sslrootcert_pem file %s: %w", tmpFile.Name(), err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return "", fmt.Errorf("could not write sslrootcert_pem file %s: %w", tmpFile.Name(), err)
+	}
+	if err := os.Rename(tmpFile.Name(), path); err != nil {
+		return "", fmt.Errorf("could not write sslrootcert_pem file %s: %w", path, err)
+	}
+	return path, nil
+}
+
+func expandSSHTunnel(spec map[string]interface{}) *SSHTunnelConfig {
+	tunnel := &SSHTunnelConfig{
+		Host:                  spec["host"].(string),
+		Port:                  spec["port"].(int),
+		User:                  spec["user"].(string),
+		PrivateKey:            spec["private_key"].(string),
+		PrivateKeyPassphrase:  spec["private_key_passphrase"].(string),
+		UseAgent:              spec["use_agent"].(bool),
+		KnownHostsFile:        spec["known_hosts_file"].(string),
+		InsecureIgnoreHostKey: spec["insecure_ignore_host_key"].(bool),
+	}
+	for _, raw := range spec["jump_host"].([]interface{}) {
+		jumpHost := raw.(map[string]interface{})
+		tunnel.JumpHosts = append(tunnel.JumpHosts, SSHJumpHost{
+			Host: jumpHost["host"].(string),
+			Port: jumpHost["port"].(int),
+			User: jumpHost["user"].(string),
+		})
+	}
+
+	return tunnel
+}
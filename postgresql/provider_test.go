@@ -2,11 +2,15 @@ package postgresql
 
 import (
 	"context"
+	"net/url"
 	"os"
+	"path/filepath"
+	"sort"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
 )
 
 var testAccProviders map[string]*schema.Provider
@@ -29,6 +33,94 @@ func TestProvider_impl(t *testing.T) {
 	var _ *schema.Provider = Provider()
 }
 
+func TestExpandClientCertificate(t *testing.T) {
+	certFile := filepath.Join(t.TempDir(), "cert.pem")
+	if err := os.WriteFile(certFile, []byte("cert content"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	clientCertSpec := func(cert, key, certPEM, keyPEM string) map[string]interface{} {
+		return map[string]interface{}{
+			"cert": cert, "key": key, "cert_pem": certPEM, "key_pem": keyPEM, "sslinline": false,
+		}
+	}
+
+	clientCert, err := expandClientCertificate(clientCertSpec(certFile, "/path/to/key.pem", "", ""))
+	assert.NoError(t, err)
+	assert.Equal(t, &ClientCertificateConfig{CertificatePath: certFile, KeyPath: "/path/to/key.pem"}, clientCert)
+
+	// The certificate file is read as the key is in memory.
+	clientCert, err = expandClientCertificate(clientCertSpec(certFile, "", "", "key content"))
+	assert.NoError(t, err)
+	assert.Equal(t, &ClientCertificateConfig{CertificatePath: "cert content", KeyPath: "key content", SSLInline: true}, clientCert)
+
+	_, err = expandClientCertificate(clientCertSpec(certFile, "", "cert content", "key content"))
+	assert.Error(t, err)
+
+	_, err = expandClientCertificate(clientCertSpec("", "/path/to/key.pem", "", ""))
+	assert.Error(t, err)
+}
+
+func TestInlineSSLCertificates(t *testing.T) {
+	rootCertFile := filepath.Join(t.TempDir(), "root.pem")
+	if err := os.WriteFile(rootCertFile, []byte("root content"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing is inline, the files are used.
+	config := &Config{SSLRootCertPath: rootCertFile}
+	assert.NoError(t, inlineSSLCertificates(config, ""))
+	assert.Equal(t, rootCertFile, config.SSLRootCertPath)
+
+	// The root certificate file is read as the client certificate is in memory.
+	config = &Config{
+		SSLClientCert:   &ClientCertificateConfig{CertificatePath: "cert", KeyPath: "key", SSLInline: true},
+		SSLRootCertPath: rootCertFile,
+	}
+	assert.NoError(t, inlineSSLCertificates(config, ""))
+	assert.Equal(t, "root content", config.SSLRootCertPath)
+
+	config = &Config{
+		SSLClientCert: &ClientCertificateConfig{CertificatePath: "cert", KeyPath: "key", SSLInline: true},
+	}
+	assert.NoError(t, inlineSSLCertificates(config, "root pem"))
+	assert.Equal(t, "root pem", config.SSLRootCertPath)
+	params := config.connParams()
+	sort.Strings(params)
+	assert.Equal(t, []string{"sslcert=cert", "sslinline=true", "sslkey=key", "sslrootcert=root+pem"}, params)
+
+	// lib/pq needs a client certificate to read the root certificate from memory, it's written in a file without.
+	config = &Config{}
+	assert.NoError(t, inlineSSLCertificates(config, "root pem"))
+	content, err := os.ReadFile(config.SSLRootCertPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "root pem", string(content))
+	assert.Equal(t, []string{"sslrootcert=" + url.QueryEscape(config.SSLRootCertPath)}, config.connParams())
+}
+
+func TestProviderResourcesEndpoint(t *testing.T) {
+	for name, resource := range Provider().ResourcesMap {
+		if _, ok := resource.Schema[endpointAttr]; !ok {
+			t.Errorf("resource %s has no %s attribute", name, endpointAttr)
+		}
+	}
+}
+
+func TestExpandEndpoints(t *testing.T) {
+	endpoint := func(name, host string) map[string]interface{} {
+		return map[string]interface{}{"name": name, "host": host, "port": 0, "username": "", "password": ""}
+	}
+
+	endpoints, err := expandEndpoints([]interface{}{endpoint("replica", "replica-host"), endpoint("instance", "instance-host")})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]ServerEndpoint{
+		"replica":  {Host: "replica-host"},
+		"instance": {Host: "instance-host"},
+	}, endpoints)
+
+	_, err = expandEndpoints([]interface{}{endpoint("replica", "a"), endpoint("replica", "b")})
+	assert.Error(t, err)
+}
+
 func testAccPreCheck(t *testing.T) {
 	var host string
 	if host = os.Getenv("PGHOST"); host == "" {
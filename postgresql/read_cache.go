@@ -0,0 +1,150 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+
+	"github.com/lib/pq"
+)
+
+// relationACLsKey identifies the relations of a kind (relkind) in a schema of a database.
+type relationACLsKey struct {
+	database string
+	schema   string
+	relkind  string
+}
+
+// relationACL is the ACL of a relation: the privileges of each grantee OID (0 is PUBLIC)
+// and the ones it can grant (WITH GRANT OPTION).
+type relationACL struct {
+	extensionMember bool
+	privileges      map[uint32]pq.ByteaArray
+	grantable       map[uint32]pq.ByteaArray
+}
+
+// readCacheMaxRelations is the number of relations above which the ACL of a schema is not loaded in the cache:
+// reading all of them would cost more than the per grant queries stopping at the first unexpected privilege.
+const readCacheMaxRelations = 1000
+
+type relationACLsEntry struct {
+	done      chan struct{}
+	relations map[string]relationACL
+	err       error
+}
+
+// readCache coalesces the catalog queries of the Read functions refreshing many resources concurrently:
+// the first resource reading the ACL of the relations of a schema loads them all, the other resources
+// of the schema wait for this query and share its result.
+// The results are only valid for the refresh, the cache is cleared each time the provider changes something
+// (see runResourceFunc).
+type readCache struct {
+	sync.Mutex
+	relations map[relationACLsKey]*relationACLsEntry
+}
+
+func newReadCache() *readCache {
+	return &readCache{relations: map[relationACLsKey]*relationACLsEntry{}}
+}
+
+// relationACLs returns the ACL of the relations for the key, by name. load is only called if the relations
+// are not already loaded (or being loaded by another resource).
+// A nil map without error means that the relations are not cached (see loadRelationACLs).
+func (c *readCache) relationACLs(key relationACLsKey, load func() (map[string]relationACL, error)) (map[string]relationACL, error) {
+	c.Lock()
+	entry, ok := c.relations[key]
+	if ok {
+		c.Unlock()
+		<-entry.done
+		return entry.relations, entry.err
+	}
+	entry = &relationACLsEntry{done: make(chan struct{})}
+	c.relations[key] = entry
+	c.Unlock()
+
+	entry.relations, entry.err = load()
+	close(entry.done)
+
+	if entry.err != nil {
+		// The next resource will try again.
+		c.Lock()
+		if c.relations[key] == entry {
+			delete(c.relations, key)
+		}
+		c.Unlock()
+	}
+	return entry.relations, entry.err
+}
+
+// clear drops the cached results. The relations being loaded are still returned to the resources waiting for them.
+func (c *readCache) clear() {
+	c.Lock()
+	defer c.Unlock()
+
+	c.relations = map[relationACLsKey]*relationACLsEntry{}
+}
+
+// loadRelationACLs reads the ACL of all the relations of the kind in the schema.
+// It returns a nil map if the schema has more than readCacheMaxRelations of them.
+func loadRelationACLs(txn QueryAble, pgSchema, relkind string) (map[string]relationACL, error) {
+	var count int
+	if err := txn.QueryRow(`
+SELECT count(*) FROM pg_class
+JOIN pg_namespace ON pg_namespace.oid = pg_class.relnamespace
+WHERE nspname = $1 AND relkind = $2
+`, pgSchema, relkind).Scan(&count); err != nil {
+		return nil, fmt.Errorf("could not count the relations of schema %s: %w", pgSchema, err)
+	}
+	if count > readCacheMaxRelations {
+		return nil, nil
+	}
+
+	// relacl contains only the table-level privileges, as in readRelationRolePrivileges.
+	rows, err := txn.Query(`
+SELECT pg_class.relname,
+    EXISTS (
+        SELECT 1 FROM pg_catalog.pg_depend dep
+        WHERE dep.classid = 'pg_catalog.pg_class'::regclass AND dep.objid = pg_class.oid AND dep.deptype = 'e'
+    ),
+    acl.grantee,
+    array_remove(array_agg(acl.privilege_type), NULL),
+    array_remove(array_agg(CASE WHEN acl.is_grantable THEN acl.privilege_type END), NULL)
+FROM pg_class
+JOIN pg_namespace ON pg_namespace.oid = pg_class.relnamespace
+LEFT JOIN LATERAL aclexplode(pg_class.relacl) acl ON true
+WHERE nspname = $1 AND relkind = $2
+GROUP BY pg_class.oid, pg_class.relname, acl.grantee
+`, pgSchema, relkind)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the privileges of the relations of schema %s: %w", pgSchema, err)
+	}
+	defer rows.Close()
+
+	relations := map[string]relationACL{}
+	for rows.Next() {
+		var name string
+		var extensionMember bool
+		var grantee sql.NullInt64
+		var privileges, grantable pq.ByteaArray
+		if err := rows.Scan(&name, &extensionMember, &grantee, &privileges, &grantable); err != nil {
+			return nil, fmt.Errorf("could not scan the privileges of the relations of schema %s: %w", pgSchema, err)
+		}
+
+		relation, ok := relations[name]
+		if !ok {
+			relation = relationACL{
+				extensionMember: extensionMember,
+				privileges:      map[uint32]pq.ByteaArray{},
+				grantable:       map[uint32]pq.ByteaArray{},
+			}
+			relations[name] = relation
+		}
+		// The grantee is NULL if the relation has no ACL.
+		if grantee.Valid {
+			relation.privileges[uint32(grantee.Int64)] = privileges
+			relation.grantable[uint32(grantee.Int64)] = grantable
+		}
+	}
+
+	return relations, rows.Err()
+}
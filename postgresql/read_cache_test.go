@@ -0,0 +1,71 @@
+package postgresql
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadCacheRelationACLs(t *testing.T) {
+	cache := newReadCache()
+	key := relationACLsKey{database: "db", schema: "public", relkind: "r"}
+
+	var loads int32
+	release := make(chan struct{})
+	load := func() (map[string]relationACL, error) {
+		atomic.AddInt32(&loads, 1)
+		<-release
+		return map[string]relationACL{"t1": {}}, nil
+	}
+
+	// The concurrent reads of the same relations share one query.
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			relations, err := cache.relationACLs(key, load)
+			assert.NoError(t, err)
+			assert.Contains(t, relations, "t1")
+		}()
+	}
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&loads))
+
+	// Another schema is loaded separately.
+	_, err := cache.relationACLs(relationACLsKey{database: "db", schema: "other", relkind: "r"}, load)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&loads))
+
+	// The relations are loaded again once the cache is cleared.
+	cache.clear()
+	_, err = cache.relationACLs(key, load)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&loads))
+
+	// The errors are not cached.
+	failingKey := relationACLsKey{database: "db", schema: "failing", relkind: "r"}
+	_, err = cache.relationACLs(failingKey, func() (map[string]relationACL, error) {
+		return nil, errors.New("connection lost")
+	})
+	assert.EqualError(t, err, "connection lost")
+	_, err = cache.relationACLs(failingKey, load)
+	assert.NoError(t, err)
+
+	// The large schemas are not loaded again by each grant.
+	var largeLoads int32
+	largeKey := relationACLsKey{database: "db", schema: "large", relkind: "r"}
+	for i := 0; i < 2; i++ {
+		relations, err := cache.relationACLs(largeKey, func() (map[string]relationACL, error) {
+			atomic.AddInt32(&largeLoads, 1)
+			return nil, nil
+		})
+		assert.NoError(t, err)
+		assert.Nil(t, relations)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&largeLoads))
+}
@@ -0,0 +1,329 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	aclAssertionDatabaseAttr         = "database"
+	aclAssertionRoleAttr             = "role"
+	aclAssertionObjectTypeAttr       = "object_type"
+	aclAssertionSchemaAttr           = "schema"
+	aclAssertionObjectsAttr          = "objects"
+	aclAssertionDeniedPrivilegesAttr = "denied_privileges"
+	aclAssertionOnViolationAttr      = "on_violation"
+	aclAssertionViolationsAttr       = "violations"
+)
+
+// aclAssertionObjectType describes how the privileges of an object type are checked:
+// from and name select the objects and their display names, acl is their ACL (with the default
+// privileges if it is null) and hasPrivilege the function checking the privileges of a role.
+type aclAssertionObjectType struct {
+	from         string
+	name         string
+	acl          string
+	hasPrivilege string
+	relkinds     string
+}
+
+var aclAssertionObjectTypes = map[string]aclAssertionObjectType{
+	"database": {
+		from:         "pg_catalog.pg_database o",
+		name:         "quote_ident(o.datname)",
+		acl:          "COALESCE(o.datacl, acldefault('d', o.datdba))",
+		hasPrivilege: "has_database_privilege",
+	},
+	"schema": {
+		from:         "pg_catalog.pg_namespace o",
+		name:         "quote_ident(o.nspname)",
+		acl:          "COALESCE(o.nspacl, acldefault('n', o.nspowner))",
+		hasPrivilege: "has_schema_privilege",
+	},
+	"table": {
+		from:         "pg_catalog.pg_class o JOIN pg_catalog.pg_namespace n ON n.oid = o.relnamespace",
+		name:         "quote_ident(n.nspname) || '.' || quote_ident(o.relname)",
+		acl:          "COALESCE(o.relacl, acldefault('r', o.relowner))",
+		hasPrivilege: "has_table_privilege",
+		relkinds:     "'r', 'p', 'v', 'm', 'f'",
+	},
+	"sequence": {
+		from:         "pg_catalog.pg_class o JOIN pg_catalog.pg_namespace n ON n.oid = o.relnamespace",
+		name:         "quote_ident(n.nspname) || '.' || quote_ident(o.relname)",
+		acl:          "COALESCE(o.relacl, acldefault('s', o.relowner))",
+		hasPrivilege: "has_sequence_privilege",
+		relkinds:     "'S'",
+	},
+	"function": {
+		from:         "pg_catalog.pg_proc o JOIN pg_catalog.pg_namespace n ON n.oid = o.pronamespace",
+		name:         "quote_ident(n.nspname) || '.' || quote_ident(o.proname) || '(' || pg_catalog.pg_get_function_identity_arguments(o.oid) || ')'",
+		acl:          "COALESCE(o.proacl, acldefault('f', o.proowner))",
+		hasPrivilege: "has_function_privilege",
+	},
+}
+
+func resourcePostgreSQLACLAssertion() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: PGResourceFuncWithWarnings(resourcePostgreSQLACLAssertionCreate, nil),
+		Read:          PGReadResourceFunc(resourcePostgreSQLACLAssertionRead),
+		UpdateContext: PGResourceFuncWithWarnings(resourcePostgreSQLACLAssertionUpdate, nil),
+		Delete:        PGResourceFunc(resourcePostgreSQLACLAssertionDelete),
+		CustomizeDiff: resourcePostgreSQLACLAssertionCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			aclAssertionDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The database in which the privileges are checked. If not specified, the provider default database is used.",
+			},
+			aclAssertionRoleAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The role which must not have the privileges, or public",
+			},
+			aclAssertionObjectTypeAttr: {
+				Type:     schema.TypeString,
+				Required: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"database",
+					"schema",
+					"table",
+					"sequence",
+					"function",
+				}, false),
+				Description: "The type of the checked objects (database, schema, table, sequence or function)",
+			},
+			aclAssertionSchemaAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The checked schema, or the schema of the checked objects",
+			},
+			aclAssertionObjectsAttr: {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "The names of the checked objects in the schema. All the objects of the type are checked if empty",
+			},
+			aclAssertionDeniedPrivilegesAttr: {
+				Type:             schema.TypeSet,
+				Required:         true,
+				MinItems:         1,
+				Elem:             &schema.Schema{Type: schema.TypeString},
+				Set:              hashPrivilege,
+				DiffSuppressFunc: privilegesDiffSuppress,
+				Description:      "The privileges the role must not have",
+			},
+			aclAssertionOnViolationAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "error",
+				ValidateFunc: validation.StringInSlice([]string{"error", "warn"}, false),
+				Description:  "If the apply fails (error) or only warns (warn) when the role has one of the privileges",
+			},
+			aclAssertionViolationsAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The privileges the role has while it must not, found during the last refresh or apply",
+			},
+		},
+	}
+}
+
+// resourcePostgreSQLACLAssertionCustomizeDiff plans an update of the existing assertions only if
+// violations have been found during the refresh, so the apply reports them.
+func resourcePostgreSQLACLAssertionCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Id() == "" || len(d.Get(aclAssertionViolationsAttr).([]interface{})) == 0 {
+		return nil
+	}
+	return d.SetNewComputed(aclAssertionViolationsAttr)
+}
+
+func resourcePostgreSQLACLAssertionCreate(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+
+	if err := checkACLAssertion(db, d); err != nil {
+		return err
+	}
+
+	d.Set(aclAssertionDatabaseAttr, database)
+	d.SetId(generateObjectID(
+		database,
+		strings.ToLower(d.Get(aclAssertionRoleAttr).(string)),
+		d.Get(aclAssertionObjectTypeAttr).(string),
+		d.Get(aclAssertionSchemaAttr).(string),
+	))
+
+	return nil
+}
+
+func resourcePostgreSQLACLAssertionRead(db *DBConnection, d *schema.ResourceData) error {
+	// The violations are only refreshed here, they are reported by the apply planned by the CustomizeDiff.
+	if err := validateACLAssertion(d); err != nil {
+		return err
+	}
+
+	violations, err := readACLAssertionViolations(db, d)
+	if err != nil {
+		return err
+	}
+	d.Set(aclAssertionViolationsAttr, violations)
+	return nil
+}
+
+func resourcePostgreSQLACLAssertionUpdate(db *DBConnection, d *schema.ResourceData) error {
+	return checkACLAssertion(db, d)
+}
+
+func resourcePostgreSQLACLAssertionDelete(db *DBConnection, d *schema.ResourceData) error {
+	d.SetId("")
+	return nil
+}
+
+// checkACLAssertion looks for the denied privileges of the role and fails, or warns if on_violation is warn,
+// if it has some of them.
+func checkACLAssertion(db *DBConnection, d *schema.ResourceData) error {
+	if err := validateACLAssertion(d); err != nil {
+		return err
+	}
+
+	violations, err := readACLAssertionViolations(db, d)
+	if err != nil {
+		return err
+	}
+
+	d.Set(aclAssertionViolationsAttr, violations)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	message := fmt.Sprintf(
+		"ACL assertion failed in database %s: %s",
+		getDatabase(d, db.client.databaseName), strings.Join(violations, ", "),
+	)
+	if d.Get(aclAssertionOnViolationAttr).(string) == "warn" {
+		db.warn("%s", message)
+		return nil
+	}
+	return fmt.Errorf("%s", message)
+}
+
+// readACLAssertionViolations lists the denied privileges the role has.
+func readACLAssertionViolations(db *DBConnection, d *schema.ResourceData) ([]string, error) {
+	database := getDatabase(d, db.client.databaseName)
+	role := d.Get(aclAssertionRoleAttr).(string)
+	objectType := d.Get(aclAssertionObjectTypeAttr).(string)
+	privileges := expandPrivileges(objectType, normalizePrivileges(d.Get(aclAssertionDeniedPrivilegesAttr).(*schema.Set)))
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return nil, err
+	}
+	defer deferredRollback(txn)
+
+	rows, err := txn.Query(aclAssertionQuery(
+		role,
+		objectType,
+		d.Get(aclAssertionSchemaAttr).(string),
+		setToSortedSlice(d.Get(aclAssertionObjectsAttr).(*schema.Set)),
+	), pq.Array(privileges))
+	if err != nil {
+		return nil, fmt.Errorf("could not check the privileges of %s in database %s: %w", role, database, err)
+	}
+	defer rows.Close()
+
+	violations := []string{}
+	for rows.Next() {
+		var object, privilege string
+		if err := rows.Scan(&object, &privilege); err != nil {
+			return nil, fmt.Errorf("could not scan the privileges of %s: %w", role, err)
+		}
+		violations = append(violations, fmt.Sprintf("%s has %s on %s %s", role, privilege, objectType, object))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return violations, nil
+}
+
+func validateACLAssertion(d *schema.ResourceData) error {
+	objectType := d.Get(aclAssertionObjectTypeAttr).(string)
+
+	for _, privilege := range d.Get(aclAssertionDeniedPrivilegesAttr).(*schema.Set).List() {
+		if !sliceContainsStr(allowedPrivileges[objectType], normalizePrivilege(privilege.(string))) {
+			return fmt.Errorf("%s is not an allowed privilege for object type %s", privilege, objectType)
+		}
+	}
+
+	schemaName := d.Get(aclAssertionSchemaAttr).(string)
+	objects := d.Get(aclAssertionObjectsAttr).(*schema.Set)
+	switch objectType {
+	case "database":
+		if schemaName != "" || objects.Len() > 0 {
+			return fmt.Errorf("cannot specify schema or objects when object_type is database")
+		}
+	case "schema":
+		if schemaName == "" {
+			return fmt.Errorf("schema is required when object_type is schema")
+		}
+		if objects.Len() > 0 {
+			return fmt.Errorf("cannot specify objects when object_type is schema")
+		}
+	default:
+		if schemaName == "" {
+			return fmt.Errorf("schema is required when object_type is %s", objectType)
+		}
+	}
+	return nil
+}
+
+// aclAssertionQuery returns the query listing the objects on which the role has the privileges ($1).
+// The privileges of PUBLIC are read from the ACL of the objects as public is not a role,
+// the privileges of the other roles include the privileges of the roles they are members of and of PUBLIC.
+func aclAssertionQuery(role, objectType, schemaName string, objects []string) string {
+	t := aclAssertionObjectTypes[objectType]
+
+	check := fmt.Sprintf("%s(%s, o.oid, p.privilege)", t.hasPrivilege, pq.QuoteLiteral(role))
+	if isPublicRole(role) {
+		check = fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM aclexplode(%s) a WHERE a.grantee = 0 AND a.privilege_type = p.privilege)",
+			t.acl,
+		)
+	}
+
+	filters := []string{}
+	switch objectType {
+	case "database":
+		filters = append(filters, "o.datname = current_database()")
+	case "schema":
+		filters = append(filters, "o.nspname = "+pq.QuoteLiteral(schemaName))
+	default:
+		filters = append(filters, "n.nspname = "+pq.QuoteLiteral(schemaName))
+		nameColumn := "o.relname"
+		if objectType == "function" {
+			nameColumn = "o.proname"
+		} else {
+			filters = append(filters, fmt.Sprintf("o.relkind IN (%s)", t.relkinds))
+		}
+		if len(objects) > 0 {
+			quoted := make([]string, 0, len(objects))
+			for _, object := range objects {
+				quoted = append(quoted, pq.QuoteLiteral(object))
+			}
+			filters = append(filters, fmt.Sprintf("%s IN (%s)", nameColumn, strings.Join(quoted, ", ")))
+		}
+	}
+
+	return fmt.Sprintf(
+		"SELECT %s, p.privilege FROM %s CROSS JOIN unnest($1::text[]) AS p(privilege) WHERE %s AND %s ORDER BY 1, 2",
+		t.name, t.from, strings.Join(filters, " AND "), check,
+	)
+}
@@ -0,0 +1,85 @@
+package postgresql
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestACLAssertionQuery(t *testing.T) {
+	query := aclAssertionQuery("public", "schema", "public", nil)
+	assert.Contains(t, query, "o.nspname = 'public'")
+	assert.Contains(t, query, "a.grantee = 0")
+	assert.NotContains(t, query, "has_schema_privilege")
+
+	query = aclAssertionQuery("app", "table", "test_schema", []string{"t1", "t2"})
+	assert.Contains(t, query, "has_table_privilege('app', o.oid, p.privilege)")
+	assert.Contains(t, query, "n.nspname = 'test_schema'")
+	assert.Contains(t, query, "o.relkind IN ('r', 'p', 'v', 'm', 'f')")
+	assert.Contains(t, query, "o.relname IN ('t1', 't2')")
+
+	query = aclAssertionQuery("app", "function", "public", []string{"f"})
+	assert.Contains(t, query, "has_function_privilege('app', o.oid, p.privilege)")
+	assert.Contains(t, query, "o.proname IN ('f')")
+	assert.NotContains(t, query, "relkind")
+}
+
+func TestAccPostgresqlACLAssertion_Table(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	createTestTables(t, dbSuffix, []string{"test_schema.test_table"}, "")
+
+	config := getTestConfig(t)
+	dbName, roleName := getTestDBNames(dbSuffix)
+	dbExecute(t, config.connStr(dbName), fmt.Sprintf("GRANT SELECT ON test_schema.test_table TO %s", roleName))
+
+	testConfig := `
+resource "postgresql_acl_assertion" "test" {
+	database          = "%s"
+	role              = "%s"
+	object_type       = "table"
+	schema            = "test_schema"
+	objects           = ["test_table"]
+	denied_privileges = ["%s"]
+	on_violation      = "%s"
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testConfig, dbName, roleName, "INSERT", "error"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_acl_assertion.test", "violations.#", "0"),
+				),
+			},
+			{
+				Config:      fmt.Sprintf(testConfig, dbName, roleName, "SELECT", "error"),
+				ExpectError: regexp.MustCompile("ACL assertion failed"),
+			},
+			{
+				Config: fmt.Sprintf(testConfig, dbName, roleName, "SELECT", "warn"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_acl_assertion.test", "violations.#", "1"),
+					resource.TestCheckResourceAttr(
+						"postgresql_acl_assertion.test", "violations.0",
+						fmt.Sprintf("%s has SELECT on table test_schema.test_table", roleName),
+					),
+				),
+				// The violations found by the refresh plan an update to report them again.
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
@@ -0,0 +1,371 @@
+package postgresql
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	aggDatabaseAttr    = "database"
+	aggSchemaAttr      = "schema"
+	aggNameAttr        = "name"
+	aggArgAttr         = "arg"
+	aggSFuncAttr       = "sfunc"
+	aggSTypeAttr       = "stype"
+	aggFinalFuncAttr   = "finalfunc"
+	aggCombineFuncAttr = "combinefunc"
+	aggInitCondAttr    = "initcond"
+	aggParallelAttr    = "parallel"
+	aggDropCascadeAttr = "drop_cascade"
+)
+
+// aggParallel maps pg_proc.proparallel to the PARALLEL option of the aggregate.
+var aggParallel = map[string]string{
+	"s": "SAFE",
+	"r": "RESTRICTED",
+	"u": "UNSAFE",
+}
+
+func resourcePostgreSQLAggregate() *schema.Resource {
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLAggregateCreate),
+		Read:   PGReadResourceFunc(resourcePostgreSQLAggregateRead),
+		Update: PGResourceFunc(resourcePostgreSQLAggregateUpdate),
+		Delete: PGResourceFunc(resourcePostgreSQLAggregateDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			aggDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The database of the aggregate. If not specified, the provider default database is used.",
+			},
+			aggSchemaAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "public",
+				ForceNew:    true,
+				Description: "The schema of the aggregate",
+			},
+			aggNameAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the aggregate",
+			},
+			aggArgAttr: {
+				Type:     schema.TypeList,
+				Required: true,
+				ForceNew: true,
+				MinItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						funcArgTypeAttr: {
+							Type:        schema.TypeString,
+							Description: "The argument type.",
+							Required:    true,
+							ForceNew:    true,
+						},
+						funcArgNameAttr: {
+							Type:        schema.TypeString,
+							Description: "The argument name, only for documentation purposes.",
+							Optional:    true,
+							ForceNew:    true,
+						},
+						funcArgModeAttr: {
+							Type:             schema.TypeString,
+							Description:      "The argument mode. One of: IN, VARIADIC",
+							Optional:         true,
+							Default:          "IN",
+							ForceNew:         true,
+							DiffSuppressFunc: defaultDiffSuppressFunc,
+							ValidateFunc:     validation.StringInSlice([]string{"IN", "VARIADIC"}, false),
+						},
+					},
+				},
+				Description: "Aggregate argument definitions.",
+			},
+			aggSFuncAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The state transition function, optionally qualified with its schema",
+			},
+			aggSTypeAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The data type of the state value",
+			},
+			aggFinalFuncAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The final function computing the result from the state value, optionally qualified with its schema",
+			},
+			aggCombineFuncAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The function combining two state values, needed for the partial (e.g.: parallel) aggregation",
+			},
+			aggInitCondAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The initial state value, as a literal of the state type. If not specified, the state value starts out null",
+			},
+			aggParallelAttr: {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          defaultFunctionParallel,
+				ForceNew:         true,
+				DiffSuppressFunc: defaultDiffSuppressFunc,
+				ValidateFunc:     validation.StringInSlice([]string{"UNSAFE", "RESTRICTED", "SAFE"}, false),
+				Description:      "If the aggregate can be executed in parallel. One of: UNSAFE, RESTRICTED, SAFE",
+			},
+			aggDropCascadeAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Automatically drop objects that depend on the aggregate (such as views), and in turn all objects that depend on those objects.",
+			},
+		},
+	}
+}
+
+func resourcePostgreSQLAggregateCreate(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureAggregate) {
+		return fmt.Errorf(
+			"postgresql_aggregate resource is not supported for this Postgres version (%s)",
+			db.version,
+		)
+	}
+
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(aggSchemaAttr).(string)
+	name := d.Get(aggNameAttr).(string)
+	args := expandFunctionArgs(d.Get(aggArgAttr).([]interface{}))
+
+	b := bytes.NewBufferString("CREATE AGGREGATE ")
+	fmt.Fprint(b, pq.QuoteIdentifier(schemaName), ".", pq.QuoteIdentifier(name), " (")
+	for i, arg := range args {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprint(b, arg)
+	}
+	b.WriteString(") (")
+
+	// The functions and the type can be qualified with their schema, they're sent as is.
+	fmt.Fprint(b, "\n    SFUNC = ", d.Get(aggSFuncAttr).(string))
+	fmt.Fprint(b, ",\n    STYPE = ", d.Get(aggSTypeAttr).(string))
+	if v, ok := d.GetOk(aggFinalFuncAttr); ok {
+		fmt.Fprint(b, ",\n    FINALFUNC = ", v.(string))
+	}
+	if v, ok := d.GetOk(aggCombineFuncAttr); ok {
+		fmt.Fprint(b, ",\n    COMBINEFUNC = ", v.(string))
+	}
+	if v, ok := d.GetOk(aggInitCondAttr); ok {
+		fmt.Fprint(b, ",\n    INITCOND = ", pq.QuoteLiteral(v.(string)))
+	}
+	if parallel := d.Get(aggParallelAttr).(string); parallel != defaultFunctionParallel {
+		fmt.Fprint(b, ",\n    PARALLEL = ", parallel)
+	}
+	b.WriteString("\n)")
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if _, err := txn.Exec(b.String()); err != nil {
+		return fmt.Errorf("could not create aggregate %s: %w", name, err)
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId(generateAggregateID(database, schemaName, name, args))
+
+	return resourcePostgreSQLAggregateReadImpl(db, d)
+}
+
+func resourcePostgreSQLAggregateRead(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureAggregate) {
+		return fmt.Errorf(
+			"postgresql_aggregate resource is not supported for this Postgres version (%s)",
+			db.version,
+		)
+	}
+
+	return resourcePostgreSQLAggregateReadImpl(db, d)
+}
+
+func resourcePostgreSQLAggregateReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	database, signature, err := expandFunctionID(d.Id(), d, db)
+	if err != nil {
+		return err
+	}
+
+	exists, err := dbExists(db, database)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		log.Printf("[WARN] PostgreSQL database (%s) not found, removing aggregate from state", database)
+		d.SetId("")
+		return nil
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	// As for the function of postgresql_conversion, the configured functions and state type are kept
+	// if they're the ones of the aggregate, as they can be qualified with their schema or not.
+	var schemaName, name, sfunc, stype, finalfunc, combinefunc, initcond, parallel string
+	var argTypes, argNames []string
+	var variadic, sameSFunc, sameSType, sameFinalFunc, sameCombineFunc bool
+	err = txn.QueryRow(`
+SELECT n.nspname, p.proname,
+	ARRAY(SELECT format_type(t, NULL) FROM unnest(p.proargtypes) t), COALESCE(p.proargnames, '{}'), p.provariadic <> 0,
+	a.aggtransfn::text, COALESCE(to_regproc(NULLIF($2, '')) = a.aggtransfn, false),
+	format_type(a.aggtranstype, NULL), COALESCE(to_regtype(NULLIF($3, '')) = a.aggtranstype, false),
+	CASE WHEN a.aggfinalfn::oid <> 0 THEN a.aggfinalfn::text ELSE '' END,
+	COALESCE(to_regproc(NULLIF($4, '')) = a.aggfinalfn, false),
+	CASE WHEN a.aggcombinefn::oid <> 0 THEN a.aggcombinefn::text ELSE '' END,
+	COALESCE(to_regproc(NULLIF($5, '')) = a.aggcombinefn, false),
+	COALESCE(a.agginitval, ''), p.proparallel
+FROM pg_catalog.pg_aggregate a
+JOIN pg_catalog.pg_proc p ON p.oid = a.aggfnoid
+JOIN pg_catalog.pg_namespace n ON n.oid = p.pronamespace
+WHERE p.oid = to_regprocedure($1)
+`,
+		signature,
+		d.Get(aggSFuncAttr).(string),
+		d.Get(aggSTypeAttr).(string),
+		d.Get(aggFinalFuncAttr).(string),
+		d.Get(aggCombineFuncAttr).(string),
+	).Scan(
+		&schemaName, &name,
+		pq.Array(&argTypes), pq.Array(&argNames), &variadic,
+		&sfunc, &sameSFunc,
+		&stype, &sameSType,
+		&finalfunc, &sameFinalFunc,
+		&combinefunc, &sameCombineFunc,
+		&initcond, &parallel,
+	)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] PostgreSQL aggregate (%s) not found, removing it from state", d.Id())
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("could not read aggregate %s: %w", d.Id(), err)
+	}
+
+	if sameSFunc {
+		sfunc = d.Get(aggSFuncAttr).(string)
+	}
+	if sameSType {
+		stype = d.Get(aggSTypeAttr).(string)
+	}
+	if sameFinalFunc {
+		finalfunc = d.Get(aggFinalFuncAttr).(string)
+	}
+	if sameCombineFunc {
+		combinefunc = d.Get(aggCombineFuncAttr).(string)
+	}
+
+	args := make([]interface{}, 0, len(argTypes))
+	for i, argType := range argTypes {
+		arg := map[string]interface{}{
+			funcArgTypeAttr: argType,
+			funcArgNameAttr: "",
+			funcArgModeAttr: "IN",
+		}
+		if i < len(argNames) {
+			arg[funcArgNameAttr] = argNames[i]
+		}
+		// Only the last argument can be variadic.
+		if variadic && i == len(argTypes)-1 {
+			arg[funcArgModeAttr] = "VARIADIC"
+		}
+		args = append(args, arg)
+	}
+
+	d.Set(aggDatabaseAttr, database)
+	d.Set(aggSchemaAttr, schemaName)
+	d.Set(aggNameAttr, name)
+	d.Set(aggArgAttr, args)
+	d.Set(aggSFuncAttr, sfunc)
+	d.Set(aggSTypeAttr, stype)
+	d.Set(aggFinalFuncAttr, finalfunc)
+	d.Set(aggCombineFuncAttr, combinefunc)
+	d.Set(aggInitCondAttr, initcond)
+	d.Set(aggParallelAttr, aggParallel[parallel])
+
+	return nil
+}
+
+// resourcePostgreSQLAggregateUpdate only updates the attributes which are not sent to PostgreSQL,
+// as all the options of the aggregate force its recreation.
+func resourcePostgreSQLAggregateUpdate(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLAggregateReadImpl(db, d)
+}
+
+func resourcePostgreSQLAggregateDelete(db *DBConnection, d *schema.ResourceData) error {
+	database, signature, err := expandFunctionID(d.Id(), d, db)
+	if err != nil {
+		return err
+	}
+
+	dropMode := "RESTRICT"
+	if d.Get(aggDropCascadeAttr).(bool) {
+		dropMode = "CASCADE"
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if _, err := txn.Exec(fmt.Sprintf("DROP AGGREGATE IF EXISTS %s %s", signature, dropMode)); err != nil {
+		return fmt.Errorf("could not drop aggregate %s: %w", d.Id(), err)
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// generateAggregateID returns the ID of the aggregate, in the format of the postgresql_function IDs
+// (database.schema.name(argument types)) so it's parsed by expandFunctionID.
+func generateAggregateID(database, schemaName, name string, args []PGFunctionArg) string {
+	argTypes := make([]string, 0, len(args))
+	for _, arg := range args {
+		argTypes = append(argTypes, arg.Type)
+	}
+	return fmt.Sprintf("%s.%s.%s(%s)", database, schemaName, name, strings.Join(argTypes, ","))
+}
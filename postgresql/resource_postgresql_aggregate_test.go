@@ -0,0 +1,118 @@
+package postgresql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccPostgresqlAggregate_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	testConfig := fmt.Sprintf(`
+resource "postgresql_aggregate" "test" {
+	database    = "%s"
+	name        = "test_sum"
+	sfunc       = "int8pl"
+	stype       = "bigint"
+	combinefunc = "int8pl"
+	initcond    = "0"
+	parallel    = "SAFE"
+
+	arg {
+		name = "value"
+		type = "bigint"
+	}
+}
+
+resource "postgresql_aggregate" "variadic" {
+	database  = "%s"
+	name      = "test_ndims"
+	sfunc     = "pg_catalog.array_cat"
+	stype     = "bigint[]"
+	finalfunc = "pg_catalog.array_ndims"
+
+	arg {
+		mode = "VARIADIC"
+		type = "bigint[]"
+	}
+}
+`, dbName, dbName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureAggregate)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlAggregateDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_aggregate.test", "id", dbName+".public.test_sum(bigint)"),
+					resource.TestCheckResourceAttr("postgresql_aggregate.test", "schema", "public"),
+					resource.TestCheckResourceAttr("postgresql_aggregate.test", "sfunc", "int8pl"),
+					resource.TestCheckResourceAttr("postgresql_aggregate.test", "stype", "bigint"),
+					resource.TestCheckResourceAttr("postgresql_aggregate.test", "combinefunc", "int8pl"),
+					resource.TestCheckResourceAttr("postgresql_aggregate.test", "finalfunc", ""),
+					resource.TestCheckResourceAttr("postgresql_aggregate.test", "initcond", "0"),
+					resource.TestCheckResourceAttr("postgresql_aggregate.test", "parallel", "SAFE"),
+					resource.TestCheckResourceAttr("postgresql_aggregate.test", "arg.0.name", "value"),
+					resource.TestCheckResourceAttr("postgresql_aggregate.test", "arg.0.mode", "IN"),
+
+					resource.TestCheckResourceAttr("postgresql_aggregate.variadic", "id", dbName+".public.test_ndims(bigint[])"),
+					resource.TestCheckResourceAttr("postgresql_aggregate.variadic", "finalfunc", "pg_catalog.array_ndims"),
+					resource.TestCheckResourceAttr("postgresql_aggregate.variadic", "initcond", ""),
+					resource.TestCheckResourceAttr("postgresql_aggregate.variadic", "parallel", "UNSAFE"),
+					resource.TestCheckResourceAttr("postgresql_aggregate.variadic", "arg.0.mode", "VARIADIC"),
+				),
+			},
+			{
+				ResourceName:            "postgresql_aggregate.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"drop_cascade"},
+			},
+		},
+	})
+}
+
+func testAccCheckPostgresqlAggregateDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "postgresql_aggregate" {
+			continue
+		}
+
+		database, signature, err := expandFunctionID(rs.Primary.ID, nil, nil)
+		if err != nil {
+			return err
+		}
+
+		txn, err := startTransaction(client, database)
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		var found bool
+		if err := txn.QueryRow("SELECT to_regprocedure($1) IS NOT NULL", signature).Scan(&found); err != nil {
+			return fmt.Errorf("could not check aggregate %s: %w", rs.Primary.ID, err)
+		}
+
+		if found {
+			return fmt.Errorf("Aggregate %s still exists after destroy", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
+)
+
+const (
+	anonMaskedRoleDatabaseAttr = "database"
+	anonMaskedRoleRoleAttr     = "role"
+
+	anonMaskedLabel = "MASKED"
+)
+
+func resourcePostgreSQLAnonymizerMaskedRole() *schema.Resource {
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLAnonymizerMaskedRoleCreate),
+		Read:   PGReadResourceFunc(resourcePostgreSQLAnonymizerMaskedRoleRead),
+		Delete: PGResourceFunc(resourcePostgreSQLAnonymizerMaskedRoleDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			anonMaskedRoleDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The database in which the anon extension is installed. If not specified, the provider default database is used.",
+			},
+			anonMaskedRoleRoleAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The role which sees the masked data",
+			},
+		},
+	}
+}
+
+func resourcePostgreSQLAnonymizerMaskedRoleCreate(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureSecurityLabel) {
+		return fmt.Errorf(
+			"security Label is not supported for this Postgres version (%s)",
+			db.version,
+		)
+	}
+
+	database := getDatabase(d, db.client.databaseName)
+	role := d.Get(anonMaskedRoleRoleAttr).(string)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if err := setAnonymizerMaskedRoleLabel(txn, role, pq.QuoteLiteral(anonMaskedLabel)); err != nil {
+		return err
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId(generateObjectID(database, role))
+
+	return resourcePostgreSQLAnonymizerMaskedRoleReadImpl(db, d)
+}
+
+func resourcePostgreSQLAnonymizerMaskedRoleRead(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLAnonymizerMaskedRoleReadImpl(db, d)
+}
+
+func resourcePostgreSQLAnonymizerMaskedRoleReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	parts, err := parseObjectID(d.Id())
+	if err != nil {
+		return err
+	}
+	if len(parts) != 2 {
+		return fmt.Errorf("anonymizer masked role ID %s has not the expected format 'database.role'", d.Id())
+	}
+	database, role := parts[0], parts[1]
+
+	var label string
+	err = db.QueryRow(`
+SELECT label
+FROM pg_shseclabel
+JOIN pg_roles ON pg_roles.oid = objoid
+WHERE provider = $1 AND classoid = 'pg_authid'::regclass AND rolname = $2
+`, anonLabelProvider, role).Scan(&label)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] PostgreSQL role (%s) is not masked, removing anonymizer masked role from state", role)
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("could not read anonymizer label of role %s: %w", role, err)
+	}
+
+	if !strings.EqualFold(label, anonMaskedLabel) {
+		log.Printf("[WARN] PostgreSQL role (%s) has the anon label %q, removing anonymizer masked role from state", role, label)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set(anonMaskedRoleDatabaseAttr, database)
+	d.Set(anonMaskedRoleRoleAttr, role)
+
+	return nil
+}
+
+func resourcePostgreSQLAnonymizerMaskedRoleDelete(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+	role := d.Get(anonMaskedRoleRoleAttr).(string)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	exists, err := roleExists(txn, role)
+	if err != nil {
+		return err
+	}
+	// The label is removed with the role.
+	if exists {
+		if err := setAnonymizerMaskedRoleLabel(txn, role, "NULL"); err != nil {
+			return err
+		}
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// setAnonymizerMaskedRoleLabel sets the anon security label of the role.
+// The transaction has to be in the database of the extension as anon has to be loaded to register its label provider.
+func setAnonymizerMaskedRoleLabel(txn *sql.Tx, role, label string) error {
+	query := fmt.Sprintf("SECURITY LABEL FOR %s ON ROLE %s IS %s", anonLabelProvider, pq.QuoteIdentifier(role), label)
+	if _, err := txn.Exec(query); err != nil {
+		return fmt.Errorf("could not set anonymizer label of role %s: %w", role, err)
+	}
+	return nil
+}
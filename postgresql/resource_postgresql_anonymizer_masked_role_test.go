@@ -0,0 +1,46 @@
+package postgresql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccPostgresqlAnonymizerMaskedRole_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+	testCheckAnonAvailable(t, dbName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureSecurityLabel)
+			testSuperuserPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "postgresql_anonymizer_masked_role" "test" {
+	database = "%s"
+	role     = "%s"
+}
+`, dbName, roleName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_anonymizer_masked_role.test", "id", fmt.Sprintf("%s.%s", dbName, roleName)),
+					resource.TestCheckResourceAttr("postgresql_anonymizer_masked_role.test", "role", roleName),
+				),
+			},
+			{
+				ResourceName:      "postgresql_anonymizer_masked_role.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
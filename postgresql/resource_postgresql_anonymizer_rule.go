@@ -0,0 +1,238 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
+)
+
+const (
+	anonRuleDatabaseAttr = "database"
+	anonRuleSchemaAttr   = "schema"
+	anonRuleTableAttr    = "table"
+	anonRuleColumnAttr   = "column"
+	anonRuleFunctionAttr = "masking_function"
+	anonRuleValueAttr    = "masking_value"
+
+	// anonLabelProvider is the security label provider registered by the anon extension.
+	anonLabelProvider = "anon"
+)
+
+// anonRuleLabelRegexp parses the masking rules (e.g.: MASKED WITH FUNCTION anon.fake_email()).
+var anonRuleLabelRegexp = regexp.MustCompile(`(?is)^\s*MASKED\s+WITH\s+(FUNCTION|VALUE)\s+(.+?)\s*$`)
+
+func resourcePostgreSQLAnonymizerRule() *schema.Resource {
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLAnonymizerRuleCreate),
+		Read:   PGReadResourceFunc(resourcePostgreSQLAnonymizerRuleRead),
+		Update: PGResourceFunc(resourcePostgreSQLAnonymizerRuleUpdate),
+		Delete: PGResourceFunc(resourcePostgreSQLAnonymizerRuleDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			anonRuleDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The database of the table. If not specified, the provider default database is used.",
+			},
+			anonRuleSchemaAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "public",
+				ForceNew:    true,
+				Description: "The schema of the table",
+			},
+			anonRuleTableAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The table of the masked column",
+			},
+			anonRuleColumnAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The masked column",
+			},
+			anonRuleFunctionAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{anonRuleFunctionAttr, anonRuleValueAttr},
+				Description:  "The masking function call replacing the column values (e.g.: anon.fake_email())",
+			},
+			anonRuleValueAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{anonRuleFunctionAttr, anonRuleValueAttr},
+				Description:  "The SQL value replacing the column values (e.g.: NULL or 'CONFIDENTIAL')",
+			},
+		},
+	}
+}
+
+func resourcePostgreSQLAnonymizerRuleCreate(db *DBConnection, d *schema.ResourceData) error {
+	if !db.featureSupported(featureSecurityLabel) {
+		return fmt.Errorf(
+			"security Label is not supported for this Postgres version (%s)",
+			db.version,
+		)
+	}
+
+	database := getDatabase(d, db.client.databaseName)
+	if err := setAnonymizerRuleLabel(db, d, pq.QuoteLiteral(anonymizerRuleLabel(d))); err != nil {
+		return err
+	}
+
+	d.SetId(generateObjectID(
+		database,
+		d.Get(anonRuleSchemaAttr).(string),
+		d.Get(anonRuleTableAttr).(string),
+		d.Get(anonRuleColumnAttr).(string),
+	))
+
+	return resourcePostgreSQLAnonymizerRuleReadImpl(db, d)
+}
+
+func resourcePostgreSQLAnonymizerRuleRead(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLAnonymizerRuleReadImpl(db, d)
+}
+
+func resourcePostgreSQLAnonymizerRuleReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	parts, err := parseObjectID(d.Id())
+	if err != nil {
+		return err
+	}
+	if len(parts) != 4 {
+		return fmt.Errorf("anonymizer rule ID %s has not the expected format 'database.schema.table.column'", d.Id())
+	}
+	database, schemaName, table, column := parts[0], parts[1], parts[2], parts[3]
+
+	exists, err := dbExists(db, database)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		log.Printf("[WARN] PostgreSQL database (%s) not found, removing anonymizer rule from state", database)
+		d.SetId("")
+		return nil
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	var label string
+	err = txn.QueryRow(`
+SELECT label
+FROM pg_seclabel
+JOIN pg_attribute ON attrelid = objoid AND attnum = objsubid
+WHERE provider = $1 AND classoid = 'pg_class'::regclass
+  AND objoid = to_regclass($2) AND attname = $3
+`, anonLabelProvider, pq.QuoteIdentifier(schemaName)+"."+pq.QuoteIdentifier(table), column).Scan(&label)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] PostgreSQL anonymizer rule (%s) not found, removing it from state", d.Id())
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("could not read anonymizer rule of %s: %w", d.Id(), err)
+	}
+
+	maskingFunction, maskingValue := parseAnonymizerRuleLabel(label)
+
+	d.Set(anonRuleDatabaseAttr, database)
+	d.Set(anonRuleSchemaAttr, schemaName)
+	d.Set(anonRuleTableAttr, table)
+	d.Set(anonRuleColumnAttr, column)
+	d.Set(anonRuleFunctionAttr, maskingFunction)
+	d.Set(anonRuleValueAttr, maskingValue)
+
+	return nil
+}
+
+func resourcePostgreSQLAnonymizerRuleUpdate(db *DBConnection, d *schema.ResourceData) error {
+	if err := setAnonymizerRuleLabel(db, d, pq.QuoteLiteral(anonymizerRuleLabel(d))); err != nil {
+		return err
+	}
+
+	return resourcePostgreSQLAnonymizerRuleReadImpl(db, d)
+}
+
+func resourcePostgreSQLAnonymizerRuleDelete(db *DBConnection, d *schema.ResourceData) error {
+	if err := setAnonymizerRuleLabel(db, d, "NULL"); err != nil {
+		return err
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// setAnonymizerRuleLabel sets the anon security label of the column.
+// label must be quoted or NULL to remove the rule.
+func setAnonymizerRuleLabel(db *DBConnection, d *schema.ResourceData, label string) error {
+	database := getDatabase(d, db.client.databaseName)
+	table := pq.QuoteIdentifier(d.Get(anonRuleSchemaAttr).(string)) + "." + pq.QuoteIdentifier(d.Get(anonRuleTableAttr).(string))
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if label == "NULL" {
+		// The rule is removed with the table.
+		var exists bool
+		if err := txn.QueryRow("SELECT to_regclass($1) IS NOT NULL", table).Scan(&exists); err != nil {
+			return fmt.Errorf("could not check if table %s exists: %w", table, err)
+		}
+		if !exists {
+			return nil
+		}
+	}
+
+	query := fmt.Sprintf(
+		"SECURITY LABEL FOR %s ON COLUMN %s.%s IS %s",
+		anonLabelProvider, table, pq.QuoteIdentifier(d.Get(anonRuleColumnAttr).(string)), label,
+	)
+	if _, err := txn.Exec(query); err != nil {
+		return fmt.Errorf("could not set anonymizer rule on %s: %w", table, err)
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func anonymizerRuleLabel(d *schema.ResourceData) string {
+	if maskingFunction := d.Get(anonRuleFunctionAttr).(string); maskingFunction != "" {
+		return "MASKED WITH FUNCTION " + maskingFunction
+	}
+	return "MASKED WITH VALUE " + d.Get(anonRuleValueAttr).(string)
+}
+
+// parseAnonymizerRuleLabel returns the masking function or the masking value of the label.
+func parseAnonymizerRuleLabel(label string) (string, string) {
+	matches := anonRuleLabelRegexp.FindStringSubmatch(label)
+	if matches == nil {
+		log.Printf("[WARN] unexpected anonymizer rule: %s", label)
+		return "", ""
+	}
+	if strings.ToUpper(matches[1]) == "FUNCTION" {
+		return matches[2], ""
+	}
+	return "", matches[2]
+}
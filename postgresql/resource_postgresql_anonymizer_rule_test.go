@@ -0,0 +1,106 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAnonymizerRuleLabel(t *testing.T) {
+	cases := []struct {
+		label           string
+		maskingFunction string
+		maskingValue    string
+	}{
+		{label: "MASKED WITH FUNCTION anon.fake_email()", maskingFunction: "anon.fake_email()"},
+		{label: "masked with function anon.partial(phone, 2, $$******$$, 2)", maskingFunction: "anon.partial(phone, 2, $$******$$, 2)"},
+		{label: "MASKED WITH VALUE NULL", maskingValue: "NULL"},
+		{label: "MASKED WITH VALUE 'CONFIDENTIAL' ", maskingValue: "'CONFIDENTIAL'"},
+		{label: "NOT MASKED"},
+	}
+
+	for _, c := range cases {
+		maskingFunction, maskingValue := parseAnonymizerRuleLabel(c.label)
+		assert.Equal(t, c.maskingFunction, maskingFunction, c.label)
+		assert.Equal(t, c.maskingValue, maskingValue, c.label)
+	}
+}
+
+func TestAccPostgresqlAnonymizerRule_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	testTables := []string{"test_schema.test_table"}
+	createTestTables(t, dbSuffix, testTables, "")
+
+	dbName, _ := getTestDBNames(dbSuffix)
+	testCheckAnonAvailable(t, dbName)
+
+	testConfig := `
+resource "postgresql_anonymizer_rule" "test" {
+	database = "%s"
+	schema   = "test_schema"
+	table    = "test_table"
+	column   = "test_column_one"
+	%s
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureSecurityLabel)
+			testSuperuserPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testConfig, dbName, `masking_function = "anon.fake_last_name()"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_anonymizer_rule.test", "id", dbName+".test_schema.test_table.test_column_one"),
+					resource.TestCheckResourceAttr("postgresql_anonymizer_rule.test", "masking_function", "anon.fake_last_name()"),
+					resource.TestCheckResourceAttr("postgresql_anonymizer_rule.test", "masking_value", ""),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testConfig, dbName, `masking_value = "'CONFIDENTIAL'"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_anonymizer_rule.test", "masking_function", ""),
+					resource.TestCheckResourceAttr("postgresql_anonymizer_rule.test", "masking_value", "'CONFIDENTIAL'"),
+				),
+			},
+			{
+				ResourceName:      "postgresql_anonymizer_rule.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// testCheckAnonAvailable skips the test if the anon extension can't be created in the database.
+func testCheckAnonAvailable(t *testing.T, dbName string) {
+	config := getTestConfig(t)
+	db, err := sql.Open("postgres", config.connStr(dbName))
+	if err != nil {
+		t.Fatalf("could not open connection pool for db %s: %v", dbName, err)
+	}
+	defer db.Close()
+
+	var available bool
+	if err := db.QueryRow("SELECT count(*) > 0 FROM pg_available_extensions WHERE name = 'anon'").Scan(&available); err != nil {
+		t.Fatalf("could not check if anon is available: %v", err)
+	}
+	if !available {
+		t.Skip("Skip test: the anon extension is not available")
+	}
+
+	if _, err := db.Exec("CREATE EXTENSION IF NOT EXISTS anon CASCADE"); err != nil {
+		t.Skipf("Skip test: could not create the anon extension: %v", err)
+	}
+}
@@ -0,0 +1,282 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
+)
+
+const (
+	conversionDatabaseAttr    = "database"
+	conversionSchemaAttr      = "schema"
+	conversionNameAttr        = "name"
+	conversionSourceAttr      = "source_encoding"
+	conversionDestinationAttr = "destination_encoding"
+	conversionFunctionAttr    = "function"
+	conversionDefaultAttr     = "default"
+	conversionOwnerAttr       = "owner"
+)
+
+func resourcePostgreSQLConversion() *schema.Resource {
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLConversionCreate),
+		Read:   PGReadResourceFunc(resourcePostgreSQLConversionRead),
+		Update: PGResourceFunc(resourcePostgreSQLConversionUpdate),
+		Delete: PGResourceFunc(resourcePostgreSQLConversionDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			conversionDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The database of the conversion. If not specified, the provider default database is used.",
+			},
+			conversionSchemaAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "public",
+				ForceNew:    true,
+				Description: "The schema of the conversion",
+			},
+			conversionNameAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the conversion",
+			},
+			conversionSourceAttr: {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: diffSuppressEncoding,
+				Description:      "The source encoding (e.g.: LATIN1)",
+			},
+			conversionDestinationAttr: {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: diffSuppressEncoding,
+				Description:      "The destination encoding (e.g.: UTF8)",
+			},
+			conversionFunctionAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The function performing the conversion, optionally qualified with its schema",
+			},
+			conversionDefaultAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				ForceNew:    true,
+				Description: "If the conversion is the default one from the source encoding to the destination encoding",
+			},
+			conversionOwnerAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The role owning the conversion",
+			},
+		},
+	}
+}
+
+// diffSuppressEncoding suppresses the diff if the encodings only differ by their case
+// as PostgreSQL reads them back in upper case (e.g.: utf8 is read as UTF8).
+func diffSuppressEncoding(_, old, new string, _ *schema.ResourceData) bool {
+	return strings.EqualFold(old, new)
+}
+
+func resourcePostgreSQLConversionCreate(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(conversionSchemaAttr).(string)
+	name := d.Get(conversionNameAttr).(string)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	defaultKeyword := ""
+	if d.Get(conversionDefaultAttr).(bool) {
+		defaultKeyword = "DEFAULT "
+	}
+	// The function can be qualified with its schema, it's sent as is.
+	query := fmt.Sprintf(
+		"CREATE %sCONVERSION %s.%s FOR %s TO %s FROM %s",
+		defaultKeyword,
+		pq.QuoteIdentifier(schemaName),
+		pq.QuoteIdentifier(name),
+		pq.QuoteLiteral(d.Get(conversionSourceAttr).(string)),
+		pq.QuoteLiteral(d.Get(conversionDestinationAttr).(string)),
+		d.Get(conversionFunctionAttr).(string),
+	)
+	if _, err := txn.Exec(query); err != nil {
+		return fmt.Errorf("could not create conversion %s: %w", name, err)
+	}
+
+	if owner, ok := d.GetOk(conversionOwnerAttr); ok {
+		if err := setConversionOwner(txn, schemaName, name, owner.(string)); err != nil {
+			return err
+		}
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId(generateObjectID(database, schemaName, name))
+
+	return resourcePostgreSQLConversionReadImpl(db, d)
+}
+
+func resourcePostgreSQLConversionRead(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLConversionReadImpl(db, d)
+}
+
+func resourcePostgreSQLConversionReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	parts, err := parseObjectID(d.Id())
+	if err != nil {
+		return err
+	}
+	if len(parts) != 3 {
+		return fmt.Errorf("conversion ID %s has not the expected format 'database.schema.name'", d.Id())
+	}
+	database, schemaName, name := parts[0], parts[1], parts[2]
+
+	exists, err := dbExists(db, database)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		log.Printf("[WARN] PostgreSQL database (%s) not found, removing conversion from state", database)
+		d.SetId("")
+		return nil
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	// The configured function is kept if it's the function of the conversion,
+	// as it can be qualified with its schema or not.
+	var source, destination, function, owner string
+	var isDefault, sameFunction bool
+	err = txn.QueryRow(`
+SELECT pg_encoding_to_char(c.conforencoding), pg_encoding_to_char(c.contoencoding),
+	c.conproc::regproc::text, COALESCE(to_regproc(NULLIF($3, '')) = c.conproc, false),
+	c.condefault, pg_get_userbyid(c.conowner)
+FROM pg_catalog.pg_conversion c
+JOIN pg_catalog.pg_namespace n ON n.oid = c.connamespace
+WHERE n.nspname = $1 AND c.conname = $2
+`, schemaName, name, d.Get(conversionFunctionAttr).(string)).Scan(&source, &destination, &function, &sameFunction, &isDefault, &owner)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] PostgreSQL conversion (%s) not found, removing it from state", d.Id())
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("could not read conversion %s: %w", d.Id(), err)
+	}
+
+	if sameFunction {
+		function = d.Get(conversionFunctionAttr).(string)
+	}
+
+	d.Set(conversionDatabaseAttr, database)
+	d.Set(conversionSchemaAttr, schemaName)
+	d.Set(conversionNameAttr, name)
+	d.Set(conversionSourceAttr, source)
+	d.Set(conversionDestinationAttr, destination)
+	d.Set(conversionFunctionAttr, function)
+	d.Set(conversionDefaultAttr, isDefault)
+	d.Set(conversionOwnerAttr, owner)
+
+	return nil
+}
+
+func resourcePostgreSQLConversionUpdate(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(conversionSchemaAttr).(string)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	oldName, newName := d.GetChange(conversionNameAttr)
+	name := oldName.(string)
+	if d.HasChange(conversionNameAttr) {
+		if _, err := txn.Exec(fmt.Sprintf(
+			"ALTER CONVERSION %s.%s RENAME TO %s",
+			pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(name), pq.QuoteIdentifier(newName.(string)),
+		)); err != nil {
+			return fmt.Errorf("could not rename conversion %s: %w", name, err)
+		}
+		name = newName.(string)
+	}
+
+	if d.HasChange(conversionOwnerAttr) {
+		if err := setConversionOwner(txn, schemaName, name, d.Get(conversionOwnerAttr).(string)); err != nil {
+			return err
+		}
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId(generateObjectID(database, schemaName, name))
+
+	return resourcePostgreSQLConversionReadImpl(db, d)
+}
+
+func resourcePostgreSQLConversionDelete(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	name := d.Get(conversionNameAttr).(string)
+	if _, err := txn.Exec(fmt.Sprintf(
+		"DROP CONVERSION IF EXISTS %s.%s",
+		pq.QuoteIdentifier(d.Get(conversionSchemaAttr).(string)), pq.QuoteIdentifier(name),
+	)); err != nil {
+		return fmt.Errorf("could not drop conversion %s: %w", name, err)
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// setConversionOwner changes the owner of the conversion. The connected user needs to be a member of the new owner.
+func setConversionOwner(txn *sql.Tx, schemaName, name, owner string) error {
+	return withRolesGranted(txn, []string{owner}, func() error {
+		if _, err := txn.Exec(fmt.Sprintf(
+			"ALTER CONVERSION %s.%s OWNER TO %s",
+			pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(name), pq.QuoteIdentifier(owner),
+		)); err != nil {
+			return fmt.Errorf("could not set owner of conversion %s: %w", name, err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,100 @@
+package postgresql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccPostgresqlConversion_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+
+	testConfig := `
+resource "postgresql_conversion" "test" {
+	database             = "%s"
+	name                 = "%s"
+	source_encoding      = "utf8"
+	destination_encoding = "LATIN1"
+	function             = "pg_catalog.utf8_to_iso8859_1"
+	%s
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testSuperuserPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlConversionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testConfig, dbName, "test_conversion", ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_conversion.test", "id", dbName+".public.test_conversion"),
+					resource.TestCheckResourceAttr("postgresql_conversion.test", "schema", "public"),
+					resource.TestCheckResourceAttr("postgresql_conversion.test", "source_encoding", "UTF8"),
+					resource.TestCheckResourceAttr("postgresql_conversion.test", "destination_encoding", "LATIN1"),
+					resource.TestCheckResourceAttr("postgresql_conversion.test", "function", "pg_catalog.utf8_to_iso8859_1"),
+					resource.TestCheckResourceAttr("postgresql_conversion.test", "default", "false"),
+					resource.TestCheckResourceAttrSet("postgresql_conversion.test", "owner"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testConfig, dbName, "test_conversion_renamed", fmt.Sprintf(`owner = "%s"`, roleName)),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_conversion.test", "id", dbName+".public.test_conversion_renamed"),
+					resource.TestCheckResourceAttr("postgresql_conversion.test", "owner", roleName),
+				),
+			},
+			{
+				ResourceName:            "postgresql_conversion.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"function"},
+			},
+		},
+	})
+}
+
+func testAccCheckPostgresqlConversionDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "postgresql_conversion" {
+			continue
+		}
+
+		parts, err := parseObjectID(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		txn, err := startTransaction(client, parts[0])
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		var found bool
+		if err := txn.QueryRow(
+			"SELECT EXISTS (SELECT 1 FROM pg_catalog.pg_conversion c JOIN pg_catalog.pg_namespace n ON n.oid = c.connamespace WHERE n.nspname = $1 AND c.conname = $2)",
+			parts[1], parts[2],
+		).Scan(&found); err != nil {
+			return fmt.Errorf("could not check conversion %s: %w", rs.Primary.ID, err)
+		}
+
+		if found {
+			return fmt.Errorf("Conversion %s still exists after destroy", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
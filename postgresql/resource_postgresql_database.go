@@ -2,10 +2,12 @@ package postgresql
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -20,23 +22,45 @@ const (
 	dbConnLimitAttr        = "connection_limit"
 	dbEncodingAttr         = "encoding"
 	dbIsTemplateAttr       = "is_template"
+	dbLCMessagesAttr       = "lc_messages"
+	dbLCMonetaryAttr       = "lc_monetary"
+	dbLCNumericAttr        = "lc_numeric"
+	dbLCTimeAttr           = "lc_time"
 	dbNameAttr             = "name"
 	dbOwnerAttr            = "owner"
+	dbOwnerOIDAttr         = "owner_oid"
 	dbTablespaceAttr       = "tablespace_name"
 	dbTemplateAttr         = "template"
+	dbLocaleProviderAttr   = "locale_provider"
+	dbICULocaleAttr        = "icu_locale"
+	dbICURulesAttr         = "icu_rules"
+	dbCollationVersionAttr = "collation_version"
 	dbAlterObjectOwnership = "alter_object_ownership"
+	dbExtensionsAttr       = "extensions"
+	dbForceRenameAttr      = "force_rename"
+	dbForceDropAttr        = "force_drop"
 )
 
+// dbLocaleSettingsAttrs are the locale categories which can be changed after the database creation
+// with ALTER DATABASE SET. The attribute names are the names of the configuration parameters.
+var dbLocaleSettingsAttrs = []string{
+	dbLCMessagesAttr,
+	dbLCMonetaryAttr,
+	dbLCNumericAttr,
+	dbLCTimeAttr,
+}
+
 func resourcePostgreSQLDatabase() *schema.Resource {
 	return &schema.Resource{
 		Create: PGResourceFunc(resourcePostgreSQLDatabaseCreate),
-		Read:   PGResourceFunc(resourcePostgreSQLDatabaseRead),
+		Read:   PGReadResourceFunc(resourcePostgreSQLDatabaseRead),
 		Update: PGResourceFunc(resourcePostgreSQLDatabaseUpdate),
 		Delete: PGResourceFunc(resourcePostgreSQLDatabaseDelete),
 		Exists: PGResourceExistsFunc(resourcePostgreSQLDatabaseExists),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		CustomizeDiff: resourcePostgreSQLDatabaseCustomizeDiff,
 
 		Schema: map[string]*schema.Schema{
 			dbNameAttr: {
@@ -50,6 +74,11 @@ func resourcePostgreSQLDatabase() *schema.Resource {
 				Computed:    true,
 				Description: "The ROLE which owns the database",
 			},
+			dbOwnerOIDAttr: {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The OID of the ROLE which owns the database",
+			},
 			dbTemplateAttr: {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -78,6 +107,54 @@ func resourcePostgreSQLDatabase() *schema.Resource {
 				ForceNew:    true,
 				Description: "Character classification (LC_CTYPE) to use in the new database",
 			},
+			dbLocaleProviderAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"libc", "icu"}, false),
+				Description:  "The locale provider (LOCALE_PROVIDER) to use in the new database: libc or icu",
+			},
+			dbICULocaleAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The ICU locale (ICU_LOCALE) to use in the new database if the locale provider is icu",
+			},
+			dbICURulesAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "Additional collation rules (ICU_RULES) of the default collation of the new database if the locale provider is icu",
+			},
+			dbCollationVersionAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The version of the default collation recorded in the database (COLLATION_VERSION). A change refreshes it to the version of the collation library",
+			},
+			dbLCMessagesAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Language in which messages are displayed (LC_MESSAGES) in this database",
+			},
+			dbLCMonetaryAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Locale to use for formatting monetary amounts (LC_MONETARY) in this database",
+			},
+			dbLCNumericAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Locale to use for formatting numbers (LC_NUMERIC) in this database",
+			},
+			dbLCTimeAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Locale to use for formatting dates and times (LC_TIME) in this database",
+			},
 			dbTablespaceAttr: {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -109,6 +186,25 @@ func resourcePostgreSQLDatabase() *schema.Resource {
 				Default:     false,
 				Description: "If true, the owner of already existing objects will change if the owner changes",
 			},
+			dbForceRenameAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If true, the connections to the database are terminated to rename it",
+			},
+			dbForceDropAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "If true, the connections to the database are terminated to drop it",
+			},
+			dbExtensionsAttr: {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "The extensions to create in the database after its creation",
+			},
 		},
 	}
 }
@@ -120,12 +216,20 @@ func resourcePostgreSQLDatabaseCreate(db *DBConnection, d *schema.ResourceData)
 
 	d.SetId(d.Get(dbNameAttr).(string))
 
+	if err := setDBLocaleSettings(db, d); err != nil {
+		return err
+	}
+
+	if err := setDBExtensions(db, d); err != nil {
+		return err
+	}
+
 	return resourcePostgreSQLDatabaseReadImpl(db, d)
 }
 
 func createDatabase(db *DBConnection, d *schema.ResourceData) error {
 	currentUser := db.client.config.getDatabaseUsername()
-	owner := d.Get(dbOwnerAttr).(string)
+	owner := getObjectOwner(d, dbOwnerAttr, db.client.config)
 
 	var err error
 	if owner != "" {
@@ -153,15 +257,19 @@ func createDatabase(db *DBConnection, d *schema.ResourceData) error {
 		}
 	}
 
+	if err := checkDBLocaleProviderSupport(db, d); err != nil {
+		return err
+	}
+
 	dbName := d.Get(dbNameAttr).(string)
 	b := bytes.NewBufferString("CREATE DATABASE ")
 	fmt.Fprint(b, pq.QuoteIdentifier(dbName))
 
 	// Handle each option individually and stream results into the query
 	// buffer.
-	switch v, ok := d.GetOk(dbOwnerAttr); {
-	case ok:
-		fmt.Fprint(b, " OWNER ", pq.QuoteIdentifier(v.(string)))
+	switch {
+	case owner != "":
+		fmt.Fprint(b, " OWNER ", pq.QuoteIdentifier(owner))
 	default:
 		// No owner specified in the config, default to using
 		// the connecting username.
@@ -204,6 +312,19 @@ func createDatabase(db *DBConnection, d *schema.ResourceData) error {
 		fmt.Fprintf(b, " LC_CTYPE '%s' ", pqQuoteLiteral(v.(string)))
 	}
 
+	// The locale provider, ICU locale and rules and the collation version are only specified if set by the user,
+	// the default ones are the ones of the template database.
+	for _, option := range []struct{ attr, keyword string }{
+		{dbLocaleProviderAttr, "LOCALE_PROVIDER"},
+		{dbICULocaleAttr, "ICU_LOCALE"},
+		{dbICURulesAttr, "ICU_RULES"},
+		{dbCollationVersionAttr, "COLLATION_VERSION"},
+	} {
+		if v, ok := d.GetOk(option.attr); ok {
+			fmt.Fprintf(b, " %s '%s'", option.keyword, pqQuoteLiteral(v.(string)))
+		}
+	}
+
 	switch v, ok := d.GetOk(dbTablespaceAttr); {
 	case ok && strings.ToUpper(v.(string)) == "DEFAULT":
 		fmt.Fprint(b, " TABLESPACE DEFAULT")
@@ -227,7 +348,7 @@ func createDatabase(db *DBConnection, d *schema.ResourceData) error {
 	}
 
 	sql := b.String()
-	if _, err := db.Exec(sql); err != nil {
+	if _, err := execWithStatementTimeout(db, sql); err != nil {
 		return fmt.Errorf("Error creating database %q: %w", dbName, err)
 	}
 
@@ -277,18 +398,26 @@ func resourcePostgreSQLDatabaseDelete(db *DBConnection, d *schema.ResourceData)
 		return err
 	}
 
-	// Terminate all active connections and block new one
-	if err := terminateBConnections(db, dbName); err != nil {
-		return err
-	}
+	// Close the connections of the provider then, if forced, terminate all active connections and block new one
+	db.client.closeDatabaseConnections(dbName)
+	force := d.Get(dbForceDropAttr).(bool)
+	if force {
+		if err := terminateBConnections(db, dbName); err != nil {
+			return err
+		}
 
-	// Drop with force only for psql 13+
-	if db.featureSupported(featureForceDropDatabase) {
-		dropWithForce = "WITH ( FORCE )"
+		// Drop with force only for psql 13+
+		if db.featureSupported(featureForceDropDatabase) {
+			dropWithForce = "WITH ( FORCE )"
+		}
 	}
 
 	sql := fmt.Sprintf("DROP DATABASE %s %s", pq.QuoteIdentifier(dbName), dropWithForce)
-	if _, err := db.Exec(sql); err != nil {
+	if _, err := execWithStatementTimeout(db, sql); err != nil {
+		var pqErr *pq.Error
+		if !force && errors.As(err, &pqErr) && pqErr.Code == "55006" {
+			return fmt.Errorf("Error dropping database, set %s to terminate the connections to the database: %w", dbForceDropAttr, err)
+		}
 		return fmt.Errorf("Error dropping database: %w", err)
 	}
 
@@ -314,15 +443,36 @@ func resourcePostgreSQLDatabaseRead(db *DBConnection, d *schema.ResourceData) er
 
 func resourcePostgreSQLDatabaseReadImpl(db *DBConnection, d *schema.ResourceData) error {
 	dbId := d.Id()
-	var dbName, ownerName string
-	err := db.QueryRow("SELECT d.datname, pg_catalog.pg_get_userbyid(d.datdba) from pg_database d WHERE datname=$1", dbId).Scan(&dbName, &ownerName)
-	switch {
-	case err == sql.ErrNoRows:
+	found, err := readDatabaseProperties(db, d, dbId)
+	if err != nil {
+		return err
+	}
+	if !found {
 		log.Printf("[WARN] PostgreSQL database (%q) not found", dbId)
 		d.SetId("")
 		return nil
+	}
+
+	dbTemplate := d.Get(dbTemplateAttr).(string)
+	if dbTemplate == "" {
+		dbTemplate = "template0"
+	}
+	d.Set(dbTemplateAttr, dbTemplate)
+
+	return nil
+}
+
+// readDatabaseProperties sets the properties of the database in the resource or data source.
+// It returns false if the database does not exist.
+func readDatabaseProperties(db *DBConnection, d *schema.ResourceData, dbId string) (bool, error) {
+	var dbName, ownerName string
+	var ownerOID int
+	err := db.QueryRow("SELECT d.datname, pg_catalog.pg_get_userbyid(d.datdba), d.datdba::bigint from pg_database d WHERE datname=$1", dbId).Scan(&dbName, &ownerName, &ownerOID)
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
 	case err != nil:
-		return fmt.Errorf("Error reading database: %w", err)
+		return false, fmt.Errorf("Error reading database: %w", err)
 	}
 
 	var dbEncoding, dbCollation, dbCType, dbTablespaceName string
@@ -350,32 +500,26 @@ func resourcePostgreSQLDatabaseReadImpl(db *DBConnection, d *schema.ResourceData
 		)
 	switch {
 	case err == sql.ErrNoRows:
-		log.Printf("[WARN] PostgreSQL database (%q) not found", dbId)
-		d.SetId("")
-		return nil
+		return false, nil
 	case err != nil:
-		return fmt.Errorf("Error reading database: %w", err)
+		return false, fmt.Errorf("Error reading database: %w", err)
 	}
 
 	d.Set(dbNameAttr, dbName)
 	d.Set(dbOwnerAttr, ownerName)
+	d.Set(dbOwnerOIDAttr, ownerOID)
 	d.Set(dbEncodingAttr, dbEncoding)
 	d.Set(dbCollationAttr, dbCollation)
 	d.Set(dbCTypeAttr, dbCType)
 	d.Set(dbTablespaceAttr, dbTablespaceName)
 	d.Set(dbConnLimitAttr, dbConnLimit)
-	dbTemplate := d.Get(dbTemplateAttr).(string)
-	if dbTemplate == "" {
-		dbTemplate = "template0"
-	}
-	d.Set(dbTemplateAttr, dbTemplate)
 
 	if db.featureSupported(featureDBAllowConnections) {
 		var dbAllowConns bool
 		dbSQL := fmt.Sprintf(dbSQLFmt, "d.datallowconn")
 		err = db.QueryRow(dbSQL, dbId).Scan(&dbAllowConns)
 		if err != nil {
-			return fmt.Errorf("Error reading ALLOW_CONNECTIONS property for DATABASE: %w", err)
+			return false, fmt.Errorf("Error reading ALLOW_CONNECTIONS property for DATABASE: %w", err)
 		}
 
 		d.Set(dbAllowConnsAttr, dbAllowConns)
@@ -386,12 +530,262 @@ func resourcePostgreSQLDatabaseReadImpl(db *DBConnection, d *schema.ResourceData
 		dbSQL := fmt.Sprintf(dbSQLFmt, "d.datistemplate")
 		err = db.QueryRow(dbSQL, dbId).Scan(&dbIsTemplate)
 		if err != nil {
-			return fmt.Errorf("Error reading IS_TEMPLATE property for DATABASE: %w", err)
+			return false, fmt.Errorf("Error reading IS_TEMPLATE property for DATABASE: %w", err)
 		}
 
 		d.Set(dbIsTemplateAttr, dbIsTemplate)
 	}
 
+	if db.featureSupported(featureDBLocaleProvider) {
+		if err := readDBLocaleProvider(db, d, dbId); err != nil {
+			return false, err
+		}
+	}
+
+	return true, readDBLocaleSettings(db, d, dbId)
+}
+
+// dbLocaleProviders are the locale providers by their code in pg_database.datlocprovider.
+var dbLocaleProviders = map[string]string{
+	"c": "libc",
+	"i": "icu",
+	"b": "builtin",
+}
+
+// readDBLocaleProvider reads the locale provider, the ICU locale and rules and the collation version of the database.
+func readDBLocaleProvider(db *DBConnection, d *schema.ResourceData, dbName string) error {
+	// daticulocale has been renamed datlocale in PostgreSQL 17 as it's also the locale of the builtin provider.
+	localeColumn := "d.daticulocale"
+	if db.featureSupported(featureDBLocale) {
+		localeColumn = "d.datlocale"
+	}
+	rulesColumn := "NULL"
+	if db.featureSupported(featureDBICURules) {
+		rulesColumn = "d.daticurules"
+	}
+
+	var localeProvider, locale, rules, collationVersion string
+	err := db.QueryRow(fmt.Sprintf(
+		"SELECT d.datlocprovider::TEXT, COALESCE(%s, ''), COALESCE(%s, ''), COALESCE(d.datcollversion, '') "+
+			"FROM pg_catalog.pg_database AS d WHERE d.datname = $1",
+		localeColumn, rulesColumn,
+	), dbName).Scan(&localeProvider, &locale, &rules, &collationVersion)
+	if err != nil {
+		return fmt.Errorf("Error reading locale provider of database: %w", err)
+	}
+
+	if localeProvider = dbLocaleProviders[localeProvider]; localeProvider != "icu" {
+		locale = ""
+	}
+
+	d.Set(dbLocaleProviderAttr, localeProvider)
+	d.Set(dbICULocaleAttr, locale)
+	d.Set(dbICURulesAttr, rules)
+	d.Set(dbCollationVersionAttr, collationVersion)
+
+	return nil
+}
+
+// checkDBLocaleProviderSupport fails if the locale provider attributes are set but not supported by the server.
+func checkDBLocaleProviderSupport(db *DBConnection, d *schema.ResourceData) error {
+	for _, attr := range []string{dbLocaleProviderAttr, dbICULocaleAttr, dbCollationVersionAttr} {
+		if _, ok := d.GetOk(attr); ok && !db.featureSupported(featureDBLocaleProvider) {
+			return fmt.Errorf("PostgreSQL client is talking with a server (%q) that does not support database %s", db.version.String(), strings.ToUpper(attr))
+		}
+	}
+	if _, ok := d.GetOk(dbICURulesAttr); ok && !db.featureSupported(featureDBICURules) {
+		return fmt.Errorf("PostgreSQL client is talking with a server (%q) that does not support database ICU_RULES", db.version.String())
+	}
+	return nil
+}
+
+// dbLocale is the encoding and the locale categories fixed at the creation of a database.
+type dbLocale struct {
+	encoding string
+	collate  string
+	ctype    string
+}
+
+// resourcePostgreSQLDatabaseCustomizeDiff fails the plan if the database is created with an encoding
+// and locales which are incompatible with each other or with the template database, rather than
+// failing the CREATE DATABASE when applying.
+// The template may not exist yet when planning (e.g.: it's created by the same apply), the check
+// is skipped in this case.
+func resourcePostgreSQLDatabaseCustomizeDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	attrs := []string{dbTemplateAttr, dbEncodingAttr, dbCollationAttr, dbCTypeAttr}
+	// These attributes force the recreation of the database.
+	if d.Id() != "" && !d.HasChanges(attrs...) {
+		return nil
+	}
+	for _, attr := range attrs {
+		if !d.NewValueKnown(attr) {
+			return nil
+		}
+	}
+
+	// The defaults are the ones of createDatabase.
+	template := d.Get(dbTemplateAttr).(string)
+	switch {
+	case template == "":
+		template = "template0"
+	case strings.ToUpper(template) == "DEFAULT":
+		template = "template1"
+	}
+	requested := dbLocale{
+		encoding: d.Get(dbEncodingAttr).(string),
+		collate:  d.Get(dbCollationAttr).(string),
+		ctype:    d.Get(dbCTypeAttr).(string),
+	}
+	if requested.encoding == "" {
+		requested.encoding = "UTF8"
+	}
+
+	endpoint, _ := d.Get(endpointAttr).(string)
+	client, err := meta.(*Client).endpointClient(endpoint)
+	if err != nil {
+		return err
+	}
+	db, err := client.readOnlyClient().Connect()
+	if err != nil {
+		log.Printf("[DEBUG] could not connect to check the locale of database %s when planning, checking it when applying: %v", d.Get(dbNameAttr), err)
+		return nil
+	}
+
+	return checkDBLocale(db, template, requested)
+}
+
+// checkDBLocale checks the encoding and the locales requested for a new database, the ones which are
+// not set or set to DEFAULT are the ones of the template.
+func checkDBLocale(db QueryAble, template string, requested dbLocale) error {
+	var templateLocale dbLocale
+	err := db.QueryRow(
+		"SELECT pg_catalog.pg_encoding_to_char(encoding), datcollate, datctype FROM pg_catalog.pg_database WHERE datname = $1",
+		template,
+	).Scan(&templateLocale.encoding, &templateLocale.collate, &templateLocale.ctype)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[DEBUG] template database %s not found when planning, checking the locale of the new database when applying", template)
+		return nil
+	case err != nil:
+		return fmt.Errorf("could not read the locale of template database %s: %w", template, err)
+	}
+
+	if requested.encoding == "" || strings.ToUpper(requested.encoding) == "DEFAULT" {
+		requested.encoding = templateLocale.encoding
+	} else {
+		// Normalize the name of the encoding, PostgreSQL accepts aliases (e.g.: UNICODE, utf-8)
+		// and encoding numbers.
+		query := "SELECT pg_catalog.pg_encoding_to_char(pg_catalog.pg_char_to_encoding($1))"
+		if _, err := strconv.Atoi(requested.encoding); err == nil {
+			query = "SELECT pg_catalog.pg_encoding_to_char($1::INTEGER)"
+		}
+		var encoding string
+		if err := db.QueryRow(query, requested.encoding).Scan(&encoding); err != nil {
+			return fmt.Errorf("could not read encoding %s: %w", requested.encoding, err)
+		}
+		if encoding == "" {
+			return fmt.Errorf("%q is not a valid encoding name", requested.encoding)
+		}
+		requested.encoding = encoding
+	}
+	if requested.collate == "" || strings.ToUpper(requested.collate) == "DEFAULT" {
+		requested.collate = templateLocale.collate
+	}
+	if requested.ctype == "" || strings.ToUpper(requested.ctype) == "DEFAULT" {
+		requested.ctype = templateLocale.ctype
+	}
+
+	for _, locale := range []struct{ attr, name string }{
+		{dbCollationAttr, requested.collate},
+		{dbCTypeAttr, requested.ctype},
+	} {
+		encodings, err := readLocaleEncodings(db, locale.name)
+		if err != nil {
+			return err
+		}
+		if err := checkLocaleEncoding(requested.encoding, locale.attr, locale.name, encodings); err != nil {
+			return err
+		}
+	}
+
+	return checkDBTemplateLocale(template, requested, templateLocale)
+}
+
+// readLocaleEncodings returns the encodings of the libc collations of the locale, in pg_collation.
+// The locales are imported in pg_collation by initdb, so the list is empty for the locales
+// installed afterwards.
+func readLocaleEncodings(db QueryAble, locale string) ([]string, error) {
+	// The codeset of the locale name is compared case insensitively and without the hyphens,
+	// as by setlocale (e.g.: en_US.UTF-8 is imported as en_US.utf8).
+	rows, err := db.Query(`
+SELECT DISTINCT pg_catalog.pg_encoding_to_char(collencoding)
+FROM pg_catalog.pg_collation
+WHERE collencoding <> -1 AND lower(replace(collcollate, '-', '')) = lower(replace($1, '-', ''))
+`, locale)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the collations of locale %s: %w", locale, err)
+	}
+	defer rows.Close()
+
+	var encodings []string
+	for rows.Next() {
+		var encoding string
+		if err := rows.Scan(&encoding); err != nil {
+			return nil, fmt.Errorf("could not scan the collations of locale %s: %w", locale, err)
+		}
+		encodings = append(encodings, encoding)
+	}
+	return encodings, rows.Err()
+}
+
+// checkLocaleEncoding checks that the encoding can be used with the locale of a database,
+// the encodings of the locale come from readLocaleEncodings.
+func checkLocaleEncoding(encoding, attr, locale string, localeEncodings []string) error {
+	// The C and POSIX locales can be used with any encoding, as can SQL_ASCII with any locale.
+	if locale == "C" || locale == "POSIX" || encoding == "SQL_ASCII" || len(localeEncodings) == 0 {
+		return nil
+	}
+	if sliceContainsStr(localeEncodings, encoding) {
+		return nil
+	}
+	return fmt.Errorf(
+		"encoding %s does not match locale %q of %s, which requires encoding %s: set the encoding accordingly or choose a locale for the encoding %s",
+		encoding, locale, attr, strings.Join(localeEncodings, " or "), encoding,
+	)
+}
+
+// checkDBTemplateLocale checks that a database can be created from the template with the encoding and the locales.
+// PostgreSQL only allows to change them when copying template0, as the other templates may contain
+// data or indexes depending on them.
+func checkDBTemplateLocale(template string, requested, templateLocale dbLocale) error {
+	if template == "template0" {
+		return nil
+	}
+	for _, setting := range []struct{ attr, requested, template string }{
+		{dbEncodingAttr, requested.encoding, templateLocale.encoding},
+		{dbCollationAttr, requested.collate, templateLocale.collate},
+		{dbCTypeAttr, requested.ctype, templateLocale.ctype},
+	} {
+		if setting.requested != setting.template {
+			return fmt.Errorf(
+				"new %s (%s) is incompatible with the %s of template database %s (%s): use the same %s as the template database, or set template to template0",
+				setting.attr, setting.requested, setting.attr, template, setting.template, setting.attr,
+			)
+		}
+	}
+	return nil
+}
+
+func readDBLocaleSettings(db QueryAble, d *schema.ResourceData, dbName string) error {
+	settings, err := readDBRoleSettings(db, "", dbName)
+	if err != nil {
+		return fmt.Errorf("Error reading database settings: %w", err)
+	}
+
+	for _, attr := range dbLocaleSettingsAttrs {
+		d.Set(attr, settings[attr])
+	}
+
 	return nil
 }
 
@@ -424,12 +818,27 @@ func resourcePostgreSQLDatabaseUpdate(db *DBConnection, d *schema.ResourceData)
 		return err
 	}
 
+	if err := setDBLocaleSettings(db, d); err != nil {
+		return err
+	}
+
+	if err := setDBCollationVersion(db, d); err != nil {
+		return err
+	}
+
+	if err := setDBExtensions(db, d); err != nil {
+		return err
+	}
+
 	// Empty values: ALTER DATABASE name RESET configuration_parameter;
 
 	return resourcePostgreSQLDatabaseReadImpl(db, d)
 }
 
-func setDBName(db QueryAble, d *schema.ResourceData) error {
+// setDBName renames the database, which keeps its OID and its content. A database can't be renamed while
+// there are connections to it: the connections of the provider are closed and, if force_rename is set,
+// the other ones are terminated (and blocked until the database is renamed).
+func setDBName(db *DBConnection, d *schema.ResourceData) (err error) {
 	if !d.HasChange(dbNameAttr) {
 		return nil
 	}
@@ -441,10 +850,38 @@ func setDBName(db QueryAble, d *schema.ResourceData) error {
 		return errors.New("Error setting database name to an empty string")
 	}
 
+	// The pools of the provider are keyed by the database name, they would keep connections to the renamed database.
+	db.client.closeDatabaseConnections(o)
+
+	// name is the current name of the database, for the restore of ALLOW_CONNECTIONS.
+	name := o
+	force := d.Get(dbForceRenameAttr).(bool)
+	if force {
+		// terminateBConnections blocks the new connections, they are allowed again
+		// whether the database has been renamed or not.
+		if db.featureSupported(featureDBAllowConnections) {
+			defer func() {
+				allowConns := d.Get(dbAllowConnsAttr).(bool)
+				sql := fmt.Sprintf("ALTER DATABASE %s ALLOW_CONNECTIONS %t", pq.QuoteIdentifier(name), allowConns)
+				if _, allowErr := execWithStatementTimeout(db, sql); allowErr != nil && err == nil {
+					err = fmt.Errorf("Error updating database ALLOW_CONNECTIONS: %w", allowErr)
+				}
+			}()
+		}
+		if err := terminateBConnections(db, o); err != nil {
+			return err
+		}
+	}
+
 	sql := fmt.Sprintf("ALTER DATABASE %s RENAME TO %s", pq.QuoteIdentifier(o), pq.QuoteIdentifier(n))
-	if _, err := db.Exec(sql); err != nil {
+	if _, err := execWithStatementTimeout(db, sql); err != nil {
+		var pqErr *pq.Error
+		if !force && errors.As(err, &pqErr) && pqErr.Code == "55006" {
+			return fmt.Errorf("Error updating database name, set %s to terminate the connections to the database: %w", dbForceRenameAttr, err)
+		}
 		return fmt.Errorf("Error updating database name: %w", err)
 	}
+	name = n
 	d.SetId(n)
 
 	return nil
@@ -459,6 +896,17 @@ func setDBOwner(db *DBConnection, d *schema.ResourceData) error {
 	if owner == "" {
 		return nil
 	}
+	dbName := d.Get(dbNameAttr).(string)
+
+	// If the owner role has been renamed in the same apply, the database is already owned by it.
+	owned, err := dbOwnedByRole(db, dbName, owner)
+	if err != nil {
+		return err
+	}
+	if owned {
+		return nil
+	}
+
 	currentUser := db.client.config.getDatabaseUsername()
 
 	lockTxn, err := startTransaction(db.client, "")
@@ -478,16 +926,32 @@ func setDBOwner(db *DBConnection, d *schema.ResourceData) error {
 		}()
 	}
 
-	dbName := d.Get(dbNameAttr).(string)
-
 	sql := fmt.Sprintf("ALTER DATABASE %s OWNER TO %s", pq.QuoteIdentifier(dbName), pq.QuoteIdentifier(owner))
-	if _, err := db.Exec(sql); err != nil {
+	if _, err := execWithStatementTimeout(db, sql); err != nil {
 		return fmt.Errorf("Error updating database OWNER: %w", err)
 	}
 
 	return err
 }
 
+// dbOwnedByRole returns true if the database is owned by the role. The roles are compared by OID,
+// as the name of the owner in the state is stale if the role has been renamed since the last refresh.
+func dbOwnedByRole(db QueryAble, dbName, role string) (bool, error) {
+	var owned bool
+	err := db.QueryRow(
+		"SELECT d.datdba = r.oid FROM pg_catalog.pg_database d, pg_catalog.pg_roles r WHERE d.datname = $1 AND r.rolname = $2",
+		dbName, role,
+	).Scan(&owned)
+	switch {
+	case err == sql.ErrNoRows:
+		// The database or the role doesn't exist, ALTER DATABASE will report it.
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("could not check owner of database %s: %w", dbName, err)
+	}
+	return owned, nil
+}
+
 func setAlterOwnership(db *DBConnection, d *schema.ResourceData) error {
 	if !d.HasChange(dbOwnerAttr) && !d.HasChange(dbAlterObjectOwnership) {
 		return nil
@@ -542,7 +1006,7 @@ func setAlterOwnership(db *DBConnection, d *schema.ResourceData) error {
 	return nil
 }
 
-func setDBTablespace(db QueryAble, d *schema.ResourceData) error {
+func setDBTablespace(db *DBConnection, d *schema.ResourceData) error {
 	if !d.HasChange(dbTablespaceAttr) {
 		return nil
 	}
@@ -556,14 +1020,14 @@ func setDBTablespace(db QueryAble, d *schema.ResourceData) error {
 		sql = fmt.Sprintf("ALTER DATABASE %s SET TABLESPACE %s", pq.QuoteIdentifier(dbName), pq.QuoteIdentifier(tbspName))
 	}
 
-	if _, err := db.Exec(sql); err != nil {
+	if _, err := execWithStatementTimeout(db, sql); err != nil {
 		return fmt.Errorf("Error updating database TABLESPACE: %w", err)
 	}
 
 	return nil
 }
 
-func setDBConnLimit(db QueryAble, d *schema.ResourceData) error {
+func setDBConnLimit(db *DBConnection, d *schema.ResourceData) error {
 	if !d.HasChange(dbConnLimitAttr) {
 		return nil
 	}
@@ -571,7 +1035,7 @@ func setDBConnLimit(db QueryAble, d *schema.ResourceData) error {
 	connLimit := d.Get(dbConnLimitAttr).(int)
 	dbName := d.Get(dbNameAttr).(string)
 	sql := fmt.Sprintf("ALTER DATABASE %s CONNECTION LIMIT = %d", pq.QuoteIdentifier(dbName), connLimit)
-	if _, err := db.Exec(sql); err != nil {
+	if _, err := execWithStatementTimeout(db, sql); err != nil {
 		return fmt.Errorf("Error updating database CONNECTION LIMIT: %w", err)
 	}
 
@@ -590,13 +1054,33 @@ func setDBAllowConns(db *DBConnection, d *schema.ResourceData) error {
 	allowConns := d.Get(dbAllowConnsAttr).(bool)
 	dbName := d.Get(dbNameAttr).(string)
 	sql := fmt.Sprintf("ALTER DATABASE %s ALLOW_CONNECTIONS %t", pq.QuoteIdentifier(dbName), allowConns)
-	if _, err := db.Exec(sql); err != nil {
+	if _, err := execWithStatementTimeout(db, sql); err != nil {
 		return fmt.Errorf("Error updating database ALLOW_CONNECTIONS: %w", err)
 	}
 
 	return nil
 }
 
+// setDBCollationVersion refreshes the collation version recorded in the database: it can't be set to a given
+// version, so it's updated to the version of the collation library (e.g.: after an upgrade of the OS).
+func setDBCollationVersion(db *DBConnection, d *schema.ResourceData) error {
+	if !d.HasChange(dbCollationVersionAttr) {
+		return nil
+	}
+
+	if !db.featureSupported(featureDBLocaleProvider) {
+		return fmt.Errorf("PostgreSQL client is talking with a server (%q) that does not support database COLLATION_VERSION", db.version.String())
+	}
+
+	dbName := d.Get(dbNameAttr).(string)
+	sql := fmt.Sprintf("ALTER DATABASE %s REFRESH COLLATION VERSION", pq.QuoteIdentifier(dbName))
+	if _, err := execWithStatementTimeout(db, sql); err != nil {
+		return fmt.Errorf("Error refreshing database COLLATION VERSION: %w", err)
+	}
+
+	return nil
+}
+
 func setDBIsTemplate(db *DBConnection, d *schema.ResourceData) error {
 	if !d.HasChange(dbIsTemplateAttr) {
 		return nil
@@ -615,20 +1099,89 @@ func doSetDBIsTemplate(db *DBConnection, dbName string, isTemplate bool) error {
 	}
 
 	sql := fmt.Sprintf("ALTER DATABASE %s IS_TEMPLATE %t", pq.QuoteIdentifier(dbName), isTemplate)
-	if _, err := db.Exec(sql); err != nil {
+	if _, err := execWithStatementTimeout(db, sql); err != nil {
 		return fmt.Errorf("Error updating database IS_TEMPLATE: %w", err)
 	}
 
 	return nil
 }
 
+func setDBLocaleSettings(db *DBConnection, d *schema.ResourceData) error {
+	dbName := d.Get(dbNameAttr).(string)
+
+	for _, attr := range dbLocaleSettingsAttrs {
+		if !d.HasChange(attr) {
+			continue
+		}
+
+		var sql string
+		if value := d.Get(attr).(string); value == "" {
+			sql = fmt.Sprintf("ALTER DATABASE %s RESET %s", pq.QuoteIdentifier(dbName), attr)
+		} else {
+			sql = fmt.Sprintf("ALTER DATABASE %s SET %s TO '%s'", pq.QuoteIdentifier(dbName), attr, pqQuoteLiteral(value))
+		}
+
+		if _, err := execWithStatementTimeout(db, sql); err != nil {
+			return fmt.Errorf("Error updating database %s: %w", strings.ToUpper(attr), err)
+		}
+	}
+
+	return nil
+}
+
+// setDBExtensions creates the extensions added to the extensions list in the database.
+// Extensions removed from the list are left in place as objects of the database may depend on them.
+func setDBExtensions(db *DBConnection, d *schema.ResourceData) error {
+	if !d.HasChange(dbExtensionsAttr) {
+		return nil
+	}
+
+	oldExts, newExts := d.GetChange(dbExtensionsAttr)
+	added := setToSortedSlice(newExts.(*schema.Set).Difference(oldExts.(*schema.Set)))
+	if len(added) == 0 {
+		return nil
+	}
+
+	if !db.featureSupported(featureExtension) {
+		return fmt.Errorf(
+			"%s attribute is not supported for this Postgres version (%s)",
+			dbExtensionsAttr, db.version,
+		)
+	}
+
+	for _, extName := range added {
+		if err := checkExtensionAllowed(db.client.config, extName); err != nil {
+			return err
+		}
+	}
+
+	dbName := d.Get(dbNameAttr).(string)
+	txn, err := startTransaction(db.client, dbName)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	for _, extName := range added {
+		if _, err := txn.Exec("CREATE EXTENSION IF NOT EXISTS " + pq.QuoteIdentifier(extName)); err != nil {
+			return fmt.Errorf("Error creating extension %s in database %s: %w", extName, dbName, err)
+		}
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("Error creating extensions in database %s: %w", dbName, err)
+	}
+
+	return nil
+}
+
 func terminateBConnections(db *DBConnection, dbName string) error {
 	var terminateSql string
 
 	if db.featureSupported(featureDBAllowConnections) {
 		alterSql := fmt.Sprintf("ALTER DATABASE %s ALLOW_CONNECTIONS false", pq.QuoteIdentifier(dbName))
 
-		if _, err := db.Exec(alterSql); err != nil {
+		if _, err := execWithStatementTimeout(db, alterSql); err != nil {
 			return fmt.Errorf("Error blocking connections to database: %w", err)
 		}
 	}
@@ -636,8 +1189,8 @@ func terminateBConnections(db *DBConnection, dbName string) error {
 	if db.featureSupported(featurePid) {
 		pid = "pid"
 	}
-	terminateSql = fmt.Sprintf("SELECT pg_terminate_backend(%s) FROM pg_stat_activity WHERE datname = '%s' AND %s <> pg_backend_pid()", pid, dbName, pid)
-	if _, err := db.Exec(terminateSql); err != nil {
+	terminateSql = fmt.Sprintf("SELECT pg_terminate_backend(%s) FROM pg_stat_activity WHERE datname = $1 AND %s <> pg_backend_pid()", pid, pid)
+	if _, err := db.Exec(terminateSql, dbName); err != nil {
 		return fmt.Errorf("Error terminating database connections: %w", err)
 	}
 
@@ -0,0 +1,157 @@
+package postgresql
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	dbSettingDatabaseAttr = "database"
+	dbSettingNameAttr     = "name"
+	dbSettingValueAttr    = "value"
+)
+
+// dbSettingNameRegexp matches the names of the configuration parameters, including the
+// parameters of the extensions which are prefixed with the extension name (e.g.: pg_stat_statements.track).
+var dbSettingNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*(\.[A-Za-z_][A-Za-z0-9_$]*)*$`)
+
+func resourcePostgreSQLDatabaseSetting() *schema.Resource {
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLDatabaseSettingCreate),
+		Read:   PGReadResourceFunc(resourcePostgreSQLDatabaseSettingRead),
+		Update: PGResourceFunc(resourcePostgreSQLDatabaseSettingUpdate),
+		Delete: PGResourceFunc(resourcePostgreSQLDatabaseSettingDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			dbSettingDatabaseAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The database to set the configuration parameter for",
+			},
+			dbSettingNameAttr: {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringMatch(
+					dbSettingNameRegexp, "must be the name of a configuration parameter (e.g.: work_mem)",
+				),
+				// The parameters are stored in lower case.
+				DiffSuppressFunc: func(_, old, new string, _ *schema.ResourceData) bool {
+					return strings.EqualFold(old, new)
+				},
+				Description: "The name of the configuration parameter",
+			},
+			dbSettingValueAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The value of the configuration parameter, as it's stored by PostgreSQL (e.g.: 64MB)",
+			},
+		},
+	}
+}
+
+func resourcePostgreSQLDatabaseSettingCreate(db *DBConnection, d *schema.ResourceData) error {
+	if err := setDatabaseSetting(db, d); err != nil {
+		return err
+	}
+
+	d.SetId(generateObjectID(d.Get(dbSettingDatabaseAttr).(string), strings.ToLower(d.Get(dbSettingNameAttr).(string))))
+
+	return resourcePostgreSQLDatabaseSettingReadImpl(db, d)
+}
+
+func resourcePostgreSQLDatabaseSettingRead(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLDatabaseSettingReadImpl(db, d)
+}
+
+func resourcePostgreSQLDatabaseSettingReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	parts, err := parseObjectID(d.Id())
+	if err != nil {
+		return err
+	}
+	if len(parts) != 2 {
+		return fmt.Errorf("database setting ID %s has not the expected format 'database.name'", d.Id())
+	}
+	database, name := parts[0], parts[1]
+
+	txn, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	exists, err := dbExists(txn, database)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		log.Printf("[WARN] PostgreSQL database (%s) not found, removing database setting from state", database)
+		d.SetId("")
+		return nil
+	}
+
+	settings, err := readDBRoleSettings(txn, "", database)
+	if err != nil {
+		return err
+	}
+
+	value, ok := settings[name]
+	if !ok {
+		log.Printf("[WARN] PostgreSQL database setting (%s) not found, removing it from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set(dbSettingDatabaseAttr, database)
+	if !strings.EqualFold(d.Get(dbSettingNameAttr).(string), name) {
+		d.Set(dbSettingNameAttr, name)
+	}
+	d.Set(dbSettingValueAttr, value)
+
+	return nil
+}
+
+func resourcePostgreSQLDatabaseSettingUpdate(db *DBConnection, d *schema.ResourceData) error {
+	if err := setDatabaseSetting(db, d); err != nil {
+		return err
+	}
+
+	return resourcePostgreSQLDatabaseSettingReadImpl(db, d)
+}
+
+func resourcePostgreSQLDatabaseSettingDelete(db *DBConnection, d *schema.ResourceData) error {
+	database := d.Get(dbSettingDatabaseAttr).(string)
+	name := d.Get(dbSettingNameAttr).(string)
+
+	if _, err := db.Exec(fmt.Sprintf("ALTER DATABASE %s RESET %s", pq.QuoteIdentifier(database), name)); err != nil {
+		return fmt.Errorf("could not reset %s of database %s: %w", name, database, err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// setDatabaseSetting sets the configuration parameter for the database. The name is validated
+// by dbSettingNameRegexp as it can't be sent as a parameter of the query.
+func setDatabaseSetting(db *DBConnection, d *schema.ResourceData) error {
+	database := d.Get(dbSettingDatabaseAttr).(string)
+	name := d.Get(dbSettingNameAttr).(string)
+
+	if _, err := db.Exec(fmt.Sprintf(
+		"ALTER DATABASE %s SET %s TO %s", pq.QuoteIdentifier(database), name, pq.QuoteLiteral(d.Get(dbSettingValueAttr).(string)),
+	)); err != nil {
+		return fmt.Errorf("could not set %s of database %s: %w", name, database, err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package postgresql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccPostgresqlDatabaseSetting_Basic(t *testing.T) {
+	config := `
+resource "postgresql_database" "test" {
+  name = "tf_tests_db_setting"
+}
+
+resource "postgresql_database_setting" "test" {
+  database = postgresql_database.test.name
+  name     = "work_mem"
+  value    = "%s"
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlDatabaseDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, "64MB"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_database_setting.test", "id", "tf_tests_db_setting.work_mem"),
+					resource.TestCheckResourceAttr("postgresql_database_setting.test", "value", "64MB"),
+					testAccCheckDatabaseSetting("tf_tests_db_setting", "work_mem", "64MB"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(config, "128MB"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_database_setting.test", "value", "128MB"),
+					testAccCheckDatabaseSetting("tf_tests_db_setting", "work_mem", "128MB"),
+				),
+			},
+			{
+				ResourceName:      "postgresql_database_setting.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: `
+resource "postgresql_database" "test" {
+  name = "tf_tests_db_setting"
+}
+`,
+				Check: testAccCheckDatabaseSetting("tf_tests_db_setting", "work_mem", ""),
+			},
+		},
+	})
+}
+
+// testAccCheckDatabaseSetting checks the value of a configuration parameter of the database,
+// an empty value means that it is not set.
+func testAccCheckDatabaseSetting(database, name, expected string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*Client)
+		db, err := client.Connect()
+		if err != nil {
+			return err
+		}
+
+		settings, err := readDBRoleSettings(db, "", database)
+		if err != nil {
+			return fmt.Errorf("could not read the settings of database %s: %w", database, err)
+		}
+
+		if settings[name] != expected {
+			return fmt.Errorf("expected %s of database %s to be %q, got %q", name, database, expected, settings[name])
+		}
+		return nil
+	}
+}
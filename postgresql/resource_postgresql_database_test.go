@@ -4,11 +4,13 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"regexp"
 	"strconv"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestAccPostgresqlDatabase_Basic(t *testing.T) {
@@ -191,6 +193,308 @@ resource postgresql_database test_db {
 	})
 }
 
+// Test that the database is renamed in place, even with open connections if force_rename is set.
+func TestAccPostgresqlDatabase_Rename(t *testing.T) {
+	skipIfNotAcc(t)
+
+	config := getTestConfig(t)
+	var dbOID int
+	var conn *sql.DB
+	defer func() {
+		if conn != nil {
+			conn.Close()
+		}
+	}()
+
+	dbConfig := `
+resource postgresql_database test_db {
+	name         = "%s"
+	force_rename = true
+}
+`
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlDatabaseDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(dbConfig, "test_db_to_rename"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlDatabaseExists("postgresql_database.test_db"),
+					func(*terraform.State) error {
+						var err error
+						if conn, err = sql.Open("postgres", config.connStr("test_db_to_rename")); err != nil {
+							return err
+						}
+						// Keep a connection open to the database.
+						if err := conn.Ping(); err != nil {
+							return err
+						}
+						return conn.QueryRow("SELECT oid FROM pg_database WHERE datname = current_database()").Scan(&dbOID)
+					},
+				),
+			},
+			{
+				Config: fmt.Sprintf(dbConfig, "test_db_renamed"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlDatabaseExists("postgresql_database.test_db"),
+					resource.TestCheckResourceAttr("postgresql_database.test_db", "id", "test_db_renamed"),
+					resource.TestCheckResourceAttr("postgresql_database.test_db", "allow_connections", "true"),
+					func(*terraform.State) error {
+						client := testAccProvider.Meta().(*Client)
+						db, err := client.Connect()
+						if err != nil {
+							return err
+						}
+
+						var oid int
+						if err := db.QueryRow("SELECT oid FROM pg_database WHERE datname = 'test_db_renamed'").Scan(&oid); err != nil {
+							return fmt.Errorf("could not read the OID of the renamed database: %w", err)
+						}
+						if oid != dbOID {
+							return fmt.Errorf("expected the renamed database to keep OID %d, got %d", dbOID, oid)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// The rename fails as the database already exists, the connections are allowed again.
+				Config:      fmt.Sprintf(dbConfig, "postgres"),
+				ExpectError: regexp.MustCompile("already exists"),
+			},
+			{
+				Config: fmt.Sprintf(dbConfig, "test_db_renamed"),
+				Check: resource.ComposeTestCheckFunc(
+					func(*terraform.State) error {
+						client := testAccProvider.Meta().(*Client)
+						db, err := client.Connect()
+						if err != nil {
+							return err
+						}
+
+						var allowConns bool
+						if err := db.QueryRow("SELECT datallowconn FROM pg_database WHERE datname = 'test_db_renamed'").Scan(&allowConns); err != nil {
+							return fmt.Errorf("could not read the database: %w", err)
+						}
+						if !allowConns {
+							return fmt.Errorf("expected the connections to test_db_renamed to be allowed after the failed rename")
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+// Test that the database is dropped even with open connections as force_drop is set by default.
+func TestAccPostgresqlDatabase_ForceDrop(t *testing.T) {
+	skipIfNotAcc(t)
+
+	config := getTestConfig(t)
+	var conn *sql.DB
+	defer func() {
+		if conn != nil {
+			conn.Close()
+		}
+	}()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlDatabaseDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource postgresql_database test_db {
+	name = "test_db_to_drop"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlDatabaseExists("postgresql_database.test_db"),
+					resource.TestCheckResourceAttr("postgresql_database.test_db", "force_drop", "true"),
+					func(*terraform.State) error {
+						var err error
+						if conn, err = sql.Open("postgres", config.connStr("test_db_to_drop")); err != nil {
+							return err
+						}
+						// Keep a connection open to the database.
+						return conn.Ping()
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlDatabase_LocaleSettings(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlDatabaseDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource postgresql_database test_db {
+	name        = "test_db"
+	lc_monetary = "C"
+	lc_numeric  = "C"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlDatabaseExists("postgresql_database.test_db"),
+					resource.TestCheckResourceAttr("postgresql_database.test_db", "lc_monetary", "C"),
+					resource.TestCheckResourceAttr("postgresql_database.test_db", "lc_numeric", "C"),
+					resource.TestCheckResourceAttr("postgresql_database.test_db", "lc_time", ""),
+				),
+			},
+			{
+				Config: `
+resource postgresql_database test_db {
+	name    = "test_db"
+	lc_time = "C"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_database.test_db", "lc_monetary", ""),
+					resource.TestCheckResourceAttr("postgresql_database.test_db", "lc_numeric", ""),
+					resource.TestCheckResourceAttr("postgresql_database.test_db", "lc_time", "C"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlDatabase_ICULocale(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureDBLocaleProvider)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlDatabaseDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource postgresql_database test_icu_db {
+	name            = "test_icu_db"
+	locale_provider = "icu"
+	icu_locale      = "en-US"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlDatabaseExists("postgresql_database.test_icu_db"),
+					resource.TestCheckResourceAttr("postgresql_database.test_icu_db", "locale_provider", "icu"),
+					resource.TestCheckResourceAttr("postgresql_database.test_icu_db", "icu_locale", "en-US"),
+					resource.TestCheckResourceAttrSet("postgresql_database.test_icu_db", "collation_version"),
+				),
+			},
+			{
+				ResourceName:            "postgresql_database.test_icu_db",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"alter_object_ownership", "force_rename", "force_drop"},
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlDatabase_TemplateLocale(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlDatabaseDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource postgresql_database test_db {
+	name     = "test_db"
+	template = "template1"
+	encoding = "SQL_ASCII"
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`new encoding \(SQL_ASCII\) is incompatible with the encoding of template database template1`),
+			},
+			{
+				Config: `
+resource postgresql_database test_db {
+	name     = "test_db"
+	template = "template0"
+	encoding = "SQL_ASCII"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlDatabaseExists("postgresql_database.test_db"),
+					resource.TestCheckResourceAttr("postgresql_database.test_db", "encoding", "SQL_ASCII"),
+				),
+			},
+		},
+	})
+}
+
+func TestCheckLocaleEncoding(t *testing.T) {
+	assert.NoError(t, checkLocaleEncoding("UTF8", "lc_collate", "en_US.utf8", []string{"UTF8"}))
+	assert.NoError(t, checkLocaleEncoding("LATIN1", "lc_collate", "C", nil))
+	assert.NoError(t, checkLocaleEncoding("SQL_ASCII", "lc_collate", "en_US", []string{"LATIN1"}))
+	// The locale is not in pg_collation.
+	assert.NoError(t, checkLocaleEncoding("UTF8", "lc_collate", "fr_FR.utf8", nil))
+
+	err := checkLocaleEncoding("UTF8", "lc_ctype", "en_US", []string{"LATIN1"})
+	assert.EqualError(t, err, `encoding UTF8 does not match locale "en_US" of lc_ctype, which requires encoding LATIN1: `+
+		"set the encoding accordingly or choose a locale for the encoding UTF8")
+}
+
+func TestCheckDBTemplateLocale(t *testing.T) {
+	templateLocale := dbLocale{encoding: "UTF8", collate: "en_US.utf8", ctype: "en_US.utf8"}
+
+	assert.NoError(t, checkDBTemplateLocale("template1", templateLocale, templateLocale))
+	assert.NoError(t, checkDBTemplateLocale("template0", dbLocale{encoding: "LATIN1", collate: "C", ctype: "C"}, templateLocale))
+
+	err := checkDBTemplateLocale("template1", dbLocale{encoding: "UTF8", collate: "C", ctype: "en_US.utf8"}, templateLocale)
+	assert.EqualError(t, err, "new lc_collate (C) is incompatible with the lc_collate of template database template1 (en_US.utf8): "+
+		"use the same lc_collate as the template database, or set template to template0")
+}
+
+func TestAccPostgresqlDatabase_Extensions(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureExtension)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlDatabaseDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource postgresql_database test_db {
+	name       = "test_db"
+	extensions = ["pgcrypto"]
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlDatabaseExists("postgresql_database.test_db"),
+					resource.TestCheckResourceAttr("postgresql_database.test_db", "extensions.#", "1"),
+					testAccCheckDatabaseExtensions("test_db", []string{"pgcrypto"}),
+				),
+			},
+			{
+				Config: `
+resource postgresql_database test_db {
+	name       = "test_db"
+	extensions = ["pgcrypto", "hstore"]
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_database.test_db", "extensions.#", "2"),
+					testAccCheckDatabaseExtensions("test_db", []string{"hstore", "pgcrypto"}),
+				),
+			},
+		},
+	})
+}
+
 // Test the case where we need to grant the owner to the connected user.
 // The owner should be revoked
 func TestAccPostgresqlDatabase_GrantOwner(t *testing.T) {
@@ -228,6 +532,66 @@ resource postgresql_database "test_db" {
 	})
 }
 
+// Test the case where the owner role is renamed in the same apply as the database owner is updated.
+func TestAccPostgresqlDatabase_RenameOwner(t *testing.T) {
+	skipIfNotAcc(t)
+
+	var stateConfig = `
+resource postgresql_role "test_owner" {
+       name = "%s"
+}
+resource postgresql_database "test_db" {
+       name  = "test_db"
+       owner = postgresql_role.test_owner.name
+}
+`
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlDatabaseDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(stateConfig, "test_owner"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_database.test_db", "owner", "test_owner"),
+					testAccCheckDatabaseOwnerOID("postgresql_database.test_db", "test_owner"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(stateConfig, "test_owner_renamed"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_database.test_db", "owner", "test_owner_renamed"),
+					testAccCheckDatabaseOwnerOID("postgresql_database.test_db", "test_owner_renamed"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDatabaseOwnerOID(n, owner string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Resource not found: %s", n)
+		}
+
+		client := testAccProvider.Meta().(*Client)
+		db, err := client.Connect()
+		if err != nil {
+			return err
+		}
+
+		var ownerOID string
+		if err := db.QueryRow("SELECT oid::text FROM pg_catalog.pg_roles WHERE rolname = $1", owner).Scan(&ownerOID); err != nil {
+			return fmt.Errorf("could not read OID of role %s: %w", owner, err)
+		}
+		if rs.Primary.Attributes["owner_oid"] != ownerOID {
+			return fmt.Errorf("owner_oid is %s, expected %s (OID of %s)", rs.Primary.Attributes["owner_oid"], ownerOID, owner)
+		}
+		return nil
+	}
+}
+
 // Test the case where the connected user is already a member of the owner.
 // There were a bug which was revoking the owner anyway.
 func TestAccPostgresqlDatabase_GrantOwnerNotNeeded(t *testing.T) {
@@ -463,6 +827,29 @@ func testAccCheckPostgresqlDatabaseExists(n string) resource.TestCheckFunc {
 	}
 }
 
+func testAccCheckDatabaseExtensions(dbName string, expected []string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		client := testAccProvider.Meta().(*Client)
+		txn, err := startTransaction(client, dbName)
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		for _, extName := range expected {
+			var exists bool
+			if err := txn.QueryRow("SELECT count(*) > 0 FROM pg_extension WHERE extname = $1", extName).Scan(&exists); err != nil {
+				return fmt.Errorf("could not check extension %s: %w", extName, err)
+			}
+			if !exists {
+				return fmt.Errorf("extension %s not found in database %s", extName, dbName)
+			}
+		}
+
+		return nil
+	}
+}
+
 func checkDatabaseExists(client *Client, dbName string) (bool, error) {
 	db, err := client.Connect()
 	if err != nil {
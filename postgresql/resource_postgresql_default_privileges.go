@@ -1,10 +1,12 @@
 package postgresql
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
 	"strings"
+	"sync"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -15,17 +17,26 @@ import (
 
 func resourcePostgreSQLDefaultPrivileges() *schema.Resource {
 	return &schema.Resource{
-		Create: PGResourceFunc(resourcePostgreSQLDefaultPrivilegesCreate),
-		Update: PGResourceFunc(resourcePostgreSQLDefaultPrivilegesCreate),
-		Read:   PGResourceFunc(resourcePostgreSQLDefaultPrivilegesRead),
-		Delete: PGResourceFunc(resourcePostgreSQLDefaultPrivilegesDelete),
+		CreateContext: PGResourceFuncWithWarnings(resourcePostgreSQLDefaultPrivilegesCreate, grantSecurityWarnings),
+		UpdateContext: PGResourceFuncWithWarnings(resourcePostgreSQLDefaultPrivilegesUpdate, grantSecurityWarnings),
+		Read:          PGReadResourceFunc(readWithSecurityWarnings(resourcePostgreSQLDefaultPrivilegesRead, grantSecurityWarnings)),
+		Delete:        PGResourceFunc(resourcePostgreSQLDefaultPrivilegesDelete),
+		CustomizeDiff: resourcePostgreSQLDefaultPrivilegesCustomizeDiff,
 
 		Schema: map[string]*schema.Schema{
 			"role": {
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
-				Description: "The name of the role to which grant default privileges on",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"role", "roles"},
+				Description:  "The name of the role to which grant default privileges on",
+			},
+			"roles": {
+				Type:         schema.TypeSet,
+				Optional:     true,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				Set:          schema.HashString,
+				ExactlyOneOf: []string{"role", "roles"},
+				Description:  "The names of the roles to which grant the same default privileges on, in the same statements",
 			},
 			"database": {
 				Type:        schema.TypeString,
@@ -36,7 +47,6 @@ func resourcePostgreSQLDefaultPrivileges() *schema.Resource {
 			"owner": {
 				Type:        schema.TypeString,
 				Required:    true,
-				ForceNew:    true,
 				Description: "Target role for which to alter default privileges.",
 			},
 			"schema": {
@@ -46,24 +56,19 @@ func resourcePostgreSQLDefaultPrivileges() *schema.Resource {
 				Description: "The database schema to set default privileges for this role",
 			},
 			"object_type": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
-				ValidateFunc: validation.StringInSlice([]string{
-					"table",
-					"sequence",
-					"function",
-					"type",
-					"schema",
-				}, false),
-				Description: "The PostgreSQL object type to set the default privileges on (one of: table, sequence, function, type, schema)",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateDefaultPrivilegesObjectType,
+				Description:  "The PostgreSQL object type to set the default privileges on (one of: table, sequence, function, type, schema)",
 			},
 			"privileges": {
-				Type:        schema.TypeSet,
-				Required:    true,
-				Elem:        &schema.Schema{Type: schema.TypeString},
-				Set:         schema.HashString,
-				Description: "The list of privileges to apply as default privileges",
+				Type:             schema.TypeSet,
+				Required:         true,
+				Elem:             &schema.Schema{Type: schema.TypeString},
+				Set:              hashPrivilege,
+				DiffSuppressFunc: privilegesDiffSuppress,
+				Description:      "The list of privileges to apply as default privileges",
 			},
 			"with_grant_option": {
 				Type:        schema.TypeBool,
@@ -72,10 +77,47 @@ func resourcePostgreSQLDefaultPrivileges() *schema.Resource {
 				Default:     false,
 				Description: "Permit the grant recipient to grant it to others",
 			},
+			"skip_final_revoke_on_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Only remove the default privileges from the state when destroying it, without running ALTER DEFAULT PRIVILEGES ... REVOKE (e.g.: when the database is dropped anyway)",
+			},
+			securityWarningsAttr: securityWarningsSchema(),
 		},
 	}
 }
 
+var defaultPrivilegesObjectTypes = []string{
+	"table",
+	"sequence",
+	"function",
+	"type",
+	"schema",
+}
+
+// validateDefaultPrivilegesObjectType validates object_type and explains
+// why column default privileges can't be managed.
+func validateDefaultPrivilegesObjectType(v interface{}, k string) ([]string, []error) {
+	if v.(string) == "column" {
+		return nil, []error{fmt.Errorf(
+			"%s: PostgreSQL does not support default privileges on columns, "+
+				"use postgresql_grant with object_type \"column\" for each table instead "+
+				"(e.g.: with the postgresql_tables data source)", k,
+		)}
+	}
+	return validation.StringInSlice(defaultPrivilegesObjectTypes, false)(v, k)
+}
+
+// resourcePostgreSQLDefaultPrivilegesCustomizeDiff plans the security warnings of the default privileges
+// (see grantSecurityWarnings).
+func resourcePostgreSQLDefaultPrivilegesCustomizeDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if err := customizeDiffSkipFinalRevoke(d, "database", "schema", "object_type", "with_grant_option"); err != nil {
+		return err
+	}
+	return customizeDiffSecurityWarnings(d, meta, grantSecurityWarnings, "role", "roles", "object_type", "database")
+}
+
 func resourcePostgreSQLDefaultPrivilegesRead(db *DBConnection, d *schema.ResourceData) error {
 	pgSchema := d.Get("schema").(string)
 	objectType := d.Get("object_type").(string)
@@ -96,16 +138,30 @@ func resourcePostgreSQLDefaultPrivilegesRead(db *DBConnection, d *schema.Resourc
 		return nil
 	}
 
-	txn, err := startTransaction(db.client, d.Get("database").(string))
-	if err != nil {
-		return err
+	// The default privileges of all the resources of the database are read at once
+	// and kept for the duration of the run.
+	roles := grantGrantees(d.Get)
+	privileges := make(map[string]pq.ByteaArray, len(roles))
+	for _, role := range roles {
+		if privileges[role], err = db.client.defaultPrivileges.get(
+			db.client, d.Get("database").(string), defaultPrivilegesKeyFromResource(d, role),
+		); err != nil {
+			return err
+		}
 	}
-	defer deferredRollback(txn)
 
-	return readRoleDefaultPrivileges(txn, d)
+	return setRoleDefaultPrivileges(d, roles, privileges)
 }
 
 func resourcePostgreSQLDefaultPrivilegesCreate(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLDefaultPrivilegesCreateOrUpdate(db, d, false)
+}
+
+func resourcePostgreSQLDefaultPrivilegesUpdate(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLDefaultPrivilegesCreateOrUpdate(db, d, true)
+}
+
+func resourcePostgreSQLDefaultPrivilegesCreateOrUpdate(db *DBConnection, d *schema.ResourceData, usePrevious bool) error {
 	pgSchema := d.Get("schema").(string)
 	objectType := d.Get("object_type").(string)
 
@@ -119,10 +175,16 @@ func resourcePostgreSQLDefaultPrivilegesCreate(db *DBConnection, d *schema.Resou
 		return fmt.Errorf("cannot specify `schema` when `object_type` is `schema`")
 	}
 
-	if d.Get("with_grant_option").(bool) && strings.ToLower(d.Get("role").(string)) == "public" {
-		return fmt.Errorf("with_grant_option cannot be true for role 'public'")
+	if d.Get("with_grant_option").(bool) {
+		for _, role := range grantGrantees(d.Get) {
+			if role == publicRole {
+				return fmt.Errorf("with_grant_option cannot be true for role 'public'")
+			}
+		}
 	}
 
+	// The privileges are stored as PostgreSQL reads them back (e.g.: TEMP as TEMPORARY).
+	d.Set("privileges", normalizePrivileges(d.Get("privileges").(*schema.Set)))
 	if err := validatePrivileges(d); err != nil {
 		return err
 	}
@@ -130,41 +192,44 @@ func resourcePostgreSQLDefaultPrivilegesCreate(db *DBConnection, d *schema.Resou
 	database := d.Get("database").(string)
 	owner := d.Get("owner").(string)
 
-	txn, err := startTransaction(db.client, database)
-	if err != nil {
-		return err
-	}
-	defer deferredRollback(txn)
-
-	if err := pgLockRole(txn, owner); err != nil {
-		return err
-	}
-
-	// Needed in order to set the owner of the db if the connection user is not a superuser
-	if err := withRolesGranted(txn, []string{owner}, func() error {
-
-		// Revoke all privileges before granting otherwise reducing privileges will not work.
-		// We just have to revoke them in the same transaction so role will not lose its privileges
-		// between revoke and grant.
-		if err = revokeRoleDefaultPrivileges(txn, d); err != nil {
+	if err := withTransaction(db.client, database, func(txn *sql.Tx) error {
+		if err := pgLockRole(txn, owner); err != nil {
 			return err
 		}
 
-		if err = grantRoleDefaultPrivileges(txn, d); err != nil {
-			return err
+		getter := d.Get
+		if usePrevious {
+			// The roles or the owner could have been renamed (or replaced) so we need
+			// to revoke the default privileges of the previous ones.
+			var err error
+			if getter, err = previousGrantGetter(txn, d, "owner"); err != nil {
+				return err
+			}
+		}
+
+		owners := []string{owner}
+		if previousOwner := getter("owner").(string); previousOwner != owner {
+			owners = append(owners, previousOwner)
 		}
-		return nil
-	}); err != nil {
-		return err
-	}
 
-	if err := txn.Commit(); err != nil {
+		// Needed in order to set the owner of the db if the connection user is not a superuser
+		return withRolesGranted(txn, owners, func() error {
+			// Revoke all privileges before granting otherwise reducing privileges will not work.
+			// We just have to revoke them in the same transaction so role will not lose its privileges
+			// between revoke and grant.
+			if err := revokeRoleDefaultPrivileges(txn, getter); err != nil {
+				return err
+			}
+			return grantRoleDefaultPrivileges(txn, d)
+		})
+	}); err != nil {
 		return err
 	}
+	db.client.defaultPrivileges.invalidate(database)
 
 	d.SetId(generateDefaultPrivilegesID(d))
 
-	txn, err = startTransaction(db.client, d.Get("database").(string))
+	txn, err := startTransaction(db.client, d.Get("database").(string))
 	if err != nil {
 		return err
 	}
@@ -174,6 +239,11 @@ func resourcePostgreSQLDefaultPrivilegesCreate(db *DBConnection, d *schema.Resou
 }
 
 func resourcePostgreSQLDefaultPrivilegesDelete(db *DBConnection, d *schema.ResourceData) error {
+	if d.Get("skip_final_revoke_on_destroy").(bool) {
+		log.Printf("[INFO] Skipping the revoke of default privileges %s", d.Id())
+		return nil
+	}
+
 	owner := d.Get("owner").(string)
 	pgSchema := d.Get("schema").(string)
 	objectType := d.Get("object_type").(string)
@@ -185,44 +255,49 @@ func resourcePostgreSQLDefaultPrivilegesDelete(db *DBConnection, d *schema.Resou
 		)
 	}
 
-	txn, err := startTransaction(db.client, d.Get("database").(string))
-	if err != nil {
-		return err
-	}
-	defer deferredRollback(txn)
+	if err := withTransaction(db.client, d.Get("database").(string), func(txn *sql.Tx) error {
+		if err := pgLockRole(txn, owner); err != nil {
+			return err
+		}
 
-	if err := pgLockRole(txn, owner); err != nil {
+		// Needed in order to set the owner of the db if the connection user is not a superuser
+		return withRolesGranted(txn, []string{owner}, func() error {
+			return revokeRoleDefaultPrivileges(txn, d.Get)
+		})
+	}); err != nil {
 		return err
 	}
+	db.client.defaultPrivileges.invalidate(d.Get("database").(string))
 
-	// Needed in order to set the owner of the db if the connection user is not a superuser
-	if err := withRolesGranted(txn, []string{owner}, func() error {
-		return revokeRoleDefaultPrivileges(txn, d)
-	}); err != nil {
+	return nil
+}
+
+func readRoleDefaultPrivileges(txn *sql.Tx, d *schema.ResourceData) error {
+	if err := pgLockRole(txn, d.Get("owner").(string)); err != nil {
 		return err
 	}
 
-	if err := txn.Commit(); err != nil {
-		return err
+	roles := grantGrantees(d.Get)
+	privileges := make(map[string]pq.ByteaArray, len(roles))
+	for _, role := range roles {
+		var err error
+		if privileges[role], err = readRoleDefaultPrivilegesOf(txn, d, role); err != nil {
+			return err
+		}
 	}
 
-	return nil
+	return setRoleDefaultPrivileges(d, roles, privileges)
 }
 
-func readRoleDefaultPrivileges(txn *sql.Tx, d *schema.ResourceData) error {
-	role := d.Get("role").(string)
+// readRoleDefaultPrivilegesOf returns the default privileges of one of the roles of the resource.
+func readRoleDefaultPrivilegesOf(txn *sql.Tx, d *schema.ResourceData, role string) (pq.ByteaArray, error) {
 	owner := d.Get("owner").(string)
 	pgSchema := d.Get("schema").(string)
 	objectType := d.Get("object_type").(string)
-	privilegesInput := d.Get("privileges").(*schema.Set).List()
-
-	if err := pgLockRole(txn, owner); err != nil {
-		return err
-	}
 
 	roleOID, err := getRoleOID(txn, role)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var query string
@@ -255,31 +330,137 @@ func readRoleDefaultPrivileges(txn *sql.Tx, d *schema.ResourceData) error {
 	if err := txn.QueryRow(
 		query, queryArgs...,
 	).Scan(&privileges); err != nil {
-		return fmt.Errorf("could not read default privileges: %w", err)
+		return nil, fmt.Errorf("could not read default privileges of role %s: %w", role, err)
 	}
 
-	// We consider no privileges as "not exists" unless no privileges were provided as input
-	if len(privileges) == 0 {
-		log.Printf("[DEBUG] no default privileges for role %s in schema %s", role, pgSchema)
-		if len(privilegesInput) != 0 {
-			d.SetId("")
-			return nil
+	return privileges, nil
+}
+
+// setRoleDefaultPrivileges updates the resource with the default privileges read from the database for each role.
+// If the privileges of one of the roles differ from the expected ones, they are set in the state
+// so the plan shows the drift and grants them again.
+func setRoleDefaultPrivileges(d *schema.ResourceData, roles []string, privileges map[string]pq.ByteaArray) error {
+	pgSchema := d.Get("schema").(string)
+	privilegesInput := d.Get("privileges").(*schema.Set).List()
+
+	// We consider no privileges for all the roles as "not exists" unless no privileges were provided as input
+	found := false
+	for _, role := range roles {
+		if len(privileges[role]) == 0 {
+			log.Printf("[DEBUG] no default privileges for role %s in schema %s", role, pgSchema)
+			continue
 		}
+		found = true
+	}
+	if !found && len(privilegesInput) != 0 {
+		d.SetId("")
+		return nil
 	}
 
-	privilegesSet := pgArrayToSet(privileges)
-	privilegesEqual := resourcePrivilegesEqual(privilegesSet, d)
-
-	if !privilegesEqual {
-		d.Set("privileges", privilegesSet)
+	for _, role := range roles {
+		privilegesSet := pgArrayToSet(privileges[role])
+		if !resourcePrivilegesEqual(privilegesSet, d) {
+			d.Set("privileges", privilegesSet)
+			break
+		}
 	}
 	d.SetId(generateDefaultPrivilegesID(d))
 
 	return nil
 }
 
+// defaultPrivilegesKey identifies the default privileges of a resource:
+// the privileges granted by owner to role on the objects of type objectType created in schema.
+type defaultPrivilegesKey struct {
+	owner      string
+	role       string
+	schema     string
+	objectType string
+}
+
+func defaultPrivilegesKeyFromResource(d *schema.ResourceData, role string) defaultPrivilegesKey {
+	return defaultPrivilegesKey{
+		owner:      d.Get("owner").(string),
+		role:       role,
+		schema:     d.Get("schema").(string),
+		objectType: objectTypes[d.Get("object_type").(string)],
+	}
+}
+
+// defaultPrivilegesCache keeps the content of pg_default_acl per database so reading
+// many postgresql_default_privileges resources only needs one query per database.
+// It's invalidated when the default privileges of the database are changed by the provider.
+type defaultPrivilegesCache struct {
+	sync.Mutex
+	databases map[string]map[defaultPrivilegesKey]pq.ByteaArray
+}
+
+func newDefaultPrivilegesCache() *defaultPrivilegesCache {
+	return &defaultPrivilegesCache{databases: map[string]map[defaultPrivilegesKey]pq.ByteaArray{}}
+}
+
+// get returns the default privileges for the key, loading all the default privileges of the database if needed.
+func (c *defaultPrivilegesCache) get(client *Client, database string, key defaultPrivilegesKey) (pq.ByteaArray, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	privileges, ok := c.databases[database]
+	if !ok {
+		var err error
+		if privileges, err = loadDefaultPrivileges(client, database); err != nil {
+			return nil, err
+		}
+		c.databases[database] = privileges
+	}
+
+	return privileges[key], nil
+}
+
+func (c *defaultPrivilegesCache) invalidate(database string) {
+	c.Lock()
+	defer c.Unlock()
+
+	delete(c.databases, database)
+}
+
+// loadDefaultPrivileges reads all the default privileges of the database.
+func loadDefaultPrivileges(client *Client, database string) (map[defaultPrivilegesKey]pq.ByteaArray, error) {
+	txn, err := startTransaction(client, database)
+	if err != nil {
+		return nil, err
+	}
+	defer deferredRollback(txn)
+
+	// Global default privileges (namespace 0) have an empty schema.
+	// Grantee 0 is PUBLIC.
+	rows, err := txn.Query(`
+SELECT pg_get_userbyid(grantor_oid), COALESCE(pg_roles.rolname, 'public'), COALESCE(nspname, ''), objtype, array_agg(prtype)
+FROM (
+	SELECT defaclnamespace, defaclobjtype, (aclexplode(defaclacl)).* FROM pg_default_acl
+) AS t (namespace, objtype, grantor_oid, grantee_oid, prtype, grantable)
+LEFT JOIN pg_namespace ON pg_namespace.oid = namespace
+LEFT JOIN pg_roles ON pg_roles.oid = grantee_oid
+GROUP BY 1, 2, 3, 4
+`)
+	if err != nil {
+		return nil, fmt.Errorf("could not read default privileges: %w", err)
+	}
+	defer rows.Close()
+
+	result := map[defaultPrivilegesKey]pq.ByteaArray{}
+	for rows.Next() {
+		var key defaultPrivilegesKey
+		var privileges pq.ByteaArray
+		if err := rows.Scan(&key.owner, &key.role, &key.schema, &key.objectType, &privileges); err != nil {
+			return nil, fmt.Errorf("could not scan default privileges: %w", err)
+		}
+		result[key] = privileges
+	}
+
+	return result, rows.Err()
+}
+
 func grantRoleDefaultPrivileges(txn *sql.Tx, d *schema.ResourceData) error {
-	role := d.Get("role").(string)
 	pgSchema := d.Get("schema").(string)
 
 	privileges := []string{}
@@ -288,7 +469,7 @@ func grantRoleDefaultPrivileges(txn *sql.Tx, d *schema.ResourceData) error {
 	}
 
 	if len(privileges) == 0 {
-		log.Printf("[DEBUG] no default privileges to grant for role %s, owner %s in database: %s,", d.Get("role").(string), d.Get("owner").(string), d.Get("database").(string))
+		log.Printf("[DEBUG] no default privileges to grant for roles %v, owner %s in database: %s,", grantGrantees(d.Get), d.Get("owner").(string), d.Get("database").(string))
 		return nil
 	}
 
@@ -304,7 +485,7 @@ func grantRoleDefaultPrivileges(txn *sql.Tx, d *schema.ResourceData) error {
 		inSchema,
 		strings.Join(privileges, ","),
 		strings.ToUpper(d.Get("object_type").(string)),
-		pq.QuoteIdentifier(role),
+		grantGranteesIdent(d.Get),
 	)
 
 	if d.Get("with_grant_option").(bool) {
@@ -321,8 +502,13 @@ func grantRoleDefaultPrivileges(txn *sql.Tx, d *schema.ResourceData) error {
 	return nil
 }
 
-func revokeRoleDefaultPrivileges(txn *sql.Tx, d *schema.ResourceData) error {
-	pgSchema := d.Get("schema").(string)
+func revokeRoleDefaultPrivileges(txn *sql.Tx, getter ResourceSchemeGetter) error {
+	roles := grantGranteesIdent(getter)
+	if roles == "" {
+		// All the previous roles have been dropped.
+		return nil
+	}
+	pgSchema := getter("schema").(string)
 
 	var inSchema string
 
@@ -332,10 +518,10 @@ func revokeRoleDefaultPrivileges(txn *sql.Tx, d *schema.ResourceData) error {
 	}
 	query := fmt.Sprintf(
 		"ALTER DEFAULT PRIVILEGES FOR ROLE %s %s REVOKE ALL ON %sS FROM %s",
-		pq.QuoteIdentifier(d.Get("owner").(string)),
+		pq.QuoteIdentifier(getter("owner").(string)),
 		inSchema,
-		strings.ToUpper(d.Get("object_type").(string)),
-		pq.QuoteIdentifier(d.Get("role").(string)),
+		strings.ToUpper(getter("object_type").(string)),
+		roles,
 	)
 
 	if _, err := txn.Exec(query); err != nil {
@@ -344,6 +530,8 @@ func revokeRoleDefaultPrivileges(txn *sql.Tx, d *schema.ResourceData) error {
 	return nil
 }
 
+// generateDefaultPrivilegesID returns the ID of the default privileges.
+// With a roles list, the role part contains the sorted roles separated by commas (e.g.: role1,role2).
 func generateDefaultPrivilegesID(d *schema.ResourceData) string {
 	pgSchema := d.Get("schema").(string)
 	if pgSchema == "" {
@@ -351,7 +539,7 @@ func generateDefaultPrivilegesID(d *schema.ResourceData) string {
 	}
 
 	return strings.Join([]string{
-		d.Get("role").(string), d.Get("database").(string), pgSchema,
+		strings.Join(grantGrantees(d.Get), ","), d.Get("database").(string), pgSchema,
 		d.Get("owner").(string), d.Get("object_type").(string),
 	}, "_")
 
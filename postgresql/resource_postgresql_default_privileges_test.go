@@ -2,12 +2,120 @@ package postgresql
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/lib/pq"
 )
 
+func TestValidateDefaultPrivilegesObjectType(t *testing.T) {
+	cases := map[string]struct {
+		objectType string
+		errorMsg   string
+	}{
+		"table":   {objectType: "table"},
+		"schema":  {objectType: "schema"},
+		"column":  {objectType: "column", errorMsg: "does not support default privileges on columns"},
+		"unknown": {objectType: "foo", errorMsg: "expected object_type to be one of"},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, errs := validateDefaultPrivilegesObjectType(c.objectType, "object_type")
+			if c.errorMsg == "" {
+				if len(errs) != 0 {
+					t.Fatalf("unexpected errors: %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || !strings.Contains(errs[0].Error(), c.errorMsg) {
+				t.Fatalf("expected error containing %q, got: %v", c.errorMsg, errs)
+			}
+		})
+	}
+}
+
+func TestDefaultPrivilegesCache(t *testing.T) {
+	key := defaultPrivilegesKey{owner: "owner", role: "reader", schema: "public", objectType: "r"}
+
+	cache := newDefaultPrivilegesCache()
+	cache.databases["mydb"] = map[defaultPrivilegesKey]pq.ByteaArray{
+		key: {[]byte("SELECT")},
+	}
+
+	// The database is already loaded, no connection is needed.
+	privileges, err := cache.get(nil, "mydb", key)
+	if err != nil {
+		t.Fatalf("could not get default privileges: %v", err)
+	}
+	if len(privileges) != 1 || string(privileges[0]) != "SELECT" {
+		t.Errorf("expected SELECT default privileges, got %v", privileges)
+	}
+
+	privileges, err = cache.get(nil, "mydb", defaultPrivilegesKey{owner: "owner", role: "writer", schema: "public", objectType: "r"})
+	if err != nil {
+		t.Fatalf("could not get default privileges: %v", err)
+	}
+	if len(privileges) != 0 {
+		t.Errorf("expected no default privileges, got %v", privileges)
+	}
+
+	cache.invalidate("mydb")
+	if _, ok := cache.databases["mydb"]; ok {
+		t.Errorf("expected mydb to be removed from the cache")
+	}
+}
+
+func TestSetRoleDefaultPrivilegesRoles(t *testing.T) {
+	newResource := func() *schema.ResourceData {
+		return schema.TestResourceDataRaw(t, resourcePostgreSQLDefaultPrivileges().Schema, map[string]interface{}{
+			"database":    "mydb",
+			"owner":       "owner",
+			"roles":       []interface{}{"writer", "reader"},
+			"object_type": "table",
+			"privileges":  []interface{}{"SELECT"},
+		})
+	}
+	roles := []string{"reader", "writer"}
+
+	d := newResource()
+	if err := setRoleDefaultPrivileges(d, roles, map[string]pq.ByteaArray{
+		"reader": {[]byte("SELECT")},
+		"writer": {[]byte("SELECT")},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if d.Id() != "reader,writer_mydb_noschema_owner_table" {
+		t.Errorf("unexpected ID %s", d.Id())
+	}
+	if d.Get("privileges").(*schema.Set).Len() != 1 {
+		t.Errorf("expected privileges to be unchanged, got %v", d.Get("privileges"))
+	}
+
+	// The privileges missing for one of the roles are detected.
+	d = newResource()
+	if err := setRoleDefaultPrivileges(d, roles, map[string]pq.ByteaArray{
+		"reader": {[]byte("SELECT")},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if d.Id() == "" || d.Get("privileges").(*schema.Set).Len() != 0 {
+		t.Errorf("expected the privileges of writer to be set, got %v", d.Get("privileges"))
+	}
+
+	// Without any privileges for all the roles, the resource is removed.
+	d = newResource()
+	if err := setRoleDefaultPrivileges(d, roles, map[string]pq.ByteaArray{}); err != nil {
+		t.Fatal(err)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected the resource to be removed, got ID %s", d.Id())
+	}
+}
+
 func TestAccPostgresqlDefaultPrivileges(t *testing.T) {
 	skipIfNotAcc(t)
 
@@ -20,11 +128,11 @@ func TestAccPostgresqlDefaultPrivileges(t *testing.T) {
 	config := getTestConfig(t)
 	dbName, roleName := getTestDBNames(dbSuffix)
 
-	// Set default privileges to the test role then to public (i.e.: everyone)
-	for _, role := range []string{roleName, "public"} {
+	// Set default privileges to the test role then to public (i.e.: everyone), in any case
+	for _, role := range []string{roleName, "public", "PUBLIC"} {
 		t.Run(role, func(t *testing.T) {
 			withGrant := true
-			if role == "public" {
+			if isPublicRole(role) {
 				withGrant = false
 			}
 
@@ -123,6 +231,84 @@ resource "postgresql_default_privileges" "test_ro" {
 	}
 }
 
+func TestAccPostgresqlDefaultPrivilegesRoles(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	config := getTestConfig(t)
+	dbName, roleName := getTestDBNames(dbSuffix)
+	otherRole := roleName + "_other"
+
+	dsn := config.connStr(dbName)
+	dbExecute(t, dsn, fmt.Sprintf("CREATE ROLE %s LOGIN PASSWORD '%s'", otherRole, testRolePassword))
+	dbExecute(t, dsn, fmt.Sprintf("GRANT USAGE ON SCHEMA test_schema TO %s", otherRole))
+	defer func() {
+		dbExecute(t, dsn, fmt.Sprintf("DROP OWNED BY %s", otherRole))
+		dbExecute(t, dsn, fmt.Sprintf("DROP ROLE %s", otherRole))
+	}()
+
+	// We set PGUSER as owner as he will create the test table
+	tfConfig := fmt.Sprintf(`
+resource "postgresql_default_privileges" "test_ro" {
+	database    = "%s"
+	owner       = "%s"
+	roles       = ["%s", "%s"]
+	schema      = "test_schema"
+	object_type = "table"
+	privileges  = ["SELECT"]
+}
+	`, dbName, config.Username, roleName, otherRole)
+
+	checkTablesPrivileges := func(*terraform.State) error {
+		tables := []string{"test_schema.test_table"}
+		// To test default privileges, we need to create a table
+		// after having apply the state.
+		dropFunc := createTestTables(t, dbSuffix, tables, "")
+		defer dropFunc()
+
+		for _, role := range []string{roleName, otherRole} {
+			if err := testCheckTablesPrivileges(t, dbName, role, tables, []string{"SELECT"}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: tfConfig,
+				Check: resource.ComposeTestCheckFunc(
+					checkTablesPrivileges,
+					resource.TestCheckResourceAttr(
+						"postgresql_default_privileges.test_ro", "id",
+						fmt.Sprintf("%s,%s_%s_test_schema_%s_table", roleName, otherRole, dbName, config.Username),
+					),
+					resource.TestCheckResourceAttr("postgresql_default_privileges.test_ro", "roles.#", "2"),
+				),
+			},
+			{
+				// The default privileges revoked from one of the roles outside of Terraform are granted again.
+				PreConfig: func() {
+					dbExecute(t, dsn, fmt.Sprintf(
+						"ALTER DEFAULT PRIVILEGES FOR ROLE %s IN SCHEMA test_schema REVOKE ALL ON TABLES FROM %s",
+						pq.QuoteIdentifier(config.Username), otherRole,
+					))
+				},
+				Config: tfConfig,
+				Check:  checkTablesPrivileges,
+			},
+		},
+	})
+}
+
 // Test the case where we need to grant the owner to the connected user.
 // The owner should be revoked
 func TestAccPostgresqlDefaultPrivileges_GrantOwner(t *testing.T) {
@@ -0,0 +1,292 @@
+package postgresql
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	txnSettingsRoleAttr               = "role"
+	txnSettingsDatabaseAttr           = "database"
+	txnSettingsIsolationAttr          = "default_transaction_isolation"
+	txnSettingsReadOnlyAttr           = "default_transaction_read_only"
+	txnSettingsIdleSessionTimeoutAttr = "idle_session_timeout"
+)
+
+var allowedTransactionIsolations = []string{
+	"read uncommitted",
+	"read committed",
+	"repeatable read",
+	"serializable",
+}
+
+func resourcePostgreSQLDefaultTransactionSettings() *schema.Resource {
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLDefaultTransactionSettingsCreate),
+		Read:   PGReadResourceFunc(resourcePostgreSQLDefaultTransactionSettingsRead),
+		Update: PGResourceFunc(resourcePostgreSQLDefaultTransactionSettingsUpdate),
+		Delete: PGResourceFunc(resourcePostgreSQLDefaultTransactionSettingsDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			txnSettingsRoleAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				AtLeastOneOf: []string{txnSettingsRoleAttr, txnSettingsDatabaseAttr},
+				Description:  "The role to set the defaults for (all roles if not set)",
+			},
+			txnSettingsDatabaseAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				AtLeastOneOf: []string{txnSettingsRoleAttr, txnSettingsDatabaseAttr},
+				Description:  "The database to set the defaults for (all databases if not set)",
+			},
+			txnSettingsIsolationAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(allowedTransactionIsolations, false),
+				Description:  "The default isolation level of each new transaction (one of: " + strings.Join(allowedTransactionIsolations, ", ") + ")",
+			},
+			txnSettingsReadOnlyAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether each new transaction is read-only by default",
+			},
+			txnSettingsIdleSessionTimeoutAttr: {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Terminate any session that has been idle for longer than the specified amount of time in milliseconds (0 disables the timeout)",
+			},
+		},
+	}
+}
+
+func resourcePostgreSQLDefaultTransactionSettingsCreate(db *DBConnection, d *schema.ResourceData) error {
+	if err := setDefaultTransactionSettings(db, d, false); err != nil {
+		return err
+	}
+
+	d.SetId(generateDefaultTransactionSettingsID(d))
+
+	return resourcePostgreSQLDefaultTransactionSettingsReadImpl(db, d)
+}
+
+func resourcePostgreSQLDefaultTransactionSettingsUpdate(db *DBConnection, d *schema.ResourceData) error {
+	if err := setDefaultTransactionSettings(db, d, false); err != nil {
+		return err
+	}
+
+	return resourcePostgreSQLDefaultTransactionSettingsReadImpl(db, d)
+}
+
+func resourcePostgreSQLDefaultTransactionSettingsDelete(db *DBConnection, d *schema.ResourceData) error {
+	if err := setDefaultTransactionSettings(db, d, true); err != nil {
+		return err
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+func resourcePostgreSQLDefaultTransactionSettingsRead(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLDefaultTransactionSettingsReadImpl(db, d)
+}
+
+func resourcePostgreSQLDefaultTransactionSettingsReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	role := d.Get(txnSettingsRoleAttr).(string)
+	database := d.Get(txnSettingsDatabaseAttr).(string)
+	if role == "" && database == "" {
+		// Imported resource: the ID is the role and the database joined with generateObjectID.
+		parts, err := parseObjectID(d.Id())
+		if err != nil {
+			return err
+		}
+		if len(parts) != 2 || (parts[0] == "" && parts[1] == "") {
+			return fmt.Errorf("default transaction settings ID %s has not the expected format <role>.<database> (one of them can be empty)", d.Id())
+		}
+		role, database = parts[0], parts[1]
+		d.Set(txnSettingsRoleAttr, role)
+		d.Set(txnSettingsDatabaseAttr, database)
+	}
+
+	txn, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if role != "" {
+		exists, err := roleExists(txn, role)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			log.Printf("[WARN] PostgreSQL role (%s) not found, removing default transaction settings from state", role)
+			d.SetId("")
+			return nil
+		}
+	}
+	if database != "" {
+		exists, err := dbExists(txn, database)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			log.Printf("[WARN] PostgreSQL database (%s) not found, removing default transaction settings from state", database)
+			d.SetId("")
+			return nil
+		}
+	}
+
+	settings, err := readDBRoleSettings(txn, role, database)
+	if err != nil {
+		return err
+	}
+
+	readOnly := false
+	if v, ok := settings[txnSettingsReadOnlyAttr]; ok {
+		// Boolean parameters accept on/off, true/false, yes/no, 1/0 and are stored as written.
+		switch strings.ToLower(v) {
+		case "on", "true", "yes", "1":
+			readOnly = true
+		case "off", "false", "no", "0":
+			readOnly = false
+		default:
+			return fmt.Errorf("could not parse %s: invalid boolean %q", txnSettingsReadOnlyAttr, v)
+		}
+	}
+
+	idleSessionTimeout := 0
+	if v, ok := settings[txnSettingsIdleSessionTimeoutAttr]; ok {
+		if idleSessionTimeout, err = parseDurationSettingMs(v); err != nil {
+			return fmt.Errorf("could not parse %s: %w", txnSettingsIdleSessionTimeoutAttr, err)
+		}
+	}
+
+	d.Set(txnSettingsIsolationAttr, settings[txnSettingsIsolationAttr])
+	d.Set(txnSettingsReadOnlyAttr, readOnly)
+	d.Set(txnSettingsIdleSessionTimeoutAttr, idleSessionTimeout)
+	d.SetId(generateDefaultTransactionSettingsID(d))
+
+	return nil
+}
+
+var durationSettingRegexp = regexp.MustCompile(`^\s*([-+]?[0-9]*\.?[0-9]+(?:[eE][-+]?[0-9]+)?)\s*([a-z]*)\s*$`)
+
+// durationUnitsMs are the time units of the PostgreSQL settings, in milliseconds.
+var durationUnitsMs = map[string]float64{
+	"us":  0.001,
+	"ms":  1,
+	"s":   1000,
+	"min": 60 * 1000,
+	"h":   60 * 60 * 1000,
+	"d":   24 * 60 * 60 * 1000,
+}
+
+// parseDurationSettingMs parses the value of a time setting in milliseconds as it's stored by PostgreSQL,
+// i.e. as it was written: a number of milliseconds or a number with a unit (e.g.: 600000, 10min, 1.5 h).
+// The value is rounded to the millisecond like PostgreSQL does.
+func parseDurationSettingMs(value string) (int, error) {
+	match := durationSettingRegexp.FindStringSubmatch(value)
+	if match == nil {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	number, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q: %w", value, err)
+	}
+	unit := match[2]
+	if unit == "" {
+		unit = "ms"
+	}
+	multiplier, ok := durationUnitsMs[unit]
+	if !ok {
+		return 0, fmt.Errorf("invalid unit %q in duration %q (expected one of: us, ms, s, min, h, d)", unit, value)
+	}
+	return int(math.Round(number * multiplier)), nil
+}
+
+// setDefaultTransactionSettings applies the settings which have changed.
+// Settings with their default value (or all settings if reset is true) are reset
+// so the server configuration applies.
+func setDefaultTransactionSettings(db *DBConnection, d *schema.ResourceData, reset bool) error {
+	if d.Get(txnSettingsIdleSessionTimeoutAttr).(int) != 0 && !db.featureSupported(featureIdleSessionTimeout) {
+		return fmt.Errorf(
+			"idle_session_timeout is not supported for this Postgres version (%s)",
+			db.version,
+		)
+	}
+
+	values := map[string]string{}
+	if v := d.Get(txnSettingsIsolationAttr).(string); v != "" {
+		values[txnSettingsIsolationAttr] = pq.QuoteLiteral(v)
+	}
+	if d.Get(txnSettingsReadOnlyAttr).(bool) {
+		values[txnSettingsReadOnlyAttr] = "on"
+	}
+	if v := d.Get(txnSettingsIdleSessionTimeoutAttr).(int); v != 0 {
+		values[txnSettingsIdleSessionTimeoutAttr] = strconv.Itoa(v)
+	}
+
+	target := defaultTransactionSettingsTarget(d)
+	for _, attr := range []string{txnSettingsIsolationAttr, txnSettingsReadOnlyAttr, txnSettingsIdleSessionTimeoutAttr} {
+		if !reset && !d.HasChange(attr) {
+			continue
+		}
+
+		var sql string
+		if value, ok := values[attr]; ok && !reset {
+			sql = fmt.Sprintf("%s SET %s TO %s", target, attr, value)
+		} else {
+			if attr == txnSettingsIdleSessionTimeoutAttr && !db.featureSupported(featureIdleSessionTimeout) {
+				continue
+			}
+			sql = fmt.Sprintf("%s RESET %s", target, attr)
+		}
+
+		if _, err := db.Exec(sql); err != nil {
+			return fmt.Errorf("could not set %s: %w", attr, err)
+		}
+	}
+
+	return nil
+}
+
+// defaultTransactionSettingsTarget returns the beginning of the ALTER statement
+// for the role and/or the database.
+func defaultTransactionSettingsTarget(d *schema.ResourceData) string {
+	role := d.Get(txnSettingsRoleAttr).(string)
+	database := d.Get(txnSettingsDatabaseAttr).(string)
+
+	switch {
+	case role != "" && database != "":
+		return fmt.Sprintf("ALTER ROLE %s IN DATABASE %s", pq.QuoteIdentifier(role), pq.QuoteIdentifier(database))
+	case role != "":
+		return fmt.Sprintf("ALTER ROLE %s", pq.QuoteIdentifier(role))
+	default:
+		return fmt.Sprintf("ALTER DATABASE %s", pq.QuoteIdentifier(database))
+	}
+}
+
+func generateDefaultTransactionSettingsID(d *schema.ResourceData) string {
+	return strings.Join([]string{
+		d.Get(txnSettingsRoleAttr).(string),
+		d.Get(txnSettingsDatabaseAttr).(string),
+	}, "_")
+}
@@ -0,0 +1,172 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccPostgresqlDefaultTransactionSettings_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDefaultTransactionSettingsDestroy(t, roleName, dbName),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "postgresql_default_transaction_settings" "test" {
+	role                          = "%s"
+	database                      = "%s"
+	default_transaction_isolation = "serializable"
+	default_transaction_read_only = true
+}
+`, roleName, dbName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_default_transaction_settings.test", "default_transaction_isolation", "serializable"),
+					resource.TestCheckResourceAttr("postgresql_default_transaction_settings.test", "default_transaction_read_only", "true"),
+					resource.TestCheckResourceAttr("postgresql_default_transaction_settings.test", "idle_session_timeout", "0"),
+					testAccCheckDefaultTransactionSettings(t, roleName, dbName, "default_transaction_isolation", "serializable"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+resource "postgresql_default_transaction_settings" "test" {
+	role                          = "%s"
+	database                      = "%s"
+	default_transaction_isolation = "repeatable read"
+}
+`, roleName, dbName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_default_transaction_settings.test", "default_transaction_isolation", "repeatable read"),
+					resource.TestCheckResourceAttr("postgresql_default_transaction_settings.test", "default_transaction_read_only", "false"),
+					testAccCheckDefaultTransactionSettings(t, roleName, dbName, "default_transaction_read_only", ""),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlDefaultTransactionSettings_IdleSessionTimeout(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, false)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureIdleSessionTimeout)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDefaultTransactionSettingsDestroy(t, "", dbName),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "postgresql_default_transaction_settings" "test" {
+	database             = "%s"
+	idle_session_timeout = 60000
+}
+`, dbName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_default_transaction_settings.test", "idle_session_timeout", "60000"),
+					testAccCheckDefaultTransactionSettings(t, "", dbName, "idle_session_timeout", "60000"),
+				),
+			},
+			// A value set with a unit outside of Terraform is read in milliseconds.
+			{
+				PreConfig: func() {
+					config := getTestConfig(t)
+					dbExecute(t, config.connStr("postgres"), fmt.Sprintf("ALTER DATABASE %s SET idle_session_timeout TO '1min'", dbName))
+				},
+				Config: fmt.Sprintf(`
+resource "postgresql_default_transaction_settings" "test" {
+	database             = "%s"
+	idle_session_timeout = 60000
+}
+`, dbName),
+				PlanOnly: true,
+			},
+			{
+				ResourceName:      "postgresql_default_transaction_settings.test",
+				ImportState:       true,
+				ImportStateId:     "." + dbName,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestParseDurationSettingMs(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected int
+		wantErr  bool
+	}{
+		{value: "600000", expected: 600000},
+		{value: "0", expected: 0},
+		{value: "10s", expected: 10000},
+		{value: "1min", expected: 60000},
+		{value: "1.5 h", expected: 5400000},
+		{value: "2d", expected: 172800000},
+		{value: "1500us", expected: 2},
+		{value: "250ms", expected: 250},
+		{value: "1week", wantErr: true},
+		{value: "abc", wantErr: true},
+	}
+
+	for _, test := range tests {
+		actual, err := parseDurationSettingMs(test.value)
+		if (err != nil) != test.wantErr {
+			t.Errorf("parseDurationSettingMs(%q) returned error %v, want error: %t", test.value, err, test.wantErr)
+			continue
+		}
+		if actual != test.expected {
+			t.Errorf("parseDurationSettingMs(%q) = %d, want %d", test.value, actual, test.expected)
+		}
+	}
+}
+
+func testAccCheckDefaultTransactionSettings(t *testing.T, role, database, setting, expected string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		config := getTestConfig(t)
+		db, err := sql.Open("postgres", config.connStr("postgres"))
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		settings, err := readDBRoleSettings(db, role, database)
+		if err != nil {
+			return err
+		}
+		if settings[setting] != expected {
+			return fmt.Errorf("expected %s to be %q, got %q", setting, expected, settings[setting])
+		}
+		return nil
+	}
+}
+
+func testAccCheckDefaultTransactionSettingsDestroy(t *testing.T, role, database string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		for _, setting := range []string{"default_transaction_isolation", "default_transaction_read_only", "idle_session_timeout"} {
+			if err := testAccCheckDefaultTransactionSettings(t, role, database, setting, "")(nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
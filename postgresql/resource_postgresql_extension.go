@@ -2,6 +2,7 @@ package postgresql
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -9,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/lib/pq"
 )
 
@@ -19,18 +21,32 @@ const (
 	extDatabaseAttr      = "database"
 	extDropCascadeAttr   = "drop_cascade"
 	extCreateCascadeAttr = "create_cascade"
+	extUpgradePolicyAttr = "upgrade_policy"
+	extAvailUpdateAttr   = "available_update"
+	extExecuteAsAttr     = "execute_as"
+)
+
+const (
+	// Run ALTER EXTENSION UPDATE when version changes, or to the default version of the server
+	// (available_update) when version is not configured
+	extUpgradePolicyAuto = "auto"
+	// Run ALTER EXTENSION UPDATE TO only when an explicit version is set
+	extUpgradePolicyExact = "exact"
+	// Never run ALTER EXTENSION UPDATE, version changes are made outside of Terraform
+	extUpgradePolicyNever = "never"
 )
 
 func resourcePostgreSQLExtension() *schema.Resource {
 	return &schema.Resource{
 		Create: PGResourceFunc(resourcePostgreSQLExtensionCreate),
-		Read:   PGResourceFunc(resourcePostgreSQLExtensionRead),
+		Read:   PGReadResourceFunc(resourcePostgreSQLExtensionRead),
 		Update: PGResourceFunc(resourcePostgreSQLExtensionUpdate),
 		Delete: PGResourceFunc(resourcePostgreSQLExtensionDelete),
 		Exists: PGResourceExistsFunc(resourcePostgreSQLExtensionExists),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		CustomizeDiff: resourcePostgreSQLExtensionCustomizeDiff,
 
 		Schema: map[string]*schema.Schema{
 			extNameAttr: {
@@ -45,10 +61,35 @@ func resourcePostgreSQLExtension() *schema.Resource {
 				Description: "Sets the schema of an extension",
 			},
 			extVersionAttr: {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					// Version is managed outside of Terraform once the extension is created
+					return d.Id() != "" && d.Get(extUpgradePolicyAttr).(string) == extUpgradePolicyNever
+				},
+				Description: "Sets the version number of the extension",
+			},
+			extUpgradePolicyAttr: {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  extUpgradePolicyAuto,
+				ValidateFunc: validation.StringInSlice([]string{
+					extUpgradePolicyAuto,
+					extUpgradePolicyExact,
+					extUpgradePolicyNever,
+				}, false),
+				Description: "Controls when ALTER EXTENSION UPDATE is run (one of: auto, exact, never)",
+			},
+			extAvailUpdateAttr: {
 				Type:        schema.TypeString,
-				Optional:    true,
 				Computed:    true,
-				Description: "Sets the version number of the extension",
+				Description: "The default version of the extension available on the server if it differs from the installed one",
+			},
+			extExecuteAsAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The role used to create the extension (with SET ROLE) so it is directly owned by this role. Only used when the extension is created",
 			},
 			extDatabaseAttr: {
 				Type:        schema.TypeString,
@@ -73,6 +114,185 @@ func resourcePostgreSQLExtension() *schema.Resource {
 	}
 }
 
+// resourcePostgreSQLExtensionCustomizeDiff fails the plan if the extension is not in the
+// allowed_extensions of the provider, if its version is not configured with the exact upgrade_policy
+// or if its schema can't be set (see checkExtensionSchema).
+// With the auto upgrade_policy and no configured version, an update to the default version of the
+// server is planned (see planExtensionAutoUpdate).
+func resourcePostgreSQLExtensionCustomizeDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown(extNameAttr) {
+		return nil
+	}
+	if err := checkExtensionAllowed(meta.(*Client).config, d.Get(extNameAttr).(string)); err != nil {
+		return err
+	}
+	if err := checkExtensionUpgradePolicy(d); err != nil {
+		return err
+	}
+	if err := planExtensionAutoUpdate(d); err != nil {
+		return err
+	}
+	return checkExtensionSchemaDiff(d, meta)
+}
+
+// planExtensionAutoUpdate plans an update to available_update (the default version of the server,
+// read by the refresh) when the upgrade_policy is auto and the version is not configured.
+// The version is computed, so without this removing it from the configuration would never
+// produce a diff and the installed version would be kept forever.
+func planExtensionAutoUpdate(d *schema.ResourceDiff) error {
+	if d.Id() == "" || d.Get(extUpgradePolicyAttr).(string) != extUpgradePolicyAuto {
+		return nil
+	}
+	availableUpdate := d.Get(extAvailUpdateAttr).(string)
+	if availableUpdate == "" {
+		return nil
+	}
+	rawConfig := d.GetRawConfig()
+	if rawConfig.IsNull() || !rawConfig.IsKnown() {
+		return nil
+	}
+	if version := rawConfig.GetAttr(extVersionAttr); !version.IsNull() {
+		return nil
+	}
+	if err := d.SetNew(extVersionAttr, availableUpdate); err != nil {
+		return err
+	}
+	return d.SetNew(extAvailUpdateAttr, "")
+}
+
+// checkExtensionUpgradePolicy requires the version to be configured with the exact upgrade_policy.
+// The version is computed, so without it the installed version would be kept in the state and
+// the extension would never be pinned. The configured version is compared to the installed one
+// read by the refresh, a difference is planned as an update to the configured version.
+func checkExtensionUpgradePolicy(d *schema.ResourceDiff) error {
+	if d.Get(extUpgradePolicyAttr).(string) != extUpgradePolicyExact {
+		return nil
+	}
+	rawConfig := d.GetRawConfig()
+	if rawConfig.IsNull() || !rawConfig.IsKnown() {
+		return nil
+	}
+	if version := rawConfig.GetAttr(extVersionAttr); version.IsNull() {
+		return fmt.Errorf(
+			"%s is required for extension %s when %s is %s",
+			extVersionAttr, d.Get(extNameAttr).(string), extUpgradePolicyAttr, extUpgradePolicyExact,
+		)
+	}
+	return nil
+}
+
+// checkExtensionSchemaDiff checks the planned schema against the control metadata of the extension.
+// The database may not be reachable yet when planning (e.g.: it's created by the same apply),
+// the check is skipped in this case and done again by the apply.
+func checkExtensionSchemaDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.HasChange(extSchemaAttr) || !d.NewValueKnown(extSchemaAttr) || !d.NewValueKnown(extDatabaseAttr) || !d.NewValueKnown(extVersionAttr) {
+		return nil
+	}
+	newSchema := d.Get(extSchemaAttr).(string)
+	if newSchema == "" {
+		return nil
+	}
+	var currentSchema, version string
+	if d.Id() != "" {
+		oldSchema, _ := d.GetChange(extSchemaAttr)
+		currentSchema = oldSchema.(string)
+	} else {
+		version = d.Get(extVersionAttr).(string)
+	}
+
+	endpoint, _ := d.Get(endpointAttr).(string)
+	client, err := meta.(*Client).endpointClient(endpoint)
+	if err != nil {
+		return err
+	}
+	client = client.readOnlyClient()
+	database := client.databaseName
+	if v, ok := d.GetOk(extDatabaseAttr); ok {
+		database = v.(string)
+	}
+	extName := d.Get(extNameAttr).(string)
+
+	control, err := func() (*extensionControl, error) {
+		db, err := client.Connect()
+		if err != nil {
+			return nil, err
+		}
+		if exists, err := dbExists(db, database); err != nil || !exists {
+			return nil, err
+		}
+		txn, err := startTransaction(client, database)
+		if err != nil {
+			return nil, err
+		}
+		defer deferredRollback(txn)
+		return getExtensionControl(txn, extName, version)
+	}()
+	if err != nil {
+		log.Printf("[DEBUG] could not read control metadata of extension %s when planning, checking it when applying: %v", extName, err)
+		return nil
+	}
+
+	return checkExtensionSchema(control, extName, currentSchema, newSchema)
+}
+
+// extensionControl is the metadata of the control file of an extension version.
+type extensionControl struct {
+	relocatable bool
+	// schema is the schema in which the extension must be installed, if the control file sets it.
+	schema string
+}
+
+// getExtensionControl returns the control metadata of the version of the extension.
+// If version is empty, the installed version is used or, if the extension is not installed, the default one.
+// It returns nil if the extension is not available on the server.
+func getExtensionControl(txn *sql.Tx, extName, version string) (*extensionControl, error) {
+	var control extensionControl
+	err := txn.QueryRow(
+		`SELECT v.relocatable, COALESCE(v.schema::TEXT, '') `+
+			`FROM pg_catalog.pg_available_extension_versions v `+
+			`JOIN pg_catalog.pg_available_extensions a ON a.name = v.name `+
+			`WHERE v.name = $1 AND v.version = COALESCE(NULLIF($2, ''), a.installed_version, a.default_version)`,
+		extName, version,
+	).Scan(&control.relocatable, &control.schema)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("could not read control metadata of extension %s: %w", extName, err)
+	}
+	return &control, nil
+}
+
+// checkExtensionSchema returns an error if the extension can't be created in (if currentSchema is empty)
+// or moved to newSchema, instead of letting the server fail the apply.
+func checkExtensionSchema(control *extensionControl, extName, currentSchema, newSchema string) error {
+	if control == nil || newSchema == "" || newSchema == currentSchema {
+		return nil
+	}
+	if control.schema != "" && control.schema != newSchema {
+		return fmt.Errorf(
+			"extension %s must be installed in schema %s, it can't be set to %s", extName, control.schema, newSchema,
+		)
+	}
+	if currentSchema != "" && !control.relocatable {
+		return fmt.Errorf(
+			"extension %s is not relocatable, its schema can't be changed from %s to %s. Recreate the extension to change its schema",
+			extName, currentSchema, newSchema,
+		)
+	}
+	return nil
+}
+
+func checkExtensionAllowed(config Config, extName string) error {
+	if len(config.AllowedExtensions) == 0 || sliceContainsStr(config.AllowedExtensions, extName) {
+		return nil
+	}
+	return fmt.Errorf(
+		"extension %q is not allowed by the provider policy, allowed extensions are: %s",
+		extName, strings.Join(config.AllowedExtensions, ", "),
+	)
+}
+
 func resourcePostgreSQLExtensionCreate(db *DBConnection, d *schema.ResourceData) error {
 	if !db.featureSupported(featureExtension) {
 		return fmt.Errorf(
@@ -82,6 +302,9 @@ func resourcePostgreSQLExtensionCreate(db *DBConnection, d *schema.ResourceData)
 	}
 
 	extName := d.Get(extNameAttr).(string)
+	if err := checkExtensionAllowed(db.client.config, extName); err != nil {
+		return err
+	}
 	databaseName := getDatabaseForExtension(d, db.client.databaseName)
 
 	b := bytes.NewBufferString("CREATE EXTENSION IF NOT EXISTS ")
@@ -105,8 +328,21 @@ func resourcePostgreSQLExtensionCreate(db *DBConnection, d *schema.ResourceData)
 	}
 	defer deferredRollback(txn)
 
+	if v, ok := d.GetOk(extSchemaAttr); ok {
+		control, err := getExtensionControl(txn, extName, d.Get(extVersionAttr).(string))
+		if err != nil {
+			return err
+		}
+		if err := checkExtensionSchema(control, extName, "", v.(string)); err != nil {
+			return err
+		}
+	}
+
 	sql := b.String()
-	if _, err := txn.Exec(sql); err != nil {
+	if err := withExecuteAs(txn, d.Get(extExecuteAsAttr).(string), func() error {
+		_, err := txn.Exec(sql)
+		return err
+	}); err != nil {
 		return err
 	}
 
@@ -181,11 +417,13 @@ func resourcePostgreSQLExtensionReadImpl(db *DBConnection, d *schema.ResourceDat
 	}
 	defer deferredRollback(txn)
 
-	var extSchema, extVersion string
-	query := `SELECT n.nspname, e.extversion ` +
+	var extSchema, extVersion, availableUpdate string
+	query := `SELECT n.nspname, e.extversion, ` +
+		`COALESCE((SELECT a.default_version FROM pg_catalog.pg_available_extensions a ` +
+		`WHERE a.name = e.extname AND a.default_version <> e.extversion), '') ` +
 		`FROM pg_catalog.pg_extension e, pg_catalog.pg_namespace n ` +
 		`WHERE n.oid = e.extnamespace AND e.extname = $1`
-	err = txn.QueryRow(query, extName).Scan(&extSchema, &extVersion)
+	err = txn.QueryRow(query, extName).Scan(&extSchema, &extVersion, &availableUpdate)
 	switch {
 	case err == sql.ErrNoRows:
 		log.Printf("[WARN] PostgreSQL extension (%s) not found for database %s", extName, database)
@@ -198,6 +436,7 @@ func resourcePostgreSQLExtensionReadImpl(db *DBConnection, d *schema.ResourceDat
 	d.Set(extNameAttr, extName)
 	d.Set(extSchemaAttr, extSchema)
 	d.Set(extVersionAttr, extVersion)
+	d.Set(extAvailUpdateAttr, availableUpdate)
 	d.Set(extDatabaseAttr, database)
 	d.SetId(generateExtensionID(d, database))
 
@@ -278,12 +517,20 @@ func setExtSchema(txn *sql.Tx, d *schema.ResourceData) error {
 	}
 
 	extName := d.Get(extNameAttr).(string)
-	_, nraw := d.GetChange(extSchemaAttr)
+	oraw, nraw := d.GetChange(extSchemaAttr)
 	n := nraw.(string)
 	if n == "" {
 		return errors.New("Error setting extension name to an empty string")
 	}
 
+	control, err := getExtensionControl(txn, extName, "")
+	if err != nil {
+		return err
+	}
+	if err := checkExtensionSchema(control, extName, oraw.(string), n); err != nil {
+		return err
+	}
+
 	sql := fmt.Sprintf("ALTER EXTENSION %s SET SCHEMA %s",
 		pq.QuoteIdentifier(extName), pq.QuoteIdentifier(n))
 	if _, err := txn.Exec(sql); err != nil {
@@ -299,12 +546,17 @@ func setExtVersion(txn *sql.Tx, d *schema.ResourceData) error {
 	}
 
 	extName := d.Get(extNameAttr).(string)
+	_, nraw := d.GetChange(extVersionAttr)
+	n := nraw.(string)
+
+	if d.Get(extUpgradePolicyAttr).(string) == extUpgradePolicyNever {
+		log.Printf("[DEBUG] upgrade_policy is %s, not updating extension %s", extUpgradePolicyNever, extName)
+		return nil
+	}
 
 	b := bytes.NewBufferString("ALTER EXTENSION ")
 	fmt.Fprintf(b, "%s UPDATE", pq.QuoteIdentifier(extName))
 
-	_, nraw := d.GetChange(extVersionAttr)
-	n := nraw.(string)
 	if n != "" {
 		fmt.Fprintf(b, " TO %s", pq.QuoteIdentifier(n))
 	}
@@ -3,10 +3,12 @@ package postgresql
 import (
 	"database/sql"
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestAccPostgresqlExtension_Basic(t *testing.T) {
@@ -34,6 +36,10 @@ func TestAccPostgresqlExtension_Basic(t *testing.T) {
 					// version 1.3 and PG 9.2 ships with pg_trgm 1.0.
 					resource.TestCheckResourceAttrSet(
 						"postgresql_extension.myextension", "version"),
+					resource.TestCheckResourceAttr(
+						"postgresql_extension.myextension", "upgrade_policy", "auto"),
+					resource.TestCheckResourceAttr(
+						"postgresql_extension.myextension", "available_update", ""),
 				),
 			},
 		},
@@ -154,6 +160,116 @@ func TestAccPostgresqlExtension_SchemaRename(t *testing.T) {
 	})
 }
 
+func TestAccPostgresqlExtension_UpgradePolicyNever(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureExtension)
+			testSuperuserPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlExtensionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccPostgresqlExtensionUpgradePolicyConfig, ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlExtensionExists("postgresql_extension.myextension"),
+					resource.TestCheckResourceAttr(
+						"postgresql_extension.myextension", "upgrade_policy", "never"),
+				),
+			},
+			// Version changes are ignored once the extension is created
+			{
+				Config:             fmt.Sprintf(testAccPostgresqlExtensionUpgradePolicyConfig, `version = "0.1"`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: false,
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlExtension_UpgradePolicyAuto(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureExtension)
+			testSuperuserPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlExtensionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccPostgresqlExtensionUpgradePolicyAutoConfig, `version = "1.3"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlExtensionExists("postgresql_extension.myextension"),
+					resource.TestCheckResourceAttr(
+						"postgresql_extension.myextension", "version", "1.3"),
+				),
+			},
+			// Removing the version updates the extension to the default version of the server
+			{
+				Config: fmt.Sprintf(testAccPostgresqlExtensionUpgradePolicyAutoConfig, ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlExtensionDefaultVersion("postgresql_extension.myextension"),
+					resource.TestCheckResourceAttr(
+						"postgresql_extension.myextension", "available_update", ""),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckPostgresqlExtensionDefaultVersion checks that both the installed version
+// and the version in the state are the default version of the server.
+func testAccCheckPostgresqlExtensionDefaultVersion(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Resource not found: %s", n)
+		}
+
+		client := testAccProvider.Meta().(*Client)
+		txn, err := startTransaction(client, rs.Primary.Attributes[extDatabaseAttr])
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		var installedVersion, defaultVersion string
+		query := "SELECT e.extversion, a.default_version FROM pg_catalog.pg_extension e " +
+			"JOIN pg_catalog.pg_available_extensions a ON a.name = e.extname WHERE e.extname = $1"
+		if err := txn.QueryRow(query, rs.Primary.Attributes[extNameAttr]).Scan(&installedVersion, &defaultVersion); err != nil {
+			return fmt.Errorf("could not read the versions of extension %s: %w", rs.Primary.Attributes[extNameAttr], err)
+		}
+
+		if installedVersion != defaultVersion {
+			return fmt.Errorf("expected extension to be updated to %s, installed version is %s", defaultVersion, installedVersion)
+		}
+		if stateVersion := rs.Primary.Attributes[extVersionAttr]; stateVersion != defaultVersion {
+			return fmt.Errorf("expected version %s in the state, got %s", defaultVersion, stateVersion)
+		}
+		return nil
+	}
+}
+
+func TestAccPostgresqlExtension_UpgradePolicyExact(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureExtension)
+			testSuperuserPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlExtensionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccPostgresqlExtensionUpgradePolicyExactConfig,
+				ExpectError: regexp.MustCompile("version is required for extension pg_trgm when upgrade_policy is exact"),
+			},
+		},
+	})
+}
+
 func checkExtensionExists(txn *sql.Tx, extensionName string) (bool, error) {
 	var _rez bool
 	err := txn.QueryRow("SELECT TRUE from pg_catalog.pg_extension d WHERE extname=$1", extensionName).Scan(&_rez)
@@ -338,3 +454,85 @@ resource "postgresql_extension" "ext1trgm" {
   schema = "${postgresql_schema.ext1foo.name}"
 }
 `
+
+var testAccPostgresqlExtensionUpgradePolicyConfig = `
+resource "postgresql_extension" "myextension" {
+  name           = "pg_trgm"
+  upgrade_policy = "never"
+  %s
+}
+`
+
+var testAccPostgresqlExtensionUpgradePolicyAutoConfig = `
+resource "postgresql_extension" "myextension" {
+  name = "pg_trgm"
+  %s
+}
+`
+
+var testAccPostgresqlExtensionUpgradePolicyExactConfig = `
+resource "postgresql_extension" "myextension" {
+  name           = "pg_trgm"
+  upgrade_policy = "exact"
+}
+`
+
+func TestCheckExtensionAllowed(t *testing.T) {
+	assert.NoError(t, checkExtensionAllowed(Config{}, "pg_trgm"))
+
+	config := Config{AllowedExtensions: []string{"pg_trgm", "pgcrypto"}}
+	assert.NoError(t, checkExtensionAllowed(config, "pgcrypto"))
+	assert.EqualError(
+		t,
+		checkExtensionAllowed(config, "plpython3u"),
+		`extension "plpython3u" is not allowed by the provider policy, allowed extensions are: pg_trgm, pgcrypto`,
+	)
+}
+
+func TestCheckExtensionSchema(t *testing.T) {
+	relocatable := &extensionControl{relocatable: true}
+	pinned := &extensionControl{relocatable: false, schema: "pg_catalog"}
+	notRelocatable := &extensionControl{relocatable: false}
+
+	assert.NoError(t, checkExtensionSchema(nil, "unknown", "", "foo"))
+	assert.NoError(t, checkExtensionSchema(relocatable, "pg_trgm", "", "foo"))
+	assert.NoError(t, checkExtensionSchema(relocatable, "pg_trgm", "foo", "bar"))
+	assert.NoError(t, checkExtensionSchema(pinned, "plpgsql", "", "pg_catalog"))
+	assert.NoError(t, checkExtensionSchema(pinned, "plpgsql", "pg_catalog", ""))
+	assert.NoError(t, checkExtensionSchema(notRelocatable, "postgis", "", "gis"))
+	assert.NoError(t, checkExtensionSchema(notRelocatable, "postgis", "gis", "gis"))
+
+	assert.EqualError(
+		t,
+		checkExtensionSchema(pinned, "plpgsql", "", "public"),
+		"extension plpgsql must be installed in schema pg_catalog, it can't be set to public",
+	)
+	assert.EqualError(
+		t,
+		checkExtensionSchema(notRelocatable, "postgis", "gis", "public"),
+		"extension postgis is not relocatable, its schema can't be changed from gis to public. Recreate the extension to change its schema",
+	)
+}
+
+func TestAccPostgresqlExtension_SchemaNotAllowed(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureExtension)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				// plpgsql is installed in pg_catalog by its control file.
+				Config: `
+resource "postgresql_extension" "plpgsql" {
+  name   = "plpgsql"
+  schema = "public"
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("extension plpgsql must be installed in schema pg_catalog"),
+			},
+		},
+	})
+}
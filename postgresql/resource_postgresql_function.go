@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -25,6 +27,13 @@ const (
 	funcSecurityDefinerAttr = "security_definer"
 	funcStrictAttr          = "strict"
 	funcVolatilityAttr      = "volatility"
+	funcExecuteAsAttr       = "execute_as"
+	funcLeakproofAttr       = "leakproof"
+	funcSupportAttr         = "support"
+	funcTransformTypesAttr  = "transform_types"
+	funcCostAttr            = "cost"
+	funcRowsAttr            = "rows"
+	funcSettingsAttr        = "settings"
 
 	funcArgTypeAttr    = "type"
 	funcArgNameAttr    = "name"
@@ -33,12 +42,13 @@ const (
 
 	defaultFunctionVolatility = "VOLATILE"
 	defaultFunctionParallel   = "UNSAFE"
+	defaultFunctionRows       = 1000
 )
 
 func resourcePostgreSQLFunction() *schema.Resource {
 	return &schema.Resource{
 		Create: PGResourceFunc(resourcePostgreSQLFunctionCreate),
-		Read:   PGResourceFunc(resourcePostgreSQLFunctionRead),
+		Read:   PGReadResourceFunc(resourcePostgreSQLFunctionRead),
 		Update: PGResourceFunc(resourcePostgreSQLFunctionUpdate),
 		Delete: PGResourceFunc(resourcePostgreSQLFunctionDelete),
 		Exists: PGResourceExistsFunc(resourcePostgreSQLFunctionExists),
@@ -167,6 +177,46 @@ func resourcePostgreSQLFunction() *schema.Resource {
 				DiffSuppressFunc: defaultDiffSuppressFunc,
 				ValidateFunc:     validation.StringInSlice([]string{"VOLATILE", "STABLE", "IMMUTABLE"}, false),
 			},
+			funcLeakproofAttr: {
+				Type:        schema.TypeBool,
+				Description: "If the function has no side effects and reveals no information about its arguments other than by its return value (required to be used with row level security).",
+				Optional:    true,
+				Default:     false,
+			},
+			funcSupportAttr: {
+				Type:        schema.TypeString,
+				Description: "The schema-qualified name of the planner support function of the function.",
+				Optional:    true,
+			},
+			funcTransformTypesAttr: {
+				Type:        schema.TypeSet,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "The types for which the transforms of the language are applied to the arguments and the result of the function.",
+				Optional:    true,
+			},
+			funcCostAttr: {
+				Type:        schema.TypeFloat,
+				Description: "The estimated execution cost of the function, in units of cpu_operator_cost. If not specified, the default of PostgreSQL is used (1 for C and internal functions, 100 otherwise).",
+				Optional:    true,
+			},
+			funcRowsAttr: {
+				Type:        schema.TypeFloat,
+				Description: "The estimated number of rows returned by the function, only for the functions returning a set. If not specified, the default of PostgreSQL is used (1000).",
+				Optional:    true,
+			},
+			funcSettingsAttr: {
+				Type:             schema.TypeMap,
+				Elem:             &schema.Schema{Type: schema.TypeString},
+				Description:      "The configuration parameters set when the function is called (SET clauses, e.g.: search_path).",
+				Optional:         true,
+				ValidateDiagFunc: validation.MapKeyMatch(dbSettingNameRegexp, "must be the name of a configuration parameter (e.g.: search_path)"),
+			},
+			funcExecuteAsAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The role used to create or replace the function (with SET ROLE) so it is directly owned by this role.",
+			},
 			funcDatabaseAttr: {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -259,9 +309,36 @@ func resourcePostgreSQLFunctionReadImpl(db *DBConnection, d *schema.ResourceData
 		return expandErr
 	}
 
-	var funcDefinition string
-
-	query := `SELECT pg_get_functiondef(p.oid::regproc) funcDefinition ` +
+	var funcDefinition, support string
+	var leakproof, retset, sameSupport bool
+	var configuredTransformTypes, transformTypes, config []string
+	var cost, rows float64
+
+	// The options which are not parsed from the definition are read from pg_proc.
+	// As for the functions of postgresql_aggregate, the configured support function and transform types
+	// are kept if they're the ones of the function, as they can be written in several ways (e.g.: int4 or integer).
+	queryArgs := []interface{}{functionSignature}
+	supportColumns := "'', false"
+	if db.featureSupported(featureFunctionSupport) {
+		queryArgs = append(queryArgs, d.Get(funcSupportAttr).(string))
+		supportColumns = fmt.Sprintf(
+			`CASE WHEN p.prosupport::oid <> 0 THEN p.prosupport::text ELSE '' END, `+
+				`COALESCE(to_regproc(NULLIF($%d, '')) = p.prosupport, false)`,
+			len(queryArgs),
+		)
+	}
+	transformColumns := "'{}'::text[], '{}'::text[]"
+	if db.featureSupported(featureFunctionTransform) {
+		queryArgs = append(queryArgs, pq.Array(setToSortedSlice(d.Get(funcTransformTypesAttr).(*schema.Set))))
+		transformColumns = fmt.Sprintf(
+			`ARRAY(SELECT c FROM unnest($%[1]d::text[]) c WHERE to_regtype(c) = ANY(p.protrftypes)), `+
+				`ARRAY(SELECT format_type(t, NULL) FROM unnest(p.protrftypes) t `+
+				`WHERE NOT EXISTS (SELECT 1 FROM unnest($%[1]d::text[]) c WHERE to_regtype(c) = t))`,
+			len(queryArgs),
+		)
+	}
+	query := `SELECT pg_get_functiondef(p.oid::regproc) funcDefinition, p.proleakproof, ` + supportColumns + `, ` +
+		transformColumns + `, p.procost, p.prorows, p.proretset, COALESCE(p.proconfig, '{}') ` +
 		`FROM pg_proc p ` +
 		`LEFT JOIN pg_namespace n ON p.pronamespace = n.oid ` +
 		`WHERE p.oid = to_regprocedure($1)`
@@ -272,7 +349,11 @@ func resourcePostgreSQLFunctionReadImpl(db *DBConnection, d *schema.ResourceData
 	}
 	defer deferredRollback(txn)
 
-	err = txn.QueryRow(query, functionSignature).Scan(&funcDefinition)
+	err = txn.QueryRow(query, queryArgs...).Scan(
+		&funcDefinition, &leakproof, &support, &sameSupport,
+		pq.Array(&configuredTransformTypes), pq.Array(&transformTypes),
+		&cost, &rows, &retset, pq.Array(&config),
+	)
 	switch {
 	case err == sql.ErrNoRows:
 		log.Printf("[WARN] PostgreSQL function: %s", functionId)
@@ -314,6 +395,23 @@ func resourcePostgreSQLFunctionReadImpl(db *DBConnection, d *schema.ResourceData
 	d.Set(funcStrictAttr, pgFunction.Strict)
 	d.Set(funcParallelAttr, pgFunction.Parallel)
 	d.Set(funcVolatilityAttr, pgFunction.Volatility)
+	d.Set(funcLeakproofAttr, leakproof)
+	if sameSupport {
+		support = d.Get(funcSupportAttr).(string)
+	}
+	d.Set(funcSupportAttr, support)
+	d.Set(funcTransformTypesAttr, append(configuredTransformTypes, transformTypes...))
+	// The defaults of PostgreSQL are read as unset, unless they are configured, so removing cost or rows
+	// from the configuration resets them.
+	if cost == defaultFunctionCost(pgFunction.Language) && d.Get(funcCostAttr).(float64) == 0 {
+		cost = 0
+	}
+	if retset && rows == defaultFunctionRows && d.Get(funcRowsAttr).(float64) == 0 {
+		rows = 0
+	}
+	d.Set(funcCostAttr, cost)
+	d.Set(funcRowsAttr, rows)
+	d.Set(funcSettingsAttr, readFunctionSettings(config, d.Get(funcSettingsAttr).(map[string]interface{})))
 	d.Set(funcArgAttr, args)
 
 	d.SetId(functionId)
@@ -383,6 +481,13 @@ func createFunction(db *DBConnection, d *schema.ResourceData, replace bool) erro
 		return err
 	}
 
+	if pgFunction.Support != "" && !db.featureSupported(featureFunctionSupport) {
+		return fmt.Errorf("PostgreSQL client is talking with a server (%q) that does not support function SUPPORT", db.version.String())
+	}
+	if len(pgFunction.TransformTypes) > 0 && !db.featureSupported(featureFunctionTransform) {
+		return fmt.Errorf("PostgreSQL client is talking with a server (%q) that does not support function TRANSFORM", db.version.String())
+	}
+
 	b := bytes.NewBufferString("CREATE ")
 
 	if replace {
@@ -400,21 +505,7 @@ func createFunction(db *DBConnection, d *schema.ResourceData, replace bool) erro
 			b.WriteRune(',')
 		}
 
-		b.WriteString("\n    ")
-
-		if arg.Mode != "" {
-			fmt.Fprint(b, arg.Mode, " ")
-		}
-
-		if arg.Name != "" {
-			fmt.Fprint(b, arg.Name, " ")
-		}
-
-		b.WriteString(arg.Type)
-
-		if arg.Default != "" {
-			fmt.Fprint(b, " DEFAULT ", arg.Default)
-		}
+		fmt.Fprint(b, "\n    ", arg)
 	}
 
 	if len(pgFunction.Args) > 0 {
@@ -425,6 +516,14 @@ func createFunction(db *DBConnection, d *schema.ResourceData, replace bool) erro
 
 	fmt.Fprint(b, "\nRETURNS ", pgFunction.Returns)
 	fmt.Fprint(b, "\nLANGUAGE ", pgFunction.Language)
+	for i, transformType := range pgFunction.TransformTypes {
+		if i == 0 {
+			b.WriteString("\nTRANSFORM ")
+		} else {
+			b.WriteString(", ")
+		}
+		fmt.Fprint(b, "FOR TYPE ", transformType)
+	}
 	if pgFunction.Volatility != defaultFunctionVolatility {
 		fmt.Fprint(b, "\n", pgFunction.Volatility)
 	}
@@ -437,6 +536,27 @@ func createFunction(db *DBConnection, d *schema.ResourceData, replace bool) erro
 	if pgFunction.Strict {
 		fmt.Fprint(b, "\nSTRICT")
 	}
+	if pgFunction.Leakproof {
+		fmt.Fprint(b, "\nLEAKPROOF")
+	}
+	if pgFunction.Support != "" {
+		fmt.Fprint(b, "\nSUPPORT ", pgFunction.Support)
+	}
+	if pgFunction.Cost > 0 {
+		fmt.Fprint(b, "\nCOST ", strconv.FormatFloat(pgFunction.Cost, 'f', -1, 64))
+	}
+	if pgFunction.Rows > 0 {
+		fmt.Fprint(b, "\nROWS ", strconv.FormatFloat(pgFunction.Rows, 'f', -1, 64))
+	}
+	// The names are validated by dbSettingNameRegexp as they can't be quoted.
+	settingNames := make([]string, 0, len(pgFunction.Settings))
+	for name := range pgFunction.Settings {
+		settingNames = append(settingNames, name)
+	}
+	sort.Strings(settingNames)
+	for _, name := range settingNames {
+		fmt.Fprint(b, "\nSET ", name, " TO ", functionSettingValue(pgFunction.Settings[name]))
+	}
 
 	fmt.Fprint(b, "\nAS $function$", pgFunction.Body, "$function$;")
 
@@ -448,7 +568,10 @@ func createFunction(db *DBConnection, d *schema.ResourceData, replace bool) erro
 	}
 	defer deferredRollback(txn)
 
-	if _, err := txn.Exec(sql); err != nil {
+	if err := withExecuteAs(txn, d.Get(funcExecuteAsAttr).(string), func() error {
+		_, err := txn.Exec(sql)
+		return err
+	}); err != nil {
 		return err
 	}
 
@@ -459,6 +582,55 @@ func createFunction(db *DBConnection, d *schema.ResourceData, replace bool) erro
 	return nil
 }
 
+// functionSettingValue returns the value of a SET clause of the function. The value is split on the commas so the
+// list parameters (e.g.: search_path) are set to lists, and each element is quoted as a literal,
+// without its double quotes if it's already quoted (e.g.: "$user").
+func functionSettingValue(value string) string {
+	elements := strings.Split(value, ",")
+	for i, element := range elements {
+		element = strings.TrimSpace(element)
+		if len(element) > 1 && strings.HasPrefix(element, `"`) && strings.HasSuffix(element, `"`) {
+			element = strings.ReplaceAll(element[1:len(element)-1], `""`, `"`)
+		}
+		elements[i] = pq.QuoteLiteral(element)
+	}
+	return strings.Join(elements, ", ")
+}
+
+// defaultFunctionCost returns the cost of the functions created without COST.
+func defaultFunctionCost(language string) float64 {
+	switch strings.ToLower(language) {
+	case "c", "internal":
+		return 1
+	default:
+		return 100
+	}
+}
+
+// readFunctionSettings returns the settings of the function from pg_proc.proconfig (name=value).
+// The configured names are kept if they only differ by their case from the stored ones, and the configured
+// values if they only differ by their formatting (e.g.: the lists are stored as "a, b").
+func readFunctionSettings(config []string, configured map[string]interface{}) map[string]string {
+	settings := map[string]string{}
+	for _, setting := range config {
+		name, value, found := strings.Cut(setting, "=")
+		if !found {
+			continue
+		}
+		for configuredName, configuredValue := range configured {
+			if strings.EqualFold(configuredName, name) {
+				name = configuredName
+				if functionSettingValue(configuredValue.(string)) == functionSettingValue(value) {
+					value = configuredValue.(string)
+				}
+				break
+			}
+		}
+		settings[name] = value
+	}
+	return settings
+}
+
 func generateFunctionID(db *DBConnection, d *schema.ResourceData) (string, error) {
 
 	b := bytes.NewBufferString("")
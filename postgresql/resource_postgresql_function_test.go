@@ -3,6 +3,7 @@ package postgresql
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -222,6 +223,196 @@ resource "postgresql_function" "func" {
 	})
 }
 
+func TestAccPostgresqlFunction_Leakproof(t *testing.T) {
+	config := `
+resource "postgresql_function" "func" {
+    name       = "leakproof_func"
+    returns    = "boolean"
+    language   = "sql"
+    volatility = "IMMUTABLE"
+    leakproof  = %t
+    arg {
+        name = "i"
+        type = "integer"
+    }
+    body = "SELECT i > 0"
+}
+`
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureFunction)
+			testSuperuserPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlFunctionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlFunctionExists("postgresql_function.func", ""),
+					resource.TestCheckResourceAttr("postgresql_function.func", "leakproof", "true"),
+					resource.TestCheckResourceAttr("postgresql_function.func", "support", ""),
+					resource.TestCheckResourceAttr("postgresql_function.func", "transform_types.#", "0"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(config, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlFunctionExists("postgresql_function.func", ""),
+					resource.TestCheckResourceAttr("postgresql_function.func", "leakproof", "false"),
+				),
+			},
+		},
+	})
+}
+
+// The support function is configured with its schema while pg_catalog is in the search_path,
+// the configured name is kept as it resolves to the same function.
+func TestAccPostgresqlFunction_Support(t *testing.T) {
+	config := `
+resource "postgresql_function" "func" {
+    name       = "support_func"
+    returns    = "boolean"
+    language   = "sql"
+    volatility = "IMMUTABLE"
+    support    = "pg_catalog.textlike_support"
+    arg {
+        name = "a"
+        type = "text"
+    }
+    arg {
+        name = "b"
+        type = "text"
+    }
+    body = "SELECT a LIKE b"
+}
+`
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureFunctionSupport)
+			testSuperuserPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlFunctionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlFunctionExists("postgresql_function.func", ""),
+					resource.TestCheckResourceAttr("postgresql_function.func", "support", "pg_catalog.textlike_support"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlFunction_CostRowsSettings(t *testing.T) {
+	config := `
+resource "postgresql_function" "func" {
+    name     = "cost_rows_func"
+    returns  = "SETOF integer"
+    language = "sql"
+    cost     = %d
+    rows     = 10
+    settings = {
+      search_path = "pg_catalog, pg_temp"
+      %s
+    }
+    body = "SELECT generate_series(1, 10)"
+}
+`
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureFunction)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlFunctionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, 50, ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlFunctionExists("postgresql_function.func", ""),
+					resource.TestCheckResourceAttr("postgresql_function.func", "cost", "50"),
+					resource.TestCheckResourceAttr("postgresql_function.func", "rows", "10"),
+					resource.TestCheckResourceAttr("postgresql_function.func", "settings.%", "1"),
+					resource.TestCheckResourceAttr("postgresql_function.func", "settings.search_path", "pg_catalog, pg_temp"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(config, 200, `work_mem = "64MB"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_function.func", "cost", "200"),
+					resource.TestCheckResourceAttr("postgresql_function.func", "settings.%", "2"),
+					resource.TestCheckResourceAttr("postgresql_function.func", "settings.work_mem", "64MB"),
+				),
+			},
+			{
+				// The cost and the rows are reset to the defaults once removed from the configuration.
+				Config: strings.NewReplacer("cost     = 200", "", "rows     = 10", "").Replace(fmt.Sprintf(config, 200, "")),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_function.func", "cost", "0"),
+					resource.TestCheckResourceAttr("postgresql_function.func", "rows", "0"),
+					func(*terraform.State) error {
+						client := testAccProvider.Meta().(*Client)
+						db, err := client.Connect()
+						if err != nil {
+							return err
+						}
+
+						var cost, rows float64
+						if err := db.QueryRow(
+							"SELECT procost, prorows FROM pg_proc WHERE proname = 'cost_rows_func'",
+						).Scan(&cost, &rows); err != nil {
+							return fmt.Errorf("could not read the cost of the function: %w", err)
+						}
+						if cost != 100 || rows != defaultFunctionRows {
+							return fmt.Errorf("expected the default cost and rows, got %v and %v", cost, rows)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestFunctionSettingValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"UTC", "'UTC'"},
+		{"pg_catalog, pg_temp", "'pg_catalog', 'pg_temp'"},
+		{`"$user", public`, "'$user', 'public'"},
+		{"it's", "'it''s'"},
+	}
+
+	for _, test := range tests {
+		if got := functionSettingValue(test.value); got != test.want {
+			t.Errorf("functionSettingValue(%q) = %s, want %s", test.value, got, test.want)
+		}
+	}
+}
+
+func TestReadFunctionSettings(t *testing.T) {
+	settings := readFunctionSettings(
+		[]string{"search_path=pg_catalog, pg_temp", "TimeZone=UTC", `temp_tablespaces="$user", ts`},
+		map[string]interface{}{"timezone": "UTC", "temp_tablespaces": "$user,ts"},
+	)
+	want := map[string]string{"search_path": "pg_catalog, pg_temp", "timezone": "UTC", "temp_tablespaces": "$user,ts"}
+	if len(settings) != len(want) {
+		t.Fatalf("readFunctionSettings returned %v, want %v", settings, want)
+	}
+	for name, value := range want {
+		if settings[name] != value {
+			t.Errorf("readFunctionSettings returned %v, want %v", settings, want)
+		}
+	}
+}
+
 func testAccCheckPostgresqlFunctionExists(n string, database string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
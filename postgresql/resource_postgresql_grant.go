@@ -1,9 +1,12 @@
 package postgresql
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -21,11 +24,29 @@ var allowedObjectTypes = []string{
 	"schema",
 	"sequence",
 	"table",
+	"type",
+	"domain",
+	"large_object",
 	"foreign_data_wrapper",
 	"foreign_server",
 	"column",
+	"external_schema",
+	"datashare",
 }
 
+// objectTypesWithoutSchema are the object types which are not in a schema.
+var objectTypesWithoutSchema = []string{
+	"database",
+	"foreign_data_wrapper",
+	"foreign_server",
+	"large_object",
+	"datashare",
+}
+
+// extensionMemberObjectTypes are the object types whose grants on all the objects of the schema
+// can exclude the members of extensions (see exclude_extension_objects).
+var extensionMemberObjectTypes = []string{"table", "sequence", "function", "procedure", "routine"}
+
 var objectTypes = map[string]string{
 	"table":    "r",
 	"sequence": "S",
@@ -38,68 +59,130 @@ type ResourceSchemeGetter func(string) interface{}
 
 func resourcePostgreSQLGrant() *schema.Resource {
 	return &schema.Resource{
-		Create: PGResourceFunc(resourcePostgreSQLGrantCreate),
-		Update: PGResourceFunc(resourcePostgreSQLGrantUpdate),
-		Read:   PGResourceFunc(resourcePostgreSQLGrantRead),
-		Delete: PGResourceFunc(resourcePostgreSQLGrantDelete),
-
-		Schema: map[string]*schema.Schema{
-			"role": {
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
-				Description: "The name of the role to grant privileges on",
-			},
-			"database": {
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
-				Description: "The database to grant privileges on for this role",
-			},
-			"schema": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				ForceNew:    true,
-				Description: "The database schema to grant privileges on for this role",
-			},
-			"object_type": {
-				Type:         schema.TypeString,
-				Required:     true,
-				ForceNew:     true,
-				ValidateFunc: validation.StringInSlice(allowedObjectTypes, false),
-				Description:  "The PostgreSQL object type to grant the privileges on (one of: " + strings.Join(allowedObjectTypes, ", ") + ")",
-			},
-			"objects": {
-				Type:        schema.TypeSet,
-				Optional:    true,
-				ForceNew:    true,
-				Elem:        &schema.Schema{Type: schema.TypeString},
-				Set:         schema.HashString,
-				Description: "The specific objects to grant privileges on for this role (empty means all objects of the requested type)",
-			},
-			"columns": {
-				Type:        schema.TypeSet,
-				Optional:    true,
-				ForceNew:    true,
-				Elem:        &schema.Schema{Type: schema.TypeString},
-				Set:         schema.HashString,
-				Description: "The specific columns to grant privileges on for this role",
-			},
-			"privileges": {
-				Type:        schema.TypeSet,
-				Required:    true,
-				Elem:        &schema.Schema{Type: schema.TypeString},
-				Set:         schema.HashString,
-				Description: "The list of privileges to grant",
-			},
-			"with_grant_option": {
-				Type:        schema.TypeBool,
-				Optional:    true,
-				ForceNew:    true,
-				Default:     false,
-				Description: "Permit the grant recipient to grant it to others",
+		CreateContext: PGResourceFuncWithWarnings(resourcePostgreSQLGrantCreate, grantSecurityWarnings),
+		UpdateContext: PGResourceFuncWithWarnings(resourcePostgreSQLGrantUpdate, grantSecurityWarnings),
+		Read:          PGReadResourceFunc(readWithSecurityWarnings(resourcePostgreSQLGrantRead, grantSecurityWarnings)),
+		Delete:        PGResourceFunc(resourcePostgreSQLGrantDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourcePostgreSQLGrantImport,
+		},
+		CustomizeDiff: resourcePostgreSQLGrantCustomizeDiff,
+
+		// The version 1 changed the format of the ID (see generateGrantID).
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourcePostgreSQLGrantV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourcePostgreSQLGrantStateUpgradeV0,
 			},
 		},
+
+		Schema: resourcePostgreSQLGrantSchema(),
+	}
+}
+
+// resourcePostgreSQLGrantV0 is the version 0 of the resource, its attributes are the same as the current version.
+func resourcePostgreSQLGrantV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: resourcePostgreSQLGrantSchema(),
+	}
+}
+
+func resourcePostgreSQLGrantSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"role": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ExactlyOneOf: []string{"role", "roles"},
+			ValidateFunc: validateIdentifier,
+			Description:  "The name of the role to grant privileges on",
+		},
+		"roles": {
+			Type:         schema.TypeSet,
+			Optional:     true,
+			Elem:         &schema.Schema{Type: schema.TypeString, ValidateFunc: validateIdentifier},
+			Set:          schema.HashString,
+			ExactlyOneOf: []string{"role", "roles"},
+			Description:  "The names of the roles to grant the same privileges on, in the same statements",
+		},
+		"database": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The database to grant privileges on for this role",
+		},
+		"schema": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validateOptionalIdentifier,
+			Description:  "The database schema to grant privileges on for this role",
+		},
+		"object_type": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.StringInSlice(allowedObjectTypes, false),
+			Description:  "The PostgreSQL object type to grant the privileges on (one of: " + strings.Join(allowedObjectTypes, ", ") + ")",
+		},
+		"objects": {
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validateQualifiedIdentifier},
+			Set:         schema.HashString,
+			Description: "The specific objects to grant privileges on for this role (empty means all objects of the requested type, OIDs for large objects)",
+		},
+		"columns": {
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validateIdentifier},
+			Set:         schema.HashString,
+			Description: "The specific columns to grant privileges on for this role",
+		},
+		"privileges": {
+			Type:             schema.TypeSet,
+			Required:         true,
+			Elem:             &schema.Schema{Type: schema.TypeString},
+			Set:              hashPrivilege,
+			DiffSuppressFunc: privilegesDiffSuppress,
+			Description:      "The list of privileges to grant",
+		},
+		"with_grant_option": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Permit the grant recipient to grant it to others",
+		},
+		"verify": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Check after apply that the role effectively has the privileges and fail otherwise",
+		},
+		"verified": {
+			Type:        schema.TypeBool,
+			Computed:    true,
+			Description: "Whether the privileges have been verified during the last apply",
+		},
+		"skip_objects_check": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Don't read the privileges of each object during the refresh (their drift is not detected), e.g. for the grants on all the tables of schemas with thousands of tables",
+		},
+		"exclude_extension_objects": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Exclude the objects which are members of an extension (e.g.: the functions of postgis) from the grants on all the objects of the schema",
+		},
+		"skip_final_revoke_on_destroy": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Only remove the grant from the state when destroying it, without running REVOKE (e.g.: when the database is dropped anyway)",
+		},
+		securityWarningsAttr: securityWarningsSchema(),
 	}
 }
 
@@ -107,6 +190,9 @@ func resourcePostgreSQLGrantRead(db *DBConnection, d *schema.ResourceData) error
 	if err := validateFeatureSupport(db, d); err != nil {
 		return fmt.Errorf("feature is not supported: %v", err)
 	}
+	if sliceContainsStr(redshiftObjectTypes, d.Get("object_type").(string)) {
+		return resourceRedshiftGrantRead(db, d)
+	}
 
 	exists, err := checkRoleDBSchemaExists(db, d)
 	if err != nil {
@@ -124,7 +210,62 @@ func resourcePostgreSQLGrantRead(db *DBConnection, d *schema.ResourceData) error
 	}
 	defer deferredRollback(txn)
 
-	return readRolePrivileges(txn, d)
+	// The privileges read after a change must not come from the cache of the refresh.
+	var cache *readCache
+	if db.client.readOnly {
+		cache = db.client.readCache
+	}
+	return readRolePrivileges(txn, d, cache)
+}
+
+// checkLargeObjectCompatPrivileges adds a warning if lo_compat_privileges is on in the database:
+// the privileges of the large objects are not checked so the grants have no effect.
+func checkLargeObjectCompatPrivileges(db *DBConnection, txn *sql.Tx, database string) error {
+	var compatPrivileges string
+	err := txn.QueryRow("SELECT setting FROM pg_catalog.pg_settings WHERE name = 'lo_compat_privileges'").Scan(&compatPrivileges)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil
+	case err != nil:
+		return fmt.Errorf("could not read lo_compat_privileges setting: %w", err)
+	}
+
+	if compatPrivileges == "on" {
+		db.warn(
+			"lo_compat_privileges is on in database %q: the privileges of the large objects are not checked, "+
+				"the grants have no effect until it is turned off",
+			database,
+		)
+	}
+	return nil
+}
+
+// grantSecurityWarnings returns a warning if the privileges are granted to PUBLIC,
+// see strict_security_warnings.
+func grantSecurityWarnings(get ResourceSchemeGetter) []string {
+	for _, role := range grantGrantees(get) {
+		if isPublicRole(role) {
+			return []string{fmt.Sprintf(
+				"%s privileges are granted to PUBLIC in database %q, every role (including roles created later) receives them",
+				get("object_type").(string), get("database").(string),
+			)}
+		}
+	}
+	return nil
+}
+
+// resourcePostgreSQLGrantCustomizeDiff plans the security warnings of the grant (see grantSecurityWarnings)
+// and fails the plan if the object type is not supported by the flavor of the provider.
+func resourcePostgreSQLGrantCustomizeDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if err := customizeDiffSkipFinalRevoke(d, "database", "object_type"); err != nil {
+		return err
+	}
+	if d.NewValueKnown("object_type") {
+		if err := checkGrantFlavor(meta.(*Client).config.Flavor, d.Get("object_type").(string)); err != nil {
+			return err
+		}
+	}
+	return customizeDiffSecurityWarnings(d, meta, grantSecurityWarnings, "role", "roles", "object_type", "database")
 }
 
 func resourcePostgreSQLGrantCreate(db *DBConnection, d *schema.ResourceData) error {
@@ -142,11 +283,11 @@ func resourcePostgreSQLGrantCreateOrUpdate(db *DBConnection, d *schema.ResourceD
 
 	// Validate parameters.
 	objectType := d.Get("object_type").(string)
-	if d.Get("schema").(string) == "" && !sliceContainsStr([]string{"database", "foreign_data_wrapper", "foreign_server"}, objectType) {
+	if d.Get("schema").(string) == "" && !sliceContainsStr(objectTypesWithoutSchema, objectType) {
 		return fmt.Errorf("parameter 'schema' is mandatory for postgresql_grant resource")
 	}
-	if d.Get("objects").(*schema.Set).Len() > 0 && (objectType == "database" || objectType == "schema") {
-		return fmt.Errorf("cannot specify `objects` when `object_type` is `database` or `schema`")
+	if d.Get("objects").(*schema.Set).Len() > 0 && (objectType == "database" || objectType == "schema" || objectType == "external_schema") {
+		return fmt.Errorf("cannot specify `objects` when `object_type` is `database`, `schema` or `external_schema`")
 	}
 	if d.Get("columns").(*schema.Set).Len() > 0 && (objectType != "column") {
 		return fmt.Errorf("cannot specify `columns` when `object_type` is not `column`")
@@ -160,141 +301,219 @@ func resourcePostgreSQLGrantCreateOrUpdate(db *DBConnection, d *schema.ResourceD
 	if (d.Get("objects").(*schema.Set).Len() != 1) && (objectType == "column") {
 		return fmt.Errorf("must specify exactly 1 table in the `objects` field when `object_type` is `column`")
 	}
-	if d.Get("objects").(*schema.Set).Len() != 1 && (objectType == "foreign_data_wrapper" || objectType == "foreign_server") {
-		return fmt.Errorf("one element must be specified in `objects` when `object_type` is `foreign_data_wrapper` or `foreign_server`")
+	if d.Get("objects").(*schema.Set).Len() != 1 && (objectType == "foreign_data_wrapper" || objectType == "foreign_server" || objectType == "datashare") {
+		return fmt.Errorf("one element must be specified in `objects` when `object_type` is `foreign_data_wrapper`, `foreign_server` or `datashare`")
+	}
+	if d.Get("objects").(*schema.Set).Len() == 0 && objectType == "large_object" {
+		return fmt.Errorf("must specify the OIDs of the large objects in `objects` when `object_type` is `large_object`")
+	}
+	if d.Get("exclude_extension_objects").(bool) && (d.Get("objects").(*schema.Set).Len() > 0 || !sliceContainsStr(extensionMemberObjectTypes, objectType)) {
+		return fmt.Errorf(
+			"`exclude_extension_objects` can only be set without `objects` when `object_type` is one of: %s",
+			strings.Join(extensionMemberObjectTypes, ", "),
+		)
 	}
+	// The privileges are stored as PostgreSQL reads them back (e.g.: TEMP as TEMPORARY).
+	d.Set("privileges", normalizePrivileges(d.Get("privileges").(*schema.Set)))
 	if err := validatePrivileges(d); err != nil {
 		return err
 	}
+	if err := validateGrantObjects(d); err != nil {
+		return err
+	}
+	if sliceContainsStr(redshiftObjectTypes, objectType) {
+		return resourceRedshiftGrantCreateOrUpdate(db, d, usePrevious)
+	}
 
 	database := d.Get("database").(string)
 
-	txn, err := startTransaction(db.client, database)
-	if err != nil {
-		return err
-	}
-	defer deferredRollback(txn)
+	if err := withTransaction(db.client, database, func(txn *sql.Tx) error {
+		for _, role := range grantGrantees(d.Get) {
+			if err := pgLockRole(txn, role); err != nil {
+				return err
+			}
+		}
 
-	role := d.Get("role").(string)
-	if err := pgLockRole(txn, role); err != nil {
-		return err
-	}
+		if objectType == "large_object" {
+			if err := checkLargeObjectCompatPrivileges(db, txn, database); err != nil {
+				return err
+			}
+		}
 
-	if objectType == "database" {
-		if err := pgLockDatabase(txn, database); err != nil {
-			return err
+		if objectType == "database" {
+			if err := pgLockDatabase(txn, database); err != nil {
+				return err
+			}
 		}
-	}
 
-	owners, err := getRolesToGrant(txn, d)
-	if err != nil {
-		return err
-	}
-	if err := withRolesGranted(txn, owners, func() error {
-		// Revoke all privileges before granting otherwise reducing privileges will not work.
-		// We just have to revoke them in the same transaction so the role will not lose its
-		// privileges between the revoke and grant statements.
-		if err := revokeRolePrivileges(txn, d, usePrevious); err != nil {
+		if err := warnGrantOnOwnedObjects(db, txn, d); err != nil {
 			return err
 		}
-		if err := grantRolePrivileges(txn, d); err != nil {
+
+		owners, err := getRolesToGrant(txn, d)
+		if err != nil {
 			return err
 		}
-		return nil
+		return withRolesGranted(txn, owners, func() error {
+			// If only with_grant_option changed, the privileges themselves are kept
+			// so the role (and the roles it granted them to) doesn't lose them.
+			if usePrevious && d.HasChange("with_grant_option") && !d.HasChanges("role", "roles", "privileges", "schema", "objects", "columns") {
+				if d.Get("with_grant_option").(bool) {
+					return grantRolePrivileges(txn, d)
+				}
+				return revokeRoleGrantOption(txn, d)
+			}
+
+			// If only the objects, the columns or the privileges changed, only the removed ones are revoked.
+			if usePrevious {
+				queries, ok, err := grantDiffQueries(txn, d)
+				if err != nil {
+					return err
+				}
+				if ok {
+					return execGrantQueries(txn, d, db.client.config.journal, queries)
+				}
+			}
+
+			// Revoke all privileges before granting otherwise reducing privileges will not work.
+			// We just have to revoke them in the same transaction so the role will not lose its
+			// privileges between the revoke and grant statements.
+			revokeQuery, err := revokeRolePrivilegesQuery(txn, d, usePrevious)
+			if err != nil {
+				return err
+			}
+			grantQuery, err := grantRolePrivilegesQuery(txn, d)
+			if err != nil {
+				return err
+			}
+			columnGrants, err := columnGrantsToRestore(txn, d, usePrevious)
+			if err != nil {
+				return err
+			}
+			// All the statements are sent in the same round trip.
+			return execGrantQueries(txn, d, db.client.config.journal, append([]string{revokeQuery, grantQuery}, columnGrants...))
+		})
 	}); err != nil {
 		return err
 	}
 
-	if err = txn.Commit(); err != nil {
-		return fmt.Errorf("could not commit transaction: %w", err)
+	if err := db.client.config.journal.commit(generateGrantID(d)); err != nil {
+		return err
+	}
+	// The state of the previous ID has been saved, its journaled statements are not needed anymore.
+	if usePrevious && d.Id() != generateGrantID(d) {
+		if err := db.client.config.journal.forget(d.Id()); err != nil {
+			return err
+		}
 	}
 
 	d.SetId(generateGrantID(d))
 
-	txn, err = startTransaction(db.client, database)
+	txn, err := startTransaction(db.client, database)
 	if err != nil {
 		return err
 	}
 	defer deferredRollback(txn)
 
-	return readRolePrivileges(txn, d)
+	verified := false
+	if d.Get("verify").(bool) {
+		if err := verifyRolePrivileges(txn, d); err != nil {
+			d.Set("verified", false)
+			return err
+		}
+		verified = true
+	}
+	d.Set("verified", verified)
+
+	return readRolePrivileges(txn, d, nil)
 }
 
 func resourcePostgreSQLGrantDelete(db *DBConnection, d *schema.ResourceData) error {
+	if d.Get("skip_final_revoke_on_destroy").(bool) {
+		log.Printf("[INFO] Skipping the revoke of the privileges of grant %s", d.Id())
+		return db.client.config.journal.forget(d.Id())
+	}
+
 	if err := validateFeatureSupport(db, d); err != nil {
 		return fmt.Errorf("feature is not supported: %v", err)
 	}
+	if sliceContainsStr(redshiftObjectTypes, d.Get("object_type").(string)) {
+		return resourceRedshiftGrantDelete(db, d)
+	}
 
 	database := d.Get("database").(string)
-	txn, err := startTransaction(db.client, database)
-	if err != nil {
-		return err
-	}
-	defer deferredRollback(txn)
+	if err := withTransaction(db.client, database, func(txn *sql.Tx) error {
+		for _, role := range grantGrantees(d.Get) {
+			if err := pgLockRole(txn, role); err != nil {
+				return err
+			}
+		}
 
-	role := d.Get("role").(string)
-	if err := pgLockRole(txn, role); err != nil {
-		return err
-	}
+		objectType := d.Get("object_type").(string)
+		if objectType == "database" {
+			if err := pgLockDatabase(txn, database); err != nil {
+				return err
+			}
+		}
 
-	objectType := d.Get("object_type").(string)
-	if objectType == "database" {
-		if err := pgLockDatabase(txn, database); err != nil {
+		owners, err := getRolesToGrant(txn, d)
+		if err != nil {
 			return err
 		}
-	}
-
-	owners, err := getRolesToGrant(txn, d)
-	if err != nil {
-		return err
-	}
 
-	if err := withRolesGranted(txn, owners, func() error {
-		return revokeRolePrivileges(txn, d, false)
+		return withRolesGranted(txn, owners, func() error {
+			columnGrants, err := columnGrantsToRestore(txn, d, false)
+			if err != nil {
+				return err
+			}
+			if err := revokeRolePrivileges(txn, d, false); err != nil {
+				return err
+			}
+			return execBatch(txn, columnGrants...)
+		})
 	}); err != nil {
 		return err
 	}
 
-	if err = txn.Commit(); err != nil {
-		return fmt.Errorf("could not commit transaction: %w", err)
-	}
-
-	return nil
+	return db.client.config.journal.forget(d.Id())
 }
 
 func readDatabaseRolePrivileges(txn *sql.Tx, d *schema.ResourceData, roleOID uint32) error {
 	dbName := d.Get("database").(string)
+	// datacl is NULL when the database has the default privileges (e.g.: CONNECT and TEMPORARY for PUBLIC),
+	// acldefault() allows to read them. The same applies to the other object types below.
 	query := `
-SELECT array_agg(privilege_type)
+SELECT array_agg(privilege_type), array_remove(array_agg(CASE WHEN is_grantable THEN privilege_type END), NULL)
 FROM (
-	SELECT (aclexplode(datacl)).* FROM pg_database WHERE datname=$1
+	SELECT (aclexplode(COALESCE(datacl, acldefault('d', datdba)))).* FROM pg_database WHERE datname=$1
 ) as privileges
 WHERE grantee = $2
 `
 
-	var privileges pq.ByteaArray
-	if err := txn.QueryRow(query, dbName, roleOID).Scan(&privileges); err != nil {
+	var privileges, grantable pq.ByteaArray
+	if err := txn.QueryRow(query, dbName, roleOID).Scan(&privileges, &grantable); err != nil {
 		return fmt.Errorf("could not read privileges for database %s: %w", dbName, err)
 	}
 	granted := pgArrayToSet(privileges)
 	if !resourcePrivilegesEqual(granted, d) {
 		return d.Set("privileges", granted)
 	}
+	readGrantOption(d, privileges, grantable)
 	return nil
 }
 
 func readSchemaRolePrivileges(txn *sql.Tx, d *schema.ResourceData, roleOID uint32) error {
 	dbName := d.Get("schema").(string)
 	query := `
-SELECT array_agg(privilege_type)
+SELECT array_agg(privilege_type), array_remove(array_agg(CASE WHEN is_grantable THEN privilege_type END), NULL)
 FROM (
-	SELECT (aclexplode(nspacl)).* FROM pg_namespace WHERE nspname=$1
+	SELECT (aclexplode(COALESCE(nspacl, acldefault('n', nspowner)))).* FROM pg_namespace WHERE nspname=$1
 ) as privileges
 WHERE grantee = $2
 `
 
-	var privileges pq.ByteaArray
-	if err := txn.QueryRow(query, dbName, roleOID).Scan(&privileges); err != nil {
+	var privileges, grantable pq.ByteaArray
+	if err := txn.QueryRow(query, dbName, roleOID).Scan(&privileges, &grantable); err != nil {
 		return fmt.Errorf("could not read privileges for schema %s: %w", dbName, err)
 	}
 
@@ -302,6 +521,7 @@ WHERE grantee = $2
 	if !resourcePrivilegesEqual(granted, d) {
 		return d.Set("privileges", granted)
 	}
+	readGrantOption(d, privileges, grantable)
 	return nil
 }
 
@@ -309,15 +529,15 @@ func readForeignDataWrapperRolePrivileges(txn *sql.Tx, d *schema.ResourceData, r
 	objects := d.Get("objects").(*schema.Set).List()
 	fdwName := objects[0].(string)
 	query := `
-SELECT pg_catalog.array_agg(privilege_type)
+SELECT pg_catalog.array_agg(privilege_type), pg_catalog.array_remove(pg_catalog.array_agg(CASE WHEN is_grantable THEN privilege_type END), NULL)
 FROM (
-	SELECT (pg_catalog.aclexplode(fdwacl)).* FROM pg_catalog.pg_foreign_data_wrapper WHERE fdwname=$1
+	SELECT (pg_catalog.aclexplode(COALESCE(fdwacl, pg_catalog.acldefault('F', fdwowner)))).* FROM pg_catalog.pg_foreign_data_wrapper WHERE fdwname=$1
 ) as privileges
 WHERE grantee = $2
 `
 
-	var privileges pq.ByteaArray
-	if err := txn.QueryRow(query, fdwName, roleOID).Scan(&privileges); err != nil {
+	var privileges, grantable pq.ByteaArray
+	if err := txn.QueryRow(query, fdwName, roleOID).Scan(&privileges, &grantable); err != nil {
 		return fmt.Errorf("could not read privileges for foreign data wrapper %s: %w", fdwName, err)
 	}
 
@@ -325,6 +545,7 @@ WHERE grantee = $2
 	if !resourcePrivilegesEqual(granted, d) {
 		return d.Set("privileges", granted)
 	}
+	readGrantOption(d, privileges, grantable)
 	return nil
 }
 
@@ -332,15 +553,15 @@ func readForeignServerRolePrivileges(txn *sql.Tx, d *schema.ResourceData, roleOI
 	objects := d.Get("objects").(*schema.Set).List()
 	srvName := objects[0].(string)
 	query := `
-SELECT pg_catalog.array_agg(privilege_type)
+SELECT pg_catalog.array_agg(privilege_type), pg_catalog.array_remove(pg_catalog.array_agg(CASE WHEN is_grantable THEN privilege_type END), NULL)
 FROM (
-	SELECT (pg_catalog.aclexplode(srvacl)).* FROM pg_catalog.pg_foreign_server WHERE srvname=$1
+	SELECT (pg_catalog.aclexplode(COALESCE(srvacl, pg_catalog.acldefault('S', srvowner)))).* FROM pg_catalog.pg_foreign_server WHERE srvname=$1
 ) as privileges
 WHERE grantee = $2
 `
 
-	var privileges pq.ByteaArray
-	if err := txn.QueryRow(query, srvName, roleOID).Scan(&privileges); err != nil {
+	var privileges, grantable pq.ByteaArray
+	if err := txn.QueryRow(query, srvName, roleOID).Scan(&privileges, &grantable); err != nil {
 		return fmt.Errorf("could not read privileges for foreign server %s: %w", srvName, err)
 	}
 
@@ -348,10 +569,11 @@ WHERE grantee = $2
 	if !resourcePrivilegesEqual(granted, d) {
 		return d.Set("privileges", granted)
 	}
+	readGrantOption(d, privileges, grantable)
 	return nil
 }
 
-func readColumnRolePrivileges(txn *sql.Tx, d *schema.ResourceData) error {
+func readColumnRolePrivileges(txn *sql.Tx, d *schema.ResourceData, roleOID uint32) error {
 	objects := d.Get("objects").(*schema.Set)
 
 	missingColumns := d.Get("columns").(*schema.Set) // Getting columns from state.
@@ -361,7 +583,8 @@ func readColumnRolePrivileges(txn *sql.Tx, d *schema.ResourceData) error {
 
 	// The attacl column of pg_attribute contains information only about explicit column grants
 	query := `
-SELECT relname AS table_name, attname AS column_name, array_agg(privilege_type) AS column_privileges
+SELECT relname AS table_name, attname AS column_name, array_agg(privilege_type) AS column_privileges,
+       array_remove(array_agg(CASE WHEN is_grantable THEN privilege_type END), NULL) AS column_grantable_privileges
 FROM (SELECT relname, attname, (aclexplode(attacl)).*
       FROM pg_class
                JOIN pg_namespace ON pg_class.relnamespace = pg_namespace.oid
@@ -370,14 +593,13 @@ FROM (SELECT relname, attname, (aclexplode(attacl)).*
         AND relname = $3
         AND relkind = $4)
          AS col_privs
-         JOIN pg_roles ON pg_roles.oid = col_privs.grantee
-WHERE rolname = $1
+WHERE grantee = $1
   AND privilege_type = $5
 GROUP BY col_privs.relname, col_privs.attname, col_privs.privilege_type
 ORDER BY col_privs.attname
 ;`
 	rows, err := txn.Query(
-		query, d.Get("role").(string), d.Get("schema"), objects.List()[0], objectTypes["table"], d.Get("privileges").(*schema.Set).List()[0],
+		query, roleOID, d.Get("schema"), objects.List()[0], objectTypes["table"], d.Get("privileges").(*schema.Set).List()[0],
 	)
 
 	if err != nil {
@@ -387,9 +609,9 @@ ORDER BY col_privs.attname
 	for rows.Next() {
 		var objName string
 		var colName string
-		var privileges pq.ByteaArray
+		var privileges, grantable pq.ByteaArray
 
-		if err := rows.Scan(&objName, &colName, &privileges); err != nil {
+		if err := rows.Scan(&objName, &colName, &privileges, &grantable); err != nil {
 			return err
 		}
 
@@ -407,12 +629,16 @@ ORDER BY col_privs.attname
 			// If any object doesn't have the same privileges as saved in the state,
 			// we return its privileges to force an update.
 			log.Printf(
-				"[DEBUG] %s %s has not the expected privileges %v for role %s",
-				strings.ToTitle("column"), objName, privileges, d.Get("role"),
+				"[DEBUG] %s %s has not the expected privileges %v for role %d",
+				strings.ToTitle("column"), objName, privileges, roleOID,
 			)
 			d.Set("privileges", privilegesSet)
 			break
 		}
+		if readGrantOption(d, privileges, grantable) {
+			log.Printf("[DEBUG] column %s.%s has not the expected grant option for role %d", objName, colName, roleOID)
+			break
+		}
 	}
 
 	if missingColumns.Len() > 0 {
@@ -420,8 +646,8 @@ ORDER BY col_privs.attname
 		// it means that a column is missing
 		remainingColumns := d.Get("columns").(*schema.Set).Difference(missingColumns)
 		log.Printf(
-			"[DEBUG] Role %s does not have the expected privileges on columns",
-			d.Get("role"),
+			"[DEBUG] Role %d does not have the expected privileges on columns",
+			roleOID,
 		)
 		d.Set("columns", remainingColumns)
 	}
@@ -429,8 +655,155 @@ ORDER BY col_privs.attname
 	return nil
 }
 
-func readRolePrivileges(txn *sql.Tx, d *schema.ResourceData) error {
-	role := d.Get("role").(string)
+func readRelationRolePrivileges(txn *sql.Tx, d *schema.ResourceData, roleOID uint32) error {
+	objectType := d.Get("object_type").(string)
+	pgSchema := d.Get("schema").(string)
+	schemas, names := splitGrantObjects(pgSchema, d.Get("objects").(*schema.Set))
+
+	// relacl contains only the table-level privileges, the column-level ones
+	// (in pg_attribute.attacl) are managed by the grants with object_type column.
+	// Only the first relation which has not the expected privileges is returned so the
+	// comparison is done by the server, even with thousands of tables in the schema.
+	query := `
+SELECT nspname, pg_class.relname, array_remove(array_agg(acl.privilege_type), NULL),
+    array_remove(array_agg(CASE WHEN acl.is_grantable THEN acl.privilege_type END), NULL)
+FROM pg_class
+JOIN pg_namespace ON pg_namespace.oid = pg_class.relnamespace
+LEFT JOIN LATERAL aclexplode(pg_class.relacl) acl ON acl.grantee = $1
+WHERE relkind = $3 AND (
+    (array_length($4::text[], 1) IS NULL AND nspname = $2)
+    OR (nspname, pg_class.relname) IN (SELECT * FROM unnest($4::text[], $5::text[]))
+) AND ` + extensionMembersFilter(d.Get, "pg_class", "pg_class.oid") + `
+GROUP BY nspname, pg_class.relname
+HAVING NOT (
+    array_remove(array_agg(acl.privilege_type), NULL) @> $6::text[]
+    AND array_remove(array_agg(acl.privilege_type), NULL) <@ $6::text[]
+    AND bool_and(acl.is_grantable IS NOT DISTINCT FROM $7 OR acl.grantee IS NULL)
+)
+LIMIT 1
+`
+	expected := expandPrivileges(objectType, d.Get("privileges").(*schema.Set))
+
+	var nspName, objName string
+	var privileges, grantable pq.ByteaArray
+	err := txn.QueryRow(
+		query, roleOID, pgSchema, objectTypes[objectType], pq.Array(schemas), pq.Array(names), pq.Array(expected),
+		d.Get("with_grant_option").(bool),
+	).Scan(&nspName, &objName, &privileges, &grantable)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil
+	case err != nil:
+		return err
+	}
+
+	// If any object doesn't have the same privileges (or grant option) as saved in the state,
+	// we return its privileges to force an update.
+	log.Printf(
+		"[DEBUG] %s %s.%s has not the expected privileges %v (grantable: %v) for role %d",
+		strings.ToTitle(objectType), nspName, objName, privileges, grantable, roleOID,
+	)
+	if privilegesSet := pgArrayToSet(privileges); !privilegesSet.Equal(stringSliceToSet(expected)) {
+		d.Set("privileges", privilegesSet)
+		return nil
+	}
+	readGrantOption(d, privileges, grantable)
+
+	return nil
+}
+
+// readGrantOption sets with_grant_option to the grant option of the privileges of an object if it's
+// not the expected one: all the privileges are grantable with with_grant_option, none of them otherwise.
+// It returns true if the grant option differs, so the plan changes it in place (see createRevokeGrantOptionQuery).
+func readGrantOption(d *schema.ResourceData, privileges, grantable pq.ByteaArray) bool {
+	withGrantOption := d.Get("with_grant_option").(bool)
+	expected := len(grantable) == 0
+	if withGrantOption {
+		expected = pgArrayToSet(grantable).Equal(pgArrayToSet(privileges))
+	}
+	if !expected {
+		d.Set("with_grant_option", !withGrantOption)
+	}
+	return !expected
+}
+
+// readCachedRelationRolePrivileges is readRelationRolePrivileges with the ACL of the relations
+// of the schema loaded once for all the grants refreshed by the provider.
+// It's only used for the grants on all the relations of the schema (without objects), the explicit
+// objects and the large schemas are read by readRelationRolePrivileges.
+func readCachedRelationRolePrivileges(txn *sql.Tx, d *schema.ResourceData, roleOID uint32, cache *readCache) error {
+	objectType := d.Get("object_type").(string)
+	pgSchema := d.Get("schema").(string)
+	excludeExtensionObjects := d.Get("exclude_extension_objects").(bool)
+	expected := stringSliceToSet(expandPrivileges(objectType, d.Get("privileges").(*schema.Set)))
+
+	key := relationACLsKey{database: d.Get("database").(string), schema: pgSchema, relkind: objectTypes[objectType]}
+	relations, err := cache.relationACLs(key, func() (map[string]relationACL, error) {
+		return loadRelationACLs(txn, pgSchema, key.relkind)
+	})
+	if err != nil {
+		return err
+	}
+	if relations == nil {
+		return readRelationRolePrivileges(txn, d, roleOID)
+	}
+
+	names := make([]string, 0, len(relations))
+	for name := range relations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		relation := relations[name]
+		if excludeExtensionObjects && relation.extensionMember {
+			continue
+		}
+
+		privileges := pgArrayToSet(relation.privileges[roleOID])
+		if !privileges.Equal(expected) {
+			log.Printf(
+				"[DEBUG] %s %s.%s has not the expected privileges %v for role %d",
+				strings.ToTitle(objectType), pgSchema, name, relation.privileges[roleOID], roleOID,
+			)
+			d.Set("privileges", privileges)
+			return nil
+		}
+		if readGrantOption(d, relation.privileges[roleOID], relation.grantable[roleOID]) {
+			log.Printf(
+				"[DEBUG] %s %s.%s has not the expected grant option for role %d",
+				strings.ToTitle(objectType), pgSchema, name, roleOID,
+			)
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// readRolePrivileges reads the privileges of each role of the grant. The privileges (and the columns
+// or the grant option) of the first role which doesn't have the expected ones are set to force an update.
+// The privileges of the relations of the grants without objects are read from the cache if it's not nil.
+func readRolePrivileges(txn *sql.Tx, d *schema.ResourceData, cache *readCache) error {
+	privileges := d.Get("privileges").(*schema.Set)
+	columns := d.Get("columns").(*schema.Set)
+	withGrantOption := d.Get("with_grant_option").(bool)
+
+	for _, role := range grantGrantees(d.Get) {
+		if err := readRolePrivilegesOf(txn, d, role, cache); err != nil {
+			return err
+		}
+		if !d.Get("privileges").(*schema.Set).Equal(privileges) || !d.Get("columns").(*schema.Set).Equal(columns) ||
+			d.Get("with_grant_option").(bool) != withGrantOption {
+			log.Printf("[DEBUG] role %s has not the expected privileges", role)
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func readRolePrivilegesOf(txn *sql.Tx, d *schema.ResourceData, role string, cache *readCache) error {
 	objectType := d.Get("object_type").(string)
 	objects := d.Get("objects").(*schema.Set)
 
@@ -439,6 +812,11 @@ func readRolePrivileges(txn *sql.Tx, d *schema.ResourceData) error {
 		return err
 	}
 
+	if d.Get("skip_objects_check").(bool) && !sliceContainsStr([]string{"database", "schema", "foreign_data_wrapper", "foreign_server", "column"}, objectType) {
+		log.Printf("[DEBUG] skipping the check of the privileges on each %s for role %s", objectType, role)
+		return nil
+	}
+
 	var query string
 	var rows *sql.Rows
 
@@ -457,18 +835,18 @@ func readRolePrivileges(txn *sql.Tx, d *schema.ResourceData) error {
 
 	case "function", "procedure", "routine":
 		query = `
-SELECT pg_proc.proname, array_remove(array_agg(privilege_type), NULL)
+SELECT pg_proc.proname, array_remove(array_agg(privilege_type), NULL), array_remove(array_agg(CASE WHEN is_grantable THEN privilege_type END), NULL)
 FROM pg_proc
 JOIN pg_namespace ON pg_namespace.oid = pg_proc.pronamespace
 LEFT JOIN (
     select acls.*
     from (
-             SELECT proname, pronamespace, (aclexplode(proacl)).* FROM pg_proc
+             SELECT proname, pronamespace, (aclexplode(COALESCE(proacl, acldefault('f', proowner)))).* FROM pg_proc
          ) acls
     WHERE grantee = $1
 ) privs
 USING (proname, pronamespace)
-      WHERE nspname = $2
+      WHERE nspname = $2 AND ` + extensionMembersFilter(d.Get, "pg_proc", "pg_proc.oid") + `
 GROUP BY pg_proc.proname
 `
 		rows, err = txn.Query(
@@ -476,26 +854,51 @@ GROUP BY pg_proc.proname
 		)
 
 	case "column":
-		return readColumnRolePrivileges(txn, d)
+		return readColumnRolePrivileges(txn, d, roleOID)
 
-	default:
+	case "large_object":
 		query = `
-SELECT pg_class.relname, array_remove(array_agg(privilege_type), NULL)
-FROM pg_class
-JOIN pg_namespace ON pg_namespace.oid = pg_class.relnamespace
+SELECT l.oid::text, array_remove(array_agg(privilege_type), NULL), array_remove(array_agg(CASE WHEN is_grantable THEN privilege_type END), NULL)
+FROM pg_largeobject_metadata l
+LEFT JOIN (
+    SELECT acls.* FROM (
+        SELECT oid, (aclexplode(COALESCE(lomacl, acldefault('L', lomowner)))).* FROM pg_largeobject_metadata
+    ) acls
+    WHERE grantee = $1
+) privs
+ON privs.oid = l.oid
+WHERE l.oid::text = ANY($2)
+GROUP BY l.oid
+`
+		rows, err = txn.Query(
+			query, roleOID, pq.Array(setToSortedSlice(objects)),
+		)
+
+	case "type", "domain":
+		// typacl is NULL when the type has the default privileges, acldefault() allows to read them.
+		query = `
+SELECT t.typname, array_remove(array_agg(privilege_type), NULL), array_remove(array_agg(CASE WHEN is_grantable THEN privilege_type END), NULL)
+FROM pg_type t
+JOIN pg_namespace ON pg_namespace.oid = t.typnamespace
 LEFT JOIN (
     SELECT acls.* FROM (
-        SELECT relname, relnamespace, relkind, (aclexplode(relacl)).* FROM pg_class c
-    ) as acls
-    WHERE grantee=$1
+        SELECT oid, (aclexplode(COALESCE(typacl, acldefault('T', typowner)))).* FROM pg_type
+    ) acls
+    WHERE grantee = $1
 ) privs
-USING (relname, relnamespace, relkind)
-WHERE nspname = $2 AND relkind = $3
-GROUP BY pg_class.relname
+ON privs.oid = t.oid
+WHERE nspname = $2 AND ` + typesFilter(objectType) + `
+GROUP BY t.typname
 `
 		rows, err = txn.Query(
-			query, roleOID, d.Get("schema"), objectTypes[objectType],
+			query, roleOID, d.Get("schema"),
 		)
+
+	default:
+		if cache != nil && d.Get("objects").(*schema.Set).Len() == 0 {
+			return readCachedRelationRolePrivileges(txn, d, roleOID, cache)
+		}
+		return readRelationRolePrivileges(txn, d, roleOID)
 	}
 
 	// This returns, for the specified role (rolname),
@@ -509,9 +912,9 @@ GROUP BY pg_class.relname
 
 	for rows.Next() {
 		var objName string
-		var privileges pq.ByteaArray
+		var privileges, grantable pq.ByteaArray
 
-		if err := rows.Scan(&objName, &privileges); err != nil {
+		if err := rows.Scan(&objName, &privileges, &grantable); err != nil {
 			return err
 		}
 
@@ -525,85 +928,366 @@ GROUP BY pg_class.relname
 			// we return its privileges to force an update.
 			log.Printf(
 				"[DEBUG] %s %s has not the expected privileges %v for role %s",
-				strings.ToTitle(objectType), objName, privileges, d.Get("role"),
+				strings.ToTitle(objectType), objName, privileges, role,
 			)
 			d.Set("privileges", privilegesSet)
 			break
 		}
+		if readGrantOption(d, privileges, grantable) {
+			log.Printf("[DEBUG] %s %s has not the expected grant option for role %s", strings.ToTitle(objectType), objName, role)
+			break
+		}
 	}
 
 	return nil
 }
 
-func createGrantQuery(d *schema.ResourceData, privileges []string) string {
-	var query string
-
-	switch strings.ToUpper(d.Get("object_type").(string)) {
-	case "DATABASE":
-		query = fmt.Sprintf(
-			"GRANT %s ON DATABASE %s TO %s",
-			strings.Join(privileges, ","),
-			pq.QuoteIdentifier(d.Get("database").(string)),
-			pq.QuoteIdentifier(d.Get("role").(string)),
-		)
-	case "SCHEMA":
-		query = fmt.Sprintf(
-			"GRANT %s ON SCHEMA %s TO %s",
-			strings.Join(privileges, ","),
-			pq.QuoteIdentifier(d.Get("schema").(string)),
-			pq.QuoteIdentifier(d.Get("role").(string)),
-		)
-	case "FOREIGN_DATA_WRAPPER":
-		fdwName := d.Get("objects").(*schema.Set).List()[0]
-		query = fmt.Sprintf(
-			"GRANT %s ON FOREIGN DATA WRAPPER %s TO %s",
-			strings.Join(privileges, ","),
-			pq.QuoteIdentifier(fdwName.(string)),
-			pq.QuoteIdentifier(d.Get("role").(string)),
-		)
-	case "FOREIGN_SERVER":
-		srvName := d.Get("objects").(*schema.Set).List()[0]
-		query = fmt.Sprintf(
-			"GRANT %s ON FOREIGN SERVER %s TO %s",
-			strings.Join(privileges, ","),
-			pq.QuoteIdentifier(srvName.(string)),
-			pq.QuoteIdentifier(d.Get("role").(string)),
-		)
-	case "COLUMN":
-		objects := d.Get("objects").(*schema.Set)
-		query = fmt.Sprintf(
-			"GRANT %s (%s) ON TABLE %s TO %s",
-			strings.Join(privileges, ","),
-			setToPgIdentListWithoutSchema(d.Get("columns").(*schema.Set)),
-			setToPgIdentList(d.Get("schema").(string), objects),
-			pq.QuoteIdentifier(d.Get("role").(string)),
-		)
-	case "TABLE", "SEQUENCE", "FUNCTION", "PROCEDURE", "ROUTINE":
-		objects := d.Get("objects").(*schema.Set)
-		if objects.Len() > 0 {
-			query = fmt.Sprintf(
-				"GRANT %s ON %s %s TO %s",
-				strings.Join(privileges, ","),
-				strings.ToUpper(d.Get("object_type").(string)),
-				setToPgIdentList(d.Get("schema").(string), objects),
-				pq.QuoteIdentifier(d.Get("role").(string)),
-			)
-		} else {
-			query = fmt.Sprintf(
-				"GRANT %s ON ALL %sS IN SCHEMA %s TO %s",
-				strings.Join(privileges, ","),
-				strings.ToUpper(d.Get("object_type").(string)),
-				pq.QuoteIdentifier(d.Get("schema").(string)),
-				pq.QuoteIdentifier(d.Get("role").(string)),
-			)
+// verifyRolePrivileges checks with the has_*_privilege functions that the role can effectively
+// use the privileges on the objects, i.e.: including the privileges inherited from other roles or PUBLIC.
+func verifyRolePrivileges(txn *sql.Tx, d *schema.ResourceData) error {
+	for _, role := range grantGrantees(d.Get) {
+		if err := verifyRolePrivilegesOf(txn, d, role); err != nil {
+			return err
 		}
 	}
-
-	if d.Get("with_grant_option").(bool) {
-		query = query + " WITH GRANT OPTION"
-	}
-
-	return query
+	return nil
+}
+
+func verifyRolePrivilegesOf(txn *sql.Tx, d *schema.ResourceData, role string) error {
+	objectType := d.Get("object_type").(string)
+	pgSchema := d.Get("schema").(string)
+	objects := pq.Array(setToSortedSlice(d.Get("objects").(*schema.Set)))
+
+	var query string
+	var args []interface{}
+
+	switch objectType {
+	case "database":
+		query = "SELECT datname FROM pg_database WHERE datname = $3 AND NOT has_database_privilege($1, oid, $2)"
+		args = []interface{}{d.Get("database").(string)}
+	case "schema":
+		query = "SELECT nspname FROM pg_namespace WHERE nspname = $3 AND NOT has_schema_privilege($1, oid, $2)"
+		args = []interface{}{pgSchema}
+	case "foreign_data_wrapper":
+		query = "SELECT fdwname FROM pg_foreign_data_wrapper WHERE fdwname = ANY($3) AND NOT has_foreign_data_wrapper_privilege($1, oid, $2)"
+		args = []interface{}{objects}
+	case "foreign_server":
+		query = "SELECT srvname FROM pg_foreign_server WHERE srvname = ANY($3) AND NOT has_server_privilege($1, oid, $2)"
+		args = []interface{}{objects}
+	case "column":
+		query = `
+SELECT attname
+FROM pg_attribute
+JOIN pg_class ON pg_class.oid = attrelid
+JOIN pg_namespace ON pg_namespace.oid = relnamespace
+WHERE nspname = $3 AND relname = ANY($4) AND attname = ANY($5)
+  AND NOT has_column_privilege($1, pg_class.oid, attname, $2)
+`
+		args = []interface{}{pgSchema, objects, pq.Array(setToSortedSlice(d.Get("columns").(*schema.Set)))}
+	case "function", "procedure", "routine":
+		query = `
+SELECT proname
+FROM pg_proc
+JOIN pg_namespace ON pg_namespace.oid = pronamespace
+WHERE nspname = $3 AND (array_length($4::text[], 1) IS NULL OR proname = ANY($4))
+  AND ` + extensionMembersFilter(d.Get, "pg_proc", "pg_proc.oid") + `
+  AND NOT has_function_privilege($1, pg_proc.oid, $2)
+`
+		args = []interface{}{pgSchema, objects}
+	case "large_object":
+		// has_largeobject_privilege only exists since PostgreSQL 17, the ACL is checked
+		// with the privileges of PUBLIC and of the roles the role is a member of.
+		query = `
+SELECT l.oid::text
+FROM pg_largeobject_metadata l
+WHERE l.oid::text = ANY($3) AND NOT EXISTS (
+    SELECT 1 FROM aclexplode(COALESCE(lomacl, acldefault('L', lomowner))) acl
+    WHERE acl.privilege_type = $2 AND (
+        acl.grantee = 0 OR CASE WHEN $1::name = 'public' THEN false ELSE pg_has_role($1::name, acl.grantee, 'USAGE') END
+    )
+)
+`
+		args = []interface{}{objects}
+	case "type", "domain":
+		query = `
+SELECT t.typname
+FROM pg_type t
+JOIN pg_namespace ON pg_namespace.oid = t.typnamespace
+WHERE nspname = $3 AND (array_length($4::text[], 1) IS NULL OR t.typname = ANY($4))
+  AND ` + typesFilter(objectType) + `
+  AND NOT has_type_privilege($1, t.oid, $2)
+`
+		args = []interface{}{pgSchema, objects}
+	default:
+		query = fmt.Sprintf(`
+SELECT relname
+FROM pg_class
+JOIN pg_namespace ON pg_namespace.oid = relnamespace
+WHERE relkind = $4 AND (
+    (array_length($5::text[], 1) IS NULL AND nspname = $3)
+    OR (nspname, relname) IN (SELECT * FROM unnest($5::text[], $6::text[]))
+)
+  AND %s
+  AND NOT has_%s_privilege($1, pg_class.oid, $2)
+`, extensionMembersFilter(d.Get, "pg_class", "pg_class.oid"), objectType)
+		schemas, names := splitGrantObjects(pgSchema, d.Get("objects").(*schema.Set))
+		args = []interface{}{pgSchema, objectTypes[objectType], pq.Array(schemas), pq.Array(names)}
+	}
+
+	var missing []string
+	for _, privilege := range expandPrivileges(objectType, d.Get("privileges").(*schema.Set)) {
+		rows, err := txn.Query(query, append([]interface{}{role, privilege}, args...)...)
+		if err != nil {
+			return fmt.Errorf("could not verify %s privilege for role %s: %w", privilege, role, err)
+		}
+		for rows.Next() {
+			var objName string
+			if err := rows.Scan(&objName); err != nil {
+				rows.Close()
+				return fmt.Errorf("could not scan object name: %w", err)
+			}
+			missing = append(missing, fmt.Sprintf("%s on %s %s", privilege, objectType, objName))
+		}
+		rows.Close()
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf(
+			"privileges verification failed, role %s cannot use: %s",
+			role, strings.Join(missing, ", "),
+		)
+	}
+	return nil
+}
+
+// warnGrantOnOwnedObjects adds a warning if a grantee owns some of the objects of the grant:
+// granting privileges to the owner has no effect, which usually means that the grant targets the wrong role.
+func warnGrantOnOwnedObjects(db *DBConnection, txn *sql.Tx, d *schema.ResourceData) error {
+	objectType := d.Get("object_type").(string)
+	pgSchema := d.Get("schema").(string)
+	objects := pq.Array(setToSortedSlice(d.Get("objects").(*schema.Set)))
+
+	var query string
+	var args []interface{}
+
+	switch objectType {
+	case "database":
+		query = "SELECT datname FROM pg_database WHERE datname = $2 AND datdba = $1"
+		args = []interface{}{d.Get("database").(string)}
+	case "schema":
+		query = "SELECT nspname FROM pg_namespace WHERE nspname = $2 AND nspowner = $1"
+		args = []interface{}{pgSchema}
+	case "foreign_data_wrapper":
+		query = "SELECT fdwname FROM pg_foreign_data_wrapper WHERE fdwname = ANY($2) AND fdwowner = $1"
+		args = []interface{}{objects}
+	case "foreign_server":
+		query = "SELECT srvname FROM pg_foreign_server WHERE srvname = ANY($2) AND srvowner = $1"
+		args = []interface{}{objects}
+	case "large_object":
+		query = "SELECT oid::text FROM pg_largeobject_metadata WHERE oid::text = ANY($2) AND lomowner = $1"
+		args = []interface{}{objects}
+	case "function", "procedure", "routine":
+		// The objects with their arguments (e.g.: my_func(integer)) are resolved with to_regprocedure,
+		// the other ones by their name.
+		query = `
+SELECT p.proname || '(' || pg_catalog.pg_get_function_identity_arguments(p.oid) || ')'
+FROM pg_proc p
+JOIN pg_namespace n ON n.oid = p.pronamespace
+WHERE (
+    (array_length($3::text[], 1) IS NULL AND array_length($5::text[], 1) IS NULL AND n.nspname = $2)
+    OR (n.nspname, p.proname) IN (SELECT * FROM unnest($3::text[], $4::text[]))
+    OR p.oid = ANY(ARRAY(SELECT to_regprocedure(s) FROM unnest($5::text[]) s))
+) AND p.proowner = $1
+`
+		var schemas, names, signatures []string
+		for _, object := range setToSortedSlice(d.Get("objects").(*schema.Set)) {
+			objectSchema, name := splitQualifiedIdent(pgSchema, object)
+			if strings.Contains(name, "(") {
+				signatures = append(signatures, pq.QuoteIdentifier(objectSchema)+"."+quoteIdentifyIdent(name))
+				continue
+			}
+			schemas = append(schemas, objectSchema)
+			names = append(names, name)
+		}
+		args = []interface{}{pgSchema, pq.Array(schemas), pq.Array(names), pq.Array(signatures)}
+	case "type", "domain":
+		query = `
+SELECT t.typname
+FROM pg_type t
+JOIN pg_namespace ON pg_namespace.oid = t.typnamespace
+WHERE nspname = $2 AND (array_length($3::text[], 1) IS NULL OR t.typname = ANY($3))
+  AND ` + typesFilter(objectType) + ` AND t.typowner = $1
+`
+		args = []interface{}{pgSchema, objects}
+	case "column":
+		query = `
+SELECT relname
+FROM pg_class
+JOIN pg_namespace ON pg_namespace.oid = relnamespace
+WHERE nspname = $2 AND relname = ANY($3) AND relowner = $1
+`
+		args = []interface{}{pgSchema, objects}
+	default:
+		query = `
+SELECT relname
+FROM pg_class
+JOIN pg_namespace ON pg_namespace.oid = relnamespace
+WHERE relkind = $3 AND (
+    (array_length($4::text[], 1) IS NULL AND nspname = $2)
+    OR (nspname, relname) IN (SELECT * FROM unnest($4::text[], $5::text[]))
+) AND relowner = $1
+`
+		schemas, names := splitGrantObjects(pgSchema, d.Get("objects").(*schema.Set))
+		args = []interface{}{pgSchema, objectTypes[objectType], pq.Array(schemas), pq.Array(names)}
+	}
+
+	for _, role := range grantGrantees(d.Get) {
+		if role == publicRole {
+			continue
+		}
+		roleOID, err := getRoleOID(txn, role)
+		if err != nil {
+			return err
+		}
+
+		rows, err := txn.Query(query, append([]interface{}{roleOID}, args...)...)
+		if err != nil {
+			return fmt.Errorf("could not read the %s objects owned by role %s: %w", objectType, role, err)
+		}
+		var owned []string
+		for rows.Next() {
+			var objName string
+			if err := rows.Scan(&objName); err != nil {
+				rows.Close()
+				return fmt.Errorf("could not scan object name: %w", err)
+			}
+			owned = append(owned, objName)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return fmt.Errorf("could not read the %s objects owned by role %s: %w", objectType, role, err)
+		}
+
+		if len(owned) > 0 {
+			db.warn(
+				"role %s owns the %s %s of the grant in database %q: the owner already has all the privileges, "+
+					"granting them has no effect",
+				role, objectType, strings.Join(owned, ", "), d.Get("database").(string),
+			)
+		}
+	}
+	return nil
+}
+
+func createGrantQuery(getter ResourceSchemeGetter, privileges []string) string {
+	var query string
+
+	switch strings.ToUpper(getter("object_type").(string)) {
+	case "DATABASE":
+		query = fmt.Sprintf(
+			"GRANT %s ON DATABASE %s TO %s",
+			strings.Join(privileges, ","),
+			pq.QuoteIdentifier(getter("database").(string)),
+			grantGranteesIdent(getter),
+		)
+	case "SCHEMA":
+		query = fmt.Sprintf(
+			"GRANT %s ON SCHEMA %s TO %s",
+			strings.Join(privileges, ","),
+			pq.QuoteIdentifier(getter("schema").(string)),
+			grantGranteesIdent(getter),
+		)
+	case "EXTERNAL_SCHEMA":
+		// Redshift grants the privileges on the external schemas as on the local ones.
+		query = fmt.Sprintf(
+			"GRANT %s ON SCHEMA %s TO %s",
+			strings.Join(privileges, ","),
+			pq.QuoteIdentifier(getter("schema").(string)),
+			grantGranteesIdent(getter),
+		)
+	case "DATASHARE":
+		query = fmt.Sprintf(
+			"GRANT %s ON DATASHARE %s TO %s",
+			strings.Join(privileges, ","),
+			pq.QuoteIdentifier(getter("objects").(*schema.Set).List()[0].(string)),
+			grantGranteesIdent(getter),
+		)
+	case "FOREIGN_DATA_WRAPPER":
+		fdwName := getter("objects").(*schema.Set).List()[0]
+		query = fmt.Sprintf(
+			"GRANT %s ON FOREIGN DATA WRAPPER %s TO %s",
+			strings.Join(privileges, ","),
+			pq.QuoteIdentifier(fdwName.(string)),
+			grantGranteesIdent(getter),
+		)
+	case "FOREIGN_SERVER":
+		srvName := getter("objects").(*schema.Set).List()[0]
+		query = fmt.Sprintf(
+			"GRANT %s ON FOREIGN SERVER %s TO %s",
+			strings.Join(privileges, ","),
+			pq.QuoteIdentifier(srvName.(string)),
+			grantGranteesIdent(getter),
+		)
+	case "COLUMN":
+		objects := getter("objects").(*schema.Set)
+		query = fmt.Sprintf(
+			"GRANT %s (%s) ON TABLE %s TO %s",
+			strings.Join(privileges, ","),
+			setToPgIdentListWithoutSchema(getter("columns").(*schema.Set)),
+			setToPgIdentList(getter("schema").(string), objects),
+			grantGranteesIdent(getter),
+		)
+	case "TYPE", "DOMAIN":
+		// There's no GRANT ON ALL TYPES IN SCHEMA, objects always contains
+		// the list of types (see withSchemaTypes).
+		objects := getter("objects").(*schema.Set)
+		if objects.Len() == 0 {
+			return ""
+		}
+		query = fmt.Sprintf(
+			"GRANT %s ON %s %s TO %s",
+			strings.Join(privileges, ","),
+			strings.ToUpper(getter("object_type").(string)),
+			setToPgIdentList(getter("schema").(string), objects),
+			grantGranteesIdent(getter),
+		)
+	case "LARGE_OBJECT":
+		query = fmt.Sprintf(
+			"GRANT %s ON LARGE OBJECT %s TO %s",
+			strings.Join(privileges, ","),
+			setToPgIdentSimpleList(getter("objects").(*schema.Set)),
+			grantGranteesIdent(getter),
+		)
+	case "TABLE", "SEQUENCE", "FUNCTION", "PROCEDURE", "ROUTINE":
+		objects := getter("objects").(*schema.Set)
+		if objects.Len() > 0 {
+			query = fmt.Sprintf(
+				"GRANT %s ON %s %s TO %s",
+				strings.Join(privileges, ","),
+				strings.ToUpper(getter("object_type").(string)),
+				setToPgIdentList(getter("schema").(string), objects),
+				grantGranteesIdent(getter),
+			)
+		} else if getter("exclude_extension_objects").(bool) {
+			// All the objects of the schema are members of extensions (see withoutExtensionMembers).
+			return ""
+		} else {
+			query = fmt.Sprintf(
+				"GRANT %s ON ALL %sS IN SCHEMA %s TO %s",
+				strings.Join(privileges, ","),
+				strings.ToUpper(getter("object_type").(string)),
+				pq.QuoteIdentifier(getter("schema").(string)),
+				grantGranteesIdent(getter),
+			)
+		}
+	}
+
+	if getter("with_grant_option").(bool) {
+		query = query + " WITH GRANT OPTION"
+	}
+
+	return query
 }
 
 func createRevokeQuery(getter ResourceSchemeGetter) string {
@@ -614,27 +1298,40 @@ func createRevokeQuery(getter ResourceSchemeGetter) string {
 		query = fmt.Sprintf(
 			"REVOKE ALL PRIVILEGES ON DATABASE %s FROM %s",
 			pq.QuoteIdentifier(getter("database").(string)),
-			pq.QuoteIdentifier(getter("role").(string)),
+			grantGranteesIdent(getter),
 		)
 	case "SCHEMA":
 		query = fmt.Sprintf(
 			"REVOKE ALL PRIVILEGES ON SCHEMA %s FROM %s",
 			pq.QuoteIdentifier(getter("schema").(string)),
-			pq.QuoteIdentifier(getter("role").(string)),
+			grantGranteesIdent(getter),
+		)
+	case "EXTERNAL_SCHEMA":
+		query = fmt.Sprintf(
+			"REVOKE ALL PRIVILEGES ON SCHEMA %s FROM %s",
+			pq.QuoteIdentifier(getter("schema").(string)),
+			grantGranteesIdent(getter),
+		)
+	case "DATASHARE":
+		// There's no ALL PRIVILEGES on the datashares.
+		query = fmt.Sprintf(
+			"REVOKE ALTER, SHARE ON DATASHARE %s FROM %s",
+			pq.QuoteIdentifier(getter("objects").(*schema.Set).List()[0].(string)),
+			grantGranteesIdent(getter),
 		)
 	case "FOREIGN_DATA_WRAPPER":
 		fdwName := getter("objects").(*schema.Set).List()[0]
 		query = fmt.Sprintf(
 			"REVOKE ALL PRIVILEGES ON FOREIGN DATA WRAPPER %s FROM %s",
 			pq.QuoteIdentifier(fdwName.(string)),
-			pq.QuoteIdentifier(getter("role").(string)),
+			grantGranteesIdent(getter),
 		)
 	case "FOREIGN_SERVER":
 		srvName := getter("objects").(*schema.Set).List()[0]
 		query = fmt.Sprintf(
 			"REVOKE ALL PRIVILEGES ON FOREIGN SERVER %s FROM %s",
 			pq.QuoteIdentifier(srvName.(string)),
-			pq.QuoteIdentifier(getter("role").(string)),
+			grantGranteesIdent(getter),
 		)
 	case "COLUMN":
 		objects := getter("objects").(*schema.Set)
@@ -649,9 +1346,30 @@ func createRevokeQuery(getter ResourceSchemeGetter) string {
 				setToPgIdentSimpleList(privileges),
 				setToPgIdentListWithoutSchema(columns),
 				setToPgIdentList(getter("schema").(string), objects),
-				pq.QuoteIdentifier(getter("role").(string)),
+				grantGranteesIdent(getter),
+			)
+		}
+	case "TYPE", "DOMAIN":
+		objects := getter("objects").(*schema.Set)
+		if objects.Len() > 0 {
+			query = fmt.Sprintf(
+				"REVOKE ALL PRIVILEGES ON %s %s FROM %s",
+				strings.ToUpper(getter("object_type").(string)),
+				setToPgIdentList(getter("schema").(string), objects),
+				grantGranteesIdent(getter),
 			)
 		}
+	case "LARGE_OBJECT":
+		privileges := "ALL PRIVILEGES"
+		if getter("privileges").(*schema.Set).Len() > 0 {
+			privileges = setToPgIdentSimpleList(getter("privileges").(*schema.Set))
+		}
+		query = fmt.Sprintf(
+			"REVOKE %s ON LARGE OBJECT %s FROM %s",
+			privileges,
+			setToPgIdentSimpleList(getter("objects").(*schema.Set)),
+			grantGranteesIdent(getter),
+		)
 	case "TABLE", "SEQUENCE", "FUNCTION", "PROCEDURE", "ROUTINE":
 		objects := getter("objects").(*schema.Set)
 		privileges := getter("privileges").(*schema.Set)
@@ -664,22 +1382,24 @@ func createRevokeQuery(getter ResourceSchemeGetter) string {
 					setToPgIdentSimpleList(privileges),
 					strings.ToUpper(getter("object_type").(string)),
 					setToPgIdentList(getter("schema").(string), objects),
-					pq.QuoteIdentifier(getter("role").(string)),
+					grantGranteesIdent(getter),
 				)
 			} else {
 				query = fmt.Sprintf(
 					"REVOKE ALL PRIVILEGES ON %s %s FROM %s",
 					strings.ToUpper(getter("object_type").(string)),
 					setToPgIdentList(getter("schema").(string), objects),
-					pq.QuoteIdentifier(getter("role").(string)),
+					grantGranteesIdent(getter),
 				)
 			}
+		} else if getter("exclude_extension_objects").(bool) {
+			return ""
 		} else {
 			query = fmt.Sprintf(
 				"REVOKE ALL PRIVILEGES ON ALL %sS IN SCHEMA %s FROM %s",
 				strings.ToUpper(getter("object_type").(string)),
 				pq.QuoteIdentifier(getter("schema").(string)),
-				pq.QuoteIdentifier(getter("role").(string)),
+				grantGranteesIdent(getter),
 			)
 		}
 	}
@@ -687,48 +1407,580 @@ func createRevokeQuery(getter ResourceSchemeGetter) string {
 	return query
 }
 
+// createRevokeGrantOptionQuery returns the query to revoke only the grant option
+// of the privileges, the privileges themselves are kept.
+func createRevokeGrantOptionQuery(getter ResourceSchemeGetter) string {
+	privileges := setToPgIdentSimpleList(getter("privileges").(*schema.Set))
+	role := grantGranteesIdent(getter)
+	objects := getter("objects").(*schema.Set)
+
+	var target string
+	switch strings.ToUpper(getter("object_type").(string)) {
+	case "DATABASE":
+		target = "DATABASE " + pq.QuoteIdentifier(getter("database").(string))
+	case "SCHEMA":
+		target = "SCHEMA " + pq.QuoteIdentifier(getter("schema").(string))
+	case "FOREIGN_DATA_WRAPPER":
+		target = "FOREIGN DATA WRAPPER " + pq.QuoteIdentifier(objects.List()[0].(string))
+	case "FOREIGN_SERVER":
+		target = "FOREIGN SERVER " + pq.QuoteIdentifier(objects.List()[0].(string))
+	case "COLUMN":
+		return fmt.Sprintf(
+			"REVOKE GRANT OPTION FOR %s (%s) ON TABLE %s FROM %s",
+			privileges,
+			setToPgIdentListWithoutSchema(getter("columns").(*schema.Set)),
+			setToPgIdentList(getter("schema").(string), objects),
+			role,
+		)
+	case "TYPE", "DOMAIN":
+		if objects.Len() == 0 {
+			return ""
+		}
+		target = strings.ToUpper(getter("object_type").(string)) + " " + setToPgIdentList(getter("schema").(string), objects)
+	case "LARGE_OBJECT":
+		target = "LARGE OBJECT " + setToPgIdentSimpleList(objects)
+	case "TABLE", "SEQUENCE", "FUNCTION", "PROCEDURE", "ROUTINE":
+		objectType := strings.ToUpper(getter("object_type").(string))
+		if objects.Len() > 0 {
+			target = objectType + " " + setToPgIdentList(getter("schema").(string), objects)
+		} else if getter("exclude_extension_objects").(bool) {
+			return ""
+		} else {
+			target = fmt.Sprintf("ALL %sS IN SCHEMA %s", objectType, pq.QuoteIdentifier(getter("schema").(string)))
+		}
+	}
+
+	return fmt.Sprintf("REVOKE GRANT OPTION FOR %s ON %s FROM %s", privileges, target, role)
+}
+
+func revokeRoleGrantOption(txn *sql.Tx, d *schema.ResourceData) error {
+	if d.Get("privileges").(*schema.Set).Len() == 0 {
+		return nil
+	}
+
+	getter, err := withSchemaObjects(txn, d.Get)
+	if err != nil {
+		return err
+	}
+
+	query := createRevokeGrantOptionQuery(getter)
+	if len(query) == 0 {
+		// Query is empty, don't run anything
+		return nil
+	}
+	if _, err := txn.Exec(query); err != nil {
+		return fmt.Errorf("could not execute revoke grant option query: %w", err)
+	}
+	return nil
+}
+
 func grantRolePrivileges(txn *sql.Tx, d *schema.ResourceData) error {
+	query, err := grantRolePrivilegesQuery(txn, d)
+	if err != nil {
+		return err
+	}
+	return execBatch(txn, query)
+}
+
+// grantRolePrivilegesQuery returns the GRANT statement for the resource,
+// or an empty string if there is nothing to grant.
+func grantRolePrivilegesQuery(txn *sql.Tx, d *schema.ResourceData) (string, error) {
 	privileges := []string{}
 	for _, priv := range d.Get("privileges").(*schema.Set).List() {
 		privileges = append(privileges, priv.(string))
 	}
 
 	if len(privileges) == 0 {
-		log.Printf("[DEBUG] no privileges to grant for role %s in database: %s,", d.Get("role").(string), d.Get("database"))
-		return nil
+		log.Printf("[DEBUG] no privileges to grant for roles %v in database: %s,", grantGrantees(d.Get), d.Get("database"))
+		return "", nil
 	}
 
-	query := createGrantQuery(d, privileges)
+	getter, err := withSchemaObjects(txn, d.Get)
+	if err != nil {
+		return "", err
+	}
 
-	_, err := txn.Exec(query)
-	return err
+	return createGrantQuery(getter, privileges), nil
 }
 
 func revokeRolePrivileges(txn *sql.Tx, d *schema.ResourceData, usePrevious bool) error {
+	query, err := revokeRolePrivilegesQuery(txn, d, usePrevious)
+	if err != nil {
+		return err
+	}
+	if err := execBatch(txn, query); err != nil {
+		return fmt.Errorf("could not execute revoke query: %w", err)
+	}
+	return nil
+}
+
+// revokeRolePrivilegesQuery returns the REVOKE statement for the resource (with its previous
+// values if usePrevious is true), or an empty string if there is nothing to revoke.
+func revokeRolePrivilegesQuery(txn *sql.Tx, d *schema.ResourceData, usePrevious bool) (string, error) {
 	getter := d.Get
 
 	if usePrevious {
-		getter = func(name string) interface{} {
-			if d.HasChange(name) {
-				old, _ := d.GetChange(name)
-				return old
+		var err error
+		if getter, err = previousGrantGetter(txn, d); err != nil {
+			return "", err
+		}
+		if len(grantGrantees(getter)) == 0 {
+			// None of the previous roles exists anymore.
+			return "", nil
+		}
+	}
+
+	getter, err := withSchemaObjects(txn, getter)
+	if err != nil {
+		return "", err
+	}
+
+	return createRevokeQuery(getter), nil
+}
+
+// grantDiffQueries returns the queries to update the grant by revoking only the removed objects,
+// columns and privileges (then granting the new ones) when the role, the schema and the object type
+// are unchanged. It returns false if the grant has to be fully revoked and granted again.
+func grantDiffQueries(txn *sql.Tx, d *schema.ResourceData) ([]string, bool, error) {
+	objectType := d.Get("object_type").(string)
+	if d.HasChanges("role", "roles", "schema") || !sliceContainsStr([]string{"table", "sequence", "function", "procedure", "routine", "type", "domain", "large_object", "column"}, objectType) {
+		return nil, false, nil
+	}
+
+	oldObjectsRaw, newObjectsRaw := d.GetChange("objects")
+	oldObjects, newObjects := oldObjectsRaw.(*schema.Set), newObjectsRaw.(*schema.Set)
+	// Empty objects means all the objects of the schema.
+	if oldObjects.Len() == 0 || newObjects.Len() == 0 {
+		return nil, false, nil
+	}
+
+	oldPrivilegesRaw, newPrivilegesRaw := d.GetChange("privileges")
+	oldPrivileges, newPrivileges := oldPrivilegesRaw.(*schema.Set), newPrivilegesRaw.(*schema.Set)
+	oldGrantOption, _ := d.GetChange("with_grant_option")
+	revokeGrantOption := oldGrantOption.(bool) && !d.Get("with_grant_option").(bool)
+
+	var queries []string
+	if objectType == "column" {
+		if d.HasChanges("objects", "privileges") {
+			return nil, false, nil
+		}
+
+		oldColumnsRaw, newColumnsRaw := d.GetChange("columns")
+		oldColumns, newColumns := oldColumnsRaw.(*schema.Set), newColumnsRaw.(*schema.Set)
+		if removed := oldColumns.Difference(newColumns); removed.Len() > 0 {
+			queries = append(queries, createRevokeQuery(overrideGetter(d.Get, map[string]interface{}{"columns": removed})))
+		}
+		if kept := oldColumns.Intersection(newColumns); revokeGrantOption && kept.Len() > 0 {
+			queries = append(queries, createRevokeGrantOptionQuery(overrideGetter(d.Get, map[string]interface{}{"columns": kept})))
+		}
+	} else {
+		kept := oldObjects.Intersection(newObjects)
+		if removed := oldObjects.Difference(newObjects); removed.Len() > 0 && oldPrivileges.Len() > 0 {
+			queries = append(queries, createRevokeQuery(overrideGetter(d.Get, map[string]interface{}{
+				"objects": removed, "privileges": oldPrivileges,
+			})))
+		}
+		if removed := oldPrivileges.Difference(newPrivileges); kept.Len() > 0 && removed.Len() > 0 {
+			queries = append(queries, createRevokeQuery(overrideGetter(d.Get, map[string]interface{}{
+				"objects": kept, "privileges": removed,
+			})))
+		}
+		if common := oldPrivileges.Intersection(newPrivileges); revokeGrantOption && kept.Len() > 0 && common.Len() > 0 {
+			queries = append(queries, createRevokeGrantOptionQuery(overrideGetter(d.Get, map[string]interface{}{
+				"objects": kept, "privileges": common,
+			})))
+		}
+	}
+
+	grantQuery, err := grantRolePrivilegesQuery(txn, d)
+	if err != nil {
+		return nil, false, err
+	}
+	queries = append(queries, grantQuery)
+
+	// Revoking table privileges also revokes the column privileges of the role.
+	columnGrants, err := columnGrantsToRestore(txn, d, true)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return append(queries, columnGrants...), true, nil
+}
+
+// execGrantQueries executes the queries of the grant and records them in the journal. If the journal shows
+// the same queries were committed by a previous apply (interrupted before the state was saved)
+// and the privileges are still in place, they are not executed again.
+func execGrantQueries(txn *sql.Tx, d *schema.ResourceData, journal *operationJournal, queries []string) error {
+	key := generateGrantID(d)
+
+	if journal.committed(key, queries) {
+		inPlace, err := grantPrivilegesInPlace(txn, d)
+		if err != nil {
+			return err
+		}
+		if inPlace {
+			log.Printf("[INFO] privileges of grant %s were already applied by a previous apply, skipping", key)
+			return nil
+		}
+	}
+
+	if err := journal.begin(key, queries); err != nil {
+		return err
+	}
+	if err := execBatch(txn, queries...); err != nil {
+		return fmt.Errorf("could not execute grant queries: %w", err)
+	}
+	return nil
+}
+
+// grantPrivilegesInPlace returns true if the privileges of the grant are the ones of the resource.
+func grantPrivilegesInPlace(txn *sql.Tx, d *schema.ResourceData) (bool, error) {
+	privileges := d.Get("privileges").(*schema.Set)
+	columns := d.Get("columns").(*schema.Set)
+
+	// readRolePrivileges sets the privileges and the columns found in the database if they differ.
+	if err := readRolePrivileges(txn, d, nil); err != nil {
+		return false, err
+	}
+	inPlace := d.Get("privileges").(*schema.Set).Equal(privileges) && d.Get("columns").(*schema.Set).Equal(columns)
+
+	d.Set("privileges", privileges)
+	d.Set("columns", columns)
+
+	return inPlace, nil
+}
+
+// previousGrantGetter returns a getter with the previous values of the resource (see previousValueGetter),
+// roleAttrs are the other attributes containing a role (e.g.: owner).
+// The previous roles which don't exist anymore are excluded from the roles list as nothing can be revoked from them.
+func previousGrantGetter(txn *sql.Tx, d *schema.ResourceData, roleAttrs ...string) (ResourceSchemeGetter, error) {
+	getter, err := previousValueGetter(txn, d, append([]string{"role"}, roleAttrs...)...)
+	if err != nil || !d.HasChange("roles") {
+		return getter, err
+	}
+
+	oldRoles, _ := d.GetChange("roles")
+	roles := schema.NewSet(schema.HashString, nil)
+	for _, role := range oldRoles.(*schema.Set).List() {
+		exists := isPublicRole(role.(string))
+		if !exists {
+			if exists, err = roleExists(txn, role.(string)); err != nil {
+				return nil, err
 			}
+		}
+		if exists {
+			roles.Add(role)
+		}
+	}
+
+	return overrideGetter(getter, map[string]interface{}{"roles": roles}), nil
+}
+
+// grantGrantees returns the roles of the grant, either its role or its roles list.
+// PUBLIC is returned as publicRole whatever its case, as it's read back with the OID 0.
+func grantGrantees(getter ResourceSchemeGetter) []string {
+	var roles []string
+	if set, ok := getter("roles").(*schema.Set); ok && set != nil && set.Len() > 0 {
+		roles = setToSortedSlice(set)
+	} else if role, ok := getter("role").(string); ok && role != "" {
+		roles = []string{role}
+	}
+
+	for i, role := range roles {
+		if isPublicRole(role) {
+			roles[i] = publicRole
+		}
+	}
+	return roles
+}
 
-			return d.Get(name)
+// grantGranteesIdent returns the roles of the grant quoted and comma-separated, for the GRANT and REVOKE statements.
+func grantGranteesIdent(getter ResourceSchemeGetter) string {
+	roles := grantGrantees(getter)
+	quoted := make([]string, len(roles))
+	for i, role := range roles {
+		if role == publicRole {
+			quoted[i] = "PUBLIC"
+			continue
 		}
+		quoted[i] = pq.QuoteIdentifier(role)
 	}
+	return strings.Join(quoted, ", ")
+}
 
-	query := createRevokeQuery(getter)
-	if len(query) == 0 {
-		// Query is empty, don't run anything
+// overrideGetter returns a getter returning the overridden values instead of the getter ones.
+func overrideGetter(getter ResourceSchemeGetter, overrides map[string]interface{}) ResourceSchemeGetter {
+	return func(key string) interface{} {
+		if value, ok := overrides[key]; ok {
+			return value
+		}
+		return getter(key)
+	}
+}
+
+// splitGrantObjects returns the schemas and the names of the objects, which can be qualified
+// with their schema (e.g.: other_schema.seq1), as two slices to unnest them together in queries.
+func splitGrantObjects(pgSchema string, objects *schema.Set) ([]string, []string) {
+	var schemas, names []string
+	for _, object := range setToSortedSlice(objects) {
+		objectSchema, name := splitQualifiedIdent(pgSchema, object)
+		schemas = append(schemas, objectSchema)
+		names = append(names, name)
+	}
+	return schemas, names
+}
+
+// validateGrantObjects checks that the objects are qualified with their schema only for tables and sequences.
+func validateGrantObjects(d *schema.ResourceData) error {
+	objectType := d.Get("object_type").(string)
+	if objectType == "table" || objectType == "sequence" {
+		// Before the objects could be qualified, an unquoted dotted name was the name of an object of the schema.
+		for _, object := range d.Get("objects").(*schema.Set).List() {
+			if name := object.(string); !strings.HasPrefix(name, `"`) && strings.Contains(name, ".") {
+				log.Printf("[WARN] %s object %s is resolved as qualified with its schema, quote its name if it contains a dot", objectType, name)
+			}
+		}
 		return nil
 	}
-	if _, err := txn.Exec(query); err != nil {
-		return fmt.Errorf("could not execute revoke query: %w", err)
+
+	if objectType == "large_object" {
+		for _, object := range d.Get("objects").(*schema.Set).List() {
+			if _, err := strconv.ParseUint(object.(string), 10, 32); err != nil {
+				return fmt.Errorf("large object %s is not a valid OID", object)
+			}
+		}
+		return nil
+	}
+
+	pgSchema := d.Get("schema").(string)
+	for _, object := range d.Get("objects").(*schema.Set).List() {
+		if objectSchema, _ := splitQualifiedIdent(pgSchema, object.(string)); objectSchema != pgSchema {
+			return fmt.Errorf(
+				"object %s is qualified with a schema which is only supported when `object_type` is `table` or `sequence`, quote its name if it contains a dot",
+				object,
+			)
+		}
 	}
 	return nil
 }
 
+// columnGrantsToRestore returns the GRANT statements restoring the column-level privileges
+// of the role on the tables of the resource. Revoking a table-level privilege also revokes it
+// on all the columns of the table, so without this a table grant would remove the privileges
+// managed by a column grant of the same role and both resources would flap.
+func columnGrantsToRestore(txn *sql.Tx, d *schema.ResourceData, usePrevious bool) ([]string, error) {
+	getter := d.Get
+	if usePrevious {
+		var err error
+		if getter, err = previousGrantGetter(txn, d); err != nil {
+			return nil, err
+		}
+	}
+
+	if getter("object_type").(string) != "table" {
+		return nil, nil
+	}
+
+	var queries []string
+	for _, role := range grantGrantees(getter) {
+		if role == "" {
+			continue
+		}
+		roleQueries, err := roleColumnGrantsToRestore(txn, getter, role)
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, roleQueries...)
+	}
+
+	return queries, nil
+}
+
+func roleColumnGrantsToRestore(txn *sql.Tx, getter ResourceSchemeGetter, role string) ([]string, error) {
+	roleOID, err := getRoleOID(txn, role)
+	if err != nil {
+		return nil, err
+	}
+
+	pgSchema := getter("schema").(string)
+	schemas, names := splitGrantObjects(pgSchema, getter("objects").(*schema.Set))
+	rows, err := txn.Query(`
+SELECT nspname, relname, privilege_type, is_grantable, array_agg(attname::text ORDER BY attname)
+FROM pg_class
+JOIN pg_namespace ON pg_namespace.oid = relnamespace
+JOIN pg_attribute ON attrelid = pg_class.oid,
+LATERAL aclexplode(attacl) acl
+WHERE grantee = $1 AND (
+    (array_length($3::text[], 1) IS NULL AND nspname = $2)
+    OR (nspname, relname) IN (SELECT * FROM unnest($3::text[], $4::text[]))
+)
+GROUP BY nspname, relname, privilege_type, is_grantable
+ORDER BY nspname, relname, privilege_type
+`, roleOID, pgSchema, pq.Array(schemas), pq.Array(names))
+	if err != nil {
+		return nil, fmt.Errorf("could not read column privileges of role %s: %w", role, err)
+	}
+	defer rows.Close()
+
+	var queries []string
+	for rows.Next() {
+		var tableSchema, table, privilege string
+		var grantable bool
+		var columns []string
+		if err := rows.Scan(&tableSchema, &table, &privilege, &grantable, pq.Array(&columns)); err != nil {
+			return nil, fmt.Errorf("could not scan column privileges: %w", err)
+		}
+
+		quotedColumns := make([]string, len(columns))
+		for i, column := range columns {
+			quotedColumns[i] = pq.QuoteIdentifier(column)
+		}
+		query := fmt.Sprintf(
+			"GRANT %s (%s) ON TABLE %s.%s TO %s",
+			privilege,
+			strings.Join(quotedColumns, ","),
+			pq.QuoteIdentifier(tableSchema),
+			pq.QuoteIdentifier(table),
+			pq.QuoteIdentifier(role),
+		)
+		if grantable {
+			query += " WITH GRANT OPTION"
+		}
+		queries = append(queries, query)
+	}
+
+	return queries, rows.Err()
+}
+
+// withSchemaTypes wraps the getter so `objects` returns all the types (or domains) of the schema
+// when granting on types without specific objects, as PostgreSQL has no
+// GRANT ... ON ALL TYPES IN SCHEMA statement.
+func withSchemaTypes(txn *sql.Tx, getter ResourceSchemeGetter) (ResourceSchemeGetter, error) {
+	objectType := getter("object_type").(string)
+	if (objectType != "type" && objectType != "domain") || getter("objects").(*schema.Set).Len() > 0 {
+		return getter, nil
+	}
+
+	types, err := getSchemaTypes(txn, getter("schema").(string), typesFilter(objectType))
+	if err != nil {
+		return nil, err
+	}
+
+	objects := schema.NewSet(schema.HashString, nil)
+	for _, typeName := range types {
+		objects.Add(typeName)
+	}
+
+	return func(key string) interface{} {
+		if key == "objects" {
+			return objects
+		}
+		return getter(key)
+	}, nil
+}
+
+// withSchemaObjects wraps the getter so `objects` returns the objects of the schema when the statements
+// can't be run on all the objects of the schema (see withSchemaTypes and withoutExtensionMembers).
+func withSchemaObjects(txn *sql.Tx, getter ResourceSchemeGetter) (ResourceSchemeGetter, error) {
+	getter, err := withSchemaTypes(txn, getter)
+	if err != nil {
+		return nil, err
+	}
+	return withoutExtensionMembers(txn, getter)
+}
+
+// withoutExtensionMembers wraps the getter so `objects` returns all the objects of the schema
+// which are not members of an extension if exclude_extension_objects is set, as
+// GRANT ON ALL ... IN SCHEMA would also change the privileges of the extension objects.
+func withoutExtensionMembers(txn *sql.Tx, getter ResourceSchemeGetter) (ResourceSchemeGetter, error) {
+	if !getter("exclude_extension_objects").(bool) || getter("objects").(*schema.Set).Len() > 0 {
+		return getter, nil
+	}
+
+	objectType := getter("object_type").(string)
+	schemaName := getter("schema").(string)
+
+	var query string
+	switch objectType {
+	case "table", "sequence":
+		// GRANT ON ALL TABLES also applies to the views, materialized views and foreign tables.
+		relkinds := "'r', 'p', 'v', 'm', 'f'"
+		if objectType == "sequence" {
+			relkinds = "'S'"
+		}
+		query = `
+SELECT c.relname
+FROM pg_catalog.pg_class c
+JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+WHERE n.nspname = $1 AND c.relkind IN (` + relkinds + `)
+  AND ` + extensionMembersFilter(getter, "pg_class", "c.oid")
+	case "function", "procedure", "routine":
+		// The functions are identified with their arguments as they can be overloaded,
+		// pg_get_function_result is NULL for the procedures.
+		kindFilter := "true"
+		switch objectType {
+		case "function":
+			kindFilter = "pg_catalog.pg_get_function_result(p.oid) IS NOT NULL"
+		case "procedure":
+			kindFilter = "pg_catalog.pg_get_function_result(p.oid) IS NULL"
+		}
+		query = `
+SELECT p.proname || '(' || pg_catalog.pg_get_function_identity_arguments(p.oid) || ')'
+FROM pg_catalog.pg_proc p
+JOIN pg_catalog.pg_namespace n ON n.oid = p.pronamespace
+WHERE n.nspname = $1 AND ` + kindFilter + `
+  AND ` + extensionMembersFilter(getter, "pg_proc", "p.oid")
+	default:
+		return getter, nil
+	}
+
+	rows, err := txn.Query(query, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the %ss of schema %s which are not members of an extension: %w", objectType, schemaName, err)
+	}
+	defer rows.Close()
+
+	objects := schema.NewSet(schema.HashString, nil)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("could not scan object name: %w", err)
+		}
+		objects.Add(quoteGrantObjectName(name))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return overrideGetter(getter, map[string]interface{}{"objects": objects}), nil
+}
+
+// quoteGrantObjectName quotes the name of an object (without its function arguments) if it contains
+// a dot or a quote, so it's not split as a qualified name (see splitQualifiedIdent).
+func quoteGrantObjectName(name string) string {
+	ident, args := name, ""
+	if i := strings.Index(name, "("); i >= 0 {
+		ident, args = name[:i], name[i:]
+	}
+	if strings.ContainsAny(ident, `."`) {
+		ident = pq.QuoteIdentifier(ident)
+	}
+	return ident + args
+}
+
+// extensionMembersFilter returns a condition excluding the objects which are members of an extension
+// (they depend on it with the 'e' dependency type) if exclude_extension_objects is set.
+// catalog is the catalog of the objects and oidColumn the column of their OID in the query.
+func extensionMembersFilter(getter ResourceSchemeGetter, catalog, oidColumn string) string {
+	if !getter("exclude_extension_objects").(bool) {
+		return "true"
+	}
+	return fmt.Sprintf(`NOT EXISTS (
+    SELECT 1 FROM pg_catalog.pg_depend dep
+    WHERE dep.classid = 'pg_catalog.%s'::regclass AND dep.objid = %s AND dep.deptype = 'e'
+)`, catalog, oidColumn)
+}
+
 func checkRoleDBSchemaExists(db *DBConnection, d *schema.ResourceData) (bool, error) {
 	// Check the database exists
 	database := d.Get("database").(string)
@@ -747,22 +1999,33 @@ func checkRoleDBSchemaExists(db *DBConnection, d *schema.ResourceData) (bool, er
 	}
 	defer deferredRollback(txn)
 
-	// Check the role exists
-	role := d.Get("role").(string)
-	if role != publicRole {
-		exists, err := roleExists(txn, role)
-		if err != nil {
-			return false, err
-		}
-		if !exists {
-			log.Printf("[DEBUG] role %s does not exists", role)
-			return false, nil
+	// Check the roles exist, the ones which don't exist anymore are removed from the roles list
+	// so they're granted again if they're recreated.
+	roles := grantGrantees(d.Get)
+	existingRoles := make([]interface{}, 0, len(roles))
+	for _, role := range roles {
+		if role != publicRole {
+			exists, err := roleExists(txn, role)
+			if err != nil {
+				return false, err
+			}
+			if !exists {
+				log.Printf("[DEBUG] role %s does not exists", role)
+				continue
+			}
 		}
+		existingRoles = append(existingRoles, role)
+	}
+	if len(existingRoles) == 0 {
+		return false, nil
+	}
+	if len(existingRoles) != len(roles) {
+		d.Set("roles", existingRoles)
 	}
 
 	// Check the schema exists (the SQL connection needs to be on the right database)
 	pgSchema := d.Get("schema").(string)
-	if !sliceContainsStr([]string{"database", "foreign_data_wrapper", "foreign_server"}, d.Get("object_type").(string)) && pgSchema != "" {
+	if !sliceContainsStr(objectTypesWithoutSchema, d.Get("object_type").(string)) && pgSchema != "" {
 		exists, err = schemaExists(txn, pgSchema)
 		if err != nil {
 			return false, err
@@ -776,24 +2039,155 @@ func checkRoleDBSchemaExists(db *DBConnection, d *schema.ResourceData) (bool, er
 	return true, nil
 }
 
+// grantID contains the parts of the ID of a grant.
+type grantID struct {
+	role       string
+	database   string
+	objectType string
+	schema     string
+	objects    []string
+	columns    []string
+}
+
+// generateGrantID returns the ID of the grant: the role, the database, the object type, the schema
+// (except for the object types without schema), the objects and the columns joined with generateObjectID,
+// e.g.: my_role.my_db.table.public.my_table
+// With a roles list, the role part contains the sorted roles separated by commas (e.g.: role1,role2).
 func generateGrantID(d *schema.ResourceData) string {
-	parts := []string{d.Get("role").(string), d.Get("database").(string)}
+	return grantID{
+		role:       strings.Join(grantGrantees(d.Get), ","),
+		database:   d.Get("database").(string),
+		objectType: d.Get("object_type").(string),
+		schema:     d.Get("schema").(string),
+		objects:    setToSortedSlice(d.Get("objects").(*schema.Set)),
+		columns:    setToSortedSlice(d.Get("columns").(*schema.Set)),
+	}.String()
+}
 
-	objectType := d.Get("object_type").(string)
-	if objectType != "database" && objectType != "foreign_data_wrapper" && objectType != "foreign_server" {
-		parts = append(parts, d.Get("schema").(string))
+func (id grantID) String() string {
+	parts := []string{id.role, id.database, id.objectType}
+	if !sliceContainsStr(objectTypesWithoutSchema, id.objectType) {
+		parts = append(parts, id.schema)
 	}
-	parts = append(parts, objectType)
+	parts = append(parts, id.objects...)
+	parts = append(parts, id.columns...)
 
-	for _, object := range d.Get("objects").(*schema.Set).List() {
-		parts = append(parts, object.(string))
+	return generateObjectID(parts...)
+}
+
+// parseGrantID parses an ID generated by generateGrantID or, for backwards compatibility,
+// an ID of the version 0 of the resource (see parseLegacyGrantID).
+func parseGrantID(rawID string) (grantID, error) {
+	parts, err := parseObjectID(rawID)
+	if err != nil || len(parts) < 3 || !sliceContainsStr(allowedObjectTypes, parts[2]) {
+		return parseLegacyGrantID(rawID)
+	}
+
+	id := grantID{role: parts[0], database: parts[1], objectType: parts[2]}
+	objects := parts[3:]
+	if !sliceContainsStr(objectTypesWithoutSchema, id.objectType) {
+		if len(objects) == 0 {
+			return grantID{}, fmt.Errorf("schema is missing in grant ID %s", rawID)
+		}
+		id.schema, objects = objects[0], objects[1:]
+	}
+	id.objects, id.columns = splitGrantIDObjects(id.objectType, objects)
+
+	return id, nil
+}
+
+// parseLegacyGrantID parses an ID of the version 0 of the resource, which joined the parts with underscores.
+// As this format is ambiguous, the names of the role, the database and the schema
+// (and of the objects if there are several of them) can't contain underscores.
+func parseLegacyGrantID(rawID string) (grantID, error) {
+	parts := strings.Split(rawID, "_")
+	if len(parts) < 3 {
+		return grantID{}, fmt.Errorf("invalid grant ID %s", rawID)
+	}
+
+	for _, objectType := range allowedObjectTypes {
+		id := grantID{role: parts[0], database: parts[1], objectType: objectType}
+		rest := parts[2:]
+		if !sliceContainsStr(objectTypesWithoutSchema, objectType) {
+			id.schema, rest = rest[0], rest[1:]
+		}
+
+		typeParts := strings.Split(objectType, "_")
+		if len(rest) < len(typeParts) || strings.Join(rest[:len(typeParts)], "_") != objectType {
+			continue
+		}
+		id.objects, id.columns = splitGrantIDObjects(objectType, rest[len(typeParts):])
+
+		return id, nil
+	}
+
+	return grantID{}, fmt.Errorf("could not find the object type in grant ID %s", rawID)
+}
+
+// splitGrantIDObjects splits the end of a grant ID in objects and columns,
+// the grants on columns have only one object (the table) followed by the columns.
+func splitGrantIDObjects(objectType string, parts []string) ([]string, []string) {
+	if len(parts) == 0 {
+		return nil, nil
+	}
+	if objectType == "column" {
+		return parts[:1], parts[1:]
 	}
+	return parts, nil
+}
 
-	for _, column := range d.Get("columns").(*schema.Set).List() {
-		parts = append(parts, column.(string))
+func resourcePostgreSQLGrantImport(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	id, err := parseGrantID(d.Id())
+	if err != nil {
+		return nil, err
 	}
 
-	return strings.Join(parts, "_")
+	if roles := strings.Split(id.role, ","); len(roles) > 1 {
+		d.Set("roles", roles)
+	} else {
+		d.Set("role", id.role)
+	}
+	d.Set("database", id.database)
+	d.Set("object_type", id.objectType)
+	d.Set("schema", id.schema)
+	d.Set("objects", id.objects)
+	d.Set("columns", id.columns)
+	d.Set("skip_final_revoke_on_destroy", false)
+	d.SetId(id.String())
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// resourcePostgreSQLGrantStateUpgradeV0 replaces the ID of the version 0, which joined the parts with underscores.
+func resourcePostgreSQLGrantStateUpgradeV0(_ context.Context, rawState map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+	if rawState == nil {
+		return rawState, nil
+	}
+
+	stringValue := func(key string) string {
+		value, _ := rawState[key].(string)
+		return value
+	}
+	sortedValues := func(key string) []string {
+		values := []string{}
+		raw, _ := rawState[key].([]interface{})
+		for _, value := range raw {
+			values = append(values, value.(string))
+		}
+		sort.Strings(values)
+		return values
+	}
+
+	rawState["id"] = grantID{
+		role:       stringValue("role"),
+		database:   stringValue("database"),
+		objectType: stringValue("object_type"),
+		schema:     stringValue("schema"),
+		objects:    sortedValues("objects"),
+		columns:    sortedValues("columns"),
+	}.String()
+
+	return rawState, nil
 }
 
 func getRolesToGrant(txn *sql.Tx, d *schema.ResourceData) ([]string, error) {
@@ -801,15 +2195,29 @@ func getRolesToGrant(txn *sql.Tx, d *schema.ResourceData) ([]string, error) {
 	// we need to grant owner of the schema and owners of tables in the schema
 	// in order to change theirs permissions.
 	owners := []string{}
-	objectType := d.Get("object_type")
+	objectType := d.Get("object_type").(string)
+
+	if objectType == "large_object" {
+		owners, err := getLargeObjectsOwner(txn, setToSortedSlice(d.Get("objects").(*schema.Set)))
+		if err != nil {
+			return nil, err
+		}
+		return resolveOwners(txn, owners)
+	}
 
-	if objectType == "database" || objectType == "foreign_data_wrapper" || objectType == "foreign_server" {
+	if sliceContainsStr(objectTypesWithoutSchema, objectType) {
 		return owners, nil
 	}
 
 	schemaName := d.Get("schema").(string)
 
-	if objectType != "schema" {
+	if objectType == "type" || objectType == "domain" {
+		var err error
+		owners, err = getTypesOwner(txn, schemaName, typesFilter(objectType))
+		if err != nil {
+			return nil, err
+		}
+	} else if objectType != "schema" {
 		var err error
 		owners, err = getTablesOwner(txn, schemaName)
 		if err != nil {
@@ -834,6 +2242,10 @@ func getRolesToGrant(txn *sql.Tx, d *schema.ResourceData) ([]string, error) {
 }
 
 func validateFeatureSupport(db *DBConnection, d *schema.ResourceData) error {
+	if objectType := d.Get("object_type").(string); sliceContainsStr(redshiftObjectTypes, objectType) {
+		// Redshift reports the version of the PostgreSQL it was forked from, only the flavor is checked.
+		return checkGrantFlavor(db.client.config.Flavor, objectType)
+	}
 	if !db.featureSupported(featurePrivileges) {
 		return fmt.Errorf(
 			"postgresql_grant resource is not supported for this Postgres version (%s)",
@@ -0,0 +1,284 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
+)
+
+// redshiftObjectTypes are the object types of postgresql_grant which only exist in Amazon Redshift.
+// They're only allowed with the redshift flavor of the provider: Redshift reports the version of the
+// PostgreSQL it was forked from and has no ACL functions (aclexplode), so their privileges are read
+// from the Redshift system views instead.
+var redshiftObjectTypes = []string{"external_schema", "datashare"}
+
+// redshiftPrivilegesQueries are the queries reading the privileges (and their grant option) of a user
+// or PUBLIC on an object of the Redshift object types.
+var redshiftPrivilegesQueries = map[string]string{
+	"external_schema": `
+SELECT privilege_type, admin_option FROM svv_schema_privileges
+WHERE namespace_name = $1 AND identity_name = $2 AND identity_type IN ('user', 'public')
+`,
+	"datashare": `
+SELECT privilege_type, admin_option FROM svv_datashare_privileges
+WHERE datashare_name = $1 AND identity_name = $2 AND identity_type IN ('user', 'public')
+`,
+}
+
+// checkGrantFlavor fails if the object type of the grant is not supported by the flavor of the provider.
+func checkGrantFlavor(flavor, objectType string) error {
+	if sliceContainsStr(redshiftObjectTypes, objectType) && flavor != flavorRedshift {
+		return fmt.Errorf("object type %s is only supported when the provider flavor is %s", objectType, flavorRedshift)
+	}
+	return nil
+}
+
+// redshiftGrantObject returns the name of the object of a grant on a Redshift object type.
+func redshiftGrantObject(getter ResourceSchemeGetter) string {
+	if getter("object_type").(string) == "external_schema" {
+		return getter("schema").(string)
+	}
+	return getter("objects").(*schema.Set).List()[0].(string)
+}
+
+func resourceRedshiftGrantRead(db *DBConnection, d *schema.ResourceData) error {
+	database := d.Get("database").(string)
+	exists, err := dbExists(db, database)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		log.Printf("[DEBUG] database %s does not exists", database)
+		d.SetId("")
+		return nil
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	exists, err = checkRedshiftGrantExists(txn, d)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		d.SetId("")
+		return nil
+	}
+	d.SetId(generateGrantID(d))
+
+	return readRedshiftRolePrivileges(txn, d)
+}
+
+// checkRedshiftGrantExists checks that the object and the users of the grant exist. As for the other
+// object types, the users which don't exist anymore are removed from the roles list.
+func checkRedshiftGrantExists(txn *sql.Tx, d *schema.ResourceData) (bool, error) {
+	roles := grantGrantees(d.Get)
+	existingRoles := make([]interface{}, 0, len(roles))
+	for _, role := range roles {
+		if role != publicRole {
+			exists, err := redshiftUserExists(txn, role)
+			if err != nil {
+				return false, err
+			}
+			if !exists {
+				log.Printf("[DEBUG] user %s does not exists", role)
+				continue
+			}
+		}
+		existingRoles = append(existingRoles, role)
+	}
+	if len(existingRoles) == 0 {
+		return false, nil
+	}
+	if len(existingRoles) != len(roles) {
+		d.Set("roles", existingRoles)
+	}
+
+	objectType := d.Get("object_type").(string)
+	object := redshiftGrantObject(d.Get)
+
+	var query string
+	switch objectType {
+	case "external_schema":
+		query = "SELECT 1 FROM svv_external_schemas WHERE schemaname = $1"
+	case "datashare":
+		query = "SELECT 1 FROM svv_datashares WHERE share_name = $1 AND share_type = 'OUTBOUND'"
+	}
+
+	err := txn.QueryRow(query, object).Scan(new(int))
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[DEBUG] %s %s does not exists", objectType, object)
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("could not check if %s %s exists: %w", objectType, object, err)
+	}
+	return true, nil
+}
+
+func redshiftUserExists(txn *sql.Tx, name string) (bool, error) {
+	err := txn.QueryRow("SELECT 1 FROM pg_user WHERE usename = $1", name).Scan(new(int))
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("could not check if user %s exists: %w", name, err)
+	}
+	return true, nil
+}
+
+// readRedshiftRolePrivileges reads the privileges of each user of the grant. As readRolePrivileges, the privileges
+// (or the grant option) of the first user which doesn't have the expected ones are set to force an update.
+func readRedshiftRolePrivileges(txn *sql.Tx, d *schema.ResourceData) error {
+	objectType := d.Get("object_type").(string)
+	object := redshiftGrantObject(d.Get)
+	expected := stringSliceToSet(expandPrivileges(objectType, d.Get("privileges").(*schema.Set)))
+
+	for _, role := range grantGrantees(d.Get) {
+		privileges, grantable, err := readRedshiftPrivilegesOf(txn, objectType, object, role)
+		if err != nil {
+			return err
+		}
+
+		if !pgArrayToSet(privileges).Equal(expected) {
+			log.Printf("[DEBUG] %s %s has not the expected privileges %v for user %s", objectType, object, privileges, role)
+			d.Set("privileges", pgArrayToSet(privileges))
+			return nil
+		}
+		if readGrantOption(d, privileges, grantable) {
+			log.Printf("[DEBUG] %s %s has not the expected grant option for user %s", objectType, object, role)
+			return nil
+		}
+	}
+	return nil
+}
+
+func readRedshiftPrivilegesOf(txn *sql.Tx, objectType, object, role string) (pq.ByteaArray, pq.ByteaArray, error) {
+	rows, err := txn.Query(redshiftPrivilegesQueries[objectType], object, role)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read the privileges of user %s on %s %s: %w", role, objectType, object, err)
+	}
+	defer rows.Close()
+
+	var privileges, grantable pq.ByteaArray
+	for rows.Next() {
+		var privilege string
+		var adminOption bool
+		if err := rows.Scan(&privilege, &adminOption); err != nil {
+			return nil, nil, fmt.Errorf("could not scan the privileges of user %s on %s %s: %w", role, objectType, object, err)
+		}
+		privileges = append(privileges, []byte(privilege))
+		if adminOption {
+			grantable = append(grantable, []byte(privilege))
+		}
+	}
+	return privileges, grantable, rows.Err()
+}
+
+// resourceRedshiftGrantCreateOrUpdate revokes the privileges (the previous ones if usePrevious is true)
+// and grants the new ones in the same transaction.
+func resourceRedshiftGrantCreateOrUpdate(db *DBConnection, d *schema.ResourceData, usePrevious bool) error {
+	if d.Get("verify").(bool) {
+		return fmt.Errorf("`verify` is not supported when `object_type` is `%s`", d.Get("object_type").(string))
+	}
+
+	database := d.Get("database").(string)
+	if err := withTransaction(db.client, database, func(txn *sql.Tx) error {
+		getter := d.Get
+		if usePrevious {
+			var err error
+			if getter, err = previousRedshiftGrantGetter(txn, d); err != nil {
+				return err
+			}
+		}
+
+		var queries []string
+		if len(grantGrantees(getter)) > 0 {
+			queries = append(queries, createRevokeQuery(getter))
+		}
+		if privileges := setToSortedSlice(d.Get("privileges").(*schema.Set)); len(privileges) > 0 {
+			queries = append(queries, createGrantQuery(d.Get, privileges))
+		}
+		return execGrantQueries(txn, d, db.client.config.journal, queries)
+	}); err != nil {
+		return err
+	}
+
+	if err := db.client.config.journal.commit(generateGrantID(d)); err != nil {
+		return err
+	}
+	if usePrevious && d.Id() != generateGrantID(d) {
+		if err := db.client.config.journal.forget(d.Id()); err != nil {
+			return err
+		}
+	}
+
+	d.SetId(generateGrantID(d))
+	d.Set("verified", false)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	return readRedshiftRolePrivileges(txn, d)
+}
+
+// previousRedshiftGrantGetter returns a getter of the previous values of the grant,
+// without the previous users which don't exist anymore.
+func previousRedshiftGrantGetter(txn *sql.Tx, d *schema.ResourceData) (ResourceSchemeGetter, error) {
+	overrides := map[string]interface{}{}
+	for _, attr := range []string{"schema", "objects"} {
+		overrides[attr], _ = d.GetChange(attr)
+	}
+
+	oldRole, _ := d.GetChange("role")
+	overrides["role"] = ""
+	if role := oldRole.(string); role != "" {
+		exists := isPublicRole(role)
+		if !exists {
+			var err error
+			if exists, err = redshiftUserExists(txn, role); err != nil {
+				return nil, err
+			}
+		}
+		if exists {
+			overrides["role"] = role
+		}
+	}
+
+	oldRoles, _ := d.GetChange("roles")
+	roles := schema.NewSet(schema.HashString, nil)
+	for _, role := range oldRoles.(*schema.Set).List() {
+		exists := isPublicRole(role.(string))
+		if !exists {
+			var err error
+			if exists, err = redshiftUserExists(txn, role.(string)); err != nil {
+				return nil, err
+			}
+		}
+		if exists {
+			roles.Add(role)
+		}
+	}
+	overrides["roles"] = roles
+
+	return overrideGetter(d.Get, overrides), nil
+}
+
+func resourceRedshiftGrantDelete(db *DBConnection, d *schema.ResourceData) error {
+	if err := withTransaction(db.client, d.Get("database").(string), func(txn *sql.Tx) error {
+		return execBatch(txn, createRevokeQuery(d.Get))
+	}); err != nil {
+		return fmt.Errorf("could not execute revoke query: %w", err)
+	}
+
+	return db.client.config.journal.forget(d.Id())
+}
@@ -1,11 +1,10 @@
 package postgresql
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
-	"strconv"
-	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/lib/pq"
@@ -28,9 +27,14 @@ WHERE
 
 func resourcePostgreSQLGrantRole() *schema.Resource {
 	return &schema.Resource{
-		Create: PGResourceFunc(resourcePostgreSQLGrantRoleCreate),
-		Read:   PGResourceFunc(resourcePostgreSQLGrantRoleRead),
-		Delete: PGResourceFunc(resourcePostgreSQLGrantRoleDelete),
+		Create:        PGResourceFunc(resourcePostgreSQLGrantRoleCreate),
+		Read:          PGReadResourceFunc(resourcePostgreSQLGrantRoleRead),
+		Update:        PGResourceFunc(resourcePostgreSQLGrantRoleUpdate),
+		Delete:        PGResourceFunc(resourcePostgreSQLGrantRoleDelete),
+		CustomizeDiff: resourcePostgreSQLGrantRoleCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: PGImportResourceFunc(resourcePostgreSQLGrantRoleImport),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"role": {
@@ -48,14 +52,34 @@ func resourcePostgreSQLGrantRole() *schema.Resource {
 			"with_admin_option": {
 				Type:        schema.TypeBool,
 				Optional:    true,
-				ForceNew:    true,
 				Default:     false,
 				Description: "Permit the grant recipient to grant it to others",
 			},
+			"verify": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Check after apply that role can use the privileges of grant_role and fail otherwise",
+			},
+			"verified": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the membership has been verified during the last apply",
+			},
+			"skip_final_revoke_on_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Only remove the membership from the state when destroying it, without running REVOKE (e.g.: when the database is dropped anyway)",
+			},
 		},
 	}
 }
 
+func resourcePostgreSQLGrantRoleCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	return customizeDiffSkipFinalRevoke(d, "role", "grant_role")
+}
+
 func resourcePostgreSQLGrantRoleRead(db *DBConnection, d *schema.ResourceData) error {
 	if !db.featureSupported(featurePrivileges) {
 		return fmt.Errorf(
@@ -75,31 +99,50 @@ func resourcePostgreSQLGrantRoleCreate(db *DBConnection, d *schema.ResourceData)
 		)
 	}
 
-	txn, err := startTransaction(db.client, "")
-	if err != nil {
+	if err := withTransaction(db.client, "", func(txn *sql.Tx) error {
+		// Revoke the granted roles before granting them again.
+		if err := revokeRole(txn, d); err != nil {
+			return err
+		}
+		return grantRole(txn, d)
+	}); err != nil {
 		return err
 	}
-	defer deferredRollback(txn)
 
-	// Revoke the granted roles before granting them again.
-	if err = revokeRole(txn, d); err != nil {
-		return err
-	}
+	d.SetId(generateGrantRoleID(d))
 
-	if err = grantRole(txn, d); err != nil {
+	if err := verifyGrantRole(db, d); err != nil {
 		return err
 	}
 
-	if err = txn.Commit(); err != nil {
-		return fmt.Errorf("could not commit transaction: %w", err)
+	return readGrantRole(db, d)
+}
+
+func resourcePostgreSQLGrantRoleUpdate(db *DBConnection, d *schema.ResourceData) error {
+	// Only with_admin_option and verify can be updated, the roles force a new resource.
+	if d.HasChange("with_admin_option") {
+		if err := withTransaction(db.client, "", func(txn *sql.Tx) error {
+			return setRoleMembershipAdminOption(
+				txn, d.Get("grant_role").(string), d.Get("role").(string), d.Get("with_admin_option").(bool),
+			)
+		}); err != nil {
+			return err
+		}
 	}
 
-	d.SetId(generateGrantRoleID(d))
+	if err := verifyGrantRole(db, d); err != nil {
+		return err
+	}
 
 	return readGrantRole(db, d)
 }
 
 func resourcePostgreSQLGrantRoleDelete(db *DBConnection, d *schema.ResourceData) error {
+	if d.Get("skip_final_revoke_on_destroy").(bool) {
+		log.Printf("[INFO] Skipping the revoke of grant role %s", d.Id())
+		return nil
+	}
+
 	if !db.featureSupported(featurePrivileges) {
 		return fmt.Errorf(
 			"postgresql_grant_role resource is not supported for this Postgres version (%s)",
@@ -107,21 +150,43 @@ func resourcePostgreSQLGrantRoleDelete(db *DBConnection, d *schema.ResourceData)
 		)
 	}
 
-	txn, err := startTransaction(db.client, "")
-	if err != nil {
-		return err
+	return withTransaction(db.client, "", func(txn *sql.Tx) error {
+		return revokeRole(txn, d)
+	})
+}
+
+// resourcePostgreSQLGrantRoleImport imports a membership from an ID made of the role and the granted role
+// joined with generateObjectID (e.g.: my_role.my_group). It fails if role is not a member of grant_role.
+func resourcePostgreSQLGrantRoleImport(db *DBConnection, d *schema.ResourceData) ([]*schema.ResourceData, error) {
+	if !db.featureSupported(featurePrivileges) {
+		return nil, fmt.Errorf(
+			"postgresql_grant_role resource is not supported for this Postgres version (%s)",
+			db.version,
+		)
 	}
-	defer deferredRollback(txn)
 
-	if err = revokeRole(txn, d); err != nil {
-		return err
+	parts, err := parseObjectID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("grant role ID %s has not the expected format 'role.grant_role'", d.Id())
 	}
+	role, grantRoleName := parts[0], parts[1]
 
-	if err = txn.Commit(); err != nil {
-		return fmt.Errorf("could not commit transaction: %w", err)
+	d.Set("role", role)
+	d.Set("grant_role", grantRoleName)
+	if err := readGrantRole(db, d); err != nil {
+		return nil, err
+	}
+	if d.Id() == "" {
+		return nil, fmt.Errorf("could not import grant role: role %s is not a member of %s", role, grantRoleName)
 	}
+	d.Set("verify", false)
+	d.Set("verified", false)
+	d.Set("skip_final_revoke_on_destroy", false)
 
-	return nil
+	return []*schema.ResourceData{d}, nil
 }
 
 func readGrantRole(db QueryAble, d *schema.ResourceData) error {
@@ -155,6 +220,32 @@ func readGrantRole(db QueryAble, d *schema.ResourceData) error {
 	return nil
 }
 
+// verifyGrantRole checks, if requested, that role can use the privileges of grant_role,
+// i.e.: role is a member of grant_role (directly or not) and inherits its privileges.
+func verifyGrantRole(db QueryAble, d *schema.ResourceData) error {
+	if !d.Get("verify").(bool) {
+		d.Set("verified", false)
+		return nil
+	}
+
+	role := d.Get("role").(string)
+	grantRole := d.Get("grant_role").(string)
+
+	var hasUsage bool
+	if err := db.QueryRow("SELECT pg_has_role($1, $2, 'USAGE')", role, grantRole).Scan(&hasUsage); err != nil {
+		return fmt.Errorf("could not verify membership of role %s in %s: %w", role, grantRole, err)
+	}
+	d.Set("verified", hasUsage)
+
+	if !hasUsage {
+		return fmt.Errorf(
+			"membership verification failed, role %s cannot use the privileges of %s (is it NOINHERIT?)",
+			role, grantRole,
+		)
+	}
+	return nil
+}
+
 func createGrantRoleQuery(d *schema.ResourceData) string {
 	grantRole, _ := d.Get("grant_role").(string)
 	role, _ := d.Get("role").(string)
@@ -182,6 +273,19 @@ func createRevokeRoleQuery(d *schema.ResourceData) string {
 	)
 }
 
+// setRoleMembershipAdminOption grants or revokes the admin option of the membership of member in role
+// without revoking the membership itself.
+func setRoleMembershipAdminOption(txn *sql.Tx, role, member string, adminOption bool) error {
+	query := fmt.Sprintf("REVOKE ADMIN OPTION FOR %s FROM %s", pq.QuoteIdentifier(role), pq.QuoteIdentifier(member))
+	if adminOption {
+		query = fmt.Sprintf("GRANT %s TO %s WITH ADMIN OPTION", pq.QuoteIdentifier(role), pq.QuoteIdentifier(member))
+	}
+	if _, err := txn.Exec(query); err != nil {
+		return fmt.Errorf("could not update admin option of the membership of %s in %s: %w", member, role, err)
+	}
+	return nil
+}
+
 func grantRole(txn *sql.Tx, d *schema.ResourceData) error {
 	query := createGrantRoleQuery(d)
 	if _, err := txn.Exec(query); err != nil {
@@ -198,6 +302,8 @@ func revokeRole(txn *sql.Tx, d *schema.ResourceData) error {
 	return nil
 }
 
+// generateGrantRoleID returns the ID of the resource, in the format of the import (see parseObjectID).
+// The ID of the resources created before, role_grant_role_with_admin_option, is replaced when they are read.
 func generateGrantRoleID(d *schema.ResourceData) string {
-	return strings.Join([]string{d.Get("role").(string), d.Get("grant_role").(string), strconv.FormatBool(d.Get("with_admin_option").(bool))}, "_")
+	return generateObjectID(d.Get("role").(string), d.Get("grant_role").(string))
 }
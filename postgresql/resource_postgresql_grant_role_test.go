@@ -3,13 +3,16 @@ package postgresql
 import (
 	"database/sql"
 	"fmt"
+	"regexp"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestCreateGrantRoleQuery(t *testing.T) {
@@ -92,6 +95,19 @@ func TestRevokeRoleQuery(t *testing.T) {
 	}
 }
 
+func TestGenerateGrantRoleID(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourcePostgreSQLGrantRole().Schema, map[string]interface{}{
+		"role":              "bob",
+		"grant_role":        "my.admin",
+		"with_admin_option": true,
+	})
+	assert.Equal(t, `bob."my.admin"`, generateGrantRoleID(d))
+
+	parts, err := parseObjectID(generateGrantRoleID(d))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"bob", "my.admin"}, parts)
+}
+
 func TestAccPostgresqlGrantRole(t *testing.T) {
 	skipIfNotAcc(t)
 
@@ -132,9 +148,83 @@ func TestAccPostgresqlGrantRole(t *testing.T) {
 						"postgresql_grant_role.grant_role", "grant_role", grantedRoleName),
 					resource.TestCheckResourceAttr(
 						"postgresql_grant_role.grant_role", "with_admin_option", strconv.FormatBool(true)),
+					resource.TestCheckResourceAttr(
+						"postgresql_grant_role.grant_role", "id", fmt.Sprintf("%s.%s", roleName, grantedRoleName)),
 					checkGrantRole(t, dsn, roleName, grantedRoleName, true),
 				),
 			},
+			{
+				ResourceName:      "postgresql_grant_role.grant_role",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%s.%s", roleName, grantedRoleName),
+				ImportStateVerify: true,
+			},
+			{
+				// The membership is checked during the import.
+				ResourceName:  "postgresql_grant_role.grant_role",
+				ImportState:   true,
+				ImportStateId: fmt.Sprintf("%s.%s", grantedRoleName, roleName),
+				ExpectError:   regexp.MustCompile("is not a member of"),
+			},
+			{
+				// The admin option is updated in place.
+				Config: strings.Replace(testAccPostgresqlGrantRoleResources, "with_admin_option = true", "with_admin_option = false", 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"postgresql_grant_role.grant_role", "with_admin_option", strconv.FormatBool(false)),
+					checkGrantRole(t, dsn, roleName, grantedRoleName, false),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlGrantRoleVerify(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, false, true)
+	defer teardown()
+
+	_, roleName := getTestDBNames(dbSuffix)
+
+	testAccPostgresqlGrantRoleVerifyResources := fmt.Sprintf(`
+	resource postgresql_role "grant" {
+		name = "foo"
+	}
+	resource postgresql_role "noinherit" {
+		name    = "foo_noinherit"
+		inherit = false
+	}
+	resource postgresql_grant_role "grant_role" {
+		role       = "%s"
+		grant_role = postgresql_role.grant.name
+		verify     = true
+	}
+	resource postgresql_grant_role "noinherit" {
+		role       = postgresql_role.noinherit.name
+		grant_role = postgresql_role.grant.name
+		verify     = %%t
+	}
+	`, roleName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccPostgresqlGrantRoleVerifyResources, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant_role.grant_role", "verified", "true"),
+					resource.TestCheckResourceAttr("postgresql_grant_role.noinherit", "verified", "false"),
+				),
+			},
+			{
+				Config:      fmt.Sprintf(testAccPostgresqlGrantRoleVerifyResources, true),
+				ExpectError: regexp.MustCompile("membership verification failed"),
+			},
 		},
 	})
 }
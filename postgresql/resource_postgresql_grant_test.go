@@ -1,6 +1,7 @@
 package postgresql
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"regexp"
@@ -11,6 +12,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestCreateGrantQuery(t *testing.T) {
@@ -148,6 +150,25 @@ func TestCreateGrantQuery(t *testing.T) {
 			privileges: []string{"ALL PRIVILEGES"},
 			expected:   fmt.Sprintf(`GRANT ALL PRIVILEGES ON FOREIGN DATA WRAPPER "baz" TO %s WITH GRANT OPTION`, pq.QuoteIdentifier(roleName)),
 		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "external_schema",
+				"schema":      "spectrum",
+				"role":        roleName,
+			}),
+			privileges: []string{"USAGE"},
+			expected:   fmt.Sprintf(`GRANT USAGE ON SCHEMA "spectrum" TO %s`, pq.QuoteIdentifier(roleName)),
+		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type":       "datashare",
+				"objects":           []interface{}{"sales_share"},
+				"role":              roleName,
+				"with_grant_option": true,
+			}),
+			privileges: []string{"ALTER", "SHARE"},
+			expected:   fmt.Sprintf(`GRANT ALTER,SHARE ON DATASHARE "sales_share" TO %s WITH GRANT OPTION`, pq.QuoteIdentifier(roleName)),
+		},
 		{
 			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
 				"object_type": "foreign_server",
@@ -167,10 +188,96 @@ func TestCreateGrantQuery(t *testing.T) {
 			privileges: []string{"ALL PRIVILEGES"},
 			expected:   fmt.Sprintf(`GRANT ALL PRIVILEGES ON FOREIGN SERVER "baz" TO %s WITH GRANT OPTION`, pq.QuoteIdentifier(roleName)),
 		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "sequence",
+				"objects":     []interface{}{"other_schema.seq1"},
+				"schema":      databaseName,
+				"role":        roleName,
+			}),
+			privileges: []string{"USAGE"},
+			expected:   fmt.Sprintf(`GRANT USAGE ON SEQUENCE "other_schema"."seq1" TO %s`, pq.QuoteIdentifier(roleName)),
+		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "table",
+				"objects":     []interface{}{`"my.table"`},
+				"schema":      databaseName,
+				"role":        roleName,
+			}),
+			privileges: []string{"SELECT"},
+			expected:   fmt.Sprintf(`GRANT SELECT ON TABLE %s."my.table" TO %s`, pq.QuoteIdentifier(databaseName), pq.QuoteIdentifier(roleName)),
+		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "type",
+				"objects":     tableObjects,
+				"schema":      databaseName,
+				"role":        roleName,
+			}),
+			privileges: []string{"USAGE"},
+			expected:   fmt.Sprintf(`GRANT USAGE ON TYPE %[1]s."o2",%[1]s."o1" TO %s`, pq.QuoteIdentifier(databaseName), pq.QuoteIdentifier(roleName)),
+		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "type",
+				"schema":      databaseName,
+				"role":        roleName,
+			}),
+			privileges: []string{"USAGE"},
+			expected:   "",
+		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "domain",
+				"objects":     []interface{}{"d1"},
+				"schema":      databaseName,
+				"role":        roleName,
+			}),
+			privileges: []string{"USAGE"},
+			expected:   fmt.Sprintf(`GRANT USAGE ON DOMAIN %s."d1" TO %s`, pq.QuoteIdentifier(databaseName), pq.QuoteIdentifier(roleName)),
+		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "large_object",
+				"objects":     []interface{}{"16403"},
+				"role":        roleName,
+			}),
+			privileges: []string{"SELECT", "UPDATE"},
+			expected:   fmt.Sprintf(`GRANT SELECT,UPDATE ON LARGE OBJECT 16403 TO %s`, pq.QuoteIdentifier(roleName)),
+		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "schema",
+				"schema":      databaseName,
+				"roles":       []interface{}{"r2", "r1", "public"},
+			}),
+			privileges: []string{"USAGE"},
+			expected:   fmt.Sprintf(`GRANT USAGE ON SCHEMA %s TO PUBLIC, "r1", "r2"`, pq.QuoteIdentifier(databaseName)),
+		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "schema",
+				"schema":      databaseName,
+				"role":        "Public",
+			}),
+			privileges: []string{"USAGE"},
+			expected:   fmt.Sprintf(`GRANT USAGE ON SCHEMA %s TO PUBLIC`, pq.QuoteIdentifier(databaseName)),
+		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type":               "function",
+				"schema":                    databaseName,
+				"role":                      roleName,
+				"exclude_extension_objects": true,
+			}),
+			privileges: []string{"EXECUTE"},
+			expected:   "",
+		},
 	}
 
 	for _, c := range cases {
-		out := createGrantQuery(c.resource, c.privileges)
+		out := createGrantQuery(c.resource.Get, c.privileges)
 		if out != c.expected {
 			t.Fatalf("Error matching output and expected: %#v vs %#v", out, c.expected)
 		}
@@ -282,6 +389,22 @@ func TestCreateRevokeQuery(t *testing.T) {
 			}),
 			expected: fmt.Sprintf(`REVOKE ALL PRIVILEGES ON FOREIGN DATA WRAPPER "baz" FROM %s`, pq.QuoteIdentifier(roleName)),
 		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "external_schema",
+				"schema":      "spectrum",
+				"role":        roleName,
+			}),
+			expected: fmt.Sprintf(`REVOKE ALL PRIVILEGES ON SCHEMA "spectrum" FROM %s`, pq.QuoteIdentifier(roleName)),
+		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "datashare",
+				"objects":     []interface{}{"sales_share"},
+				"role":        roleName,
+			}),
+			expected: fmt.Sprintf(`REVOKE ALTER, SHARE ON DATASHARE "sales_share" FROM %s`, pq.QuoteIdentifier(roleName)),
+		},
 		{
 			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
 				"object_type": "foreign_server",
@@ -290,6 +413,51 @@ func TestCreateRevokeQuery(t *testing.T) {
 			}),
 			expected: fmt.Sprintf(`REVOKE ALL PRIVILEGES ON FOREIGN SERVER "baz" FROM %s`, pq.QuoteIdentifier(roleName)),
 		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "type",
+				"objects":     tableObjects,
+				"schema":      databaseName,
+				"role":        roleName,
+			}),
+			expected: fmt.Sprintf(`REVOKE ALL PRIVILEGES ON TYPE %[1]s."o2",%[1]s."o1" FROM %s`, pq.QuoteIdentifier(databaseName), pq.QuoteIdentifier(roleName)),
+		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "domain",
+				"objects":     []interface{}{"d1"},
+				"schema":      databaseName,
+				"role":        roleName,
+			}),
+			expected: fmt.Sprintf(`REVOKE ALL PRIVILEGES ON DOMAIN %s."d1" FROM %s`, pq.QuoteIdentifier(databaseName), pq.QuoteIdentifier(roleName)),
+		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "large_object",
+				"objects":     []interface{}{"16403"},
+				"role":        roleName,
+			}),
+			expected: fmt.Sprintf(`REVOKE ALL PRIVILEGES ON LARGE OBJECT 16403 FROM %s`, pq.QuoteIdentifier(roleName)),
+		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "large_object",
+				"objects":     []interface{}{"16403"},
+				"role":        roleName,
+				"privileges":  []interface{}{"UPDATE"},
+			}),
+			expected: fmt.Sprintf(`REVOKE UPDATE ON LARGE OBJECT 16403 FROM %s`, pq.QuoteIdentifier(roleName)),
+		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "table",
+				"objects":     []interface{}{"o1"},
+				"schema":      databaseName,
+				"roles":       []interface{}{"r2", "r1"},
+				"privileges":  []interface{}{"SELECT"},
+			}),
+			expected: fmt.Sprintf(`REVOKE SELECT ON TABLE %s."o1" FROM "r1", "r2"`, pq.QuoteIdentifier(databaseName)),
+		},
 	}
 
 	for _, c := range cases {
@@ -300,6 +468,237 @@ func TestCreateRevokeQuery(t *testing.T) {
 	}
 }
 
+func TestCheckGrantFlavor(t *testing.T) {
+	assert.NoError(t, checkGrantFlavor(flavorPostgreSQL, "table"))
+	assert.NoError(t, checkGrantFlavor(flavorRedshift, "table"))
+	assert.NoError(t, checkGrantFlavor(flavorRedshift, "datashare"))
+	assert.EqualError(
+		t, checkGrantFlavor(flavorPostgreSQL, "external_schema"),
+		"object type external_schema is only supported when the provider flavor is redshift",
+	)
+}
+
+func TestReadGrantOption(t *testing.T) {
+	for _, c := range []struct {
+		withGrantOption bool
+		grantable       pq.ByteaArray
+		drift           bool
+	}{
+		{withGrantOption: true, grantable: pq.ByteaArray{[]byte("SELECT"), []byte("INSERT")}},
+		{withGrantOption: true, grantable: pq.ByteaArray{[]byte("SELECT")}, drift: true},
+		{withGrantOption: false, grantable: nil},
+		{withGrantOption: false, grantable: pq.ByteaArray{[]byte("INSERT")}, drift: true},
+	} {
+		d := schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+			"with_grant_option": c.withGrantOption,
+		})
+		privileges := pq.ByteaArray{[]byte("INSERT"), []byte("SELECT")}
+
+		assert.Equal(t, c.drift, readGrantOption(d, privileges, c.grantable))
+		assert.Equal(t, c.withGrantOption != c.drift, d.Get("with_grant_option").(bool))
+	}
+}
+
+func TestCreateRevokeGrantOptionQuery(t *testing.T) {
+	var databaseName = "foo"
+	var roleName = "bar"
+	var tableObjects = []interface{}{"o1"}
+
+	cases := []struct {
+		resource *schema.ResourceData
+		expected string
+	}{
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "table",
+				"schema":      databaseName,
+				"role":        roleName,
+				"privileges":  []interface{}{"SELECT"},
+			}),
+			expected: fmt.Sprintf("REVOKE GRANT OPTION FOR SELECT ON ALL TABLES IN SCHEMA %s FROM %s", pq.QuoteIdentifier(databaseName), pq.QuoteIdentifier(roleName)),
+		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "table",
+				"objects":     tableObjects,
+				"schema":      databaseName,
+				"role":        roleName,
+				"privileges":  []interface{}{"SELECT"},
+			}),
+			expected: fmt.Sprintf(`REVOKE GRANT OPTION FOR SELECT ON TABLE %s."o1" FROM %s`, pq.QuoteIdentifier(databaseName), pq.QuoteIdentifier(roleName)),
+		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "database",
+				"database":    databaseName,
+				"role":        roleName,
+				"privileges":  []interface{}{"CONNECT"},
+			}),
+			expected: fmt.Sprintf("REVOKE GRANT OPTION FOR CONNECT ON DATABASE %s FROM %s", pq.QuoteIdentifier(databaseName), pq.QuoteIdentifier(roleName)),
+		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "column",
+				"objects":     tableObjects,
+				"columns":     []interface{}{"col1"},
+				"schema":      databaseName,
+				"role":        roleName,
+				"privileges":  []interface{}{"SELECT"},
+			}),
+			expected: fmt.Sprintf(`REVOKE GRANT OPTION FOR SELECT ("col1") ON TABLE %s."o1" FROM %s`, pq.QuoteIdentifier(databaseName), pq.QuoteIdentifier(roleName)),
+		},
+		{
+			resource: schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+				"object_type": "large_object",
+				"objects":     []interface{}{"16403"},
+				"role":        roleName,
+				"privileges":  []interface{}{"SELECT"},
+			}),
+			expected: fmt.Sprintf(`REVOKE GRANT OPTION FOR SELECT ON LARGE OBJECT 16403 FROM %s`, pq.QuoteIdentifier(roleName)),
+		},
+	}
+
+	for _, c := range cases {
+		out := createRevokeGrantOptionQuery(c.resource.Get)
+		if out != c.expected {
+			t.Fatalf("Error matching output and expected: %#v vs %#v", out, c.expected)
+		}
+	}
+}
+
+func TestQuoteGrantObjectName(t *testing.T) {
+	assert.Equal(t, "test", quoteGrantObjectName("test"))
+	assert.Equal(t, `"my.table"`, quoteGrantObjectName("my.table"))
+	assert.Equal(t, "test(integer, public.my_type)", quoteGrantObjectName("test(integer, public.my_type)"))
+	assert.Equal(t, `"my.func"(text)`, quoteGrantObjectName("my.func(text)"))
+}
+
+func TestGrantID(t *testing.T) {
+	cases := []struct {
+		id       grantID
+		expected string
+	}{
+		{
+			id:       grantID{role: "my_role", database: "my_db", objectType: "database"},
+			expected: "my_role.my_db.database",
+		},
+		{
+			id:       grantID{role: "my_role", database: "my_db", objectType: "table", schema: "my_schema"},
+			expected: "my_role.my_db.table.my_schema",
+		},
+		{
+			id:       grantID{role: "my_role", database: "my.db", objectType: "table", schema: "public", objects: []string{"t1", "other.t2"}},
+			expected: `my_role."my.db".table.public.t1."other.t2"`,
+		},
+		{
+			id:       grantID{role: "my_role", database: "my_db", objectType: "column", schema: "public", objects: []string{"t1"}, columns: []string{"c1", "c2"}},
+			expected: "my_role.my_db.column.public.t1.c1.c2",
+		},
+		{
+			id:       grantID{role: "my_role", database: "my_db", objectType: "large_object", objects: []string{"16403"}},
+			expected: "my_role.my_db.large_object.16403",
+		},
+		{
+			id:       grantID{role: "role1,role2", database: "my_db", objectType: "schema", schema: "public"},
+			expected: "role1,role2.my_db.schema.public",
+		},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.expected, c.id.String())
+
+		parsed, err := parseGrantID(c.expected)
+		assert.NoError(t, err)
+		assert.Equal(t, c.id, parsed)
+	}
+}
+
+func TestGenerateGrantIDWithRoles(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+		"database":    "my_db",
+		"object_type": "schema",
+		"schema":      "public",
+		"roles":       []interface{}{"role2", "role1"},
+		"privileges":  []interface{}{"USAGE"},
+	})
+	assert.Equal(t, "role1,role2.my_db.schema.public", generateGrantID(d))
+	assert.Equal(t, []string{"role1", "role2"}, grantGrantees(d.Get))
+
+	d = schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+		"role": "role1",
+	})
+	assert.Equal(t, []string{"role1"}, grantGrantees(d.Get))
+}
+
+func TestGrantSecurityWarnings(t *testing.T) {
+	for _, role := range []string{"public", "PUBLIC", "Public"} {
+		d := schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+			"database":    "my_db",
+			"object_type": "database",
+			"roles":       []interface{}{"role1", role},
+			"privileges":  []interface{}{"CONNECT"},
+		})
+		assert.Len(t, grantSecurityWarnings(d.Get), 1, role)
+	}
+
+	d := schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+		"database":    "my_db",
+		"object_type": "database",
+		"role":        "role1",
+		"privileges":  []interface{}{"CONNECT"},
+	})
+	assert.Empty(t, grantSecurityWarnings(d.Get))
+}
+
+func TestParseLegacyGrantID(t *testing.T) {
+	cases := []struct {
+		id       string
+		expected grantID
+	}{
+		{
+			id:       "role_db_database",
+			expected: grantID{role: "role", database: "db", objectType: "database"},
+		},
+		{
+			id:       "role_db_public_table_t1_t2",
+			expected: grantID{role: "role", database: "db", objectType: "table", schema: "public", objects: []string{"t1", "t2"}},
+		},
+		{
+			id:       "role_db_foreign_data_wrapper_fdw",
+			expected: grantID{role: "role", database: "db", objectType: "foreign_data_wrapper", objects: []string{"fdw"}},
+		},
+		{
+			id:       "role_db_public_column_t1_c1_c2",
+			expected: grantID{role: "role", database: "db", objectType: "column", schema: "public", objects: []string{"t1"}, columns: []string{"c1", "c2"}},
+		},
+	}
+
+	for _, c := range cases {
+		parsed, err := parseGrantID(c.id)
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, parsed)
+	}
+
+	_, err := parseGrantID("role_db_unknown")
+	assert.Error(t, err)
+}
+
+func TestResourcePostgreSQLGrantStateUpgradeV0(t *testing.T) {
+	state, err := resourcePostgreSQLGrantStateUpgradeV0(context.Background(), map[string]interface{}{
+		"id":          "my_role_my_db_public_table_t2_t1",
+		"role":        "my_role",
+		"database":    "my_db",
+		"schema":      "public",
+		"object_type": "table",
+		"objects":     []interface{}{"t2", "t1"},
+		"columns":     []interface{}{},
+		"privileges":  []interface{}{"SELECT"},
+	}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "my_role.my_db.table.public.t1.t2", state["id"])
+	assert.Equal(t, []interface{}{"SELECT"}, state["privileges"])
+}
+
 func TestAccPostgresqlGrant(t *testing.T) {
 	skipIfNotAcc(t)
 
@@ -337,7 +736,7 @@ func TestAccPostgresqlGrant(t *testing.T) {
 				Config: fmt.Sprintf(testGrant, `["SELECT"]`),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr(
-						"postgresql_grant.test", "id", fmt.Sprintf("%s_%s_test_schema_table", roleName, dbName),
+						"postgresql_grant.test", "id", fmt.Sprintf("%s.%s.table.test_schema", roleName, dbName),
 					),
 					resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.#", "1"),
 					resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.0", "SELECT"),
@@ -369,13 +768,290 @@ func TestAccPostgresqlGrant(t *testing.T) {
 					},
 				),
 			},
-			// We test to revoke everything
+			// We test to revoke everything
+			{
+				Config: fmt.Sprintf(testGrant, `[]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.#", "0"),
+					func(*terraform.State) error {
+						return testCheckTablesPrivileges(t, dbName, roleName, testTables, []string{})
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlGrantRoles(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	testTables := []string{"test_schema.test_table", "test_schema.test_table2"}
+	createTestTables(t, dbSuffix, testTables, "")
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+	otherRole := roleName + "_other"
+
+	config := getTestConfig(t)
+	dsn := config.connStr(dbName)
+	dbExecute(t, dsn, fmt.Sprintf("CREATE ROLE %s LOGIN PASSWORD '%s'", otherRole, testRolePassword))
+	dbExecute(t, dsn, fmt.Sprintf("GRANT USAGE ON SCHEMA test_schema TO %s", otherRole))
+	defer func() {
+		dbExecute(t, dsn, fmt.Sprintf("DROP OWNED BY %s", otherRole))
+		dbExecute(t, dsn, fmt.Sprintf("DROP ROLE %s", otherRole))
+	}()
+
+	var testGrant = fmt.Sprintf(`
+	resource "postgresql_grant" "test" {
+		database    = "%s"
+		roles       = ["%s", "%s"]
+		schema      = "test_schema"
+		object_type = "table"
+		privileges  = %%s
+	}
+	`, dbName, roleName, otherRole)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testGrant, `["SELECT"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"postgresql_grant.test", "id", fmt.Sprintf("%s,%s.%s.table.test_schema", roleName, otherRole, dbName),
+					),
+					resource.TestCheckResourceAttr("postgresql_grant.test", "roles.#", "2"),
+					func(*terraform.State) error {
+						if err := testCheckTablesPrivileges(t, dbName, roleName, testTables, []string{"SELECT"}); err != nil {
+							return err
+						}
+						return testCheckTablesPrivileges(t, dbName, otherRole, testTables, []string{"SELECT"})
+					},
+				),
+			},
+			{
+				// A privilege revoked from one of the roles outside of Terraform is detected.
+				PreConfig: func() {
+					dbExecute(t, dsn, fmt.Sprintf("REVOKE SELECT ON test_schema.test_table FROM %s", otherRole))
+				},
+				Config: fmt.Sprintf(testGrant, `["SELECT"]`),
+				Check: func(*terraform.State) error {
+					return testCheckTablesPrivileges(t, dbName, otherRole, testTables, []string{"SELECT"})
+				},
+			},
+			{
+				Config: fmt.Sprintf(testGrant, `["SELECT", "INSERT"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.#", "2"),
+					func(*terraform.State) error {
+						if err := testCheckTablesPrivileges(t, dbName, roleName, testTables, []string{"SELECT", "INSERT"}); err != nil {
+							return err
+						}
+						return testCheckTablesPrivileges(t, dbName, otherRole, testTables, []string{"SELECT", "INSERT"})
+					},
+				),
+			},
+			{
+				ResourceName:      "postgresql_grant.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlGrantSkipObjectsCheck(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	testTables := []string{"test_schema.test_table", "test_schema.test_table2"}
+	createTestTables(t, dbSuffix, testTables, "")
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+	config := getTestConfig(t)
+	dsn := config.connStr(dbName)
+
+	var testGrant = fmt.Sprintf(`
+	resource "postgresql_grant" "test" {
+		database           = "%s"
+		role               = "%s"
+		schema             = "test_schema"
+		object_type        = "table"
+		privileges         = ["SELECT"]
+		skip_objects_check = %%t
+	}
+	`, dbName, roleName)
+
+	revokeOnTable := func() {
+		dbExecute(t, dsn, fmt.Sprintf("REVOKE SELECT ON test_schema.test_table2 FROM %s", pq.QuoteIdentifier(roleName)))
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testGrant, false),
+				Check: func(*terraform.State) error {
+					return testCheckTablesPrivileges(t, dbName, roleName, testTables, []string{"SELECT"})
+				},
+			},
+			// The privilege revoked on one of the tables is detected.
+			{
+				PreConfig:          revokeOnTable,
+				Config:             fmt.Sprintf(testGrant, false),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: fmt.Sprintf(testGrant, true),
+				Check: func(*terraform.State) error {
+					return testCheckTablesPrivileges(t, dbName, roleName, testTables, []string{"SELECT"})
+				},
+			},
+			// The privileges of each table are not read anymore.
+			{
+				PreConfig: revokeOnTable,
+				Config:    fmt.Sprintf(testGrant, true),
+				PlanOnly:  true,
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlGrantSkipFinalRevokeOnDestroy(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	testTables := []string{"test_schema.test_table"}
+	createTestTables(t, dbSuffix, testTables, "")
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+
+	var testGrant = fmt.Sprintf(`
+	resource "postgresql_grant" "test" {
+		database                     = "%s"
+		role                         = "%s"
+		schema                       = "test_schema"
+		object_type                  = "table"
+		privileges                   = ["SELECT"]
+		skip_final_revoke_on_destroy = true
+	}
+	`, dbName, roleName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+		},
+		Providers: testAccProviders,
+		// The privileges are kept when the grant is destroyed.
+		CheckDestroy: func(*terraform.State) error {
+			return testCheckTablesPrivileges(t, dbName, roleName, testTables, []string{"SELECT"})
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testGrant,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant.test", "skip_final_revoke_on_destroy", "true"),
+					func(*terraform.State) error {
+						return testCheckTablesPrivileges(t, dbName, roleName, testTables, []string{"SELECT"})
+					},
+				),
+			},
+			{
+				// The replaced grant would keep its privileges.
+				Config:      strings.Replace(testGrant, `object_type                  = "table"`, `object_type                  = "sequence"`, 1),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("cannot be replaced while skip_final_revoke_on_destroy is set"),
+			},
+		},
+	})
+}
+
+// Test that a grant option revoked outside of Terraform is detected and granted again in place.
+func TestAccPostgresqlGrantGrantOptionDrift(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	testTables := []string{"test_schema.test_table", "test_schema.test_table2"}
+	createTestTables(t, dbSuffix, testTables, "")
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+	config := getTestConfig(t)
+	dsn := config.connStr(dbName)
+
+	var testGrant = fmt.Sprintf(`
+	resource "postgresql_grant" "test" {
+		database          = "%s"
+		role              = "%s"
+		schema            = "test_schema"
+		object_type       = "table"
+		privileges        = ["SELECT"]
+		with_grant_option = true
+	}
+	`, dbName, roleName)
+
+	checkGrantOption := func(*terraform.State) error {
+		db, err := sql.Open("postgres", dsn)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		var grantable bool
+		if err := db.QueryRow(
+			"SELECT has_table_privilege($1, 'test_schema.test_table2', 'SELECT WITH GRANT OPTION')", roleName,
+		).Scan(&grantable); err != nil {
+			return err
+		}
+		if !grantable {
+			return fmt.Errorf("expected role %s to have SELECT WITH GRANT OPTION on test_schema.test_table2", roleName)
+		}
+		return nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testGrant,
+				Check:  checkGrantOption,
+			},
 			{
-				Config: fmt.Sprintf(testGrant, `[]`),
+				PreConfig: func() {
+					dbExecute(t, dsn, fmt.Sprintf("REVOKE GRANT OPTION FOR SELECT ON test_schema.test_table2 FROM %s", pq.QuoteIdentifier(roleName)))
+				},
+				Config:             testGrant,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testGrant,
 				Check: resource.ComposeTestCheckFunc(
-					resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.#", "0"),
+					resource.TestCheckResourceAttr("postgresql_grant.test", "with_grant_option", "true"),
+					checkGrantOption,
 					func(*terraform.State) error {
-						return testCheckTablesPrivileges(t, dbName, roleName, testTables, []string{})
+						return testCheckTablesPrivileges(t, dbName, roleName, testTables, []string{"SELECT"})
 					},
 				),
 			},
@@ -506,7 +1182,7 @@ func TestAccPostgresqlGrantObjects(t *testing.T) {
 				Config: fmt.Sprintf(testGrant, `["test_table"]`),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr(
-						"postgresql_grant.test", "id", fmt.Sprintf("%s_%s_test_schema_table_test_table", roleName, dbName),
+						"postgresql_grant.test", "id", fmt.Sprintf("%s.%s.table.test_schema.test_table", roleName, dbName),
 					),
 					resource.TestCheckResourceAttr("postgresql_grant.test", "objects.#", "1"),
 					resource.TestCheckResourceAttr("postgresql_grant.test", "objects.0", "test_table"),
@@ -566,6 +1242,83 @@ func TestAccPostgresqlGrantObjects(t *testing.T) {
 	})
 }
 
+func TestAccPostgresqlGrantObjectsInPlace(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	testTables := []string{"test_schema.test_table", "test_schema.test_table2"}
+	createTestTables(t, dbSuffix, testTables, "")
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+
+	var testGrant = fmt.Sprintf(`
+	resource "postgresql_grant" "test" {
+		database          = "%s"
+		role              = "%s"
+		schema            = "test_schema"
+		object_type       = "table"
+		objects           = %%s
+		privileges        = %%s
+		with_grant_option = true
+	}
+	`, dbName, roleName)
+
+	// The role grants its privilege on test_table to PUBLIC: revoking it from the role
+	// would fail without CASCADE, so the update must not revoke the kept privileges.
+	grantToPublic := func(query string) func() {
+		return func() {
+			db := connectAsTestRole(t, roleName, dbName)
+			defer db.Close()
+			if _, err := db.Exec(query); err != nil {
+				t.Fatalf("could not execute %s: %v", query, err)
+			}
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testGrant, `["test_table"]`, `["SELECT", "INSERT"]`),
+				Check: resource.ComposeTestCheckFunc(
+					func(*terraform.State) error {
+						return testCheckTablesPrivileges(t, dbName, roleName, []string{testTables[0]}, []string{"SELECT", "INSERT"})
+					},
+				),
+			},
+			{
+				PreConfig: grantToPublic("GRANT SELECT ON test_schema.test_table TO PUBLIC"),
+				Config:    fmt.Sprintf(testGrant, `["test_table", "test_table2"]`, `["SELECT"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant.test", "objects.#", "2"),
+					func(*terraform.State) error {
+						return testCheckTablesPrivileges(t, dbName, roleName, testTables, []string{"SELECT"})
+					},
+				),
+			},
+			{
+				PreConfig: grantToPublic("REVOKE SELECT ON test_schema.test_table FROM PUBLIC"),
+				Config:    fmt.Sprintf(testGrant, `["test_table2"]`, `["SELECT"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant.test", "objects.#", "1"),
+					func(*terraform.State) error {
+						return testCheckTablesPrivileges(t, dbName, roleName, []string{testTables[0]}, []string{})
+					},
+					func(*terraform.State) error {
+						return testCheckTablesPrivileges(t, dbName, roleName, []string{testTables[1]}, []string{"SELECT"})
+					},
+				),
+			},
+		},
+	})
+}
+
 func TestAccPostgresqlGrantObjectsError(t *testing.T) {
 	skipIfNotAcc(t)
 
@@ -584,7 +1337,7 @@ func TestAccPostgresqlGrantObjectsError(t *testing.T) {
 					objects     = ["o1", "o2"]
 					privileges  = ["CONNECT"]
 				}`,
-				ExpectError: regexp.MustCompile("cannot specify `objects` when `object_type` is `database` or `schema`"),
+				ExpectError: regexp.MustCompile("cannot specify `objects` when `object_type` is `database`, `schema` or `external_schema`"),
 			},
 			{
 				Config: `resource "postgresql_grant" "test" {
@@ -595,7 +1348,7 @@ func TestAccPostgresqlGrantObjectsError(t *testing.T) {
 					objects     = ["o1", "o2"]
 					privileges  = ["CONNECT"]
 				}`,
-				ExpectError: regexp.MustCompile("cannot specify `objects` when `object_type` is `database` or `schema`"),
+				ExpectError: regexp.MustCompile("cannot specify `objects` when `object_type` is `database`, `schema` or `external_schema`"),
 			},
 			{
 				Config: `resource "postgresql_grant" "test" {
@@ -605,7 +1358,7 @@ func TestAccPostgresqlGrantObjectsError(t *testing.T) {
 					objects     = ["o1", "o2"]
 					privileges  = ["USAGE"]
 				}`,
-				ExpectError: regexp.MustCompile("one element must be specified in `objects` when `object_type` is `foreign_data_wrapper` or `foreign_server`"),
+				ExpectError: regexp.MustCompile("one element must be specified in `objects` when `object_type` is `foreign_data_wrapper`, `foreign_server` or `datashare`"),
 			},
 			{
 				Config: `resource "postgresql_grant" "test" {
@@ -615,7 +1368,7 @@ func TestAccPostgresqlGrantObjectsError(t *testing.T) {
 					objects     = ["o1", "o2"]
 					privileges  = ["USAGE"]
 				}`,
-				ExpectError: regexp.MustCompile("one element must be specified in `objects` when `object_type` is `foreign_data_wrapper` or `foreign_server`"),
+				ExpectError: regexp.MustCompile("one element must be specified in `objects` when `object_type` is `foreign_data_wrapper`, `foreign_server` or `datashare`"),
 			},
 		},
 	})
@@ -682,6 +1435,165 @@ func TestAccPostgresqlGrantColumnsError(t *testing.T) {
 	})
 }
 
+func TestAccPostgresqlGrantTableAndColumns(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	testTables := []string{"test_schema.test_table"}
+	createTestTables(t, dbSuffix, testTables, "")
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+
+	// The table grant is applied after the column grant so revoking its privileges
+	// would also revoke the column ones if they were not restored.
+	var testGrant = fmt.Sprintf(`
+	resource "postgresql_grant" "columns" {
+		database    = "%[1]s"
+		role        = "%[2]s"
+		schema      = "test_schema"
+		object_type = "column"
+		objects     = ["test_table"]
+		columns     = ["test_column_one"]
+		privileges  = ["SELECT"]
+	}
+
+	resource "postgresql_grant" "table" {
+		database    = "%[1]s"
+		role        = "%[2]s"
+		schema      = "test_schema"
+		object_type = "table"
+		objects     = ["test_table"]
+		privileges  = %%s
+
+		depends_on = [postgresql_grant.columns]
+	}
+	`, dbName, roleName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testGrant, `["SELECT"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant.columns", "columns.#", "1"),
+					resource.TestCheckResourceAttr("postgresql_grant.table", "privileges.#", "1"),
+					testCheckColumnACL(t, dbName, roleName, "test_column_one", "SELECT"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testGrant, `["INSERT"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant.columns", "columns.#", "1"),
+					resource.TestCheckResourceAttr("postgresql_grant.table", "privileges.0", "INSERT"),
+					testCheckColumnACL(t, dbName, roleName, "test_column_one", "SELECT"),
+				),
+			},
+		},
+	})
+}
+
+// testCheckColumnACL checks that the role has the column-level privilege in the ACL of the column of test_schema.test_table.
+func testCheckColumnACL(t *testing.T, dbName, roleName, column, privilege string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		config := getTestConfig(t)
+		db, err := sql.Open("postgres", config.connStr(dbName))
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		var granted bool
+		if err := db.QueryRow(`
+SELECT count(*) > 0
+FROM pg_attribute, aclexplode(attacl) acl
+WHERE attrelid = 'test_schema.test_table'::regclass AND attname = $1
+  AND acl.grantee = to_regrole($2) AND acl.privilege_type = $3
+`, column, roleName, privilege).Scan(&granted); err != nil {
+			return fmt.Errorf("could not read column privileges: %w", err)
+		}
+		if !granted {
+			return fmt.Errorf("role %s has not the column privilege %s on %s", roleName, privilege, column)
+		}
+		return nil
+	}
+}
+
+func TestAccPostgresqlGrantQualifiedObjects(t *testing.T) {
+	skipIfNotAcc(t)
+
+	config := getTestConfig(t)
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+	dsn := config.connStr(dbName)
+	dbExecute(t, dsn, "CREATE SEQUENCE test_schema.test_seq")
+	dbExecute(t, dsn, "CREATE SCHEMA other_schema")
+	dbExecute(t, dsn, "CREATE SEQUENCE other_schema.other_seq")
+
+	var testGrant = fmt.Sprintf(`
+	resource "postgresql_grant" "test" {
+		database    = "%s"
+		role        = "%s"
+		schema      = "test_schema"
+		object_type = "sequence"
+		objects     = %%s
+		privileges  = ["USAGE"]
+	}
+	`, dbName, roleName)
+
+	testCheckSequencePrivilege := func(sequence string, expected bool) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			db, err := sql.Open("postgres", dsn)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+
+			var granted bool
+			if err := db.QueryRow("SELECT has_sequence_privilege($1, $2, 'USAGE')", roleName, sequence).Scan(&granted); err != nil {
+				return fmt.Errorf("could not check privileges on %s: %w", sequence, err)
+			}
+			if granted != expected {
+				return fmt.Errorf("expected USAGE privilege of %s on %s to be %t", roleName, sequence, expected)
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testGrant, `["test_seq", "other_schema.other_seq"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant.test", "objects.#", "2"),
+					testCheckSequencePrivilege("test_schema.test_seq", true),
+					testCheckSequencePrivilege("other_schema.other_seq", true),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testGrant, `["other_schema.other_seq"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant.test", "objects.#", "1"),
+					testCheckSequencePrivilege("test_schema.test_seq", false),
+					testCheckSequencePrivilege("other_schema.other_seq", true),
+				),
+			},
+		},
+	})
+}
+
 func TestAccPostgresqlGrantPublic(t *testing.T) {
 	skipIfNotAcc(t)
 
@@ -735,7 +1647,7 @@ func TestAccPostgresqlGrantPublic(t *testing.T) {
 				Config: fmt.Sprintf(testGrant, `["SELECT"]`),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr(
-						"postgresql_grant.test", "id", fmt.Sprintf("public_%s_test_schema_table", dbName),
+						"postgresql_grant.test", "id", fmt.Sprintf("public.%s.table.test_schema", dbName),
 					),
 					resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.#", "1"),
 					func(*terraform.State) error {
@@ -776,6 +1688,137 @@ func TestAccPostgresqlGrantPublic(t *testing.T) {
 	})
 }
 
+func TestAccPostgresqlGrantPublicObjectTypes(t *testing.T) {
+	skipIfNotAcc(t)
+	skipIfNotSuperuser(t)
+
+	config := getTestConfig(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	testTables := []string{"test_schema.test_table"}
+	createTestTables(t, dbSuffix, testTables, "")
+
+	dbName, _ := getTestDBNames(dbSuffix)
+	dsn := config.connStr(dbName)
+	dbExecute(t, dsn, "CREATE SEQUENCE test_schema.test_seq")
+	dbExecute(t, dsn, "CREATE FUNCTION test_schema.test_func() RETURNS integer AS 'SELECT 1' LANGUAGE SQL")
+	dbExecute(t, dsn, "CREATE FOREIGN DATA WRAPPER test_fdw")
+	dbExecute(t, dsn, "CREATE SERVER test_srv FOREIGN DATA WRAPPER test_fdw")
+	defer dbExecute(t, dsn, "DROP FOREIGN DATA WRAPPER test_fdw CASCADE")
+
+	testCases := []struct {
+		objectType string
+		attrs      string
+		privilege  string
+		id         string
+		check      string
+	}{
+		{
+			objectType: "database",
+			privilege:  "CONNECT",
+			id:         fmt.Sprintf("public.%s.database", dbName),
+			check:      fmt.Sprintf("SELECT has_database_privilege('public', '%s', 'CONNECT')", dbName),
+		},
+		{
+			objectType: "schema",
+			attrs:      `schema = "test_schema"`,
+			privilege:  "USAGE",
+			id:         fmt.Sprintf("public.%s.schema.test_schema", dbName),
+			check:      "SELECT has_schema_privilege('public', 'test_schema', 'USAGE')",
+		},
+		{
+			objectType: "sequence",
+			attrs:      `schema = "test_schema"` + "\n" + `objects = ["test_seq"]`,
+			privilege:  "USAGE",
+			id:         fmt.Sprintf("public.%s.sequence.test_schema.test_seq", dbName),
+			check:      "SELECT has_sequence_privilege('public', 'test_schema.test_seq', 'USAGE')",
+		},
+		{
+			objectType: "function",
+			attrs:      `schema = "test_schema"` + "\n" + `objects = ["test_func"]`,
+			privilege:  "EXECUTE",
+			id:         fmt.Sprintf("public.%s.function.test_schema.test_func", dbName),
+			check:      "SELECT has_function_privilege('public', 'test_schema.test_func()', 'EXECUTE')",
+		},
+		{
+			objectType: "column",
+			attrs:      `schema = "test_schema"` + "\n" + `objects = ["test_table"]` + "\n" + `columns = ["val"]`,
+			privilege:  "SELECT",
+			id:         fmt.Sprintf("public.%s.column.test_schema.test_table.val", dbName),
+			check:      "SELECT has_column_privilege('public', 'test_schema.test_table', 'val', 'SELECT')",
+		},
+		{
+			objectType: "foreign_data_wrapper",
+			attrs:      `objects = ["test_fdw"]`,
+			privilege:  "USAGE",
+			id:         fmt.Sprintf("public.%s.foreign_data_wrapper.test_fdw", dbName),
+			check:      "SELECT has_foreign_data_wrapper_privilege('public', 'test_fdw', 'USAGE')",
+		},
+		{
+			objectType: "foreign_server",
+			attrs:      `objects = ["test_srv"]`,
+			privilege:  "USAGE",
+			id:         fmt.Sprintf("public.%s.foreign_server.test_srv", dbName),
+			check:      "SELECT has_server_privilege('public', 'test_srv', 'USAGE')",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.objectType, func(t *testing.T) {
+			tfConfig := fmt.Sprintf(`
+resource "postgresql_grant" "test" {
+	database    = "%s"
+	role        = "public"
+	object_type = "%s"
+	%s
+	privileges  = ["%s"]
+}
+`, dbName, tc.objectType, tc.attrs, tc.privilege)
+
+			checkPublicPrivilege := func(expected bool) resource.TestCheckFunc {
+				return func(*terraform.State) error {
+					db, err := sql.Open("postgres", dsn)
+					if err != nil {
+						return err
+					}
+					defer db.Close()
+
+					var granted bool
+					if err := db.QueryRow(tc.check).Scan(&granted); err != nil {
+						return fmt.Errorf("could not check PUBLIC privileges: %w", err)
+					}
+					if granted != expected {
+						return fmt.Errorf("expected %s on %s to be %t for PUBLIC", tc.privilege, tc.objectType, expected)
+					}
+					return nil
+				}
+			}
+
+			resource.Test(t, resource.TestCase{
+				PreCheck: func() {
+					testAccPreCheck(t)
+					testCheckCompatibleVersion(t, featurePrivileges)
+				},
+				Providers: testAccProviders,
+				// Destroying the resource revokes the privilege from PUBLIC
+				CheckDestroy: checkPublicPrivilege(false),
+				Steps: []resource.TestStep{
+					{
+						Config: tfConfig,
+						Check: resource.ComposeTestCheckFunc(
+							resource.TestCheckResourceAttr("postgresql_grant.test", "id", tc.id),
+							resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.#", "1"),
+							checkPublicPrivilege(true),
+						),
+					},
+				},
+			})
+		})
+	}
+}
+
 func TestAccPostgresqlGrantEmptyPrivileges(t *testing.T) {
 	skipIfNotAcc(t)
 
@@ -813,7 +1856,7 @@ func TestAccPostgresqlGrantEmptyPrivileges(t *testing.T) {
 				Config: tfConfig,
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr(
-						"postgresql_grant.test", "id", fmt.Sprintf("%s_%s_test_schema_table", roleName, dbName),
+						"postgresql_grant.test", "id", fmt.Sprintf("%s.%s.table.test_schema", roleName, dbName),
 					),
 					resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.#", "0"),
 					func(*terraform.State) error {
@@ -873,17 +1916,86 @@ resource postgresql_grant "test" {
 					{
 						Config: tfConfig,
 						Check: resource.ComposeTestCheckFunc(
-							resource.TestCheckResourceAttr("postgresql_grant.test", "id", fmt.Sprintf("%s_postgres_test_schema_function", role)),
+							resource.TestCheckResourceAttr("postgresql_grant.test", "id", fmt.Sprintf("%s.postgres.function.test_schema", role)),
 							resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.#", "1"),
 							resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.0", "EXECUTE"),
 							resource.TestCheckResourceAttr("postgresql_grant.test", "with_grant_option", "false"),
 							testCheckFunctionExecutable(t, "test_role", "test_schema.test"),
 						),
 					},
-				},
-			})
-		})
-	}
+				},
+			})
+		})
+	}
+}
+
+func TestAccPostgresqlGrantFunctionExcludeExtensionObjects(t *testing.T) {
+	skipIfNotAcc(t)
+
+	config := getTestConfig(t)
+	dsn := config.connStr("postgres")
+
+	dbExecute(t, dsn, fmt.Sprintf("CREATE ROLE test_role LOGIN PASSWORD '%s'", testRolePassword))
+	dbExecute(t, dsn, "CREATE SCHEMA test_schema")
+	dbExecute(t, dsn, "GRANT USAGE ON SCHEMA test_schema TO test_role")
+	dbExecute(t, dsn, "CREATE EXTENSION pgcrypto SCHEMA test_schema")
+	dbExecute(t, dsn, `
+CREATE FUNCTION test_schema.test() RETURNS text
+	AS $$ select 'foo'::text $$
+    LANGUAGE SQL;
+`)
+	defer func() {
+		dbExecute(t, dsn, "DROP SCHEMA test_schema CASCADE")
+		dbExecute(t, dsn, "DROP ROLE test_role")
+	}()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource postgresql_grant "test" {
+  database                  = "postgres"
+  role                      = "test_role"
+  schema                    = "test_schema"
+  object_type               = "function"
+  privileges                = ["EXECUTE"]
+  exclude_extension_objects = true
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant.test", "exclude_extension_objects", "true"),
+					resource.TestCheckResourceAttr("postgresql_grant.test", "objects.#", "0"),
+					testCheckFunctionExecutable(t, "test_role", "test_schema.test"),
+					func(*terraform.State) error {
+						db, err := sql.Open("postgres", dsn)
+						if err != nil {
+							return err
+						}
+						defer db.Close()
+
+						var count int
+						if err := db.QueryRow(`
+SELECT count(*)
+FROM pg_proc p, aclexplode(p.proacl) acl
+WHERE p.pronamespace = 'test_schema'::regnamespace AND acl.grantee = 'test_role'::regrole
+  AND EXISTS (SELECT 1 FROM pg_depend WHERE classid = 'pg_proc'::regclass AND objid = p.oid AND deptype = 'e')
+`).Scan(&count); err != nil {
+							return err
+						}
+						if count > 0 {
+							return fmt.Errorf("%d functions of pgcrypto were granted to test_role", count)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
 }
 
 func TestAccPostgresqlGrantFunctionWithArgs(t *testing.T) {
@@ -935,7 +2047,7 @@ resource postgresql_grant "test" {
 					{
 						Config: tfConfig,
 						Check: resource.ComposeTestCheckFunc(
-							resource.TestCheckResourceAttr("postgresql_grant.test", "id", fmt.Sprintf("%s_postgres_test_schema_function_test(text, char)", role)),
+							resource.TestCheckResourceAttr("postgresql_grant.test", "id", fmt.Sprintf("%s.postgres.function.test_schema.test(text, char)", role)),
 							resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.#", "1"),
 							resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.0", "EXECUTE"),
 							resource.TestCheckResourceAttr("postgresql_grant.test", "with_grant_option", "false"),
@@ -997,7 +2109,7 @@ resource postgresql_grant "test" {
 					{
 						Config: tfConfig,
 						Check: resource.ComposeTestCheckFunc(
-							resource.TestCheckResourceAttr("postgresql_grant.test", "id", fmt.Sprintf("%s_postgres_test_schema_procedure", role)),
+							resource.TestCheckResourceAttr("postgresql_grant.test", "id", fmt.Sprintf("%s.postgres.procedure.test_schema", role)),
 							resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.#", "1"),
 							resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.0", "EXECUTE"),
 							resource.TestCheckResourceAttr("postgresql_grant.test", "with_grant_option", "false"),
@@ -1010,6 +2122,307 @@ resource postgresql_grant "test" {
 	}
 }
 
+func TestAccPostgresqlGrantVerify(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	testTables := []string{"test_schema.test_table", "test_schema.test_table2"}
+	createTestTables(t, dbSuffix, testTables, "")
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "postgresql_grant" "test" {
+  database    = "%s"
+  role        = "%s"
+  schema      = "test_schema"
+  object_type = "table"
+  privileges  = ["ALL"]
+  verify      = true
+}
+`, dbName, roleName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant.test", "verified", "true"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+resource "postgresql_grant" "test" {
+  database    = "%s"
+  role        = "%s"
+  schema      = "test_schema"
+  object_type = "table"
+  privileges  = ["SELECT"]
+}
+`, dbName, roleName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant.test", "verified", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlGrantType(t *testing.T) {
+	skipIfNotAcc(t)
+
+	config := getTestConfig(t)
+	dsn := config.connStr("postgres")
+
+	dbExecute(t, dsn, fmt.Sprintf("CREATE ROLE test_role LOGIN PASSWORD '%s'", testRolePassword))
+	dbExecute(t, dsn, "CREATE SCHEMA test_schema")
+	dbExecute(t, dsn, "GRANT USAGE ON SCHEMA test_schema TO test_role")
+	dbExecute(t, dsn, "CREATE TYPE test_schema.test_enum AS ENUM ('a', 'b')")
+	dbExecute(t, dsn, "CREATE TYPE test_schema.test_composite AS (a int, b text)")
+	// The row type of this table and the implicit array types should not be managed.
+	dbExecute(t, dsn, "CREATE TABLE test_schema.test_table (id int)")
+	dbExecute(t, dsn, "REVOKE ALL ON TYPE test_schema.test_enum, test_schema.test_composite FROM PUBLIC")
+	defer func() {
+		dbExecute(t, dsn, "DROP SCHEMA test_schema CASCADE")
+		dbExecute(t, dsn, "DROP ROLE test_role")
+	}()
+
+	testCheckTypePrivileges := func(typeName string, expected bool) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			db := connectAsTestRole(t, "test_role", "postgres")
+			defer db.Close()
+
+			var hasUsage bool
+			if err := db.QueryRow(
+				"SELECT has_type_privilege($1, 'USAGE')", typeName,
+			).Scan(&hasUsage); err != nil {
+				return fmt.Errorf("could not check privileges on type %s: %w", typeName, err)
+			}
+			if hasUsage != expected {
+				return fmt.Errorf("expected USAGE on type %s to be %t", typeName, expected)
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource postgresql_grant "test" {
+  database    = "postgres"
+  role        = "test_role"
+  schema      = "test_schema"
+  object_type = "type"
+  objects     = ["test_enum"]
+  privileges  = ["USAGE"]
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant.test", "id", "test_role.postgres.type.test_schema.test_enum"),
+					resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.#", "1"),
+					testCheckTypePrivileges("test_schema.test_enum", true),
+					testCheckTypePrivileges("test_schema.test_composite", false),
+				),
+			},
+			{
+				Config: `
+resource postgresql_grant "test" {
+  database    = "postgres"
+  role        = "test_role"
+  schema      = "test_schema"
+  object_type = "type"
+  privileges  = ["USAGE"]
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant.test", "objects.#", "0"),
+					testCheckTypePrivileges("test_schema.test_enum", true),
+					testCheckTypePrivileges("test_schema.test_composite", true),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlGrantDomain(t *testing.T) {
+	skipIfNotAcc(t)
+
+	config := getTestConfig(t)
+	dsn := config.connStr("postgres")
+
+	dbExecute(t, dsn, fmt.Sprintf("CREATE ROLE test_role LOGIN PASSWORD '%s'", testRolePassword))
+	dbExecute(t, dsn, "CREATE SCHEMA test_schema")
+	dbExecute(t, dsn, "GRANT USAGE ON SCHEMA test_schema TO test_role")
+	dbExecute(t, dsn, "CREATE DOMAIN test_schema.test_domain AS int CHECK (VALUE > 0)")
+	// Only the domains should be managed.
+	dbExecute(t, dsn, "CREATE TYPE test_schema.test_enum AS ENUM ('a', 'b')")
+	dbExecute(t, dsn, "REVOKE ALL ON TYPE test_schema.test_domain, test_schema.test_enum FROM PUBLIC")
+	defer func() {
+		dbExecute(t, dsn, "DROP SCHEMA test_schema CASCADE")
+		dbExecute(t, dsn, "DROP ROLE test_role")
+	}()
+
+	testCheckTypePrivileges := func(typeName string, expected bool) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			db := connectAsTestRole(t, "test_role", "postgres")
+			defer db.Close()
+
+			var hasUsage bool
+			if err := db.QueryRow(
+				"SELECT has_type_privilege($1, 'USAGE')", typeName,
+			).Scan(&hasUsage); err != nil {
+				return fmt.Errorf("could not check privileges on type %s: %w", typeName, err)
+			}
+			if hasUsage != expected {
+				return fmt.Errorf("expected USAGE on type %s to be %t", typeName, expected)
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource postgresql_grant "test" {
+  database    = "postgres"
+  role        = "test_role"
+  schema      = "test_schema"
+  object_type = "domain"
+  privileges  = ["USAGE"]
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant.test", "id", "test_role.postgres.domain.test_schema"),
+					resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.#", "1"),
+					testCheckTypePrivileges("test_schema.test_domain", true),
+					testCheckTypePrivileges("test_schema.test_enum", false),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlGrantLargeObject(t *testing.T) {
+	skipIfNotAcc(t)
+
+	config := getTestConfig(t)
+	dsn := config.connStr("postgres")
+
+	dbExecute(t, dsn, fmt.Sprintf("CREATE ROLE test_role LOGIN PASSWORD '%s'", testRolePassword))
+	dbExecute(t, dsn, "SELECT lo_create(424242)")
+	defer func() {
+		dbExecute(t, dsn, "SELECT lo_unlink(424242)")
+		dbExecute(t, dsn, "DROP ROLE test_role")
+	}()
+
+	testCheckLargeObjectPrivileges := func(expected bool) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			db := connectAsTestRole(t, "test_role", "postgres")
+			defer db.Close()
+
+			_, err := db.Exec("SELECT lo_get(424242)")
+			if expected && err != nil {
+				return fmt.Errorf("expected SELECT on large object 424242: %w", err)
+			}
+			if !expected && err == nil {
+				return fmt.Errorf("did not expect SELECT on large object 424242")
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource postgresql_grant "test" {
+  database    = "postgres"
+  role        = "test_role"
+  object_type = "large_object"
+  objects     = ["424242"]
+  privileges  = ["SELECT", "UPDATE"]
+  verify      = true
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant.test", "id", "test_role.postgres.large_object.424242"),
+					resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.#", "2"),
+					resource.TestCheckResourceAttr("postgresql_grant.test", "verified", "true"),
+					testCheckLargeObjectPrivileges(true),
+				),
+			},
+			{
+				Config: `
+resource postgresql_grant "test" {
+  database    = "postgres"
+  role        = "test_role"
+  object_type = "large_object"
+  objects     = ["424242"]
+  privileges  = []
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.#", "0"),
+					testCheckLargeObjectPrivileges(false),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCheckLargeObjectCompatPrivileges(t *testing.T) {
+	skipIfNotAcc(t)
+
+	config := getTestConfig(t)
+	db, err := config.NewClient("postgres").Connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txn, err := startTransaction(db.client, "postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer deferredRollback(txn)
+
+	if err := checkLargeObjectCompatPrivileges(db, txn, "postgres"); err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, db.warnings)
+
+	if _, err := txn.Exec("SET LOCAL lo_compat_privileges = on"); err != nil {
+		t.Skipf("could not set lo_compat_privileges (superuser required): %v", err)
+	}
+	if err := checkLargeObjectCompatPrivileges(db, txn, "postgres"); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{
+		`lo_compat_privileges is on in database "postgres": the privileges of the large objects are not checked, ` +
+			"the grants have no effect until it is turned off",
+	}, db.warnings)
+}
 func TestAccPostgresqlGrantRoutine(t *testing.T) {
 	skipIfNotAcc(t)
 	testCheckCompatibleVersion(t, featureRoutine)
@@ -1065,7 +2478,7 @@ resource postgresql_grant "test" {
 					{
 						Config: tfConfigRoutine,
 						Check: resource.ComposeTestCheckFunc(
-							resource.TestCheckResourceAttr("postgresql_grant.test", "id", fmt.Sprintf("%s_postgres_test_schema_routine", role)),
+							resource.TestCheckResourceAttr("postgresql_grant.test", "id", fmt.Sprintf("%s.postgres.routine.test_schema", role)),
 							resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.#", "1"),
 							resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.0", "EXECUTE"),
 							resource.TestCheckResourceAttr("postgresql_grant.test", "with_grant_option", "false"),
@@ -1113,7 +2526,7 @@ resource "postgresql_grant" "test" {
 			{
 				Config: fmt.Sprintf(config, `["CONNECT"]`),
 				Check: resource.ComposeTestCheckFunc(
-					resource.TestCheckResourceAttr("postgresql_grant.test", "id", "test_grant_role_test_grant_db_database"),
+					resource.TestCheckResourceAttr("postgresql_grant.test", "id", "test_grant_role.test_grant_db.database"),
 					resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.#", "1"),
 					resource.TestCheckResourceAttr("postgresql_grant.test", "with_grant_option", "false"),
 					testCheckDatabasesPrivileges(t, false),
@@ -1127,6 +2540,16 @@ resource "postgresql_grant" "test" {
 					testCheckDatabasesPrivileges(t, true),
 				),
 			},
+			// Other spellings are normalized without causing a diff
+			{
+				Config: fmt.Sprintf(config, `["connect", "temp"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.#", "2"),
+					resource.TestCheckTypeSetElemAttr("postgresql_grant.test", "privileges.*", "CONNECT"),
+					resource.TestCheckTypeSetElemAttr("postgresql_grant.test", "privileges.*", "TEMPORARY"),
+					testCheckDatabasesPrivileges(t, false),
+				),
+			},
 			// Revoke
 			{
 				Config: fmt.Sprintf(config, "[]"),
@@ -1139,6 +2562,56 @@ resource "postgresql_grant" "test" {
 	})
 }
 
+func TestAccPostgresqlGrantDatabaseRoleRename(t *testing.T) {
+	// create a TF config with placeholder for the role name
+	// it will be filled in each step.
+	config := fmt.Sprintf(`
+resource "postgresql_role" "test" {
+	name     = "%%s"
+	password = "%s"
+	login    = true
+}
+
+resource "postgresql_database" "test_db" {
+	name = "test_grant_db"
+}
+
+resource "postgresql_grant" "test" {
+	database    = postgresql_database.test_db.name
+	role        = postgresql_role.test.name
+	object_type = "database"
+	privileges  = ["CONNECT", "CREATE"]
+}
+`, testRolePassword)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, "test_grant_role_before"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant.test", "id", "test_grant_role_before.test_grant_db.database"),
+					resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.#", "2"),
+				),
+			},
+			// Renaming the role should update the grant in place
+			{
+				Config: fmt.Sprintf(config, "test_grant_role"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant.test", "id", "test_grant_role.test_grant_db.database"),
+					resource.TestCheckResourceAttr("postgresql_grant.test", "role", "test_grant_role"),
+					resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.#", "2"),
+					testCheckDatabasesPrivileges(t, true),
+				),
+			},
+		},
+	})
+}
+
 func TestAccPostgresqlImplicitGrants(t *testing.T) {
 	skipIfNotAcc(t)
 
@@ -1179,7 +2652,7 @@ func TestAccPostgresqlImplicitGrants(t *testing.T) {
 				Config: fmt.Sprintf(testGrant, `["ALL"]`),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr(
-						"postgresql_grant.test", "id", fmt.Sprintf("%s_%s_test_schema_table_test_table", roleName, dbName),
+						"postgresql_grant.test", "id", fmt.Sprintf("%s.%s.table.test_schema.test_table", roleName, dbName),
 					),
 					resource.TestCheckResourceAttr("postgresql_grant.test", "objects.#", "1"),
 					resource.TestCheckResourceAttr("postgresql_grant.test", "objects.0", "test_table"),
@@ -1251,7 +2724,7 @@ resource "postgresql_grant" "test" {
 			{
 				Config: fmt.Sprintf(config, `["USAGE"]`),
 				Check: resource.ComposeTestCheckFunc(
-					resource.TestCheckResourceAttr("postgresql_grant.test", "id", "test_grant_role_postgres_test_schema_schema"),
+					resource.TestCheckResourceAttr("postgresql_grant.test", "id", "test_grant_role.postgres.schema.test_schema"),
 					resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.#", "1"),
 					resource.TestCheckResourceAttr("postgresql_grant.test", "with_grant_option", "false"),
 					testCheckSchemaPrivileges(t, true, false),
@@ -1322,12 +2795,21 @@ resource "postgresql_grant" "test" {
 			{
 				Config: fmt.Sprintf(tfConfig, `["USAGE"]`, `true`),
 				Check: resource.ComposeTestCheckFunc(
-					resource.TestCheckResourceAttr("postgresql_grant.test", "id", "test_role_postgres_foreign_data_wrapper_test_fdw"),
+					resource.TestCheckResourceAttr("postgresql_grant.test", "id", "test_role.postgres.foreign_data_wrapper.test_fdw"),
 					resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.#", "1"),
 					resource.TestCheckResourceAttr("postgresql_grant.test", "with_grant_option", "true"),
 					testCheckForeignDataWrapperPrivileges(t, true),
 				),
 			},
+			// Only revoke the grant option, the privileges are kept
+			{
+				Config: fmt.Sprintf(tfConfig, `["USAGE"]`, `false`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.#", "1"),
+					resource.TestCheckResourceAttr("postgresql_grant.test", "with_grant_option", "false"),
+					testCheckForeignDataWrapperPrivileges(t, true),
+				),
+			},
 			// Revoke all privileges
 			{
 				Config: fmt.Sprintf(tfConfig, `[]`, `false`),
@@ -1396,7 +2878,7 @@ resource "postgresql_grant" "test" {
 			{
 				Config: fmt.Sprintf(tfConfig, `["USAGE"]`, `false`),
 				Check: resource.ComposeTestCheckFunc(
-					resource.TestCheckResourceAttr("postgresql_grant.test", "id", "test_role_postgres_foreign_server_test_srv"),
+					resource.TestCheckResourceAttr("postgresql_grant.test", "id", "test_role.postgres.foreign_server.test_srv"),
 					resource.TestCheckResourceAttr("postgresql_grant.test", "privileges.#", "1"),
 					resource.TestCheckResourceAttr("postgresql_grant.test", "with_grant_option", "false"),
 					testCheckForeignServerPrivileges(t, true),
@@ -1593,3 +3075,80 @@ func testCheckForeignServerPrivileges(t *testing.T, usage bool) func(*terraform.
 		return nil
 	}
 }
+
+func TestAccWarnGrantOnOwnedObjects(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+	createTestTables(t, dbSuffix, []string{"test_schema.test_table"}, roleName)
+
+	config := getTestConfig(t)
+	db, err := config.NewClient(dbName).Connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txn, err := startTransaction(db.client, dbName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer deferredRollback(txn)
+
+	// The schema is owned by the provider user.
+	d := schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+		"database":    dbName,
+		"role":        roleName,
+		"schema":      "test_schema",
+		"object_type": "schema",
+		"privileges":  []interface{}{"USAGE"},
+	})
+	if err := warnGrantOnOwnedObjects(db, txn, d); err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, db.warnings)
+
+	d = schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+		"database":    dbName,
+		"role":        roleName,
+		"schema":      "test_schema",
+		"object_type": "table",
+		"objects":     []interface{}{"test_table"},
+		"privileges":  []interface{}{"SELECT"},
+	})
+	if err := warnGrantOnOwnedObjects(db, txn, d); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{fmt.Sprintf(
+		"role %s owns the table test_table of the grant in database %q: the owner already has all the privileges, "+
+			"granting them has no effect",
+		roleName, dbName,
+	)}, db.warnings)
+
+	// The functions are matched with their arguments.
+	if _, err := txn.Exec(fmt.Sprintf(`
+CREATE FUNCTION test_schema.test_func(a integer) RETURNS integer LANGUAGE sql AS 'SELECT a';
+ALTER FUNCTION test_schema.test_func(integer) OWNER TO %s;
+`, pq.QuoteIdentifier(roleName))); err != nil {
+		t.Fatal(err)
+	}
+	db.warnings = nil
+	d = schema.TestResourceDataRaw(t, resourcePostgreSQLGrant().Schema, map[string]interface{}{
+		"database":    dbName,
+		"role":        roleName,
+		"schema":      "test_schema",
+		"object_type": "function",
+		"objects":     []interface{}{"test_func(int4)"},
+		"privileges":  []interface{}{"EXECUTE"},
+	})
+	if err := warnGrantOnOwnedObjects(db, txn, d); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{fmt.Sprintf(
+		"role %s owns the function test_func(a integer) of the grant in database %q: the owner already has all the privileges, "+
+			"granting them has no effect",
+		roleName, dbName,
+	)}, db.warnings)
+}
@@ -0,0 +1,304 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
+)
+
+const (
+	pgauditRoleDatabaseAttr   = "database"
+	pgauditRoleRoleAttr       = "role"
+	pgauditRoleTablesAttr     = "tables"
+	pgauditRolePrivilegesAttr = "privileges"
+
+	pgauditRoleSetting = "pgaudit.role"
+)
+
+// pgauditAllowedPrivileges are the privileges which trigger object audit logging.
+var pgauditAllowedPrivileges = []string{"SELECT", "INSERT", "UPDATE", "DELETE"}
+
+func resourcePostgreSQLPgauditRole() *schema.Resource {
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLPgauditRoleCreate),
+		Read:   PGReadResourceFunc(resourcePostgreSQLPgauditRoleRead),
+		Update: PGResourceFunc(resourcePostgreSQLPgauditRoleUpdate),
+		Delete: PGResourceFunc(resourcePostgreSQLPgauditRoleDelete),
+
+		Schema: map[string]*schema.Schema{
+			pgauditRoleDatabaseAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The database in which pgaudit.role is set and the audited tables are",
+			},
+			pgauditRoleRoleAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The master role used by pgAudit for object audit logging",
+			},
+			pgauditRoleTablesAttr: {
+				Type:        schema.TypeSet,
+				Required:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "The tables to audit, with their schema (e.g.: public.accounts)",
+			},
+			pgauditRolePrivilegesAttr: {
+				Type:        schema.TypeSet,
+				Required:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "The statements to audit on the tables (any of: " + strings.Join(pgauditAllowedPrivileges, ", ") + ")",
+			},
+		},
+	}
+}
+
+func resourcePostgreSQLPgauditRoleCreate(db *DBConnection, d *schema.ResourceData) error {
+	if err := validatePgauditPrivileges(d); err != nil {
+		return err
+	}
+
+	database := d.Get(pgauditRoleDatabaseAttr).(string)
+	role := d.Get(pgauditRoleRoleAttr).(string)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if _, err := txn.Exec(fmt.Sprintf(
+		"ALTER DATABASE %s SET %s TO %s", pq.QuoteIdentifier(database), pgauditRoleSetting, pq.QuoteLiteral(role),
+	)); err != nil {
+		return fmt.Errorf("could not set %s for database %s: %w", pgauditRoleSetting, database, err)
+	}
+
+	if err := grantPgauditPrivileges(txn, d); err != nil {
+		return err
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId(generatePgauditRoleID(d))
+
+	return resourcePostgreSQLPgauditRoleReadImpl(db, d)
+}
+
+func resourcePostgreSQLPgauditRoleRead(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLPgauditRoleReadImpl(db, d)
+}
+
+func resourcePostgreSQLPgauditRoleReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	database := d.Get(pgauditRoleDatabaseAttr).(string)
+	role := d.Get(pgauditRoleRoleAttr).(string)
+
+	exists, err := dbExists(db, database)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		log.Printf("[WARN] PostgreSQL database (%s) not found, removing pgAudit role from state", database)
+		d.SetId("")
+		return nil
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	exists, err = roleExists(txn, role)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		log.Printf("[WARN] PostgreSQL role (%s) not found, removing pgAudit role from state", role)
+		d.SetId("")
+		return nil
+	}
+
+	roleOID, err := getRoleOID(txn, role)
+	if err != nil {
+		return err
+	}
+
+	settings, err := readDBRoleSettings(txn, "", database)
+	if err != nil {
+		return err
+	}
+
+	if settings[pgauditRoleSetting] != role {
+		log.Printf(
+			"[WARN] %s is %q instead of %q in database %s, removing pgAudit role from state",
+			pgauditRoleSetting, settings[pgauditRoleSetting], role, database,
+		)
+		d.SetId("")
+		return nil
+	}
+
+	// Remove from the state the tables which don't have the expected privileges (or don't exist anymore)
+	// so they are granted again on the next apply.
+	wanted := d.Get(pgauditRolePrivilegesAttr).(*schema.Set)
+	tables := d.Get(pgauditRoleTablesAttr).(*schema.Set)
+	granted := schema.NewSet(schema.HashString, nil)
+	for _, table := range tables.List() {
+		var privileges pq.ByteaArray
+		err := txn.QueryRow(`
+SELECT array_agg(privilege_type)
+FROM (
+	SELECT (aclexplode(relacl)).* FROM pg_class WHERE oid = to_regclass($1)
+) AS privileges
+WHERE grantee = $2
+`, quoteTableName(table.(string)), roleOID).Scan(&privileges)
+		if err != nil {
+			return fmt.Errorf("could not read privileges of %s on table %s: %w", role, table, err)
+		}
+
+		if pgArrayToSet(privileges).Equal(wanted) {
+			granted.Add(table)
+		} else {
+			log.Printf("[DEBUG] role %s has not the expected privileges on table %s", role, table)
+		}
+	}
+
+	d.Set(pgauditRoleTablesAttr, granted)
+	d.SetId(generatePgauditRoleID(d))
+
+	return nil
+}
+
+func resourcePostgreSQLPgauditRoleUpdate(db *DBConnection, d *schema.ResourceData) error {
+	if err := validatePgauditPrivileges(d); err != nil {
+		return err
+	}
+
+	txn, err := startTransaction(db.client, d.Get(pgauditRoleDatabaseAttr).(string))
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	// Revoke the previous privileges then grant the new ones in the same transaction
+	// so the audit is not interrupted for the tables which stay audited.
+	oldTables, _ := d.GetChange(pgauditRoleTablesAttr)
+	if err := revokePgauditPrivileges(txn, d.Get(pgauditRoleRoleAttr).(string), oldTables.(*schema.Set)); err != nil {
+		return err
+	}
+	if err := grantPgauditPrivileges(txn, d); err != nil {
+		return err
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return resourcePostgreSQLPgauditRoleReadImpl(db, d)
+}
+
+func resourcePostgreSQLPgauditRoleDelete(db *DBConnection, d *schema.ResourceData) error {
+	database := d.Get(pgauditRoleDatabaseAttr).(string)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if err := revokePgauditPrivileges(txn, d.Get(pgauditRoleRoleAttr).(string), d.Get(pgauditRoleTablesAttr).(*schema.Set)); err != nil {
+		return err
+	}
+
+	if _, err := txn.Exec(fmt.Sprintf(
+		"ALTER DATABASE %s RESET %s", pq.QuoteIdentifier(database), pgauditRoleSetting,
+	)); err != nil {
+		return fmt.Errorf("could not reset %s for database %s: %w", pgauditRoleSetting, database, err)
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+func validatePgauditPrivileges(d *schema.ResourceData) error {
+	for _, priv := range d.Get(pgauditRolePrivilegesAttr).(*schema.Set).List() {
+		if !sliceContainsStr(pgauditAllowedPrivileges, priv.(string)) {
+			return fmt.Errorf("%s is not an allowed privilege for pgAudit object audit logging", priv)
+		}
+	}
+	return nil
+}
+
+func grantPgauditPrivileges(txn *sql.Tx, d *schema.ResourceData) error {
+	privileges := d.Get(pgauditRolePrivilegesAttr).(*schema.Set)
+	tables := d.Get(pgauditRoleTablesAttr).(*schema.Set)
+	if privileges.Len() == 0 || tables.Len() == 0 {
+		return nil
+	}
+
+	query := fmt.Sprintf(
+		"GRANT %s ON TABLE %s TO %s",
+		strings.Join(setToSortedSlice(privileges), ","),
+		quoteTableNames(tables),
+		pq.QuoteIdentifier(d.Get(pgauditRoleRoleAttr).(string)),
+	)
+	if _, err := txn.Exec(query); err != nil {
+		return fmt.Errorf("could not grant audit privileges: %w", err)
+	}
+	return nil
+}
+
+func revokePgauditPrivileges(txn *sql.Tx, role string, tables *schema.Set) error {
+	var existing []string
+	for _, table := range tables.List() {
+		// Tables which have been dropped in the meantime can't be revoked.
+		var exists bool
+		if err := txn.QueryRow("SELECT to_regclass($1) IS NOT NULL", quoteTableName(table.(string))).Scan(&exists); err != nil {
+			return fmt.Errorf("could not check if table %s exists: %w", table, err)
+		}
+		if exists {
+			existing = append(existing, table.(string))
+		}
+	}
+	if len(existing) == 0 {
+		return nil
+	}
+
+	query := fmt.Sprintf(
+		"REVOKE ALL PRIVILEGES ON TABLE %s FROM %s",
+		quoteTableNames(stringSliceToSet(existing)),
+		pq.QuoteIdentifier(role),
+	)
+	if _, err := txn.Exec(query); err != nil {
+		return fmt.Errorf("could not revoke audit privileges: %w", err)
+	}
+	return nil
+}
+
+func quoteTableNames(tables *schema.Set) string {
+	quoted := []string{}
+	for _, table := range setToSortedSlice(tables) {
+		quoted = append(quoted, quoteTableName(table))
+	}
+	return strings.Join(quoted, ",")
+}
+
+func generatePgauditRoleID(d *schema.ResourceData) string {
+	return strings.Join([]string{
+		d.Get(pgauditRoleDatabaseAttr).(string),
+		d.Get(pgauditRoleRoleAttr).(string),
+	}, "_")
+}
@@ -0,0 +1,85 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccPostgresqlPgauditRole_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	testTables := []string{"test_schema.test_table", "test_schema.test_table2"}
+	createTestTables(t, dbSuffix, testTables, "")
+
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	testConfig := `
+resource "postgresql_role" "auditor" {
+	name = "tf_tests_auditor"
+}
+
+resource "postgresql_pgaudit_role" "test" {
+	database   = "%s"
+	role       = postgresql_role.auditor.name
+	tables     = %s
+	privileges = %s
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testSuperuserPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testConfig, dbName, `["test_schema.test_table"]`, `["SELECT"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_pgaudit_role.test", "tables.#", "1"),
+					testCheckPgauditPrivileges(t, dbName, "test_schema.test_table", "SELECT", true),
+					testCheckPgauditPrivileges(t, dbName, "test_schema.test_table", "UPDATE", false),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testConfig, dbName, `["test_schema.test_table2"]`, `["SELECT", "UPDATE"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_pgaudit_role.test", "tables.#", "1"),
+					resource.TestCheckResourceAttr("postgresql_pgaudit_role.test", "privileges.#", "2"),
+					testCheckPgauditPrivileges(t, dbName, "test_schema.test_table", "SELECT", false),
+					testCheckPgauditPrivileges(t, dbName, "test_schema.test_table2", "SELECT", true),
+					testCheckPgauditPrivileges(t, dbName, "test_schema.test_table2", "UPDATE", true),
+				),
+			},
+		},
+	})
+}
+
+func testCheckPgauditPrivileges(t *testing.T, dbName, table, privilege string, expected bool) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		config := getTestConfig(t)
+		db, err := sql.Open("postgres", config.connStr(dbName))
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		var granted bool
+		if err := db.QueryRow(
+			"SELECT has_table_privilege('tf_tests_auditor', $1, $2)", table, privilege,
+		).Scan(&granted); err != nil {
+			return fmt.Errorf("could not check privileges on %s: %w", table, err)
+		}
+		if granted != expected {
+			return fmt.Errorf("expected %s on %s to be %t for tf_tests_auditor", privilege, table, expected)
+		}
+		return nil
+	}
+}
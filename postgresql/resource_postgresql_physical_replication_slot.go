@@ -9,7 +9,7 @@ import (
 func resourcePostgreSQLPhysicalReplicationSlot() *schema.Resource {
 	return &schema.Resource{
 		Create: PGResourceFunc(resourcePostgreSQLPhysicalReplicationSlotCreate),
-		Read:   PGResourceFunc(resourcePostgreSQLPhysicalReplicationSlotRead),
+		Read:   PGReadResourceFunc(resourcePostgreSQLPhysicalReplicationSlotRead),
 		Delete: PGResourceFunc(resourcePostgreSQLPhysicalReplicationSlotDelete),
 		Exists: PGResourceExistsFunc(resourcePostgreSQLPhysicalReplicationSlotExists),
 		Importer: &schema.ResourceImporter{
@@ -1,6 +1,7 @@
 package postgresql
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
@@ -25,73 +26,94 @@ const (
 func resourcePostgreSQLPublication() *schema.Resource {
 	return &schema.Resource{
 		Create: PGResourceFunc(resourcePostgreSQLPublicationCreate),
-		Read:   PGResourceFunc(resourcePostgreSQLPublicationRead),
+		Read:   PGReadResourceFunc(resourcePostgreSQLPublicationRead),
 		Delete: PGResourceFunc(resourcePostgreSQLPublicationDelete),
 		Update: PGResourceFunc(resourcePostgreSQLPublicationUpdate),
 		Exists: PGResourceExistsFunc(resourcePostgreSQLPublicationExists),
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: PGImportResourceFunc(resourcePostgreSQLPublicationImport),
 		},
 
-		Schema: map[string]*schema.Schema{
-			pubNameAttr: {
-				Type:         schema.TypeString,
-				Required:     true,
-				ForceNew:     false,
-				ValidateFunc: validation.StringIsNotEmpty,
-			},
-			pubDatabaseAttr: {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Computed:    true,
-				ForceNew:    true,
-				Description: "Sets the database to add the publication for",
-			},
-			pubOwnerAttr: {
-				Type:         schema.TypeString,
-				Optional:     true,
-				Computed:     true,
-				ForceNew:     false,
-				Description:  "Sets the owner of the publication",
-				ValidateFunc: validation.StringIsNotEmpty,
-			},
-			pubTablesAttr: {
-				Type:          schema.TypeSet,
-				Optional:      true,
-				Computed:      true,
-				ForceNew:      false,
-				Elem:          &schema.Schema{Type: schema.TypeString},
-				Description:   "Sets the tables list to publish",
-				ConflictsWith: []string{pubAllTablesAttr},
-			},
-			pubAllTablesAttr: {
-				Type:        schema.TypeBool,
-				Optional:    true,
-				Computed:    true,
-				ForceNew:    true,
-				Description: "Sets the tables list to publish to ALL tables",
-			},
-			pubPublishAttr: {
-				Type:        schema.TypeList,
-				Optional:    true,
-				Computed:    true,
-				MinItems:    1,
-				Elem:        &schema.Schema{Type: schema.TypeString},
-				Description: "Sets which DML operations will be published",
-			},
-			pubPublishViaPartitionRootAttr: {
-				Type:        schema.TypeBool,
-				Optional:    true,
-				ForceNew:    false,
-				Description: "Sets whether changes in a partitioned table using the identity and schema of the partitioned table",
-			},
-			pubDropCascadeAttr: {
-				Type:        schema.TypeBool,
-				Optional:    true,
-				Default:     false,
-				Description: "When true, will also drop all the objects that depend on the publication, and in turn all objects that depend on those objects",
+		// The version 1 quotes the dotted names in the ID (see generateObjectID).
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourcePostgreSQLPublicationV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourcePostgreSQLPublicationStateUpgradeV0,
 			},
 		},
+
+		Schema: resourcePostgreSQLPublicationSchema(),
+	}
+}
+
+// resourcePostgreSQLPublicationV0 is the version 0 of the resource, its attributes are the same as the current version.
+func resourcePostgreSQLPublicationV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: resourcePostgreSQLPublicationSchema(),
+	}
+}
+
+func resourcePostgreSQLPublicationSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		pubNameAttr: {
+			Type:         schema.TypeString,
+			Required:     true,
+			ForceNew:     false,
+			ValidateFunc: validation.StringIsNotEmpty,
+		},
+		pubDatabaseAttr: {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    true,
+			ForceNew:    true,
+			Description: "Sets the database to add the publication for",
+		},
+		pubOwnerAttr: {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Computed:     true,
+			ForceNew:     false,
+			Description:  "Sets the owner of the publication",
+			ValidateFunc: validation.StringIsNotEmpty,
+		},
+		pubTablesAttr: {
+			Type:          schema.TypeSet,
+			Optional:      true,
+			Computed:      true,
+			ForceNew:      false,
+			Elem:          &schema.Schema{Type: schema.TypeString},
+			Description:   "Sets the tables list to publish",
+			ConflictsWith: []string{pubAllTablesAttr},
+		},
+		pubAllTablesAttr: {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Computed:    true,
+			ForceNew:    true,
+			Description: "Sets the tables list to publish to ALL tables",
+		},
+		pubPublishAttr: {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Computed:    true,
+			MinItems:    1,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Description: "Sets which DML operations will be published",
+		},
+		pubPublishViaPartitionRootAttr: {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			ForceNew:    false,
+			Description: "Sets whether changes in a partitioned table using the identity and schema of the partitioned table",
+		},
+		pubDropCascadeAttr: {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "When true, will also drop all the objects that depend on the publication, and in turn all objects that depend on those objects",
+		},
 	}
 }
 
@@ -111,20 +133,34 @@ func resourcePostgreSQLPublicationUpdate(db *DBConnection, d *schema.ResourceDat
 
 	defer deferredRollback(txn)
 
-	if err := setPubOwner(txn, d); err != nil {
-		return fmt.Errorf("could not update publication owner: %w", err)
+	// The publication is tracked by its OID as its name can change in this update:
+	// it's renamed first and the other statements use the name read back from the OID.
+	oldName, _ := d.GetChange(pubNameAttr)
+	pubOID, err := getPublicationOID(txn, oldName.(string))
+	if err != nil {
+		return err
 	}
 
-	if err := setPubTables(txn, d); err != nil {
-		return fmt.Errorf("could not update publication tables: %w", err)
+	if err := setPubName(txn, d, pubOID); err != nil {
+		return fmt.Errorf("could not update publication name: %w", err)
+	}
+
+	pubName, err := getPublicationNameByOID(txn, pubOID)
+	if err != nil {
+		return err
 	}
+	pubName = pq.QuoteIdentifier(pubName)
 
-	if err := setPubParams(txn, d, db.featureSupported(featurePublishViaRoot)); err != nil {
+	if err := setPubOwner(txn, d, pubName); err != nil {
+		return fmt.Errorf("could not update publication owner: %w", err)
+	}
+
+	if err := setPubTables(txn, d, pubName); err != nil {
 		return fmt.Errorf("could not update publication tables: %w", err)
 	}
 
-	if err := setPubName(txn, d); err != nil {
-		return fmt.Errorf("could not update publication name: %w", err)
+	if err := setPubParams(txn, d, pubName, db.featureSupported(featurePublishViaRoot)); err != nil {
+		return fmt.Errorf("could not update publication tables: %w", err)
 	}
 
 	if err = txn.Commit(); err != nil {
@@ -133,13 +169,37 @@ func resourcePostgreSQLPublicationUpdate(db *DBConnection, d *schema.ResourceDat
 	return resourcePostgreSQLPublicationReadImpl(db, d)
 }
 
-func setPubName(txn *sql.Tx, d *schema.ResourceData) error {
+// getPublicationOID returns the OID of the publication in the database of the transaction.
+func getPublicationOID(txn *sql.Tx, pubName string) (uint32, error) {
+	var oid uint32
+	err := txn.QueryRow("SELECT oid FROM pg_catalog.pg_publication WHERE pubname = $1", pqQuoteLiteral(pubName)).Scan(&oid)
+	switch {
+	case err == sql.ErrNoRows:
+		return 0, fmt.Errorf("publication %s not found", pubName)
+	case err != nil:
+		return 0, fmt.Errorf("could not read OID of publication %s: %w", pubName, err)
+	}
+	return oid, nil
+}
+
+// getPublicationNameByOID returns the current name of the publication.
+func getPublicationNameByOID(txn *sql.Tx, oid uint32) (string, error) {
+	var pubName string
+	if err := txn.QueryRow("SELECT pubname FROM pg_catalog.pg_publication WHERE oid = $1", oid).Scan(&pubName); err != nil {
+		return "", fmt.Errorf("could not read name of publication %d: %w", oid, err)
+	}
+	return pubName, nil
+}
+
+func setPubName(txn *sql.Tx, d *schema.ResourceData, pubOID uint32) error {
 	if !d.HasChange(pubNameAttr) {
 		return nil
 	}
-	oraw, nraw := d.GetChange(pubNameAttr)
-	o := oraw.(string)
-	n := nraw.(string)
+	o, err := getPublicationNameByOID(txn, pubOID)
+	if err != nil {
+		return err
+	}
+	n := d.Get(pubNameAttr).(string)
 	database := d.Get(pubDatabaseAttr).(string)
 	sql := fmt.Sprintf("ALTER PUBLICATION %s RENAME TO %s", pq.QuoteIdentifier(o), pq.QuoteIdentifier(n))
 	if _, err := txn.Exec(sql); err != nil {
@@ -149,29 +209,38 @@ func setPubName(txn *sql.Tx, d *schema.ResourceData) error {
 	return nil
 }
 
-func setPubOwner(txn *sql.Tx, d *schema.ResourceData) error {
+// setPubOwner changes the owner of the publication, pubName is the publication as referenced in the statement.
+func setPubOwner(txn *sql.Tx, d *schema.ResourceData, pubName string) error {
 	if !d.HasChange(pubOwnerAttr) {
 		return nil
 	}
 
-	_, nraw := d.GetChange(pubOwnerAttr)
+	oraw, nraw := d.GetChange(pubOwnerAttr)
+	o := oraw.(string)
 	n := nraw.(string)
-	pubName := d.Get(pubNameAttr).(string)
 
-	sql := fmt.Sprintf("ALTER PUBLICATION %s OWNER TO %s", pubName, n)
-	if _, err := txn.Exec(sql); err != nil {
-		return fmt.Errorf("Error updating publication owner: %w", err)
+	// If the connected user is not a superuser, it needs to be a member of the current owner
+	// (to alter the publication) and of the new owner.
+	rolesToGrant := []string{n}
+	if o != "" && o != n {
+		rolesToGrant = append(rolesToGrant, o)
 	}
-	return nil
+
+	return withRolesGranted(txn, rolesToGrant, func() error {
+		sql := fmt.Sprintf("ALTER PUBLICATION %s OWNER TO %s", pubName, pq.QuoteIdentifier(n))
+		if _, err := txn.Exec(sql); err != nil {
+			return fmt.Errorf("Error updating publication owner: %w", err)
+		}
+		return nil
+	})
 }
 
-func setPubTables(txn *sql.Tx, d *schema.ResourceData) error {
+func setPubTables(txn *sql.Tx, d *schema.ResourceData, pubName string) error {
 	if !d.HasChange(pubTablesAttr) {
 		return nil
 	}
 
 	var queries []string
-	pubName := d.Get(pubNameAttr).(string)
 
 	oraw, nraw := d.GetChange(pubTablesAttr)
 	oldList := oraw.(*schema.Set).List()
@@ -200,8 +269,7 @@ func setPubTables(txn *sql.Tx, d *schema.ResourceData) error {
 	return nil
 }
 
-func setPubParams(txn *sql.Tx, d *schema.ResourceData, pubViaRootEnabled bool) error {
-	pubName := d.Get(pubNameAttr).(string)
+func setPubParams(txn *sql.Tx, d *schema.ResourceData, pubName string, pubViaRootEnabled bool) error {
 	paramAlterTemplate := "ALTER PUBLICATION %s %s"
 	publicationParametersString, err := getPublicationParameters(d, pubViaRootEnabled)
 	if err != nil {
@@ -245,9 +313,18 @@ func resourcePostgreSQLPublicationCreate(db *DBConnection, d *schema.ResourceDat
 	if _, err := txn.Exec(sql); err != nil {
 		return fmt.Errorf("Error creating Publication: %w", err)
 	}
-	if err := setPubOwner(txn, d); err != nil {
+	if err := setPubOwner(txn, d, name); err != nil {
 		return fmt.Errorf("could not set publication owner during creation: %w", err)
 	}
+	if _, ok := d.GetOk(pubOwnerAttr); !ok && db.client.config.ObjectOwnerRole != "" {
+		owner := db.client.config.ObjectOwnerRole
+		if err := withRolesGranted(txn, []string{owner}, func() error {
+			_, err := txn.Exec(fmt.Sprintf("ALTER PUBLICATION %s OWNER TO %s", name, pq.QuoteIdentifier(owner)))
+			return err
+		}); err != nil {
+			return fmt.Errorf("could not set publication owner to %s: %w", owner, err)
+		}
+	}
 
 	if err = txn.Commit(); err != nil {
 		return fmt.Errorf("Error creating Publication: %w", err)
@@ -297,6 +374,33 @@ func resourcePostgreSQLPublicationExists(db *DBConnection, d *schema.ResourceDat
 	return true, nil
 }
 
+// resourcePostgreSQLPublicationImport imports a publication from an ID made of the database and the publication name
+// (see generatePublicationID). It fails if the publication doesn't exist.
+func resourcePostgreSQLPublicationImport(db *DBConnection, d *schema.ResourceData) ([]*schema.ResourceData, error) {
+	database, publicationName, err := getDBPublicationName(d, db.client)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := dbExists(db, database)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("could not import publication %s: database %s does not exist", publicationName, database)
+	}
+
+	if err := resourcePostgreSQLPublicationReadImpl(db, d); err != nil {
+		return nil, err
+	}
+	if d.Id() == "" {
+		return nil, fmt.Errorf("could not import publication: publication %s does not exist in database %s", publicationName, database)
+	}
+	d.Set(pubDropCascadeAttr, false)
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourcePostgreSQLPublicationRead(db *DBConnection, d *schema.ResourceData) error {
 	return resourcePostgreSQLPublicationReadImpl(db, d)
 }
@@ -423,9 +527,19 @@ func resourcePostgreSQLPublicationDelete(db *DBConnection, d *schema.ResourceDat
 		dropMode = "CASCADE"
 	}
 
-	sql := fmt.Sprintf("DROP PUBLICATION %s %s", pq.QuoteIdentifier(publicationName), dropMode)
-	if _, err := txn.Exec(sql); err != nil {
-		return fmt.Errorf("could not execute sql: %w", err)
+	// The publication can only be dropped by its owner.
+	rolesToGrant := []string{}
+	if owner := d.Get(pubOwnerAttr).(string); owner != "" {
+		rolesToGrant = append(rolesToGrant, owner)
+	}
+	if err := withRolesGranted(txn, rolesToGrant, func() error {
+		sql := fmt.Sprintf("DROP PUBLICATION %s %s", pq.QuoteIdentifier(publicationName), dropMode)
+		if _, err := txn.Exec(sql); err != nil {
+			return fmt.Errorf("could not execute sql: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return err
 	}
 
 	if err = txn.Commit(); err != nil {
@@ -543,11 +657,13 @@ func getPublicationParameters(d *schema.ResourceData, pubViaRootEnabled bool) (s
 	return returnValue, nil
 }
 
+// resourcePostgreSQLPublicationStateUpgradeV0 quotes the dotted names in the ID of the version 0.
+func resourcePostgreSQLPublicationStateUpgradeV0(_ context.Context, rawState map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+	return upgradeObjectIDStateV0(rawState, pubDatabaseAttr, pubNameAttr), nil
+}
+
 func generatePublicationID(d *schema.ResourceData, databaseName string) string {
-	return strings.Join([]string{
-		databaseName,
-		d.Get(pubNameAttr).(string),
-	}, ".")
+	return generateObjectID(databaseName, d.Get(pubNameAttr).(string))
 }
 
 // getDBPublicationName returns database and publication name. If we are importing this
@@ -559,7 +675,10 @@ func getDBPublicationName(d *schema.ResourceData, client *Client) (string, strin
 
 	// When importing, we have to parse the ID to find publication and database names.
 	if PublicationName == "" {
-		parsed := strings.Split(d.Id(), ".")
+		parsed, err := parseObjectID(d.Id())
+		if err != nil {
+			return "", "", err
+		}
 		if len(parsed) != 2 {
 			return "", "", fmt.Errorf("Publication ID %s has not the expected format 'database.publication_name': %v", d.Id(), parsed)
 		}
@@ -570,6 +689,9 @@ func getDBPublicationName(d *schema.ResourceData, client *Client) (string, strin
 }
 
 func getPublicationNameFromID(ID string) string {
-	splitted := strings.Split(ID, ".")
-	return splitted[0]
+	parsed, err := parseObjectID(ID)
+	if err != nil || len(parsed) == 0 {
+		return ""
+	}
+	return parsed[len(parsed)-1]
 }
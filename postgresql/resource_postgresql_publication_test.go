@@ -416,6 +416,67 @@ func TestAccPostgresqlPublication_UpdateOwner(t *testing.T) {
 	})
 }
 
+// Test the owner changes when the connected user is not a superuser (e.g.: on RDS),
+// it's temporarily granted the current and the new owners.
+func TestAccPostgresqlPublication_UpdateOwnerNonSuperuser(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	config := getTestConfig(t)
+	dsn := config.connStr("postgres")
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	owners := []string{"test_pub_owner_1", "test_pub_owner_2"}
+	for _, owner := range owners {
+		dbExecute(t, dsn, fmt.Sprintf("CREATE ROLE %s", owner))
+		dbExecute(t, dsn, fmt.Sprintf("GRANT CREATE ON DATABASE %s TO %s", dbName, owner))
+	}
+	defer func() {
+		for _, owner := range owners {
+			dbExecute(t, dsn, fmt.Sprintf("DROP OWNED BY %s", owner))
+			dbExecute(t, dsn, fmt.Sprintf("DROP ROLE %s", owner))
+		}
+	}()
+
+	testConfig := `
+	resource "postgresql_publication" "test" {
+		name     = "publication"
+		database = "%s"
+		owner    = "%s"
+	}
+	`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePublication)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlPublicationDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testConfig, dbName, owners[0]),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlPublicationExists("postgresql_publication.test"),
+					resource.TestCheckResourceAttr("postgresql_publication.test", "owner", owners[0]),
+					checkUserMembership(t, dsn, config.Username, owners[0], false),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testConfig, dbName, owners[1]),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlPublicationExists("postgresql_publication.test"),
+					resource.TestCheckResourceAttr("postgresql_publication.test", "owner", owners[1]),
+					checkUserMembership(t, dsn, config.Username, owners[0], false),
+					checkUserMembership(t, dsn, config.Username, owners[1], false),
+				),
+			},
+		},
+	})
+}
+
 func TestAccPostgresqlPublication_UpdateName(t *testing.T) {
 	skipIfNotAcc(t)
 
@@ -482,6 +543,82 @@ func TestAccPostgresqlPublication_UpdateName(t *testing.T) {
 	})
 }
 
+// TestAccPostgresqlPublication_UpdateNameOwnerAndTables renames the publication and changes
+// its owner and its tables in the same step.
+func TestAccPostgresqlPublication_UpdateNameOwnerAndTables(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	tables := []string{"test_schema.test_table_1", "test_schema.test_table_2"}
+	dropTables := createTestTables(t, dbSuffix, tables, "")
+	defer dropTables()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	testAccPostgresqlPublicationBaseConfig := fmt.Sprintf(`
+	resource "postgresql_publication" "test" {
+		name     = "%s_publication_1"
+		database = "%s"
+		tables   = ["%s"]
+	}
+	`, dbName, dbName, tables[0])
+
+	testAccPostgresqlPublicationUpdateConfig := fmt.Sprintf(`
+	resource "postgresql_role" "test_owner" {
+		name  = "%s_pub_owner"
+		login = true
+	}
+	resource "postgresql_publication" "test" {
+		name     = "%s_publication_2"
+		database = "%s"
+		owner    = postgresql_role.test_owner.name
+		tables   = ["%s"]
+	}
+	`, dbName, dbName, dbName, tables[1])
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePublication)
+			testSuperuserPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlPublicationDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPostgresqlPublicationBaseConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlPublicationExists("postgresql_publication.test"),
+					resource.TestCheckResourceAttr(
+						"postgresql_publication.test", "name", fmt.Sprintf("%s_publication_1", dbName)),
+					resource.TestCheckResourceAttr(
+						"postgresql_publication.test", "owner", "postgres"),
+					resource.TestCheckTypeSetElemAttr(
+						"postgresql_publication.test", "tables.*", tables[0]),
+				),
+			},
+			{
+				Config: testAccPostgresqlPublicationUpdateConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlPublicationExists("postgresql_publication.test"),
+					resource.TestCheckResourceAttr(
+						"postgresql_publication.test", "id", fmt.Sprintf("%s.%s_publication_2", dbName, dbName)),
+					resource.TestCheckResourceAttr(
+						"postgresql_publication.test", "name", fmt.Sprintf("%s_publication_2", dbName)),
+					resource.TestCheckResourceAttr(
+						"postgresql_publication.test", "owner", fmt.Sprintf("%s_pub_owner", dbName)),
+					resource.TestCheckResourceAttr(
+						"postgresql_publication.test", "tables.#", "1"),
+					resource.TestCheckTypeSetElemAttr(
+						"postgresql_publication.test", "tables.*", tables[1]),
+				),
+			},
+		},
+	})
+}
+
 func checkPublicationExists(txn *sql.Tx, pubName string) (bool, error) {
 	var _rez bool
 	err := txn.QueryRow("SELECT TRUE from pg_catalog.pg_publication WHERE pubname=$1", pubName).Scan(&_rez)
@@ -560,6 +697,18 @@ resource "postgresql_publication" "test" {
 						"postgresql_publication.test", fmt.Sprintf("%s.3", pubPublishAttr), "truncate"),
 				),
 			},
+			{
+				ResourceName:      "postgresql_publication.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				// The publication is checked during the import.
+				ResourceName:  "postgresql_publication.test",
+				ImportState:   true,
+				ImportStateId: fmt.Sprintf("%s.unknown_publication", dbName),
+				ExpectError:   regexp.MustCompile("publication unknown_publication does not exist"),
+			},
 		},
 	})
 }
@@ -12,7 +12,7 @@ import (
 func resourcePostgreSQLReplicationSlot() *schema.Resource {
 	return &schema.Resource{
 		Create: PGResourceFunc(resourcePostgreSQLReplicationSlotCreate),
-		Read:   PGResourceFunc(resourcePostgreSQLReplicationSlotRead),
+		Read:   PGReadResourceFunc(resourcePostgreSQLReplicationSlotRead),
 		Delete: PGResourceFunc(resourcePostgreSQLReplicationSlotDelete),
 		Exists: PGResourceExistsFunc(resourcePostgreSQLReplicationSlotExists),
 		Importer: &schema.ResourceImporter{
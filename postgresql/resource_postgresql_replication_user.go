@@ -0,0 +1,517 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
+)
+
+const (
+	replicationUserNameAttr        = "name"
+	replicationUserPasswordAttr    = "password"
+	replicationUserDatabaseAttr    = "database"
+	replicationUserTablesAttr      = "tables"
+	replicationUserPublicationAttr = "publication"
+)
+
+// resourcePostgreSQLReplicationUser prepares a role for logical replication (e.g.: for Debezium):
+// the role can log in with the REPLICATION attribute, reads the published tables and, optionally,
+// the publication of these tables is managed with it.
+func resourcePostgreSQLReplicationUser() *schema.Resource {
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLReplicationUserCreate),
+		Read:   PGReadResourceFunc(resourcePostgreSQLReplicationUserRead),
+		Update: PGResourceFunc(resourcePostgreSQLReplicationUserUpdate),
+		Delete: PGResourceFunc(resourcePostgreSQLReplicationUserDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourcePostgreSQLReplicationUserImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			replicationUserNameAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the replication role",
+			},
+			replicationUserPasswordAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				ValidateFunc: validateRolePassword,
+				Description:  "The password of the replication role, in plain text or already hashed (md5 or SCRAM-SHA-256)",
+			},
+			replicationUserDatabaseAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The database of the replicated tables",
+			},
+			replicationUserTablesAttr: {
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    1,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "The replicated tables, with their schema (e.g.: public.orders), on which SELECT is granted to the role",
+			},
+			replicationUserPublicationAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The name of the publication of the tables to create, if any",
+			},
+		},
+	}
+}
+
+func resourcePostgreSQLReplicationUserCreate(db *DBConnection, d *schema.ResourceData) error {
+	if err := checkReplicationUserFeatures(db, d); err != nil {
+		return err
+	}
+
+	name := d.Get(replicationUserNameAttr).(string)
+
+	txn, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if _, err := txn.Exec(fmt.Sprintf(
+		"CREATE ROLE %s WITH LOGIN REPLICATION PASSWORD %s", pq.QuoteIdentifier(name), replicationUserPassword(d),
+	)); err != nil {
+		return fmt.Errorf("could not create replication role %s: %w", name, err)
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	// The role is created in its own transaction (the other statements run in the replicated database),
+	// the ID is set so the resource is tainted and the role dropped if the next steps fail.
+	d.SetId(generateObjectID(d.Get(replicationUserDatabaseAttr).(string), name))
+
+	txn, err = startTransaction(db.client, d.Get(replicationUserDatabaseAttr).(string))
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if _, err := txn.Exec(fmt.Sprintf(
+		"GRANT CONNECT ON DATABASE %s TO %s",
+		pq.QuoteIdentifier(d.Get(replicationUserDatabaseAttr).(string)), pq.QuoteIdentifier(name),
+	)); err != nil {
+		return fmt.Errorf("could not grant connect to replication role %s: %w", name, err)
+	}
+
+	if err := grantReplicationUserTables(txn, name, d.Get(replicationUserTablesAttr).(*schema.Set)); err != nil {
+		return err
+	}
+
+	if err := setReplicationUserPublication(txn, d); err != nil {
+		return err
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return resourcePostgreSQLReplicationUserReadImpl(db, d)
+}
+
+func resourcePostgreSQLReplicationUserRead(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLReplicationUserReadImpl(db, d)
+}
+
+// resourcePostgreSQLReplicationUserImport sets the database and the role from the ID (database.role),
+// the tables are read from the privileges of the role. The publication is not imported.
+func resourcePostgreSQLReplicationUserImport(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	parts, err := parseObjectID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("replication user ID %s has not the expected format 'database.role'", d.Id())
+	}
+
+	d.Set(replicationUserDatabaseAttr, parts[0])
+	d.Set(replicationUserNameAttr, parts[1])
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourcePostgreSQLReplicationUserReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	database := d.Get(replicationUserDatabaseAttr).(string)
+	name := d.Get(replicationUserNameAttr).(string)
+
+	exists, err := dbExists(db, database)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		log.Printf("[WARN] PostgreSQL database (%s) not found, removing replication user from state", database)
+		d.SetId("")
+		return nil
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	var roleOID uint32
+	var canLogin, replication bool
+	err = txn.QueryRow(
+		"SELECT oid, rolcanlogin, rolreplication FROM pg_catalog.pg_roles WHERE rolname = $1", name,
+	).Scan(&roleOID, &canLogin, &replication)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] PostgreSQL role (%s) not found, removing replication user from state", name)
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("could not read replication role %s: %w", name, err)
+	}
+	if !canLogin || !replication {
+		// The role is not usable for the replication anymore, it's created again.
+		log.Printf("[WARN] PostgreSQL role (%s) has not the LOGIN and REPLICATION attributes, removing replication user from state", name)
+		d.SetId("")
+		return nil
+	}
+
+	publication := d.Get(replicationUserPublicationAttr).(string)
+	var publishedTables *schema.Set
+	if publication != "" {
+		if publishedTables, err = getReplicationUserPublicationTables(txn, publication); err != nil {
+			return err
+		}
+		if publishedTables == nil {
+			log.Printf("[WARN] PostgreSQL publication (%s) not found in database %s", publication, database)
+			d.Set(replicationUserPublicationAttr, "")
+		}
+	}
+
+	// When importing, the tables are the ones the role can read.
+	stateTables := d.Get(replicationUserTablesAttr).(*schema.Set)
+	if stateTables.Len() == 0 {
+		if stateTables, err = getReplicationUserReadableTables(txn, roleOID); err != nil {
+			return fmt.Errorf("could not read tables of replication role %s: %w", name, err)
+		}
+	}
+
+	// Remove from the state the tables which can't be read by the role (or don't exist anymore)
+	// or which are not in the publication, so they are granted and published again on the next apply.
+	tables := schema.NewSet(schema.HashString, nil)
+	for _, table := range stateTables.List() {
+		var granted bool
+		err := txn.QueryRow(`
+SELECT EXISTS (
+	SELECT 1 FROM pg_catalog.pg_class c, aclexplode(c.relacl) acl
+	WHERE c.oid = to_regclass($1) AND acl.grantee = $2 AND acl.privilege_type = 'SELECT'
+)
+`, quoteTableName(table.(string)), roleOID).Scan(&granted)
+		if err != nil {
+			return fmt.Errorf("could not read privileges of %s on table %s: %w", name, table, err)
+		}
+
+		switch {
+		case !granted:
+			log.Printf("[DEBUG] role %s can't read table %s", name, table)
+		case publishedTables != nil && !publishedTables.Contains(table):
+			log.Printf("[DEBUG] table %s is not in publication %s", table, publication)
+		default:
+			tables.Add(table)
+		}
+	}
+
+	d.Set(replicationUserTablesAttr, tables)
+	d.SetId(generateObjectID(database, name))
+
+	return nil
+}
+
+func resourcePostgreSQLReplicationUserUpdate(db *DBConnection, d *schema.ResourceData) error {
+	if err := checkReplicationUserFeatures(db, d); err != nil {
+		return err
+	}
+
+	name := d.Get(replicationUserNameAttr).(string)
+
+	if d.HasChange(replicationUserPasswordAttr) {
+		txn, err := startTransaction(db.client, "")
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		if _, err := txn.Exec(fmt.Sprintf(
+			"ALTER ROLE %s PASSWORD %s", pq.QuoteIdentifier(name), replicationUserPassword(d),
+		)); err != nil {
+			return fmt.Errorf("could not update password of replication role %s: %w", name, err)
+		}
+		if err := txn.Commit(); err != nil {
+			return fmt.Errorf("could not commit transaction: %w", err)
+		}
+	}
+
+	if !d.HasChanges(replicationUserTablesAttr, replicationUserPublicationAttr) {
+		return resourcePostgreSQLReplicationUserReadImpl(db, d)
+	}
+
+	txn, err := startTransaction(db.client, d.Get(replicationUserDatabaseAttr).(string))
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	// Only the removed tables are revoked so the role can still read the other ones during the update.
+	oldTables, newTables := d.GetChange(replicationUserTablesAttr)
+	if err := revokeReplicationUserTables(txn, name, oldTables.(*schema.Set).Difference(newTables.(*schema.Set))); err != nil {
+		return err
+	}
+	if err := revokeReplicationUserSchemas(txn, name, oldTables.(*schema.Set), newTables.(*schema.Set)); err != nil {
+		return err
+	}
+	if err := grantReplicationUserTables(txn, name, newTables.(*schema.Set)); err != nil {
+		return err
+	}
+
+	if oldPublication, _ := d.GetChange(replicationUserPublicationAttr); d.HasChange(replicationUserPublicationAttr) && oldPublication.(string) != "" {
+		if _, err := txn.Exec(fmt.Sprintf("DROP PUBLICATION IF EXISTS %s", pq.QuoteIdentifier(oldPublication.(string)))); err != nil {
+			return fmt.Errorf("could not drop publication %s: %w", oldPublication, err)
+		}
+	}
+	if err := setReplicationUserPublication(txn, d); err != nil {
+		return err
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return resourcePostgreSQLReplicationUserReadImpl(db, d)
+}
+
+func resourcePostgreSQLReplicationUserDelete(db *DBConnection, d *schema.ResourceData) error {
+	database := d.Get(replicationUserDatabaseAttr).(string)
+	name := d.Get(replicationUserNameAttr).(string)
+
+	exists, err := dbExists(db, database)
+	if err != nil {
+		return err
+	}
+	// The role can't be dropped while it has privileges in the database.
+	if exists {
+		txn, err := startTransaction(db.client, database)
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		if publication := d.Get(replicationUserPublicationAttr).(string); publication != "" {
+			if _, err := txn.Exec(fmt.Sprintf("DROP PUBLICATION IF EXISTS %s", pq.QuoteIdentifier(publication))); err != nil {
+				return fmt.Errorf("could not drop publication %s: %w", publication, err)
+			}
+		}
+
+		if err := revokeReplicationUserTables(txn, name, d.Get(replicationUserTablesAttr).(*schema.Set)); err != nil {
+			return err
+		}
+		if err := revokeReplicationUserSchemas(txn, name, d.Get(replicationUserTablesAttr).(*schema.Set), schema.NewSet(schema.HashString, nil)); err != nil {
+			return err
+		}
+		if _, err := txn.Exec(fmt.Sprintf(
+			"REVOKE ALL PRIVILEGES ON DATABASE %s FROM %s", pq.QuoteIdentifier(database), pq.QuoteIdentifier(name),
+		)); err != nil {
+			return fmt.Errorf("could not revoke database privileges of replication role %s: %w", name, err)
+		}
+
+		if err := txn.Commit(); err != nil {
+			return fmt.Errorf("could not commit transaction: %w", err)
+		}
+	}
+
+	txn, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if _, err := txn.Exec(fmt.Sprintf("DROP ROLE IF EXISTS %s", pq.QuoteIdentifier(name))); err != nil {
+		return fmt.Errorf("could not drop replication role %s: %w", name, err)
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+func checkReplicationUserFeatures(db *DBConnection, d *schema.ResourceData) error {
+	if d.Get(replicationUserPublicationAttr).(string) != "" && !db.featureSupported(featurePublication) {
+		return fmt.Errorf(
+			"publications are not supported for this Postgres version (%s)",
+			db.version,
+		)
+	}
+	return nil
+}
+
+// replicationUserPassword returns the password literal of the role, NULL if it's not set.
+func replicationUserPassword(d *schema.ResourceData) string {
+	password := d.Get(replicationUserPasswordAttr).(string)
+	if password == "" {
+		return "NULL"
+	}
+	return fmt.Sprintf("'%s'", pqQuoteLiteral(password))
+}
+
+// replicationUserSchemas returns the schemas of the tables.
+func replicationUserSchemas(tables *schema.Set) []string {
+	schemas := []string{}
+	for _, table := range setToSortedSlice(tables) {
+		schemaName, _ := splitQualifiedIdent("public", table)
+		if !sliceContainsStr(schemas, schemaName) {
+			schemas = append(schemas, schemaName)
+		}
+	}
+	return schemas
+}
+
+func grantReplicationUserTables(txn *sql.Tx, role string, tables *schema.Set) error {
+	for _, schemaName := range replicationUserSchemas(tables) {
+		if _, err := txn.Exec(fmt.Sprintf(
+			"GRANT USAGE ON SCHEMA %s TO %s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(role),
+		)); err != nil {
+			return fmt.Errorf("could not grant usage on schema %s to replication role %s: %w", schemaName, role, err)
+		}
+	}
+
+	if _, err := txn.Exec(fmt.Sprintf(
+		"GRANT SELECT ON TABLE %s TO %s", quoteTableNames(tables), pq.QuoteIdentifier(role),
+	)); err != nil {
+		return fmt.Errorf("could not grant select on tables to replication role %s: %w", role, err)
+	}
+	return nil
+}
+
+// revokeReplicationUserTables revokes the privileges of the role on the tables which still exist.
+// The usage of their schemas is revoked by revokeReplicationUserSchemas.
+func revokeReplicationUserTables(txn *sql.Tx, role string, tables *schema.Set) error {
+	var existing []string
+	for _, table := range tables.List() {
+		var exists bool
+		if err := txn.QueryRow("SELECT to_regclass($1) IS NOT NULL", quoteTableName(table.(string))).Scan(&exists); err != nil {
+			return fmt.Errorf("could not check if table %s exists: %w", table, err)
+		}
+		if exists {
+			existing = append(existing, table.(string))
+		}
+	}
+	if len(existing) == 0 {
+		return nil
+	}
+
+	if _, err := txn.Exec(fmt.Sprintf(
+		"REVOKE ALL PRIVILEGES ON TABLE %s FROM %s",
+		quoteTableNames(stringSliceToSet(existing)), pq.QuoteIdentifier(role),
+	)); err != nil {
+		return fmt.Errorf("could not revoke privileges on tables from replication role %s: %w", role, err)
+	}
+	return nil
+}
+
+// revokeReplicationUserSchemas revokes the usage of the schemas of the old tables which have no table anymore
+// in the new tables (and which still exist).
+func revokeReplicationUserSchemas(txn *sql.Tx, role string, oldTables, newTables *schema.Set) error {
+	newSchemas := replicationUserSchemas(newTables)
+	for _, schemaName := range replicationUserSchemas(oldTables) {
+		if sliceContainsStr(newSchemas, schemaName) {
+			continue
+		}
+
+		var exists bool
+		if err := txn.QueryRow("SELECT EXISTS (SELECT 1 FROM pg_catalog.pg_namespace WHERE nspname = $1)", schemaName).Scan(&exists); err != nil {
+			return fmt.Errorf("could not check if schema %s exists: %w", schemaName, err)
+		}
+		if !exists {
+			continue
+		}
+
+		if _, err := txn.Exec(fmt.Sprintf(
+			"REVOKE USAGE ON SCHEMA %s FROM %s", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(role),
+		)); err != nil {
+			return fmt.Errorf("could not revoke usage on schema %s from replication role %s: %w", schemaName, role, err)
+		}
+	}
+	return nil
+}
+
+// getReplicationUserReadableTables returns the tables (with their schema) on which SELECT is granted to the role.
+func getReplicationUserReadableTables(txn *sql.Tx, roleOID uint32) (*schema.Set, error) {
+	var tables pq.StringArray
+	if err := txn.QueryRow(`
+SELECT ARRAY(
+	SELECT DISTINCT n.nspname || '.' || c.relname
+	FROM pg_catalog.pg_class c
+	JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace, aclexplode(c.relacl) acl
+	WHERE c.relkind IN ('r', 'p') AND acl.grantee = $1 AND acl.privilege_type = 'SELECT'
+)
+`, roleOID).Scan(&tables); err != nil {
+		return nil, err
+	}
+	return stringSliceToSet([]string(tables)), nil
+}
+
+// setReplicationUserPublication creates the publication of the tables or sets its tables if it exists.
+func setReplicationUserPublication(txn *sql.Tx, d *schema.ResourceData) error {
+	publication := d.Get(replicationUserPublicationAttr).(string)
+	if publication == "" {
+		return nil
+	}
+
+	publishedTables, err := getReplicationUserPublicationTables(txn, publication)
+	if err != nil {
+		return err
+	}
+
+	tables := quoteTableNames(d.Get(replicationUserTablesAttr).(*schema.Set))
+	query := fmt.Sprintf("CREATE PUBLICATION %s FOR TABLE %s", pq.QuoteIdentifier(publication), tables)
+	if publishedTables != nil {
+		query = fmt.Sprintf("ALTER PUBLICATION %s SET TABLE %s", pq.QuoteIdentifier(publication), tables)
+	}
+	if _, err := txn.Exec(query); err != nil {
+		return fmt.Errorf("could not set tables of publication %s: %w", publication, err)
+	}
+	return nil
+}
+
+// getReplicationUserPublicationTables returns the tables of the publication, nil if it doesn't exist.
+func getReplicationUserPublicationTables(txn *sql.Tx, publication string) (*schema.Set, error) {
+	var exists bool
+	if err := txn.QueryRow(
+		"SELECT EXISTS (SELECT 1 FROM pg_catalog.pg_publication WHERE pubname = $1)", publication,
+	).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("could not read publication %s: %w", publication, err)
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	var tables pq.StringArray
+	if err := txn.QueryRow(
+		"SELECT ARRAY(SELECT schemaname || '.' || tablename FROM pg_catalog.pg_publication_tables WHERE pubname = $1)",
+		publication,
+	).Scan(&tables); err != nil {
+		return nil, fmt.Errorf("could not read tables of publication %s: %w", publication, err)
+	}
+	return stringSliceToSet([]string(tables)), nil
+}
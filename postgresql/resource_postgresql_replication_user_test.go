@@ -0,0 +1,103 @@
+package postgresql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplicationUserSchemas(t *testing.T) {
+	tables := schema.NewSet(schema.HashString, []interface{}{"public.t1", "inventory.t2", "public.t3"})
+	assert.Equal(t, []string{"inventory", "public"}, replicationUserSchemas(tables))
+}
+
+func TestAccPostgresqlReplicationUser_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, false)
+	defer teardown()
+
+	testTables := []string{"test_schema.test_table", "test_schema.test_table2"}
+	createTestTables(t, dbSuffix, testTables, "")
+
+	dbName, _ := getTestDBNames(dbSuffix)
+	roleName := fmt.Sprintf("tf_tests_replication_%s", dbSuffix)
+
+	testConfig := `
+resource "postgresql_replication_user" "test" {
+	name        = "%s"
+	password    = "%s"
+	database    = "%s"
+	tables      = [%s]
+	publication = "test_publication"
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePublication)
+			testSuperuserPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlReplicationUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testConfig, roleName, testRolePassword, dbName, `"test_schema.test_table"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_replication_user.test", "id", dbName+"."+roleName),
+					resource.TestCheckResourceAttr("postgresql_replication_user.test", "tables.#", "1"),
+					resource.TestCheckResourceAttr("postgresql_replication_user.test", "publication", "test_publication"),
+					func(*terraform.State) error {
+						return testCheckTablesPrivileges(t, dbName, roleName, []string{"test_schema.test_table"}, []string{"SELECT"})
+					},
+				),
+			},
+			{
+				Config: fmt.Sprintf(testConfig, roleName, testRolePassword, dbName, `"test_schema.test_table", "test_schema.test_table2"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_replication_user.test", "tables.#", "2"),
+					func(*terraform.State) error {
+						return testCheckTablesPrivileges(t, dbName, roleName, testTables, []string{"SELECT"})
+					},
+				),
+			},
+			{
+				ResourceName:            "postgresql_replication_user.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"password", "publication"},
+			},
+		},
+	})
+}
+
+func testAccCheckPostgresqlReplicationUserDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "postgresql_replication_user" {
+			continue
+		}
+
+		txn, err := startTransaction(client, "")
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		exists, err := roleExists(txn, rs.Primary.Attributes["name"])
+		if err != nil {
+			return fmt.Errorf("could not check role %s: %w", rs.Primary.Attributes["name"], err)
+		}
+		if exists {
+			return fmt.Errorf("Replication role %s still exists after destroy", rs.Primary.Attributes["name"])
+		}
+	}
+
+	return nil
+}
@@ -1,18 +1,24 @@
 package postgresql
 
 import (
+	"context"
+	"crypto/hmac"
 	"crypto/md5"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/base64"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"log"
+	"regexp"
 	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/lib/pq"
+	"golang.org/x/crypto/pbkdf2"
 )
 
 const (
@@ -20,21 +26,32 @@ const (
 	roleConnLimitAttr                       = "connection_limit"
 	roleCreateDBAttr                        = "create_database"
 	roleCreateRoleAttr                      = "create_role"
+	roleDisableConnectionsAttr              = "disable_connections"
 	roleEncryptedPassAttr                   = "encrypted_password"
 	roleIdleInTransactionSessionTimeoutAttr = "idle_in_transaction_session_timeout"
 	roleInheritAttr                         = "inherit"
 	roleLoginAttr                           = "login"
 	roleNameAttr                            = "name"
 	rolePasswordAttr                        = "password"
+	rolePasswordWOAttr                      = "password_wo"
+	rolePasswordWOVersionAttr               = "password_wo_version"
+	rolePasswordTriggerAttr                 = "password_trigger"
 	roleReplicationAttr                     = "replication"
 	roleSkipDropRoleAttr                    = "skip_drop_role"
 	roleSkipReassignOwnedAttr               = "skip_reassign_owned"
+	roleDropOwnedCascadeAttr                = "drop_owned_cascade"
 	roleSuperuserAttr                       = "superuser"
 	roleValidUntilAttr                      = "valid_until"
 	roleRolesAttr                           = "roles"
 	roleSearchPathAttr                      = "search_path"
+	roleRawSearchPathAttr                   = "raw_search_path"
 	roleStatementTimeoutAttr                = "statement_timeout"
 	roleAssumeRoleAttr                      = "assume_role"
+	roleTerminateSessionsAttr               = "terminate_sessions_on_disable_login"
+	roleMembersAttr                         = "members"
+	roleMembershipAttr                      = "membership"
+	rolePgbouncerUserlistAttr               = "pgbouncer_userlist"
+	roleGeneratePgbouncerUserlistAttr       = "generate_pgbouncer_userlist"
 
 	// Deprecated options
 	roleDepEncryptedAttr = "encrypted"
@@ -42,26 +59,66 @@ const (
 
 func resourcePostgreSQLRole() *schema.Resource {
 	return &schema.Resource{
-		Create: PGResourceFunc(resourcePostgreSQLRoleCreate),
-		Read:   PGResourceFunc(resourcePostgreSQLRoleRead),
-		Update: PGResourceFunc(resourcePostgreSQLRoleUpdate),
-		Delete: PGResourceFunc(resourcePostgreSQLRoleDelete),
-		Exists: PGResourceExistsFunc(resourcePostgreSQLRoleExists),
+		CreateContext: PGResourceFuncWithWarnings(resourcePostgreSQLRoleCreate, roleSecurityWarnings),
+		Read:          PGReadResourceFunc(readWithSecurityWarnings(resourcePostgreSQLRoleRead, roleSecurityWarnings)),
+		UpdateContext: PGResourceFuncWithWarnings(resourcePostgreSQLRoleUpdate, roleSecurityWarnings),
+		Delete:        PGResourceFunc(resourcePostgreSQLRoleDelete),
+		Exists:        PGResourceExistsFunc(resourcePostgreSQLRoleExists),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		CustomizeDiff: resourcePostgreSQLRoleCustomizeDiff,
 
 		Schema: map[string]*schema.Schema{
 			roleNameAttr: {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The name of the role",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateIdentifier,
+				Description:  "The name of the role",
 			},
 			rolePasswordAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				ValidateFunc: validateRolePassword,
+				Description:  "Sets the role's password, in plain text or already hashed (md5 or SCRAM-SHA-256)",
+			},
+			rolePasswordWOAttr: {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				ValidateFunc:  validateRolePassword,
+				ConflictsWith: []string{rolePasswordAttr},
+				RequiredWith:  []string{rolePasswordWOVersionAttr},
+				// The password is not stored in the state, it's read from the configuration
+				// when the role is created or when password_wo_version changes.
+				StateFunc:   func(interface{}) string { return "" },
+				Description: "Sets the role's password without storing it in the state, it's only set when password_wo_version changes",
+			},
+			rolePasswordWOVersionAttr: {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				RequiredWith: []string{rolePasswordWOAttr},
+				Description:  "The version of password_wo, to change to set the password again (e.g. to rotate it)",
+			},
+			rolePasswordTriggerAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{rolePasswordAttr},
+				Description:  "An arbitrary value, to change to set password again even if it's unchanged (e.g. the id of a time_rotating resource)",
+			},
+			rolePgbouncerUserlistAttr: {
 				Type:        schema.TypeString,
-				Optional:    true,
+				Computed:    true,
 				Sensitive:   true,
-				Description: "Sets the role's password",
+				Description: "The line of the role for the userlist.txt file of pgbouncer, with the password hash stored by PostgreSQL if it can be read, only set if generate_pgbouncer_userlist is true",
+			},
+			roleGeneratePgbouncerUserlistAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Set pgbouncer_userlist with the password hash of the role (it's a secret stored in the state)",
 			},
 			roleDepEncryptedAttr: {
 				Type:       schema.TypeString,
@@ -69,20 +126,82 @@ func resourcePostgreSQLRole() *schema.Resource {
 				Deprecated: fmt.Sprintf("Rename PostgreSQL role resource attribute %q to %q", roleDepEncryptedAttr, roleEncryptedPassAttr),
 			},
 			roleRolesAttr: {
+				Type:          schema.TypeSet,
+				Optional:      true,
+				Elem:          &schema.Schema{Type: schema.TypeString, ValidateFunc: validateIdentifier},
+				Set:           schema.HashString,
+				MinItems:      0,
+				ConflictsWith: []string{roleMembershipAttr},
+				Description:   "Role(s) to grant to this new role, i.e. the roles it's a member of (IN ROLE)",
+			},
+			roleMembershipAttr: {
+				Type:          schema.TypeSet,
+				Optional:      true,
+				ConflictsWith: []string{roleRolesAttr},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"role": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateIdentifier,
+							Description:  "The role granted to this role",
+						},
+						"admin_option": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "If this role can grant the membership to other roles (WITH ADMIN OPTION)",
+						},
+						"inherit_option": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     true,
+							Description: "If this role inherits the privileges of the granted role (PostgreSQL >= 16)",
+						},
+						"set_option": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     true,
+							Description: "If this role can SET ROLE to the granted role (PostgreSQL >= 16)",
+						},
+						"granted_by": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The role recorded as the grantor of the membership",
+						},
+					},
+				},
+				Description: "Role(s) to grant to this new role, with the options of the memberships",
+			},
+			roleMembersAttr: {
 				Type:        schema.TypeSet,
-				Optional:    true,
+				Computed:    true,
 				Elem:        &schema.Schema{Type: schema.TypeString},
 				Set:         schema.HashString,
-				MinItems:    0,
-				Description: "Role(s) to grant to this new role",
+				Description: "Roles which are members of this role",
 			},
 			roleSearchPathAttr: {
-				Type:        schema.TypeList,
-				Optional:    true,
-				Elem:        &schema.Schema{Type: schema.TypeString},
-				MinItems:    0,
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateSearchPathElement,
+				},
+				MinItems:      0,
+				ConflictsWith: []string{roleRawSearchPathAttr},
+				// Quoted elements (e.g.: `"$user"`) are read back unquoted
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return strings.Trim(old, `"`) == strings.Trim(new, `"`)
+				},
 				Description: "Sets the role's search path",
 			},
+			roleRawSearchPathAttr: {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ConflictsWith:    []string{roleSearchPathAttr},
+				DiffSuppressFunc: searchPathDiffSuppressFunc,
+				Description:      "Sets the role's search path as a raw SQL value (e.g.: `\"$user\", public`)",
+			},
 			roleEncryptedPassAttr: {
 				Type:        schema.TypeBool,
 				Optional:    true,
@@ -102,6 +221,12 @@ func resourcePostgreSQLRole() *schema.Resource {
 				Description:  "How many concurrent connections can be made with this role",
 				ValidateFunc: validation.IntAtLeast(-1),
 			},
+			roleDisableConnectionsAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Forbid the role to connect by setting its connection limit to 0",
+			},
 			roleSuperuserAttr: {
 				Type:        schema.TypeBool,
 				Optional:    true,
@@ -162,6 +287,12 @@ func resourcePostgreSQLRole() *schema.Resource {
 				Default:     false,
 				Description: "Skip actually running the REASSIGN OWNED command when removing a role from PostgreSQL",
 			},
+			roleDropOwnedCascadeAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Run the DROP OWNED command with CASCADE when removing a role from PostgreSQL, to also drop the objects depending on the dropped ones",
+			},
 			roleStatementTimeoutAttr: {
 				Type:         schema.TypeInt,
 				Optional:     true,
@@ -173,10 +304,51 @@ func resourcePostgreSQLRole() *schema.Resource {
 				Optional:    true,
 				Description: "Role to switch to at login",
 			},
+			roleTerminateSessionsAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Terminate the active sessions of the role when its login is disabled",
+			},
+			securityWarningsAttr: securityWarningsSchema(),
 		},
 	}
 }
 
+// roleSecurityWarnings returns the risky settings of the role, see strict_security_warnings.
+func roleSecurityWarnings(get ResourceSchemeGetter) []string {
+	var warnings []string
+
+	roleName := get(roleNameAttr).(string)
+	if !get(roleLoginAttr).(bool) {
+		return warnings
+	}
+
+	validUntil := get(roleValidUntilAttr).(string)
+	hasPassword := get(rolePasswordAttr).(string) != "" || get(rolePasswordWOVersionAttr).(int) != 0
+	if hasPassword && (validUntil == "" || validUntil == "infinity") {
+		warnings = append(warnings, fmt.Sprintf(
+			"login role %q has a password which never expires, set %q to limit its validity",
+			roleName, roleValidUntilAttr,
+		))
+	}
+	if get(roleSuperuserAttr).(bool) {
+		warnings = append(warnings, fmt.Sprintf(
+			"role %q is a superuser which can login, consider granting only the required privileges",
+			roleName,
+		))
+	}
+	// A connection limit of 0 is often mistaken for no limit (-1). It will be refused in the next major release.
+	if !get(roleDisableConnectionsAttr).(bool) && get(roleConnLimitAttr).(int) == 0 {
+		warnings = append(warnings, fmt.Sprintf(
+			"%s = 0 forbids login role %q to connect and is deprecated: set %s = true to disable its connections, or %s = -1 for no limit",
+			roleConnLimitAttr, roleName, roleDisableConnectionsAttr, roleConnLimitAttr,
+		))
+	}
+
+	return warnings
+}
+
 func resourcePostgreSQLRoleCreate(db *DBConnection, d *schema.ResourceData) error {
 	txn, err := startTransaction(db.client, "")
 	if err != nil {
@@ -256,8 +428,20 @@ func resourcePostgreSQLRoleCreate(db *DBConnection, d *schema.ResourceData) erro
 		}
 	}
 
+	if password := rolePasswordWO(d); password != "" {
+		if d.Get(roleEncryptedPassAttr).(bool) {
+			createOpts = append(createOpts, "ENCRYPTED")
+		} else {
+			createOpts = append(createOpts, "UNENCRYPTED")
+		}
+		createOpts = append(createOpts, fmt.Sprintf("PASSWORD '%s'", pqQuoteLiteral(password)))
+	}
+
 	for _, opt := range intOpts {
 		val := d.Get(opt.hclKey).(int)
+		if opt.hclKey == roleConnLimitAttr {
+			val = roleConnLimit(d)
+		}
 		createOpts = append(createOpts, fmt.Sprintf("%s %d", opt.sqlKey, val))
 	}
 
@@ -275,6 +459,16 @@ func resourcePostgreSQLRoleCreate(db *DBConnection, d *schema.ResourceData) erro
 		createOpts = append(createOpts, valStr)
 	}
 
+	// The roles are granted by the IN ROLE clause, the membership blocks are granted after
+	// the creation as IN ROLE has no options.
+	if inRoles := d.Get(roleRolesAttr).(*schema.Set); inRoles.Len() > 0 {
+		quoted := make([]string, 0, inRoles.Len())
+		for _, inRole := range setToSortedSlice(inRoles) {
+			quoted = append(quoted, pq.QuoteIdentifier(inRole))
+		}
+		createOpts = append(createOpts, "IN ROLE "+strings.Join(quoted, ", "))
+	}
+
 	roleName := d.Get(roleNameAttr).(string)
 	createStr := strings.Join(createOpts, " ")
 	if len(createOpts) > 0 {
@@ -291,7 +485,7 @@ func resourcePostgreSQLRoleCreate(db *DBConnection, d *schema.ResourceData) erro
 		return fmt.Errorf("error creating role %s: %w", roleName, err)
 	}
 
-	if err = grantRoles(txn, d); err != nil {
+	if err = grantRoleMemberships(db, txn, d); err != nil {
 		return err
 	}
 
@@ -336,11 +530,25 @@ func resourcePostgreSQLRoleDelete(db *DBConnection, d *schema.ResourceData) erro
 	if !d.Get(roleSkipReassignOwnedAttr).(bool) {
 		if err := withRolesGranted(txn, []string{roleName}, func() error {
 			currentUser := db.client.config.getDatabaseUsername()
-			if _, err := txn.Exec(fmt.Sprintf("REASSIGN OWNED BY %s TO %s", pq.QuoteIdentifier(roleName), pq.QuoteIdentifier(currentUser))); err != nil {
+
+			// The current user needs to be able to own the objects of the role,
+			// otherwise REASSIGN OWNED fails with a permission denied on the first one.
+			owners, err := reassignOwnedRolesToGrant(txn, roleName, currentUser)
+			if err != nil {
+				return err
+			}
+			if err := withRolesGranted(txn, owners, func() error {
+				_, err := txn.Exec(fmt.Sprintf("REASSIGN OWNED BY %s TO %s", pq.QuoteIdentifier(roleName), pq.QuoteIdentifier(currentUser)))
+				return err
+			}); err != nil {
 				return fmt.Errorf("could not reassign owned by role %s to %s: %w", roleName, currentUser, err)
 			}
 
-			if _, err := txn.Exec(fmt.Sprintf("DROP OWNED BY %s", pq.QuoteIdentifier(roleName))); err != nil {
+			dropOwned := fmt.Sprintf("DROP OWNED BY %s", pq.QuoteIdentifier(roleName))
+			if d.Get(roleDropOwnedCascadeAttr).(bool) {
+				dropOwned += " CASCADE"
+			}
+			if _, err := txn.Exec(dropOwned); err != nil {
 				return fmt.Errorf("could not drop owned by role %s: %w", roleName, err)
 			}
 			return nil
@@ -363,6 +571,72 @@ func resourcePostgreSQLRoleDelete(db *DBConnection, d *schema.ResourceData) erro
 	return nil
 }
 
+// reassignOwnedRolesToGrant returns the roles the current user has to be a member of to own the objects
+// of the role with REASSIGN OWNED: the owners of the schemas (and of the database) in which it doesn't
+// have the CREATE privilege. It fails if the role owns databases and the current user can't own them.
+func reassignOwnedRolesToGrant(txn *sql.Tx, roleName, currentUser string) ([]string, error) {
+	superuser, err := isSuperuser(txn, currentUser)
+	if err != nil {
+		return nil, err
+	}
+	if superuser {
+		return nil, nil
+	}
+
+	var createDB bool
+	var databases []string
+	if err := txn.QueryRow(`
+SELECT rolcreatedb, ARRAY(
+	SELECT datname FROM pg_catalog.pg_database
+	WHERE datdba = (SELECT oid FROM pg_catalog.pg_roles WHERE rolname = $1)
+	ORDER BY datname
+)
+FROM pg_catalog.pg_roles WHERE rolname = $2
+`, roleName, currentUser).Scan(&createDB, pq.Array(&databases)); err != nil {
+		return nil, fmt.Errorf("could not read databases owned by role %s: %w", roleName, err)
+	}
+	if !createDB && len(databases) > 0 {
+		return nil, fmt.Errorf(
+			"role %s owns the databases %s which can't be reassigned to %s as it doesn't have the CREATEDB attribute, change their owner before deleting the role",
+			roleName, strings.Join(databases, ", "), currentUser,
+		)
+	}
+
+	rows, err := txn.Query(`
+WITH r AS (SELECT oid FROM pg_catalog.pg_roles WHERE rolname = $1)
+SELECT pg_catalog.pg_get_userbyid(n.nspowner)
+FROM pg_catalog.pg_namespace n
+WHERE NOT has_schema_privilege($2, n.oid, 'CREATE') AND n.oid IN (
+	SELECT relnamespace FROM pg_catalog.pg_class WHERE relowner = (SELECT oid FROM r)
+	UNION SELECT pronamespace FROM pg_catalog.pg_proc WHERE proowner = (SELECT oid FROM r)
+	UNION SELECT typnamespace FROM pg_catalog.pg_type WHERE typowner = (SELECT oid FROM r)
+)
+UNION
+SELECT pg_catalog.pg_get_userbyid(d.datdba)
+FROM pg_catalog.pg_database d
+WHERE d.datname = current_database() AND NOT has_database_privilege($2, d.oid, 'CREATE')
+	AND EXISTS (SELECT 1 FROM pg_catalog.pg_namespace WHERE nspowner = (SELECT oid FROM r))
+`, roleName, currentUser)
+	if err != nil {
+		return nil, fmt.Errorf("could not check privileges of %s on the schemas of role %s: %w", currentUser, roleName, err)
+	}
+	defer rows.Close()
+
+	var owners []string
+	for rows.Next() {
+		var owner string
+		if err := rows.Scan(&owner); err != nil {
+			return nil, fmt.Errorf("could not scan schema owner: %w", err)
+		}
+		owners = append(owners, owner)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return resolveOwners(txn, owners)
+}
+
 func resourcePostgreSQLRoleExists(db *DBConnection, d *schema.ResourceData) (bool, error) {
 	var roleName string
 	err := db.QueryRow("SELECT rolname FROM pg_catalog.pg_roles WHERE rolname=$1", d.Id()).Scan(&roleName)
@@ -384,7 +658,7 @@ func resourcePostgreSQLRoleReadImpl(db *DBConnection, d *schema.ResourceData) er
 	var roleSuperuser, roleInherit, roleCreateRole, roleCreateDB, roleCanLogin, roleReplication, roleBypassRLS bool
 	var roleConnLimit int
 	var roleName, roleValidUntil string
-	var roleRoles, roleConfig pq.ByteaArray
+	var roleRoles, roleMembers, roleConfig pq.ByteaArray
 
 	roleID := d.Id()
 
@@ -402,6 +676,7 @@ func resourcePostgreSQLRoleReadImpl(db *DBConnection, d *schema.ResourceData) er
 
 	values := []interface{}{
 		&roleRoles,
+		&roleMembers,
 		&roleName,
 		&roleSuperuser,
 		&roleInherit,
@@ -424,7 +699,9 @@ func resourcePostgreSQLRoleReadImpl(db *DBConnection, d *schema.ResourceData) er
 	}
 
 	roleSQL := fmt.Sprintf(`SELECT ARRAY(
-			SELECT pg_get_userbyid(roleid) FROM pg_catalog.pg_auth_members members WHERE member = pg_roles.oid
+			SELECT DISTINCT pg_get_userbyid(roleid) FROM pg_catalog.pg_auth_members members WHERE member = pg_roles.oid
+		), ARRAY(
+			SELECT DISTINCT pg_get_userbyid(member) FROM pg_catalog.pg_auth_members members WHERE roleid = pg_roles.oid
 		), %s
 		FROM pg_catalog.pg_roles WHERE rolname=$1`,
 		// select columns
@@ -442,20 +719,46 @@ func resourcePostgreSQLRoleReadImpl(db *DBConnection, d *schema.ResourceData) er
 	}
 
 	d.Set(roleNameAttr, roleName)
-	d.Set(roleConnLimitAttr, roleConnLimit)
+	// A connection limit of 0 is read as disable_connections, unless connection_limit = 0 is configured
+	// (deprecated for a role which can login, see roleSecurityWarnings).
+	if roleConnLimit == 0 && d.Get(roleConnLimitAttr).(int) != 0 {
+		d.Set(roleConnLimitAttr, -1)
+		d.Set(roleDisableConnectionsAttr, true)
+	} else {
+		d.Set(roleConnLimitAttr, roleConnLimit)
+		d.Set(roleDisableConnectionsAttr, false)
+	}
 	d.Set(roleCreateDBAttr, roleCreateDB)
 	d.Set(roleCreateRoleAttr, roleCreateRole)
 	d.Set(roleEncryptedPassAttr, true)
 	d.Set(roleInheritAttr, roleInherit)
 	d.Set(roleLoginAttr, roleCanLogin)
 	d.Set(roleSkipDropRoleAttr, d.Get(roleSkipDropRoleAttr).(bool))
+	d.Set(roleGeneratePgbouncerUserlistAttr, d.Get(roleGeneratePgbouncerUserlistAttr).(bool))
 	d.Set(roleSkipReassignOwnedAttr, d.Get(roleSkipReassignOwnedAttr).(bool))
+	d.Set(roleDropOwnedCascadeAttr, d.Get(roleDropOwnedCascadeAttr).(bool))
+	d.Set(roleTerminateSessionsAttr, d.Get(roleTerminateSessionsAttr).(bool))
 	d.Set(roleSuperuserAttr, roleSuperuser)
 	d.Set(roleValidUntilAttr, roleValidUntil)
 	d.Set(roleReplicationAttr, roleReplication)
 	d.Set(roleBypassRLSAttr, roleBypassRLS)
-	d.Set(roleRolesAttr, pgArrayToSet(roleRoles))
-	d.Set(roleSearchPathAttr, readSearchPath(roleConfig))
+	// The memberships are read in the attribute used to configure them.
+	if d.Get(roleMembershipAttr).(*schema.Set).Len() > 0 {
+		memberships, err := readRoleMemberships(db, d, roleName)
+		if err != nil {
+			return err
+		}
+		d.Set(roleMembershipAttr, memberships)
+		d.Set(roleRolesAttr, schema.NewSet(schema.HashString, nil))
+	} else {
+		d.Set(roleRolesAttr, pgArrayToSet(roleRoles))
+	}
+	d.Set(roleMembersAttr, pgArrayToSet(roleMembers))
+	if _, ok := d.GetOk(roleRawSearchPathAttr); ok {
+		d.Set(roleRawSearchPathAttr, readRawSearchPath(roleConfig))
+	} else {
+		d.Set(roleSearchPathAttr, readSearchPath(roleConfig))
+	}
 	d.Set(roleAssumeRoleAttr, readAssumeRole(roleConfig))
 
 	statementTimeout, err := readStatementTimeout(roleConfig)
@@ -480,23 +783,235 @@ func resourcePostgreSQLRoleReadImpl(db *DBConnection, d *schema.ResourceData) er
 	}
 
 	d.Set(rolePasswordAttr, password)
+
+	userlist, err := rolePgbouncerUserlist(db, d, roleName, roleCanLogin)
+	if err != nil {
+		return err
+	}
+
+	d.Set(rolePgbouncerUserlistAttr, userlist)
 	return nil
 }
 
+// rolePgbouncerUserlist returns the line of the role for the userlist.txt file of pgbouncer ("name" "hash")
+// if generate_pgbouncer_userlist is set. The password hash (MD5 or SCRAM-SHA-256) is read from pg_shadow if the
+// provider is connected as a superuser, it's otherwise the password of the state if it's already hashed, or its MD5
+// hash if the server stores the passwords as MD5 (a SCRAM-SHA-256 verifier would need the salt used by Postgres).
+// It's empty if the role cannot login, has no known password or a write-only password, which must not reach the state.
+func rolePgbouncerUserlist(db *DBConnection, d *schema.ResourceData, roleName string, roleCanLogin bool) (string, error) {
+	if !d.Get(roleGeneratePgbouncerUserlistAttr).(bool) || !roleCanLogin || d.Get(rolePasswordWOVersionAttr).(int) != 0 {
+		return "", nil
+	}
+
+	var passwordHash string
+	if db.client.config.Superuser {
+		superuser, err := db.isSuperuser()
+		if err != nil {
+			return "", err
+		}
+		if superuser {
+			err := db.QueryRow("SELECT COALESCE(passwd, '') FROM pg_catalog.pg_shadow AS s WHERE s.usename = $1", roleName).Scan(&passwordHash)
+			if err != nil && err != sql.ErrNoRows {
+				return "", fmt.Errorf("Error reading role password: %w", err)
+			}
+		}
+	}
+
+	if passwordHash == "" {
+		passwordHash = d.Get(rolePasswordAttr).(string)
+		if passwordHash == "" {
+			return "", nil
+		}
+		if !isHashedPassword(passwordHash) {
+			var passwordEncryption string
+			if err := db.QueryRow("SHOW password_encryption").Scan(&passwordEncryption); err != nil {
+				return "", fmt.Errorf("could not read password_encryption: %w", err)
+			}
+			// Before PostgreSQL 10, on means md5.
+			if passwordEncryption != "md5" && passwordEncryption != "on" {
+				return "", nil
+			}
+			passwordHash = md5RolePassword(passwordHash, roleName)
+		}
+	}
+
+	return pgbouncerQuote(roleName) + " " + pgbouncerQuote(passwordHash), nil
+}
+
+// pgbouncerQuote quotes a field of the userlist.txt file of pgbouncer, the double quotes are doubled.
+func pgbouncerQuote(field string) string {
+	return `"` + strings.ReplaceAll(field, `"`, `""`) + `"`
+}
+
+// md5RolePassword returns the MD5 hash of the password as stored by Postgres: md5 followed by
+// the MD5 of the password concatenated with the role name.
+func md5RolePassword(password, roleName string) string {
+	hash := md5.Sum([]byte(password + roleName))
+	return "md5" + hex.EncodeToString(hash[:])
+}
+
+// readRoleMemberships reads the roles granted to the role with the options of the memberships.
+// The grantor is only read for the memberships configured with granted_by, as it's otherwise
+// the role which ran the GRANT.
+// Since PostgreSQL 16, a role can be granted to the same member by several grantors: the grants are
+// read as one membership with the options of any of them, and the configured grantor if it's one of them.
+func readRoleMemberships(db *DBConnection, d *schema.ResourceData, roleName string) ([]interface{}, error) {
+	configuredGrantors := map[string]string{}
+	for _, m := range d.Get(roleMembershipAttr).(*schema.Set).List() {
+		membership := m.(map[string]interface{})
+		if grantor := membership["granted_by"].(string); grantor != "" {
+			configuredGrantors[membership["role"].(string)] = grantor
+		}
+	}
+
+	// inherit_option and set_option exist since PostgreSQL 16, before that a membership is always inherited
+	// (depending on the inherit attribute of the role) and allows to SET ROLE.
+	options := "true, true"
+	if db.featureSupported(featureRoleMembershipOptions) {
+		options = "bool_or(m.inherit_option), bool_or(m.set_option)"
+	}
+
+	rows, err := db.Query(fmt.Sprintf(`SELECT pg_get_userbyid(m.roleid), bool_or(m.admin_option), %s,
+			array_agg(pg_get_userbyid(m.grantor) ORDER BY pg_get_userbyid(m.grantor))
+		FROM pg_catalog.pg_auth_members m
+		JOIN pg_catalog.pg_roles r ON r.oid = m.member
+		WHERE r.rolname = $1
+		GROUP BY m.roleid`, options), roleName)
+	if err != nil {
+		return nil, fmt.Errorf("could not read memberships of role %s: %w", roleName, err)
+	}
+	defer rows.Close()
+
+	memberships := []interface{}{}
+	for rows.Next() {
+		var grantedRole string
+		var grantors pq.StringArray
+		var adminOption, inheritOption, setOption bool
+		if err := rows.Scan(&grantedRole, &adminOption, &inheritOption, &setOption, &grantors); err != nil {
+			return nil, fmt.Errorf("could not scan memberships of role %s: %w", roleName, err)
+		}
+
+		grantor := ""
+		if configured, ok := configuredGrantors[grantedRole]; ok {
+			grantor = grantors[0]
+			if sliceContainsStr(grantors, configured) {
+				grantor = configured
+			}
+		}
+		memberships = append(memberships, map[string]interface{}{
+			"role":           grantedRole,
+			"admin_option":   adminOption,
+			"inherit_option": inheritOption,
+			"set_option":     setOption,
+			"granted_by":     grantor,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return memberships, nil
+}
+
 // readSearchPath searches for a search_path entry in the rolconfig array.
 // In case no such value is present, it returns nil.
 func readSearchPath(roleConfig pq.ByteaArray) []string {
+	if searchPath := readRawSearchPath(roleConfig); searchPath != "" {
+		return parseSearchPath(searchPath)
+	}
+	return nil
+}
+
+// readRawSearchPath searches for a search_path entry in the rolconfig array
+// and returns it as stored by PostgreSQL (e.g.: `"$user", public`).
+// In case no such value is present, it returns an empty string.
+func readRawSearchPath(roleConfig pq.ByteaArray) string {
 	for _, v := range roleConfig {
 		config := string(v)
-		if strings.HasPrefix(config, roleSearchPathAttr) {
-			var result = strings.Split(strings.TrimPrefix(config, roleSearchPathAttr+"="), ", ")
-			for i := range result {
-				result[i] = strings.Trim(result[i], `"`)
-			}
-			return result
+		if strings.HasPrefix(config, roleSearchPathAttr+"=") {
+			return strings.TrimPrefix(config, roleSearchPathAttr+"=")
 		}
 	}
-	return nil
+	return ""
+}
+
+// parseSearchPath splits a search_path value into its elements.
+// Elements are separated by commas which are not in a quoted identifier
+// and quoted identifiers are unquoted (e.g.: `"$user", "a,b"` -> ["$user", "a,b"]).
+func parseSearchPath(searchPath string) []string {
+	var result []string
+	var current strings.Builder
+	inQuotes := false
+
+	for i := 0; i < len(searchPath); i++ {
+		c := searchPath[i]
+		switch {
+		case c == '"' && inQuotes && i+1 < len(searchPath) && searchPath[i+1] == '"':
+			// Escaped double quote in a quoted identifier
+			current.WriteByte('"')
+			i++
+		case c == '"':
+			inQuotes = !inQuotes
+		case c == ',' && !inQuotes:
+			result = append(result, current.String())
+			current.Reset()
+		case c == ' ' && !inQuotes:
+			// Spaces are not allowed in unquoted identifiers
+			continue
+		default:
+			current.WriteByte(c)
+		}
+	}
+
+	return append(result, current.String())
+}
+
+// quoteSearchPathElement quotes a search_path element.
+// Elements which are already correctly quoted (e.g.: `"$user"`) are kept as is.
+func quoteSearchPathElement(elem string) string {
+	if isQuotedIdentifier(elem) {
+		return elem
+	}
+	return pq.QuoteIdentifier(elem)
+}
+
+// isQuotedIdentifier returns true if the value is a single quoted identifier,
+// i.e. it is enclosed in double quotes and the double quotes inside are doubled.
+func isQuotedIdentifier(value string) bool {
+	if len(value) < 2 || !strings.HasPrefix(value, `"`) || !strings.HasSuffix(value, `"`) {
+		return false
+	}
+	inner := value[1 : len(value)-1]
+	return !strings.Contains(strings.ReplaceAll(inner, `""`, ""), `"`)
+}
+
+// validateSearchPathElement rejects the search_path elements which look quoted but are not
+// a single correctly quoted identifier (e.g.: `"$user` or `"a"b"`), as they would be sent as is.
+func validateSearchPathElement(v interface{}, key string) (warnings []string, errors []error) {
+	elem := v.(string)
+	if (strings.HasPrefix(elem, `"`) || strings.HasSuffix(elem, `"`)) && !isQuotedIdentifier(elem) {
+		errors = append(errors, fmt.Errorf(
+			"%s: %q is not correctly quoted, quote the whole element and double the quotes inside it (e.g.: %q)",
+			key, elem, `"a""b"`,
+		))
+	}
+	return
+}
+
+// searchPathDiffSuppressFunc compares two raw search_path values by their elements
+// as PostgreSQL normalizes the value (e.g.: `$user,public` is stored as `"$user", public`).
+func searchPathDiffSuppressFunc(k, old, new string, d *schema.ResourceData) bool {
+	oldPath := parseSearchPath(old)
+	newPath := parseSearchPath(new)
+	if len(oldPath) != len(newPath) {
+		return false
+	}
+	for i := range oldPath {
+		if oldPath[i] != newPath[i] {
+			return false
+		}
+	}
+	return true
 }
 
 // readIdleInTransactionSessionTimeout searches for an idle_in_transaction_session_timeout entry in the rolconfig array.
@@ -552,6 +1067,11 @@ func readAssumeRole(roleConfig pq.ByteaArray) string {
 func readRolePassword(db *DBConnection, d *schema.ResourceData, roleCanLogin bool) (string, error) {
 	statePassword := d.Get(rolePasswordAttr).(string)
 
+	// The write-only password is not in the state, there is nothing to compare it with.
+	if d.Get(rolePasswordWOVersionAttr).(int) != 0 {
+		return statePassword, nil
+	}
+
 	// Role which cannot login does not have password in pg_shadow.
 	// Also, if user specifies that admin is not a superuser we don't try to read pg_shadow
 	// (only superuser can read pg_shadow)
@@ -584,30 +1104,112 @@ func readRolePassword(db *DBConnection, d *schema.ResourceData, roleCanLogin boo
 	case err != nil:
 		return "", fmt.Errorf("Error reading role: %w", err)
 	}
-	// If the password isn't already in md5 format, but hashing the input
-	// matches the password in the database for the user, they are the same
-	if statePassword != "" && !strings.HasPrefix(statePassword, "md5") && !strings.HasPrefix(statePassword, "SCRAM-SHA-256") {
+	// If the password isn't already hashed, but hashing the input
+	// matches the password in the database for the user, they are the same.
+	// A hashed password is stored as is by Postgres so it's compared directly.
+	if statePassword != "" && !isHashedPassword(statePassword) {
 		if strings.HasPrefix(rolePassword, "md5") {
-			hasher := md5.New()
-			if _, err := hasher.Write([]byte(statePassword + d.Id())); err != nil {
-				return "", err
-			}
-			hashedPassword := "md5" + hex.EncodeToString(hasher.Sum(nil))
-
-			if hashedPassword == rolePassword {
+			if md5RolePassword(statePassword, d.Id()) == rolePassword {
 				// The passwords are actually the same
 				// make Terraform think they are the same
 				return statePassword, nil
 			}
 		}
 		if strings.HasPrefix(rolePassword, "SCRAM-SHA-256") {
-			return statePassword, nil
-			// TODO : implement scram-sha-256 challenge request to the server
+			matches, err := scramSHA256VerifierMatches(rolePassword, statePassword)
+			if err != nil {
+				// Don't report a drift for a verifier we can't check.
+				log.Printf("[WARN] could not check SCRAM-SHA-256 password of role %s: %v", d.Id(), err)
+				return statePassword, nil
+			}
+			if matches {
+				return statePassword, nil
+			}
 		}
 	}
 	return rolePassword, nil
 }
 
+// scramSHA256VerifierMatches checks if the password matches the SCRAM-SHA-256 verifier stored by Postgres
+// (SCRAM-SHA-256$<iterations>:<salt>$<StoredKey>:<ServerKey>) by computing the verifier keys with the same salt
+// and iterations, as described in RFC 5802.
+func scramSHA256VerifierMatches(verifier, password string) (bool, error) {
+	parts := strings.Split(strings.TrimPrefix(verifier, "SCRAM-SHA-256$"), "$")
+	if len(parts) != 2 {
+		return false, errors.New("invalid SCRAM-SHA-256 verifier format")
+	}
+	saltParts := strings.Split(parts[0], ":")
+	keyParts := strings.Split(parts[1], ":")
+	if len(saltParts) != 2 || len(keyParts) != 2 {
+		return false, errors.New("invalid SCRAM-SHA-256 verifier format")
+	}
+
+	iterations, err := strconv.Atoi(saltParts[0])
+	if err != nil || iterations <= 0 {
+		return false, fmt.Errorf("invalid SCRAM-SHA-256 iteration count %q", saltParts[0])
+	}
+	salt, err := base64.StdEncoding.DecodeString(saltParts[1])
+	if err != nil {
+		return false, fmt.Errorf("invalid SCRAM-SHA-256 salt: %w", err)
+	}
+	storedKey, err := base64.StdEncoding.DecodeString(keyParts[0])
+	if err != nil {
+		return false, fmt.Errorf("invalid SCRAM-SHA-256 stored key: %w", err)
+	}
+	serverKey, err := base64.StdEncoding.DecodeString(keyParts[1])
+	if err != nil {
+		return false, fmt.Errorf("invalid SCRAM-SHA-256 server key: %w", err)
+	}
+
+	// Postgres normalizes the passwords with SASLprep, which doesn't change the printable ASCII passwords.
+	// The other ones are not checked as we don't implement SASLprep.
+	for _, c := range password {
+		if c < 0x20 || c > 0x7e {
+			return false, errors.New("only printable ASCII passwords can be checked")
+		}
+	}
+
+	saltedPassword := pbkdf2.Key([]byte(password), salt, iterations, sha256.Size, sha256.New)
+	clientKey := scramHMAC(saltedPassword, "Client Key")
+	computedStoredKey := sha256.Sum256(clientKey)
+	computedServerKey := scramHMAC(saltedPassword, "Server Key")
+
+	return hmac.Equal(computedStoredKey[:], storedKey) && hmac.Equal(computedServerKey, serverKey), nil
+}
+
+func scramHMAC(key []byte, message string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(message))
+	return mac.Sum(nil)
+}
+
+var (
+	md5PasswordRegexp   = regexp.MustCompile(`^md5[0-9a-f]{32}$`)
+	scramPasswordRegexp = regexp.MustCompile(`^SCRAM-SHA-256\$[0-9]+:[A-Za-z0-9+/]+={0,2}\$[A-Za-z0-9+/]+={0,2}:[A-Za-z0-9+/]+={0,2}$`)
+)
+
+// isHashedPassword returns true if the password is a md5 hash or a SCRAM-SHA-256 verifier,
+// which Postgres stores as is instead of hashing it.
+func isHashedPassword(password string) bool {
+	return md5PasswordRegexp.MatchString(password) || scramPasswordRegexp.MatchString(password)
+}
+
+// validateRolePassword rejects the passwords which look like hashes but are not recognized by Postgres,
+// they would be hashed as plain text passwords and the role could not log in with the expected password.
+func validateRolePassword(v interface{}, key string) ([]string, []error) {
+	password := v.(string)
+	if isHashedPassword(password) {
+		return nil, nil
+	}
+	if strings.HasPrefix(password, "SCRAM-SHA-256$") {
+		return nil, []error{fmt.Errorf("%s is not a valid SCRAM-SHA-256 verifier (SCRAM-SHA-256$<iterations>:<salt>$<StoredKey>:<ServerKey>)", key)}
+	}
+	if len(password) == len("md5")+32 && strings.HasPrefix(password, "md5") && md5PasswordRegexp.MatchString(strings.ToLower(password)) {
+		return nil, []error{fmt.Errorf("%s looks like a md5 hash but Postgres only recognizes them in lower case", key)}
+	}
+	return nil, nil
+}
+
 func resourcePostgreSQLRoleUpdate(db *DBConnection, d *schema.ResourceData) error {
 	txn, err := startTransaction(db.client, "")
 	if err != nil {
@@ -669,7 +1271,7 @@ func resourcePostgreSQLRoleUpdate(db *DBConnection, d *schema.ResourceData) erro
 		return err
 	}
 
-	if err = grantRoles(txn, d); err != nil {
+	if err = grantRoles(db, txn, d); err != nil {
 		return err
 	}
 
@@ -693,9 +1295,34 @@ func resourcePostgreSQLRoleUpdate(db *DBConnection, d *schema.ResourceData) erro
 		return fmt.Errorf("could not commit transaction: %w", err)
 	}
 
+	if err = terminateRoleSessions(db, d); err != nil {
+		return err
+	}
+
 	return resourcePostgreSQLRoleReadImpl(db, d)
 }
 
+// terminateRoleSessions terminates the sessions of the role if its login has just been disabled
+// and terminate_sessions_on_disable_login is set.
+// It's called once NOLOGIN is committed: until then, the role could still open new sessions.
+func terminateRoleSessions(db *DBConnection, d *schema.ResourceData) error {
+	if !d.HasChange(roleLoginAttr) || d.Get(roleLoginAttr).(bool) || !d.Get(roleTerminateSessionsAttr).(bool) {
+		return nil
+	}
+
+	pid := "procpid"
+	if db.featureSupported(featurePid) {
+		pid = "pid"
+	}
+	roleName := d.Get(roleNameAttr).(string)
+	sql := fmt.Sprintf("SELECT pg_terminate_backend(%s) FROM pg_stat_activity WHERE usename = $1 AND %s <> pg_backend_pid()", pid, pid)
+	if _, err := db.Exec(sql, roleName); err != nil {
+		return fmt.Errorf("could not terminate sessions of role %s: %w", roleName, err)
+	}
+
+	return nil
+}
+
 func setRoleName(txn *sql.Tx, d *schema.ResourceData) error {
 	if !d.HasChange(roleNameAttr) {
 		return nil
@@ -719,15 +1346,27 @@ func setRoleName(txn *sql.Tx, d *schema.ResourceData) error {
 }
 
 func setRolePassword(txn *sql.Tx, d *schema.ResourceData) error {
+	if d.Get(rolePasswordWOVersionAttr).(int) != 0 {
+		return setRolePasswordWO(txn, d)
+	}
+
 	// If role is renamed, password is reset (as the md5 sum is also base on the role name)
-	// so we need to update it
-	if !d.HasChange(rolePasswordAttr) && !d.HasChange(roleNameAttr) {
+	// so we need to update it. A change of password_trigger sets the same password again.
+	if !d.HasChanges(rolePasswordAttr, roleNameAttr, rolePasswordTriggerAttr) {
 		return nil
 	}
 
 	roleName := d.Get(roleNameAttr).(string)
 	password := d.Get(rolePasswordAttr).(string)
 
+	// The md5 hash is salted with the role name, setting it again after a rename would set another password.
+	if d.HasChange(roleNameAttr) && !d.HasChange(rolePasswordAttr) && md5PasswordRegexp.MatchString(password) {
+		return fmt.Errorf(
+			"the md5 hashed password of role %s is computed with its name, a new hash has to be set when renaming it",
+			roleName,
+		)
+	}
+
 	sql := fmt.Sprintf("ALTER ROLE %s PASSWORD '%s'", pq.QuoteIdentifier(roleName), pqQuoteLiteral(password))
 	if _, err := txn.Exec(sql); err != nil {
 		return fmt.Errorf("Error updating role password: %w", err)
@@ -735,6 +1374,40 @@ func setRolePassword(txn *sql.Tx, d *schema.ResourceData) error {
 	return nil
 }
 
+// setRolePasswordWO sets the write-only password when its version changes, or when the role is renamed
+// as the md5 hash of the password is salted with the role name.
+func setRolePasswordWO(txn *sql.Tx, d *schema.ResourceData) error {
+	if !d.HasChange(rolePasswordWOVersionAttr) && !d.HasChange(roleNameAttr) {
+		return nil
+	}
+
+	roleName := d.Get(roleNameAttr).(string)
+	password := rolePasswordWO(d)
+	if password == "" {
+		return fmt.Errorf("%s of role %s is not known, it has to be set with %s", rolePasswordWOAttr, roleName, rolePasswordWOVersionAttr)
+	}
+
+	sql := fmt.Sprintf("ALTER ROLE %s PASSWORD '%s'", pq.QuoteIdentifier(roleName), pqQuoteLiteral(password))
+	if _, err := txn.Exec(sql); err != nil {
+		return fmt.Errorf("Error updating role password: %w", err)
+	}
+	return nil
+}
+
+// rolePasswordWO returns the write-only password. It's read from the configuration
+// as the state only contains an empty string.
+func rolePasswordWO(d *schema.ResourceData) string {
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() {
+		return ""
+	}
+	password := config.GetAttr(rolePasswordWOAttr)
+	if password.IsNull() || !password.IsKnown() {
+		return ""
+	}
+	return password.AsString()
+}
+
 func setRoleBypassRLS(db *DBConnection, txn *sql.Tx, d *schema.ResourceData) error {
 	if !d.HasChange(roleBypassRLSAttr) {
 		return nil
@@ -758,12 +1431,49 @@ func setRoleBypassRLS(db *DBConnection, txn *sql.Tx, d *schema.ResourceData) err
 	return nil
 }
 
+// roleConnLimit returns the CONNECTION LIMIT of the role: 0 if its connections are disabled, connection_limit otherwise.
+func roleConnLimit(d *schema.ResourceData) int {
+	if d.Get(roleDisableConnectionsAttr).(bool) {
+		return 0
+	}
+	return d.Get(roleConnLimitAttr).(int)
+}
+
+// resourcePostgreSQLRoleCustomizeDiff plans the security warnings of the role (see roleSecurityWarnings), which
+// include the deprecated connection limit of 0 for a role which can login, and fails the plan if connection_limit
+// is set with disable_connections.
+func resourcePostgreSQLRoleCustomizeDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.HasChange(roleGeneratePgbouncerUserlistAttr) {
+		if err := d.SetNewComputed(rolePgbouncerUserlistAttr); err != nil {
+			return err
+		}
+	}
+
+	if err := customizeDiffSecurityWarnings(
+		d, meta, roleSecurityWarnings,
+		roleNameAttr, roleLoginAttr, roleValidUntilAttr, rolePasswordAttr, rolePasswordWOVersionAttr, roleSuperuserAttr,
+	); err != nil {
+		return err
+	}
+
+	for _, attr := range []string{roleConnLimitAttr, roleDisableConnectionsAttr, roleLoginAttr} {
+		if !d.NewValueKnown(attr) {
+			return nil
+		}
+	}
+
+	if d.Get(roleDisableConnectionsAttr).(bool) && d.Get(roleConnLimitAttr).(int) != -1 {
+		return fmt.Errorf("%s can't be set if %s is true", roleConnLimitAttr, roleDisableConnectionsAttr)
+	}
+	return nil
+}
+
 func setRoleConnLimit(txn *sql.Tx, d *schema.ResourceData) error {
-	if !d.HasChange(roleConnLimitAttr) {
+	if !d.HasChanges(roleConnLimitAttr, roleDisableConnectionsAttr) {
 		return nil
 	}
 
-	connLimit := d.Get(roleConnLimitAttr).(int)
+	connLimit := roleConnLimit(d)
 	roleName := d.Get(roleNameAttr).(string)
 	sql := fmt.Sprintf("ALTER ROLE %s CONNECTION LIMIT %d", pq.QuoteIdentifier(roleName), connLimit)
 	if _, err := txn.Exec(sql); err != nil {
@@ -911,7 +1621,17 @@ func setRoleValidUntil(txn *sql.Tx, d *schema.ResourceData) error {
 func revokeRoles(txn *sql.Tx, d *schema.ResourceData) error {
 	role := d.Get(roleNameAttr).(string)
 
-	query := `SELECT pg_get_userbyid(roleid)
+	// The memberships granted with an explicit grantor have to be revoked with the same grantor.
+	grantors := map[string]string{}
+	oldMemberships, _ := d.GetChange(roleMembershipAttr)
+	for _, m := range oldMemberships.(*schema.Set).List() {
+		membership := m.(map[string]interface{})
+		if grantor := membership["granted_by"].(string); grantor != "" {
+			grantors[membership["role"].(string)] = grantor
+		}
+	}
+
+	query := `SELECT DISTINCT pg_get_userbyid(roleid)
 		FROM pg_catalog.pg_auth_members members
 		JOIN pg_catalog.pg_roles ON members.member = pg_roles.oid
 		WHERE rolname = $1`
@@ -937,6 +1657,9 @@ func revokeRoles(txn *sql.Tx, d *schema.ResourceData) error {
 
 	for _, grantedRole := range grantedRoles {
 		query = fmt.Sprintf("REVOKE %s FROM %s", pq.QuoteIdentifier(grantedRole), pq.QuoteIdentifier(role))
+		if grantor, ok := grantors[grantedRole]; ok {
+			query += " GRANTED BY " + pq.QuoteIdentifier(grantor)
+		}
 
 		log.Printf("[DEBUG] revoking role %s from %s", grantedRole, role)
 		if _, err := txn.Exec(query); err != nil {
@@ -947,7 +1670,7 @@ func revokeRoles(txn *sql.Tx, d *schema.ResourceData) error {
 	return nil
 }
 
-func grantRoles(txn *sql.Tx, d *schema.ResourceData) error {
+func grantRoles(db *DBConnection, txn *sql.Tx, d *schema.ResourceData) error {
 	role := d.Get(roleNameAttr).(string)
 
 	for _, grantingRole := range d.Get("roles").(*schema.Set).List() {
@@ -958,21 +1681,69 @@ func grantRoles(txn *sql.Tx, d *schema.ResourceData) error {
 			return fmt.Errorf("could not grant role %s to %s: %w", grantingRole, role, err)
 		}
 	}
+
+	return grantRoleMemberships(db, txn, d)
+}
+
+// grantRoleMemberships grants the roles of the membership blocks with their options.
+func grantRoleMemberships(db *DBConnection, txn *sql.Tx, d *schema.ResourceData) error {
+	role := d.Get(roleNameAttr).(string)
+
+	withOptions := db.featureSupported(featureRoleMembershipOptions)
+	for _, m := range d.Get(roleMembershipAttr).(*schema.Set).List() {
+		membership := m.(map[string]interface{})
+		query, err := roleMembershipGrantQuery(role, membership, withOptions)
+		if err != nil {
+			return err
+		}
+		if _, err := txn.Exec(query); err != nil {
+			return fmt.Errorf("could not grant role %s to %s: %w", membership["role"], role, err)
+		}
+	}
 	return nil
 }
 
+// roleMembershipGrantQuery returns the GRANT query of a membership block.
+// The INHERIT and SET options can only be set with PostgreSQL >= 16 (withOptions).
+func roleMembershipGrantQuery(role string, membership map[string]interface{}, withOptions bool) (string, error) {
+	grantedRole := membership["role"].(string)
+	adminOption := membership["admin_option"].(bool)
+	inheritOption := membership["inherit_option"].(bool)
+	setOption := membership["set_option"].(bool)
+
+	query := fmt.Sprintf("GRANT %s TO %s", pq.QuoteIdentifier(grantedRole), pq.QuoteIdentifier(role))
+	switch {
+	case withOptions:
+		query += fmt.Sprintf(
+			" WITH ADMIN %s, INHERIT %s, SET %s",
+			strings.ToUpper(strconv.FormatBool(adminOption)),
+			strings.ToUpper(strconv.FormatBool(inheritOption)),
+			strings.ToUpper(strconv.FormatBool(setOption)),
+		)
+	case !inheritOption || !setOption:
+		return "", fmt.Errorf("inherit_option and set_option of the membership of %s require PostgreSQL 16 or later", grantedRole)
+	case adminOption:
+		query += " WITH ADMIN OPTION"
+	}
+
+	if grantor := membership["granted_by"].(string); grantor != "" {
+		query += " GRANTED BY " + pq.QuoteIdentifier(grantor)
+	}
+
+	return query, nil
+}
+
 func alterSearchPath(txn *sql.Tx, d *schema.ResourceData) error {
 	role := d.Get(roleNameAttr).(string)
 	searchPathInterface := d.Get(roleSearchPathAttr).([]interface{})
 
 	var searchPathString []string
-	if len(searchPathInterface) > 0 {
+	if rawSearchPath := d.Get(roleRawSearchPathAttr).(string); rawSearchPath != "" {
+		searchPathString = []string{rawSearchPath}
+	} else if len(searchPathInterface) > 0 {
 		searchPathString = make([]string, len(searchPathInterface))
 		for i, searchPathPart := range searchPathInterface {
-			if strings.Contains(searchPathPart.(string), ", ") {
-				return fmt.Errorf("search_path cannot contain `, `: %v", searchPathPart)
-			}
-			searchPathString[i] = pq.QuoteIdentifier(searchPathPart.(string))
+			searchPathString[i] = quoteSearchPathElement(searchPathPart.(string))
 		}
 	} else {
 		searchPathString = []string{"DEFAULT"}
@@ -0,0 +1,372 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	roleDBSettingsRoleAttr             = "role"
+	roleDBSettingsDatabasesAttr        = "databases"
+	roleDBSettingsSettingsAttr         = "settings"
+	roleDBSettingsDatabaseSettingsAttr = "database_settings"
+)
+
+func resourcePostgreSQLRoleDatabaseSettings() *schema.Resource {
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLRoleDatabaseSettingsCreate),
+		Read:   PGReadResourceFunc(resourcePostgreSQLRoleDatabaseSettingsRead),
+		Update: PGResourceFunc(resourcePostgreSQLRoleDatabaseSettingsUpdate),
+		Delete: PGResourceFunc(resourcePostgreSQLRoleDatabaseSettingsDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			roleDBSettingsRoleAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The role to set the configuration parameters for",
+			},
+			roleDBSettingsDatabasesAttr: {
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    1,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "The databases in which the configuration parameters are set for the role",
+			},
+			roleDBSettingsSettingsAttr: {
+				Type:             schema.TypeMap,
+				Required:         true,
+				Elem:             &schema.Schema{Type: schema.TypeString},
+				ValidateDiagFunc: validation.MapKeyMatch(dbSettingNameRegexp, "must be the name of a configuration parameter (e.g.: work_mem)"),
+				Description:      "The configuration parameters set for the role in each database, with their value as it's stored by PostgreSQL (e.g.: 64MB)",
+			},
+			roleDBSettingsDatabaseSettingsAttr: {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"database": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"value": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+				Description: "The value of each configuration parameter in each database, sorted by database and name",
+			},
+		},
+	}
+}
+
+func resourcePostgreSQLRoleDatabaseSettingsCreate(db *DBConnection, d *schema.ResourceData) error {
+	if err := setRoleDatabaseSettings(db, d); err != nil {
+		return err
+	}
+
+	d.SetId(generateRoleDatabaseSettingsID(d))
+
+	return resourcePostgreSQLRoleDatabaseSettingsReadImpl(db, d)
+}
+
+// generateRoleDatabaseSettingsID returns the ID of the settings: the role followed by the sorted databases,
+// so two resources can set parameters of the same role in distinct databases.
+func generateRoleDatabaseSettingsID(d *schema.ResourceData) string {
+	return generateObjectID(append(
+		[]string{d.Get(roleDBSettingsRoleAttr).(string)},
+		setToSortedSlice(d.Get(roleDBSettingsDatabasesAttr).(*schema.Set))...,
+	)...)
+}
+
+func resourcePostgreSQLRoleDatabaseSettingsRead(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLRoleDatabaseSettingsReadImpl(db, d)
+}
+
+func resourcePostgreSQLRoleDatabaseSettingsReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	parts, err := parseObjectID(d.Id())
+	if err != nil {
+		return err
+	}
+	if len(parts) < 2 {
+		return fmt.Errorf("role database settings ID %s has not the expected format 'role.database[.database...]'", d.Id())
+	}
+	role := parts[0]
+	// The role and the databases are only set from the ID when importing.
+	databasesSet := d.Get(roleDBSettingsDatabasesAttr).(*schema.Set)
+	if databasesSet.Len() == 0 {
+		databasesSet = stringSliceToSet(parts[1:])
+	}
+
+	txn, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	exists, err := roleExists(txn, role)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		log.Printf("[WARN] PostgreSQL role (%s) not found, removing role database settings from state", role)
+		d.SetId("")
+		return nil
+	}
+
+	// The databases which have been dropped are removed from the state.
+	databases := []string{}
+	for _, database := range setToSortedSlice(databasesSet) {
+		exists, err := dbExists(txn, database)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			log.Printf("[WARN] PostgreSQL database (%s) not found, removing it from the database settings of role %s", database, role)
+			continue
+		}
+		databases = append(databases, database)
+	}
+	if len(databases) == 0 {
+		log.Printf("[WARN] No database of the role database settings (%s) found, removing them from state", role)
+		d.SetId("")
+		return nil
+	}
+
+	actual, err := readRoleDatabaseSettings(txn, role, databases)
+	if err != nil {
+		return err
+	}
+
+	// All the parameters of the role in the databases are imported.
+	expected := d.Get(roleDBSettingsSettingsAttr).(map[string]interface{})
+	if len(expected) == 0 {
+		for _, database := range databases {
+			for name, value := range actual[database] {
+				expected[name] = value
+			}
+		}
+	}
+
+	settings, databaseSettings := diffRoleDatabaseSettings(expected, databases, actual)
+
+	d.Set(roleDBSettingsRoleAttr, role)
+	d.Set(roleDBSettingsDatabasesAttr, databases)
+	d.Set(roleDBSettingsSettingsAttr, settings)
+	d.Set(roleDBSettingsDatabaseSettingsAttr, databaseSettings)
+
+	return nil
+}
+
+// diffRoleDatabaseSettings returns the managed settings as they are in all the databases and the value of each
+// of them in each database. A setting which has not the expected value in one of the databases is returned with
+// this value (or not returned if it's not set in the database), so it's applied again to all the databases.
+// actual contains the settings by database, with the names in lower case as they are stored by PostgreSQL.
+func diffRoleDatabaseSettings(expected map[string]interface{}, databases []string, actual map[string]map[string]string) (map[string]interface{}, []interface{}) {
+	names := mapKeys(expected)
+
+	settings := map[string]interface{}{}
+	databaseSettings := []interface{}{}
+	for _, name := range names {
+		value, found := expected[name].(string), true
+		for _, database := range databases {
+			actualValue, ok := actual[database][strings.ToLower(name)]
+			if !ok {
+				found = false
+				continue
+			}
+			// The lists are stored as "a, b", whatever their formatting in the configuration.
+			if found && functionSettingValue(actualValue) != functionSettingValue(value) {
+				value = actualValue
+			}
+		}
+		if found {
+			settings[name] = value
+		}
+	}
+
+	for _, database := range databases {
+		for _, name := range names {
+			value, ok := actual[database][strings.ToLower(name)]
+			if !ok {
+				continue
+			}
+			databaseSettings = append(databaseSettings, map[string]interface{}{
+				"database": database,
+				"name":     name,
+				"value":    value,
+			})
+		}
+	}
+
+	return settings, databaseSettings
+}
+
+// readRoleDatabaseSettings reads the settings of the role in each of the databases in one query.
+func readRoleDatabaseSettings(txn *sql.Tx, role string, databases []string) (map[string]map[string]string, error) {
+	rows, err := txn.Query(`
+SELECT d.datname, unnest(s.setconfig)
+FROM pg_catalog.pg_db_role_setting s
+JOIN pg_catalog.pg_database d ON d.oid = s.setdatabase
+JOIN pg_catalog.pg_roles r ON r.oid = s.setrole
+WHERE r.rolname = $1 AND d.datname = ANY($2)
+`, role, pq.Array(databases))
+	if err != nil {
+		return nil, fmt.Errorf("could not read the database settings of role %s: %w", role, err)
+	}
+	defer rows.Close()
+
+	settings := map[string]map[string]string{}
+	for rows.Next() {
+		var database, setting string
+		if err := rows.Scan(&database, &setting); err != nil {
+			return nil, fmt.Errorf("could not scan the database settings of role %s: %w", role, err)
+		}
+		name, value, found := strings.Cut(setting, "=")
+		if !found {
+			continue
+		}
+		if _, ok := settings[database]; !ok {
+			settings[database] = map[string]string{}
+		}
+		settings[database][name] = value
+	}
+
+	return settings, rows.Err()
+}
+
+func resourcePostgreSQLRoleDatabaseSettingsUpdate(db *DBConnection, d *schema.ResourceData) error {
+	if err := setRoleDatabaseSettings(db, d); err != nil {
+		return err
+	}
+	d.SetId(generateRoleDatabaseSettingsID(d))
+
+	return resourcePostgreSQLRoleDatabaseSettingsReadImpl(db, d)
+}
+
+func resourcePostgreSQLRoleDatabaseSettingsDelete(db *DBConnection, d *schema.ResourceData) error {
+	role := d.Get(roleDBSettingsRoleAttr).(string)
+
+	txn, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	names := mapKeys(d.Get(roleDBSettingsSettingsAttr).(map[string]interface{}))
+	for _, database := range setToSortedSlice(d.Get(roleDBSettingsDatabasesAttr).(*schema.Set)) {
+		if err := resetRoleDatabaseSettings(txn, role, database, names); err != nil {
+			return err
+		}
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// setRoleDatabaseSettings sets the configuration parameters for the role in all the databases
+// and resets the ones which are not managed anymore, in one transaction.
+// The names are validated by dbSettingNameRegexp as they can't be sent as parameters of the queries.
+func setRoleDatabaseSettings(db *DBConnection, d *schema.ResourceData) error {
+	role := d.Get(roleDBSettingsRoleAttr).(string)
+
+	txn, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	oldDatabasesRaw, newDatabasesRaw := d.GetChange(roleDBSettingsDatabasesAttr)
+	oldSettingsRaw, newSettingsRaw := d.GetChange(roleDBSettingsSettingsAttr)
+	oldDatabases, newDatabases := oldDatabasesRaw.(*schema.Set), newDatabasesRaw.(*schema.Set)
+	oldSettings, newSettings := oldSettingsRaw.(map[string]interface{}), newSettingsRaw.(map[string]interface{})
+
+	// The settings are not managed anymore in the removed databases.
+	for _, database := range setToSortedSlice(oldDatabases.Difference(newDatabases)) {
+		if err := resetRoleDatabaseSettings(txn, role, database, mapKeys(oldSettings)); err != nil {
+			return err
+		}
+	}
+
+	removedSettings := []string{}
+	for _, name := range mapKeys(oldSettings) {
+		if _, ok := newSettings[name]; !ok {
+			removedSettings = append(removedSettings, name)
+		}
+	}
+
+	for _, database := range setToSortedSlice(newDatabases) {
+		if err := resetRoleDatabaseSettings(txn, role, database, removedSettings); err != nil {
+			return err
+		}
+		for _, name := range mapKeys(newSettings) {
+			if _, err := txn.Exec(fmt.Sprintf(
+				"ALTER ROLE %s IN DATABASE %s SET %s TO %s",
+				pq.QuoteIdentifier(role), pq.QuoteIdentifier(database), name, functionSettingValue(newSettings[name].(string)),
+			)); err != nil {
+				return fmt.Errorf("could not set %s of role %s in database %s: %w", name, role, database, err)
+			}
+		}
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+	return nil
+}
+
+// resetRoleDatabaseSettings resets the configuration parameters of the role in the database,
+// nothing is done if the database has been dropped.
+func resetRoleDatabaseSettings(txn *sql.Tx, role, database string, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	exists, err := dbExists(txn, database)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return nil
+	}
+
+	for _, name := range names {
+		if _, err := txn.Exec(fmt.Sprintf(
+			"ALTER ROLE %s IN DATABASE %s RESET %s", pq.QuoteIdentifier(role), pq.QuoteIdentifier(database), name,
+		)); err != nil {
+			return fmt.Errorf("could not reset %s of role %s in database %s: %w", name, role, database, err)
+		}
+	}
+	return nil
+}
+
+// mapKeys returns the sorted keys of a map attribute.
+func mapKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
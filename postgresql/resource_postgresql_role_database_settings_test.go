@@ -0,0 +1,168 @@
+package postgresql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccPostgresqlRoleDatabaseSettings_Basic(t *testing.T) {
+	config := `
+resource "postgresql_role" "test" {
+  name = "tf_tests_role_db_settings"
+}
+
+resource "postgresql_database" "tenant_1" {
+  name = "tf_tests_role_db_settings_1"
+}
+
+resource "postgresql_database" "tenant_2" {
+  name = "tf_tests_role_db_settings_2"
+}
+
+resource "postgresql_role_database_settings" "test" {
+  role      = postgresql_role.test.name
+  databases = [%s]
+  settings  = {
+    work_mem          = "%s"
+    statement_timeout = "30s"
+    search_path       = "app,public"
+  }
+}
+`
+	bothDatabases := "postgresql_database.tenant_1.name, postgresql_database.tenant_2.name"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckPostgresqlRoleDestroy,
+			testAccCheckPostgresqlDatabaseDestroy,
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, bothDatabases, "64MB"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_role_database_settings.test", "databases.#", "2"),
+					resource.TestCheckResourceAttr("postgresql_role_database_settings.test", "settings.work_mem", "64MB"),
+					resource.TestCheckResourceAttr(
+						"postgresql_role_database_settings.test", "id",
+						"tf_tests_role_db_settings.tf_tests_role_db_settings_1.tf_tests_role_db_settings_2",
+					),
+					resource.TestCheckResourceAttr("postgresql_role_database_settings.test", "database_settings.#", "6"),
+					testAccCheckRoleDatabaseSetting("tf_tests_role_db_settings", "tf_tests_role_db_settings_1", "work_mem", "64MB"),
+					testAccCheckRoleDatabaseSetting("tf_tests_role_db_settings", "tf_tests_role_db_settings_2", "work_mem", "64MB"),
+					testAccCheckRoleDatabaseSetting("tf_tests_role_db_settings", "tf_tests_role_db_settings_2", "statement_timeout", "30s"),
+					testAccCheckRoleDatabaseSetting("tf_tests_role_db_settings", "tf_tests_role_db_settings_2", "search_path", "app, public"),
+				),
+			},
+			{
+				ResourceName:      "postgresql_role_database_settings.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// The lists are imported as PostgreSQL stores them.
+				ImportStateVerifyIgnore: []string{"settings.search_path"},
+			},
+			// The drift of one database is detected and the settings are applied again.
+			{
+				PreConfig: func() {
+					testConfig := getTestConfig(t)
+					dbExecute(t, testConfig.connStr("postgres"),
+						"ALTER ROLE tf_tests_role_db_settings IN DATABASE tf_tests_role_db_settings_2 SET work_mem TO '1MB'",
+					)
+				},
+				Config:             fmt.Sprintf(config, bothDatabases, "64MB"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: fmt.Sprintf(config, bothDatabases, "128MB"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_role_database_settings.test", "settings.work_mem", "128MB"),
+					testAccCheckRoleDatabaseSetting("tf_tests_role_db_settings", "tf_tests_role_db_settings_1", "work_mem", "128MB"),
+					testAccCheckRoleDatabaseSetting("tf_tests_role_db_settings", "tf_tests_role_db_settings_2", "work_mem", "128MB"),
+				),
+			},
+			// The settings are reset in the database removed from the list.
+			{
+				Config: fmt.Sprintf(config, "postgresql_database.tenant_1.name", "128MB"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_role_database_settings.test", "databases.#", "1"),
+					resource.TestCheckResourceAttr("postgresql_role_database_settings.test", "id", "tf_tests_role_db_settings.tf_tests_role_db_settings_1"),
+					resource.TestCheckResourceAttr("postgresql_role_database_settings.test", "database_settings.#", "3"),
+					testAccCheckRoleDatabaseSetting("tf_tests_role_db_settings", "tf_tests_role_db_settings_1", "work_mem", "128MB"),
+					testAccCheckRoleDatabaseSetting("tf_tests_role_db_settings", "tf_tests_role_db_settings_2", "work_mem", ""),
+					testAccCheckRoleDatabaseSetting("tf_tests_role_db_settings", "tf_tests_role_db_settings_2", "statement_timeout", ""),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckRoleDatabaseSetting checks the value of a configuration parameter of the role in the database,
+// an empty value means that it is not set.
+func testAccCheckRoleDatabaseSetting(role, database, name, expected string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*Client)
+		db, err := client.Connect()
+		if err != nil {
+			return err
+		}
+
+		settings, err := readDBRoleSettings(db, role, database)
+		if err != nil {
+			return fmt.Errorf("could not read the settings of role %s in database %s: %w", role, database, err)
+		}
+
+		if settings[name] != expected {
+			return fmt.Errorf("expected %s of role %s in database %s to be %q, got %q", name, role, database, expected, settings[name])
+		}
+		return nil
+	}
+}
+
+func TestDiffRoleDatabaseSettings(t *testing.T) {
+	expected := map[string]interface{}{
+		"Work_Mem":          "64MB",
+		"statement_timeout": "30s",
+		"search_path":       "app",
+	}
+	databases := []string{"tenant_1", "tenant_2"}
+	actual := map[string]map[string]string{
+		"tenant_1": {"work_mem": "64MB", "statement_timeout": "30s", "search_path": "app", "lock_timeout": "5s"},
+		"tenant_2": {"work_mem": "64MB", "statement_timeout": "1min"},
+	}
+
+	settings, databaseSettings := diffRoleDatabaseSettings(expected, databases, actual)
+
+	// statement_timeout drifted in tenant_2 and search_path is missing from it, the unmanaged lock_timeout is ignored.
+	assert.Equal(t, map[string]interface{}{"Work_Mem": "64MB", "statement_timeout": "1min"}, settings)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"database": "tenant_1", "name": "Work_Mem", "value": "64MB"},
+		map[string]interface{}{"database": "tenant_1", "name": "search_path", "value": "app"},
+		map[string]interface{}{"database": "tenant_1", "name": "statement_timeout", "value": "30s"},
+		map[string]interface{}{"database": "tenant_2", "name": "Work_Mem", "value": "64MB"},
+		map[string]interface{}{"database": "tenant_2", "name": "statement_timeout", "value": "1min"},
+	}, databaseSettings)
+
+	// The lists are compared whatever their formatting.
+	settings, _ = diffRoleDatabaseSettings(
+		map[string]interface{}{"search_path": `"$user",app`},
+		[]string{"tenant_1"},
+		map[string]map[string]string{"tenant_1": {"search_path": `"$user", app`}},
+	)
+	assert.Equal(t, map[string]interface{}{"search_path": `"$user",app`}, settings)
+}
+
+func TestGenerateRoleDatabaseSettingsID(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourcePostgreSQLRoleDatabaseSettings().Schema, map[string]interface{}{
+		"role":      "app",
+		"databases": []interface{}{"tenant_2", "tenant.1"},
+		"settings":  map[string]interface{}{"work_mem": "64MB"},
+	})
+	assert.Equal(t, `app."tenant.1".tenant_2`, generateRoleDatabaseSettingsID(d))
+}
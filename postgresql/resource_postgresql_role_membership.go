@@ -0,0 +1,165 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
+)
+
+const (
+	roleMembershipRoleAttr        = "role"
+	roleMembershipMemberAttr      = "member"
+	roleMembershipAdminOptionAttr = "admin_option"
+)
+
+// resourcePostgreSQLRoleMembership manages a membership like postgresql_grant_role, from the side of the group role:
+// its role is the granted role (grant_role of postgresql_grant_role) and its member the role which is granted it.
+func resourcePostgreSQLRoleMembership() *schema.Resource {
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLRoleMembershipCreate),
+		Read:   PGReadResourceFunc(resourcePostgreSQLRoleMembershipRead),
+		Update: PGResourceFunc(resourcePostgreSQLRoleMembershipUpdate),
+		Delete: PGResourceFunc(resourcePostgreSQLRoleMembershipDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			roleMembershipRoleAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The role (group) in which member is granted a membership",
+			},
+			roleMembershipMemberAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The role which is granted the membership in role",
+			},
+			roleMembershipAdminOptionAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Permit member to grant the membership in role to others",
+			},
+		},
+	}
+}
+
+func resourcePostgreSQLRoleMembershipCreate(db *DBConnection, d *schema.ResourceData) error {
+	role := d.Get(roleMembershipRoleAttr).(string)
+	member := d.Get(roleMembershipMemberAttr).(string)
+
+	txn, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	query := fmt.Sprintf("GRANT %s TO %s", pq.QuoteIdentifier(role), pq.QuoteIdentifier(member))
+	if d.Get(roleMembershipAdminOptionAttr).(bool) {
+		query += " WITH ADMIN OPTION"
+	}
+	if _, err := txn.Exec(query); err != nil {
+		return fmt.Errorf("could not grant role %s to %s: %w", role, member, err)
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId(generateObjectID(role, member))
+
+	return resourcePostgreSQLRoleMembershipReadImpl(db, d)
+}
+
+func resourcePostgreSQLRoleMembershipRead(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLRoleMembershipReadImpl(db, d)
+}
+
+func resourcePostgreSQLRoleMembershipReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	parts, err := parseObjectID(d.Id())
+	if err != nil {
+		return err
+	}
+	if len(parts) != 2 {
+		return fmt.Errorf("role membership ID %s has not the expected format 'role.member'", d.Id())
+	}
+	role, member := parts[0], parts[1]
+
+	// Since PostgreSQL 16, a membership can be granted several times by different grantors.
+	var adminOption bool
+	err = db.QueryRow(`
+SELECT bool_or(m.admin_option)
+FROM pg_catalog.pg_auth_members m
+JOIN pg_catalog.pg_roles r ON r.oid = m.roleid
+JOIN pg_catalog.pg_roles mr ON mr.oid = m.member
+WHERE r.rolname = $1 AND mr.rolname = $2
+HAVING count(*) > 0
+`, role, member).Scan(&adminOption)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] PostgreSQL role membership (%s) not found, removing it from state", d.Id())
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("could not read membership of %s in %s: %w", member, role, err)
+	}
+
+	d.Set(roleMembershipRoleAttr, role)
+	d.Set(roleMembershipMemberAttr, member)
+	d.Set(roleMembershipAdminOptionAttr, adminOption)
+
+	return nil
+}
+
+func resourcePostgreSQLRoleMembershipUpdate(db *DBConnection, d *schema.ResourceData) error {
+	// Only admin_option can be updated, role and member force a new resource.
+	role := d.Get(roleMembershipRoleAttr).(string)
+	member := d.Get(roleMembershipMemberAttr).(string)
+
+	txn, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if err := setRoleMembershipAdminOption(txn, role, member, d.Get(roleMembershipAdminOptionAttr).(bool)); err != nil {
+		return err
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return resourcePostgreSQLRoleMembershipReadImpl(db, d)
+}
+
+func resourcePostgreSQLRoleMembershipDelete(db *DBConnection, d *schema.ResourceData) error {
+	role := d.Get(roleMembershipRoleAttr).(string)
+	member := d.Get(roleMembershipMemberAttr).(string)
+
+	txn, err := startTransaction(db.client, "")
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if _, err := txn.Exec(fmt.Sprintf(
+		"REVOKE %s FROM %s", pq.QuoteIdentifier(role), pq.QuoteIdentifier(member),
+	)); err != nil {
+		return fmt.Errorf("could not revoke role %s from %s: %w", role, member, err)
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
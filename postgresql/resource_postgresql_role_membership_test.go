@@ -0,0 +1,62 @@
+package postgresql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccPostgresqlRoleMembership_Basic(t *testing.T) {
+	config := `
+resource "postgresql_role" "group" {
+  name = "tf_tests_membership_group"
+}
+
+resource "postgresql_role" "member" {
+  name = "tf_tests_membership_member"
+
+  lifecycle {
+    ignore_changes = [roles]
+  }
+}
+
+resource "postgresql_role_membership" "test" {
+  role         = postgresql_role.group.name
+  member       = postgresql_role.member.name
+  admin_option = %t
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlRoleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_role_membership.test", "id", "tf_tests_membership_group.tf_tests_membership_member"),
+					resource.TestCheckResourceAttr("postgresql_role_membership.test", "admin_option", "false"),
+					testAccCheckRoleMembershipAdminOption("tf_tests_membership_member", "tf_tests_membership_group", false),
+				),
+			},
+			{
+				Config: fmt.Sprintf(config, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_role_membership.test", "id", "tf_tests_membership_group.tf_tests_membership_member"),
+					resource.TestCheckResourceAttr("postgresql_role_membership.test", "admin_option", "true"),
+					testAccCheckRoleMembershipAdminOption("tf_tests_membership_member", "tf_tests_membership_group", true),
+				),
+			},
+			{
+				ResourceName:      "postgresql_role_membership.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
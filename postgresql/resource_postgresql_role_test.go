@@ -1,16 +1,20 @@
 package postgresql
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"os"
 	"reflect"
+	"regexp"
 	"sort"
 	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestAccPostgresqlRole_Basic(t *testing.T) {
@@ -44,6 +48,7 @@ func TestAccPostgresqlRole_Basic(t *testing.T) {
 					resource.TestCheckResourceAttr("postgresql_role.role_with_defaults", "valid_until", "infinity"),
 					resource.TestCheckResourceAttr("postgresql_role.role_with_defaults", "skip_drop_role", "false"),
 					resource.TestCheckResourceAttr("postgresql_role.role_with_defaults", "skip_reassign_owned", "false"),
+					resource.TestCheckResourceAttr("postgresql_role.role_with_defaults", "drop_owned_cascade", "false"),
 					resource.TestCheckResourceAttr("postgresql_role.role_with_defaults", "statement_timeout", "0"),
 					resource.TestCheckResourceAttr("postgresql_role.role_with_defaults", "idle_in_transaction_session_timeout", "0"),
 					resource.TestCheckResourceAttr("postgresql_role.role_with_defaults", "assume_role", ""),
@@ -58,6 +63,18 @@ func TestAccPostgresqlRole_Basic(t *testing.T) {
 					resource.TestCheckResourceAttr("postgresql_role.sub_role", "roles.1", "role_simple"),
 
 					testAccCheckPostgresqlRoleExists("role_with_search_path", nil, []string{"bar", "foo-with-hyphen"}),
+
+					testAccCheckPostgresqlRoleExists("role_with_raw_search_path", nil, []string{"$user", "public"}),
+					resource.TestCheckResourceAttr("postgresql_role.role_with_raw_search_path", "raw_search_path", `"$user", public`),
+				),
+			},
+			// The members are read from the other roles once they are refreshed.
+			{
+				Config: testAccPostgresqlRoleConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_role.myrole2", "members.#", "1"),
+					resource.TestCheckTypeSetElemAttr("postgresql_role.myrole2", "members.*", "sub_role"),
+					resource.TestCheckResourceAttr("postgresql_role.sub_role", "members.#", "0"),
 				),
 			},
 		},
@@ -96,6 +113,202 @@ resource "postgresql_role" "role_with_superuser" {
 	})
 }
 
+// Test creating roles with passwords which are already hashed.
+func TestAccPostgresqlRole_HashedPassword(t *testing.T) {
+	scramPassword := "SCRAM-SHA-256$4096:ZmVkY2JhOTg3NjU0MzIxMA==$MrKk0s9cjN1ewN4zQapfzlaJopr/eTZLFFY1Z7Y1KUQ=:tjBf3a9Z9RPw14ttPoVhscsSUTTdwTTvprYvikDknPc="
+	md5Password := "md55616a93546bf7d6f342ef984b63d8e82"
+
+	roleConfig := fmt.Sprintf(`
+resource "postgresql_role" "role_with_scram_hash" {
+  name                        = "role_with_scram_hash"
+  login                       = true
+  password                    = "%s"
+  generate_pgbouncer_userlist = true
+}
+
+resource "postgresql_role" "role_with_md5_hash" {
+  name     = "role_with_md5_hash"
+  login    = true
+  password = "%s"
+}`, scramPassword, md5Password)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlRoleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: roleConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_role.role_with_scram_hash", "password", scramPassword),
+					resource.TestCheckResourceAttr("postgresql_role.role_with_md5_hash", "password", md5Password),
+					resource.TestCheckResourceAttr(
+						"postgresql_role.role_with_scram_hash", "pgbouncer_userlist", fmt.Sprintf(`"role_with_scram_hash" "%s"`, scramPassword),
+					),
+					// The userlist is opt-in.
+					resource.TestCheckResourceAttr("postgresql_role.role_with_md5_hash", "pgbouncer_userlist", ""),
+					// The verifier is the one of testRolePassword.
+					func(*terraform.State) error {
+						db := connectAsTestRole(t, "role_with_scram_hash", "postgres")
+						defer db.Close()
+						return db.Ping()
+					},
+				),
+			},
+		},
+	})
+}
+
+// Test setting and rotating a password which is not stored in the state.
+func TestAccPostgresqlRole_WriteOnlyPassword(t *testing.T) {
+	roleConfig := `
+resource "postgresql_role" "role_with_wo_password" {
+  name                = "role_with_wo_password"
+  login               = true
+  password_wo         = "%s"
+  password_wo_version = %d
+}`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlRoleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(roleConfig, "first-password", 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_role.role_with_wo_password", "password_wo", ""),
+					resource.TestCheckResourceAttr("postgresql_role.role_with_wo_password", "password", ""),
+					resource.TestCheckResourceAttr("postgresql_role.role_with_wo_password", "password_wo_version", "1"),
+					testAccCheckRoleCanLogin(t, "role_with_wo_password", "first-password"),
+				),
+			},
+			{
+				// The password is not set again until the version changes.
+				Config: fmt.Sprintf(roleConfig, "second-password", 1),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRoleCanLogin(t, "role_with_wo_password", "first-password"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(roleConfig, "second-password", 2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_role.role_with_wo_password", "password_wo", ""),
+					testAccCheckRoleCanLogin(t, "role_with_wo_password", "second-password"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlRole_PasswordTrigger(t *testing.T) {
+	var roleConfig = `
+resource "postgresql_role" "test" {
+  name             = "test_password_trigger"
+  login            = true
+  password         = "toto"
+  password_trigger = "%s"
+}
+`
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlRoleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(roleConfig, "2024-01-01"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_role.test", "password_trigger", "2024-01-01"),
+					testAccCheckRoleCanLogin(t, "test_password_trigger", "toto"),
+				),
+			},
+			{
+				// The password changed outside of Terraform is set again when the trigger changes.
+				PreConfig: func() {
+					client := testAccProvider.Meta().(*Client)
+					db, err := client.Connect()
+					if err != nil {
+						t.Fatal(err)
+					}
+					if _, err := db.Exec("ALTER ROLE test_password_trigger PASSWORD 'titi'"); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: fmt.Sprintf(roleConfig, "2024-02-01"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_role.test", "password_trigger", "2024-02-01"),
+					testAccCheckRoleCanLogin(t, "test_password_trigger", "toto"),
+				),
+			},
+		},
+	})
+}
+
+func TestValidateRolePassword(t *testing.T) {
+	for _, password := range []string{
+		"mypass",
+		"md55616a93546bf7d6f342ef984b63d8e82",
+		"SCRAM-SHA-256$4096:ZmVkY2JhOTg3NjU0MzIxMA==$MrKk0s9cjN1ewN4zQapfzlaJopr/eTZLFFY1Z7Y1KUQ=:tjBf3a9Z9RPw14ttPoVhscsSUTTdwTTvprYvikDknPc=",
+		"md5 is not a hash",
+	} {
+		_, errs := validateRolePassword(password, "password")
+		assert.Empty(t, errs, password)
+	}
+
+	for _, password := range []string{
+		"md55616A93546BF7D6F342EF984B63D8E82",
+		"SCRAM-SHA-256$4096:ZmVkY2JhOTg3NjU0MzIxMA==",
+	} {
+		_, errs := validateRolePassword(password, "password")
+		assert.Len(t, errs, 1, password)
+	}
+}
+
+func TestRoleMembershipGrantQuery(t *testing.T) {
+	membership := func(adminOption, inheritOption, setOption bool, grantor string) map[string]interface{} {
+		return map[string]interface{}{
+			"role":           "group",
+			"admin_option":   adminOption,
+			"inherit_option": inheritOption,
+			"set_option":     setOption,
+			"granted_by":     grantor,
+		}
+	}
+
+	tests := []struct {
+		name        string
+		membership  map[string]interface{}
+		withOptions bool
+		expected    string
+		expectErr   bool
+	}{
+		{"default", membership(false, true, true, ""), false, `GRANT "group" TO "member"`, false},
+		{"admin option", membership(true, true, true, ""), false, `GRANT "group" TO "member" WITH ADMIN OPTION`, false},
+		{"grantor", membership(false, true, true, "admin"), false, `GRANT "group" TO "member" GRANTED BY "admin"`, false},
+		{"options not supported", membership(false, false, true, ""), false, "", true},
+		{"options", membership(true, false, true, ""), true, `GRANT "group" TO "member" WITH ADMIN TRUE, INHERIT FALSE, SET TRUE`, false},
+		{"options and grantor", membership(false, true, false, "admin"), true, `GRANT "group" TO "member" WITH ADMIN FALSE, INHERIT TRUE, SET FALSE GRANTED BY "admin"`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := roleMembershipGrantQuery("member", tt.membership, tt.withOptions)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, query)
+		})
+	}
+}
+
 func TestAccPostgresqlRole_Update(t *testing.T) {
 
 	var configCreate = `
@@ -192,6 +405,179 @@ resource "postgresql_role" "update_role" {
 	})
 }
 
+func TestAccPostgresqlRole_DisableConnections(t *testing.T) {
+	var roleConfig = `
+resource "postgresql_role" "test" {
+  name     = "test_disable_connections"
+  login    = true
+  password = "toto"
+  %s
+}
+`
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlRoleDestroy,
+		Steps: []resource.TestStep{
+			{
+				// Deprecated, only a security warning for now.
+				Config: fmt.Sprintf(roleConfig, "connection_limit = 0"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_role.test", "disable_connections", "false"),
+					resource.TestCheckResourceAttr("postgresql_role.test", "connection_limit", "0"),
+					resource.TestMatchResourceAttr(
+						"postgresql_role.test", "security_warnings.1", regexp.MustCompile("connection_limit = 0 forbids login role"),
+					),
+					testAccCheckRoleConnLimit("test_disable_connections", 0),
+				),
+			},
+			{
+				Config:      fmt.Sprintf(roleConfig, "disable_connections = true\n  connection_limit = 5"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("connection_limit can't be set if disable_connections is true"),
+			},
+			{
+				Config: fmt.Sprintf(roleConfig, "disable_connections = true"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_role.test", "disable_connections", "true"),
+					resource.TestCheckResourceAttr("postgresql_role.test", "connection_limit", "-1"),
+					testAccCheckRoleConnLimit("test_disable_connections", 0),
+				),
+			},
+			{
+				ResourceName:            "postgresql_role.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateId:           "test_disable_connections",
+				ImportStateVerifyIgnore: []string{"password"},
+			},
+			{
+				Config: fmt.Sprintf(roleConfig, "connection_limit = 3"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_role.test", "disable_connections", "false"),
+					resource.TestCheckResourceAttr("postgresql_role.test", "connection_limit", "3"),
+					testAccCheckRoleConnLimit("test_disable_connections", 3),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlRole_DropOwnedCascade(t *testing.T) {
+	skipIfNotAcc(t)
+	skipIfNotSuperuser(t)
+
+	config := getTestConfig(t)
+	dsn := config.connStr("postgres")
+	defer dbExecute(t, dsn, "DROP TABLE IF EXISTS test_drop_owned_cascade")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlRoleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "postgresql_role" "test" {
+  name               = "test_drop_owned_cascade"
+  drop_owned_cascade = true
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_role.test", "drop_owned_cascade", "true"),
+					// The privilege granted by the role with its grant option can only be revoked with CASCADE.
+					func(*terraform.State) error {
+						dbExecute(t, dsn, `
+CREATE TABLE test_drop_owned_cascade (id int);
+GRANT SELECT ON test_drop_owned_cascade TO test_drop_owned_cascade WITH GRANT OPTION;
+SET ROLE test_drop_owned_cascade;
+GRANT SELECT ON test_drop_owned_cascade TO PUBLIC;
+RESET ROLE;
+`)
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckRoleConnLimit(roleName string, expected int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*Client)
+		db, err := client.Connect()
+		if err != nil {
+			return err
+		}
+
+		var connLimit int
+		if err := db.QueryRow("SELECT rolconnlimit FROM pg_roles WHERE rolname = $1", roleName).Scan(&connLimit); err != nil {
+			return fmt.Errorf("could not read the connection limit of role %s: %w", roleName, err)
+		}
+		if connLimit != expected {
+			return fmt.Errorf("expected the connection limit of role %s to be %d, got %d", roleName, expected, connLimit)
+		}
+		return nil
+	}
+}
+
+func TestAccPostgresqlRole_TerminateSessionsOnDisableLogin(t *testing.T) {
+	config := `
+resource "postgresql_role" "session_role" {
+  name     = "session_role"
+  login    = %t
+  password = "toto"
+
+  terminate_sessions_on_disable_login = true
+}
+`
+	var session *sql.Conn
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlRoleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_role.session_role", "login", "true"),
+					func(s *terraform.State) error {
+						// Open a session which is kept until the login is disabled.
+						testConfig := getTestConfig(t)
+						testConfig.Username = "session_role"
+						testConfig.Password = "toto"
+						db, err := sql.Open("postgres", testConfig.connStr("postgres"))
+						if err != nil {
+							return fmt.Errorf("could not open SQL connection: %v", err)
+						}
+						session, err = db.Conn(context.Background())
+						if err != nil {
+							return fmt.Errorf("could not connect as role session_role: %v", err)
+						}
+						return session.PingContext(context.Background())
+					},
+				),
+			},
+			{
+				Config: fmt.Sprintf(config, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_role.session_role", "login", "false"),
+					func(s *terraform.State) error {
+						defer session.Close()
+						if _, err := session.ExecContext(context.Background(), "SELECT 1"); err == nil {
+							return fmt.Errorf("session of role session_role should have been terminated")
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
 // Test to create a role with admin user (usually postgres) granted to it
 // There were a bug on RDS like setup (with a non-superuser postgres role)
 // where it couldn't delete the role in this case.
@@ -237,6 +623,135 @@ resource "postgresql_role" "test_role" {
 	})
 }
 
+func TestAccPostgresqlRole_Membership(t *testing.T) {
+	roleConfig := `
+resource "postgresql_role" "group_role" {
+  name = "group_role"
+}
+
+resource "postgresql_role" "member_role" {
+  name = "member_role"
+
+  membership {
+    role         = postgresql_role.group_role.name
+    admin_option = %t
+  }
+}`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featurePrivileges)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlRoleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(roleConfig, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_role.member_role", "roles.#", "0"),
+					resource.TestCheckTypeSetElemNestedAttrs("postgresql_role.member_role", "membership.*", map[string]string{
+						"role":           "group_role",
+						"admin_option":   "true",
+						"inherit_option": "true",
+						"set_option":     "true",
+					}),
+					testAccCheckRoleMembershipAdminOption("member_role", "group_role", true),
+				),
+			},
+			{
+				Config: fmt.Sprintf(roleConfig, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs("postgresql_role.member_role", "membership.*", map[string]string{
+						"role":         "group_role",
+						"admin_option": "false",
+					}),
+					testAccCheckRoleMembershipAdminOption("member_role", "group_role", false),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlRole_MembershipSeveralGrantors(t *testing.T) {
+	skipIfNotAcc(t)
+
+	config := getTestConfig(t)
+	dsn := config.connStr("postgres")
+
+	roleConfig := `
+resource "postgresql_role" "group_role" {
+  name = "group_role"
+}
+
+resource "postgresql_role" "other_admin" {
+  name = "other_admin"
+
+  membership {
+    role         = postgresql_role.group_role.name
+    admin_option = true
+  }
+}
+
+resource "postgresql_role" "member_role" {
+  name = "member_role"
+
+  membership {
+    role = postgresql_role.group_role.name
+  }
+}`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckCompatibleVersion(t, featureRoleMembershipOptions)
+			testSuperuserPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlRoleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: roleConfig,
+			},
+			{
+				// Since PostgreSQL 16, the same membership granted by another grantor is another row of pg_auth_members.
+				PreConfig: func() {
+					dbExecute(t, dsn, "GRANT group_role TO member_role GRANTED BY other_admin")
+				},
+				Config:   roleConfig,
+				PlanOnly: true,
+			},
+			{
+				Config: roleConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_role.member_role", "membership.#", "1"),
+					resource.TestCheckResourceAttr("postgresql_role.group_role", "members.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckRoleMembershipAdminOption(member, role string, expected bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*Client)
+		db, err := client.Connect()
+		if err != nil {
+			return err
+		}
+
+		var adminOption bool
+		if err := db.QueryRow(`SELECT bool_or(admin_option) FROM pg_catalog.pg_auth_members
+			WHERE roleid = $1::regrole AND member = $2::regrole`, role, member).Scan(&adminOption); err != nil {
+			return fmt.Errorf("could not read membership of %s in %s: %w", member, role, err)
+		}
+		if adminOption != expected {
+			return fmt.Errorf("admin_option of the membership of %s in %s is %t, expected %t", member, role, adminOption, expected)
+		}
+		return nil
+	}
+}
+
 func testAccCheckPostgresqlRoleDestroy(s *terraform.State) error {
 	client := testAccProvider.Meta().(*Client)
 
@@ -287,6 +802,51 @@ func testAccCheckPostgresqlRoleExists(roleName string, grantedRoles []string, se
 	}
 }
 
+func TestParseSearchPath(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{`"$user", public`, []string{"$user", "public"}},
+		{`bar, "foo-with-hyphen"`, []string{"bar", "foo-with-hyphen"}},
+		{`"with, comma", "with""quote"`, []string{"with, comma", `with"quote`}},
+		{`single`, []string{"single"}},
+	}
+
+	for _, test := range tests {
+		actual := parseSearchPath(test.input)
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("parseSearchPath(%q) = %#v, want %#v", test.input, actual, test.expected)
+		}
+	}
+}
+
+func TestQuoteSearchPathElement(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		valid    bool
+	}{
+		{`public`, `"public"`, true},
+		{`"$user"`, `"$user"`, true},
+		{`"with""quote"`, `"with""quote"`, true},
+		{`with"quote`, `"with""quote"`, true},
+		{`"$user`, `"""$user"`, false},
+		{`"a"b"`, `"""a""b"""`, false},
+		{`"`, `""""`, false},
+	}
+
+	for _, test := range tests {
+		if actual := quoteSearchPathElement(test.input); actual != test.expected {
+			t.Errorf("quoteSearchPathElement(%q) = %q, want %q", test.input, actual, test.expected)
+		}
+		_, errs := validateSearchPathElement(test.input, "search_path.0")
+		if valid := len(errs) == 0; valid != test.valid {
+			t.Errorf("validateSearchPathElement(%q) valid = %v, want %v", test.input, valid, test.valid)
+		}
+	}
+}
+
 func checkRoleExists(client *Client, roleName string) (bool, error) {
 	db, err := client.Connect()
 	if err != nil {
@@ -446,4 +1006,82 @@ resource "postgresql_role" "role_with_search_path" {
   name = "role_with_search_path"
   search_path = ["bar", "foo-with-hyphen"]
 }
+
+resource "postgresql_role" "role_with_raw_search_path" {
+  name = "role_with_raw_search_path"
+  raw_search_path = "\"$user\", public"
+}
 `
+
+func TestRoleSecurityWarnings(t *testing.T) {
+	cases := []struct {
+		name     string
+		config   map[string]interface{}
+		expected int
+	}{
+		{
+			name:     "no login",
+			config:   map[string]interface{}{"name": "role", "password": "secret", "superuser": true},
+			expected: 0,
+		},
+		{
+			name:     "password without expiry",
+			config:   map[string]interface{}{"name": "role", "login": true, "password": "secret"},
+			expected: 1,
+		},
+		{
+			name:     "password with expiry",
+			config:   map[string]interface{}{"name": "role", "login": true, "password": "secret", "valid_until": "2030-01-01"},
+			expected: 0,
+		},
+		{
+			name:     "superuser login without password",
+			config:   map[string]interface{}{"name": "role", "login": true, "superuser": true},
+			expected: 1,
+		},
+		{
+			name:     "login with connection limit 0",
+			config:   map[string]interface{}{"name": "role", "login": true, "connection_limit": 0},
+			expected: 1,
+		},
+		{
+			name:     "login with disabled connections",
+			config:   map[string]interface{}{"name": "role", "login": true, "disable_connections": true},
+			expected: 0,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourcePostgreSQLRole().Schema, c.config)
+			assert.Len(t, roleSecurityWarnings(d.Get), c.expected)
+		})
+	}
+}
+
+func TestPgbouncerUserlist(t *testing.T) {
+	assert.Equal(t, "md5916d989e8db03bd94a2a4f60d791e733", md5RolePassword("toto", "role_with_md5"))
+	assert.Equal(t, `"role"`, pgbouncerQuote("role"))
+	assert.Equal(t, `"my""role"`, pgbouncerQuote(`my"role`))
+}
+
+func TestScramSHA256VerifierMatches(t *testing.T) {
+	verifier := "SCRAM-SHA-256$4096:MDEyMzQ1Njc4OWFiY2RlZg==$a+6Wfgp1oHKG4Gb8TXatBnCeFAPKXik+7leVCJs/RM8=:UexTAuWtYWy/xYYF2loQXFnI86MRZpd+bsZ9KOl3R8g="
+
+	matches, err := scramSHA256VerifierMatches(verifier, "my-secret")
+	assert.NoError(t, err)
+	assert.True(t, matches)
+
+	matches, err = scramSHA256VerifierMatches(verifier, "other-secret")
+	assert.NoError(t, err)
+	assert.False(t, matches)
+
+	_, err = scramSHA256VerifierMatches(verifier, "sécret")
+	assert.Error(t, err)
+
+	_, err = scramSHA256VerifierMatches("SCRAM-SHA-256$4096:salt", "my-secret")
+	assert.Error(t, err)
+
+	_, err = scramSHA256VerifierMatches("SCRAM-SHA-256$many:MDEy$a+6W:UexT", "my-secret")
+	assert.Error(t, err)
+}
@@ -2,6 +2,7 @@ package postgresql
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -21,6 +22,7 @@ const (
 	schemaPolicyAttr   = "policy"
 	schemaIfNotExists  = "if_not_exists"
 	schemaDropCascade  = "drop_cascade"
+	schemaExecuteAs    = "execute_as"
 
 	schemaPolicyCreateAttr          = "create"
 	schemaPolicyCreateWithGrantAttr = "create_with_grant"
@@ -32,83 +34,110 @@ const (
 func resourcePostgreSQLSchema() *schema.Resource {
 	return &schema.Resource{
 		Create: PGResourceFunc(resourcePostgreSQLSchemaCreate),
-		Read:   PGResourceFunc(resourcePostgreSQLSchemaRead),
+		Read:   PGReadResourceFunc(resourcePostgreSQLSchemaRead),
 		Update: PGResourceFunc(resourcePostgreSQLSchemaUpdate),
 		Delete: PGResourceFunc(resourcePostgreSQLSchemaDelete),
 		Exists: PGResourceExistsFunc(resourcePostgreSQLSchemaExists),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
-
-		Schema: map[string]*schema.Schema{
-			schemaNameAttr: {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The name of the schema",
-			},
-			schemaDatabaseAttr: {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Computed:    true,
-				ForceNew:    true,
-				Description: "The database name to alter schema",
-			},
-			schemaOwnerAttr: {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Computed:    true,
-				Description: "The ROLE name who owns the schema",
+		CustomizeDiff: resourcePostgreSQLSchemaCustomizeDiff,
+
+		// The version 1 quotes the dotted names in the ID (see generateObjectID).
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourcePostgreSQLSchemaV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourcePostgreSQLSchemaStateUpgradeV0,
 			},
-			schemaIfNotExists: {
-				Type:        schema.TypeBool,
-				Optional:    true,
-				Default:     true,
-				Description: "When true, use the existing schema if it exists",
-			},
-			schemaDropCascade: {
-				Type:        schema.TypeBool,
-				Optional:    true,
-				Default:     false,
-				Description: "When true, will also drop all the objects that are contained in the schema",
-			},
-			schemaPolicyAttr: {
-				Type:       schema.TypeSet,
-				Optional:   true,
-				Computed:   true,
-				Deprecated: "Use postgresql_grant resource instead (with object_type=\"schema\")",
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						schemaPolicyCreateAttr: {
-							Type:        schema.TypeBool,
-							Optional:    true,
-							Default:     false,
-							Description: "If true, allow the specified ROLEs to CREATE new objects within the schema(s)",
-						},
-						schemaPolicyCreateWithGrantAttr: {
-							Type:        schema.TypeBool,
-							Optional:    true,
-							Default:     false,
-							Description: "If true, allow the specified ROLEs to CREATE new objects within the schema(s) and GRANT the same CREATE privilege to different ROLEs",
-						},
-						schemaPolicyRoleAttr: {
-							Type:        schema.TypeString,
-							Elem:        &schema.Schema{Type: schema.TypeString},
-							Optional:    true,
-							Default:     "",
-							Description: "ROLE who will receive this policy (default: PUBLIC)",
-						},
-						schemaPolicyUsageAttr: {
-							Type:        schema.TypeBool,
-							Optional:    true,
-							Default:     false,
-							Description: "If true, allow the specified ROLEs to use objects within the schema(s)",
-						},
-						schemaPolicyUsageWithGrantAttr: {
-							Type:        schema.TypeBool,
-							Optional:    true,
-							Default:     false,
-							Description: "If true, allow the specified ROLEs to use objects within the schema(s) and GRANT the same USAGE privilege to different ROLEs",
-						},
+		},
+
+		Schema: resourcePostgreSQLSchemaSchema(),
+	}
+}
+
+// resourcePostgreSQLSchemaV0 is the version 0 of the resource, its attributes are the same as the current version.
+func resourcePostgreSQLSchemaV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: resourcePostgreSQLSchemaSchema(),
+	}
+}
+
+func resourcePostgreSQLSchemaSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		schemaNameAttr: {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The name of the schema",
+		},
+		schemaDatabaseAttr: {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    true,
+			ForceNew:    true,
+			Description: "The database name to alter schema",
+		},
+		schemaOwnerAttr: {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    true,
+			Description: "The ROLE name who owns the schema, or CURRENT_USER, CURRENT_ROLE or SESSION_USER to use the role of the provider resolved when applying",
+		},
+		schemaIfNotExists: {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     true,
+			Description: "When true, use the existing schema if it exists",
+		},
+		schemaExecuteAs: {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The role used to create the schema (with SET ROLE) so it is directly owned by this role. Only used when the schema is created",
+		},
+		schemaDropCascade: {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "When true, will also drop all the objects that are contained in the schema",
+		},
+		schemaPolicyAttr: {
+			Type:       schema.TypeSet,
+			Optional:   true,
+			Computed:   true,
+			Deprecated: "Use postgresql_grant resource instead (with object_type=\"schema\")",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					schemaPolicyCreateAttr: {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Default:     false,
+						Description: "If true, allow the specified ROLEs to CREATE new objects within the schema(s)",
+					},
+					schemaPolicyCreateWithGrantAttr: {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Default:     false,
+						Description: "If true, allow the specified ROLEs to CREATE new objects within the schema(s) and GRANT the same CREATE privilege to different ROLEs",
+					},
+					schemaPolicyRoleAttr: {
+						Type:        schema.TypeString,
+						Elem:        &schema.Schema{Type: schema.TypeString},
+						Optional:    true,
+						Default:     "",
+						Description: "ROLE who will receive this policy (default: PUBLIC)",
+					},
+					schemaPolicyUsageAttr: {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Default:     false,
+						Description: "If true, allow the specified ROLEs to use objects within the schema(s)",
+					},
+					schemaPolicyUsageWithGrantAttr: {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Default:     false,
+						Description: "If true, allow the specified ROLEs to use objects within the schema(s) and GRANT the same USAGE privilege to different ROLEs",
 					},
 				},
 			},
@@ -124,6 +153,10 @@ func resourcePostgreSQLSchemaCreate(db *DBConnection, d *schema.ResourceData) er
 	}
 	defer deferredRollback(txn)
 
+	if err := resolveSchemaOwner(txn, d, db.client.config); err != nil {
+		return err
+	}
+
 	// If the authenticated user is not a superuser (e.g. on AWS RDS)
 	// we'll need to temporarily grant it membership in the following roles:
 	//  * the owner of the db (to have the permissions to create the schema)
@@ -136,14 +169,16 @@ func resourcePostgreSQLSchemaCreate(db *DBConnection, d *schema.ResourceData) er
 	}
 	rolesToGrant = append(rolesToGrant, dbOwner)
 
-	schemaOwner := d.Get("owner").(string)
+	schemaOwner := getObjectOwner(d, schemaOwnerAttr, db.client.config)
 	if schemaOwner != "" && schemaOwner != dbOwner {
 		rolesToGrant = append(rolesToGrant, schemaOwner)
 
 	}
 
 	if err := withRolesGranted(txn, rolesToGrant, func() error {
-		return createSchema(db, txn, d)
+		return withExecuteAs(txn, d.Get(schemaExecuteAs).(string), func() error {
+			return createSchema(db, txn, d)
+		})
 	}); err != nil {
 		return err
 	}
@@ -175,9 +210,8 @@ func createSchema(db *DBConnection, txn *sql.Tx, d *schema.ResourceData) error {
 		}
 		fmt.Fprint(b, pq.QuoteIdentifier(schemaName))
 
-		switch v, ok := d.GetOk(schemaOwnerAttr); {
-		case ok:
-			fmt.Fprint(b, " AUTHORIZATION ", pq.QuoteIdentifier(v.(string)))
+		if owner := getObjectOwner(d, schemaOwnerAttr, db.client.config); owner != "" {
+			fmt.Fprint(b, " AUTHORIZATION ", pq.QuoteIdentifier(owner))
 		}
 		queries = append(queries, b.String())
 
@@ -376,6 +410,10 @@ func resourcePostgreSQLSchemaUpdate(db *DBConnection, d *schema.ResourceData) er
 		return err
 	}
 
+	if err := resolveSchemaOwner(txn, d, db.client.config); err != nil {
+		return err
+	}
+
 	if err := setSchemaOwner(txn, d); err != nil {
 		return err
 	}
@@ -412,6 +450,117 @@ func setSchemaName(txn *sql.Tx, d *schema.ResourceData, databaseName string) err
 	return nil
 }
 
+// resourcePostgreSQLSchemaCustomizeDiff plans the owner set to CURRENT_USER, CURRENT_ROLE or SESSION_USER:
+// it's unknown for a new schema as the token is resolved when applying. For an existing schema,
+// the owner is only changed if the token doesn't stand for its current owner, i.e. the role itself or
+// pg_database_owner (e.g.: the public schema since PostgreSQL 15) if the role owns the database.
+func resourcePostgreSQLSchemaCustomizeDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown(schemaOwnerAttr) {
+		return nil
+	}
+	owner := d.Get(schemaOwnerAttr).(string)
+	if !isOwnerToken(owner) {
+		return nil
+	}
+	if d.Id() == "" {
+		return d.SetNewComputed(schemaOwnerAttr)
+	}
+
+	currentOwnerRaw, _ := d.GetChange(schemaOwnerAttr)
+	currentOwner := currentOwnerRaw.(string)
+
+	role, isCurrentOwner, err := func() (string, bool, error) {
+		endpoint, _ := d.Get(endpointAttr).(string)
+		client, err := meta.(*Client).endpointClient(endpoint)
+		if err != nil {
+			return "", false, err
+		}
+		client = client.readOnlyClient()
+		database := client.databaseName
+		if v, ok := d.GetOk(schemaDatabaseAttr); ok {
+			database = v.(string)
+		}
+
+		txn, err := startTransaction(client, database)
+		if err != nil {
+			return "", false, err
+		}
+		defer deferredRollback(txn)
+
+		role, err := resolveOwner(txn, owner)
+		if err != nil {
+			return "", false, err
+		}
+		if role == currentOwner {
+			return role, true, nil
+		}
+		isDatabaseOwner, err := isDatabaseOwnerRoleOf(txn, currentOwner, role)
+		return role, isDatabaseOwner, err
+	}()
+	if err != nil {
+		log.Printf("[DEBUG] could not resolve owner %s of schema when planning, resolving it when applying: %v", owner, err)
+		return d.SetNewComputed(schemaOwnerAttr)
+	}
+
+	if isCurrentOwner {
+		return d.Clear(schemaOwnerAttr)
+	}
+	return d.SetNew(schemaOwnerAttr, role)
+}
+
+// resolveSchemaOwner sets the owner to the role CURRENT_USER, CURRENT_ROLE or SESSION_USER stands for if one of
+// them is configured (directly or by object_owner_role in the provider). The configuration is used as the owner is
+// planned with the role name or as unknown (see resourcePostgreSQLSchemaCustomizeDiff).
+// The owner of an existing schema owned by pg_database_owner is kept if the role owns the database.
+func resolveSchemaOwner(txn *sql.Tx, d *schema.ResourceData, config Config) error {
+	owner := getObjectOwner(d, schemaOwnerAttr, config)
+	if rawConfig := d.GetRawConfig(); rawConfig.IsKnown() && !rawConfig.IsNull() {
+		if v := rawConfig.GetAttr(schemaOwnerAttr); v.IsKnown() && !v.IsNull() {
+			owner = v.AsString()
+		}
+	}
+	if !isOwnerToken(owner) {
+		return nil
+	}
+
+	role, err := resolveOwner(txn, owner)
+	if err != nil {
+		return err
+	}
+
+	var currentOwner string
+	err = txn.QueryRow(
+		"SELECT pg_catalog.pg_get_userbyid(nspowner) FROM pg_catalog.pg_namespace WHERE nspname = $1", d.Get(schemaNameAttr).(string),
+	).Scan(&currentOwner)
+	switch {
+	case err == sql.ErrNoRows:
+	case err != nil:
+		return fmt.Errorf("could not read the owner of schema %s: %w", d.Get(schemaNameAttr).(string), err)
+	default:
+		isDatabaseOwner, err := isDatabaseOwnerRoleOf(txn, currentOwner, role)
+		if err != nil {
+			return err
+		}
+		if isDatabaseOwner {
+			role = currentOwner
+		}
+	}
+	return d.Set(schemaOwnerAttr, role)
+}
+
+// isDatabaseOwnerRoleOf returns true if the owner is pg_database_owner and the role is the owner
+// of the current database, i.e.: the member of pg_database_owner.
+func isDatabaseOwnerRoleOf(db QueryAble, owner, role string) (bool, error) {
+	if owner != pgDatabaseOwnerRole {
+		return false, nil
+	}
+	dbOwner, err := getDatabaseOwner(db, "")
+	if err != nil {
+		return false, err
+	}
+	return role == dbOwner, nil
+}
+
 func setSchemaOwner(txn *sql.Tx, d *schema.ResourceData) error {
 	if !d.HasChange(schemaOwnerAttr) {
 		return nil
@@ -592,13 +741,13 @@ func schemaPolicyToACL(policyMap map[string]interface{}) acl.Schema {
 	return rolePolicy
 }
 
-func generateSchemaID(d *schema.ResourceData, databaseName string) string {
-	SchemaID := strings.Join([]string{
-		getDatabase(d, databaseName),
-		d.Get(schemaNameAttr).(string),
-	}, ".")
+// resourcePostgreSQLSchemaStateUpgradeV0 quotes the dotted names in the ID of the version 0.
+func resourcePostgreSQLSchemaStateUpgradeV0(_ context.Context, rawState map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+	return upgradeObjectIDStateV0(rawState, schemaDatabaseAttr, schemaNameAttr), nil
+}
 
-	return SchemaID
+func generateSchemaID(d *schema.ResourceData, databaseName string) string {
+	return generateObjectID(getDatabase(d, databaseName), d.Get(schemaNameAttr).(string))
 }
 
 func getDBSchemaName(d *schema.ResourceData, databaseName string) (string, string, error) {
@@ -607,7 +756,10 @@ func getDBSchemaName(d *schema.ResourceData, databaseName string) (string, strin
 
 	// When importing, we have to parse the ID to find schema and database names.
 	if schemaName == "" {
-		parsed := strings.Split(d.Id(), ".")
+		parsed, err := parseObjectID(d.Id())
+		if err != nil {
+			return "", "", err
+		}
 		if len(parsed) != 2 {
 			return "", "", fmt.Errorf("schema ID %s has not the expected format 'database.schema': %v", d.Id(), parsed)
 		}
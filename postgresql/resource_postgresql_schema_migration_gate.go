@@ -0,0 +1,205 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	migrationGateDatabaseAttr    = "database"
+	migrationGateNameAttr        = "name"
+	migrationGateLockIDAttr      = "lock_id"
+	migrationGateWaitTimeoutAttr = "wait_timeout"
+	migrationGateTriggersAttr    = "triggers"
+	migrationGateAcquiredAtAttr  = "acquired_at"
+)
+
+var (
+	// migrationGateLocks are the dedicated connections holding the session advisory locks of the gates.
+	// They are kept open until the gate is deleted or the end of the provider process (i.e.: the end of the apply)
+	// as the lock is released when its connection is closed (see releaseMigrationGateConn).
+	migrationGateLocksLock sync.Mutex
+	migrationGateLocks     = make(map[string]*sql.Conn)
+)
+
+func resourcePostgreSQLSchemaMigrationGate() *schema.Resource {
+	return &schema.Resource{
+		Create:        PGResourceFunc(resourcePostgreSQLSchemaMigrationGateCreate),
+		Read:          PGReadResourceFunc(resourcePostgreSQLSchemaMigrationGateRead),
+		Update:        PGResourceFunc(resourcePostgreSQLSchemaMigrationGateUpdate),
+		Delete:        PGResourceFunc(resourcePostgreSQLSchemaMigrationGateDelete),
+		CustomizeDiff: resourcePostgreSQLSchemaMigrationGateCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			migrationGateDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The database in which the advisory lock is acquired. If not specified, the provider default database is used.",
+			},
+			migrationGateNameAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the lock, used to compute lock_id if it is not specified",
+			},
+			migrationGateLockIDAttr: {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The key of the advisory lock. Defaults to hashtext(name)",
+			},
+			migrationGateWaitTimeoutAttr: {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "How many seconds to wait for the lock (0 waits indefinitely)",
+			},
+			migrationGateTriggersAttr: {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Arbitrary values which acquire the lock again in the apply when they change",
+			},
+			migrationGateAcquiredAtAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "When the lock was last acquired",
+			},
+		},
+	}
+}
+
+// resourcePostgreSQLSchemaMigrationGateCustomizeDiff plans acquired_at as computed whenever the gate
+// has a change in the plan, so every apply changing the gate acquires the lock again.
+func resourcePostgreSQLSchemaMigrationGateCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Id() == "" || len(d.GetChangedKeysPrefix("")) == 0 {
+		return nil
+	}
+	return d.SetNewComputed(migrationGateAcquiredAtAttr)
+}
+
+func resourcePostgreSQLSchemaMigrationGateCreate(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+
+	if _, ok := d.GetOk(migrationGateLockIDAttr); !ok {
+		var lockID int64
+		if err := db.QueryRow("SELECT hashtext($1)", d.Get(migrationGateNameAttr).(string)).Scan(&lockID); err != nil {
+			return fmt.Errorf("could not compute the lock ID of %s: %w", d.Get(migrationGateNameAttr).(string), err)
+		}
+		d.Set(migrationGateLockIDAttr, lockID)
+	}
+
+	if err := acquireMigrationGateLock(db, d); err != nil {
+		return err
+	}
+
+	d.Set(migrationGateDatabaseAttr, database)
+	d.SetId(generateObjectID(database, d.Get(migrationGateNameAttr).(string)))
+
+	return nil
+}
+
+func resourcePostgreSQLSchemaMigrationGateRead(db *DBConnection, d *schema.ResourceData) error {
+	// The lock only lives during the apply, there is nothing to read.
+	return nil
+}
+
+func resourcePostgreSQLSchemaMigrationGateUpdate(db *DBConnection, d *schema.ResourceData) error {
+	return acquireMigrationGateLock(db, d)
+}
+
+func resourcePostgreSQLSchemaMigrationGateDelete(db *DBConnection, d *schema.ResourceData) error {
+	key := migrationGateLockKey(db, d)
+
+	migrationGateLocksLock.Lock()
+	defer migrationGateLocksLock.Unlock()
+
+	if conn, ok := migrationGateLocks[key]; ok {
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", d.Get(migrationGateLockIDAttr).(int)); err != nil {
+			log.Printf("[WARN] could not release advisory lock %s: %v", d.Id(), err)
+		}
+		releaseMigrationGateConn(conn, d.Id())
+		delete(migrationGateLocks, key)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// acquireMigrationGateLock takes the session advisory lock of the gate on a dedicated connection
+// which is kept open until the end of the provider process.
+func acquireMigrationGateLock(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+	lockID := d.Get(migrationGateLockIDAttr).(int)
+	key := migrationGateLockKey(db, d)
+
+	migrationGateLocksLock.Lock()
+	defer migrationGateLocksLock.Unlock()
+
+	if _, ok := migrationGateLocks[key]; !ok {
+		lockDB, err := db.client.config.NewClient(database).Connect()
+		if err != nil {
+			return err
+		}
+
+		ctx := context.Background()
+		conn, err := lockDB.Conn(ctx)
+		if err != nil {
+			return fmt.Errorf("could not open connection for advisory lock %d: %w", lockID, err)
+		}
+
+		if err := lockMigrationGate(ctx, conn, lockID, d.Get(migrationGateWaitTimeoutAttr).(int)); err != nil {
+			releaseMigrationGateConn(conn, d.Id())
+			return err
+		}
+		migrationGateLocks[key] = conn
+	}
+
+	d.Set(migrationGateAcquiredAtAttr, time.Now().UTC().Format(time.RFC3339))
+
+	return nil
+}
+
+// releaseMigrationGateConn closes the connection of a gate instead of returning it to the pool of the provider,
+// so its session (advisory lock and settings) can't be reused by the other resources, even if the unlock failed.
+func releaseMigrationGateConn(conn *sql.Conn, id string) {
+	// Returning driver.ErrBadConn makes database/sql discard the connection.
+	_ = conn.Raw(func(interface{}) error {
+		return driver.ErrBadConn
+	})
+	if err := conn.Close(); err != nil && err != sql.ErrConnDone {
+		log.Printf("[WARN] could not close connection of advisory lock %s: %v", id, err)
+	}
+}
+
+func lockMigrationGate(ctx context.Context, conn *sql.Conn, lockID, waitTimeout int) error {
+	// Disable statement timeout for this connection otherwise the lock could fail
+	if _, err := conn.ExecContext(ctx, "SET statement_timeout = 0"); err != nil {
+		return fmt.Errorf("could not disable statement_timeout: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf("SET lock_timeout = %d", waitTimeout*1000)); err != nil {
+		return fmt.Errorf("could not set lock_timeout: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockID); err != nil {
+		return fmt.Errorf("could not get advisory lock %d: %w", lockID, err)
+	}
+	return nil
+}
+
+func migrationGateLockKey(db *DBConnection, d *schema.ResourceData) string {
+	database := getDatabase(d, db.client.databaseName)
+	return fmt.Sprintf("%s/%d", db.client.config.connStr(database), d.Get(migrationGateLockIDAttr).(int))
+}
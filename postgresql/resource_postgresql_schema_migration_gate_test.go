@@ -0,0 +1,87 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccPostgresqlSchemaMigrationGate_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, false)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	config := `
+resource "postgresql_schema_migration_gate" "test" {
+	database     = "%s"
+	name         = "tf_tests_migrations"
+	lock_id      = 4242
+	wait_timeout = %d
+
+	triggers = {
+		version = "%s"
+	}
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, dbName, 5, "1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_schema_migration_gate.test", "id", dbName+".tf_tests_migrations"),
+					resource.TestCheckResourceAttr("postgresql_schema_migration_gate.test", "lock_id", "4242"),
+					resource.TestCheckResourceAttrSet("postgresql_schema_migration_gate.test", "acquired_at"),
+					testAccCheckAdvisoryLockHeld(t, dbName, 4242),
+				),
+			},
+			{
+				// Changing the triggers acquires the lock again.
+				Config: fmt.Sprintf(config, dbName, 5, "2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_schema_migration_gate.test", "triggers.version", "2"),
+					resource.TestCheckResourceAttrSet("postgresql_schema_migration_gate.test", "acquired_at"),
+					testAccCheckAdvisoryLockHeld(t, dbName, 4242),
+				),
+			},
+			{
+				// Any other change of the gate acquires the lock again too.
+				Config: fmt.Sprintf(config, dbName, 10, "2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_schema_migration_gate.test", "wait_timeout", "10"),
+					resource.TestCheckResourceAttrSet("postgresql_schema_migration_gate.test", "acquired_at"),
+					testAccCheckAdvisoryLockHeld(t, dbName, 4242),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckAdvisoryLockHeld checks that the advisory lock can't be taken from another session.
+func testAccCheckAdvisoryLockHeld(t *testing.T, dbName string, lockID int) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		config := getTestConfig(t)
+		db, err := sql.Open("postgres", config.connStr(dbName))
+		if err != nil {
+			return fmt.Errorf("could not open connection pool for db %s: %w", dbName, err)
+		}
+		defer db.Close()
+
+		var acquired bool
+		if err := db.QueryRow("SELECT pg_try_advisory_lock($1)", lockID).Scan(&acquired); err != nil {
+			return fmt.Errorf("could not try advisory lock %d: %w", lockID, err)
+		}
+		if acquired {
+			return fmt.Errorf("advisory lock %d is not held by the gate", lockID)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,337 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/lib/pq"
+)
+
+const (
+	schemaOwnershipDatabaseAttr    = "database"
+	schemaOwnershipSchemaAttr      = "schema"
+	schemaOwnershipOwnerAttr       = "owner"
+	schemaOwnershipPatternAttr     = "pattern"
+	schemaOwnershipObjectTypesAttr = "object_types"
+	schemaOwnershipObjectsAttr     = "objects"
+)
+
+// schemaOwnershipObjectTypes are the object types whose ownership can be managed by postgresql_schema_ownership.
+var schemaOwnershipObjectTypes = []string{"table", "sequence", "function"}
+
+func resourcePostgreSQLSchemaOwnership() *schema.Resource {
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLSchemaOwnershipCreate),
+		Read:   PGReadResourceFunc(resourcePostgreSQLSchemaOwnershipRead),
+		Update: PGResourceFunc(resourcePostgreSQLSchemaOwnershipUpdate),
+		Delete: PGResourceFunc(resourcePostgreSQLSchemaOwnershipDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			schemaOwnershipDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The database of the schema. If not specified, the provider default database is used.",
+			},
+			schemaOwnershipSchemaAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The schema of the objects",
+			},
+			schemaOwnershipOwnerAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The role owning the matched objects",
+			},
+			schemaOwnershipPatternAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "%",
+				ForceNew:    true,
+				Description: "The LIKE pattern matching the names of the objects. Defaults to all the objects",
+			},
+			schemaOwnershipObjectTypesAttr: {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice(schemaOwnershipObjectTypes, false),
+				},
+				Set:         schema.HashString,
+				Description: "The types of the objects (table, sequence and/or function). Defaults to all of them",
+			},
+			schemaOwnershipObjectsAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The objects matched by the pattern",
+			},
+		},
+	}
+}
+
+// ownedObject is an object matched by a postgresql_schema_ownership resource.
+type ownedObject struct {
+	objectType string
+	name       string
+	// arguments are the identity arguments of the functions.
+	arguments string
+	owner     string
+}
+
+// String returns the object as it's referenced in the ALTER statement, without the schema.
+func (o ownedObject) String() string {
+	if o.objectType == "function" {
+		return fmt.Sprintf("%s(%s)", pq.QuoteIdentifier(o.name), o.arguments)
+	}
+	return pq.QuoteIdentifier(o.name)
+}
+
+func resourcePostgreSQLSchemaOwnershipCreate(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+
+	if err := setSchemaOwnership(db, d, database); err != nil {
+		return err
+	}
+
+	d.SetId(generateSchemaOwnershipID(d, database))
+
+	return resourcePostgreSQLSchemaOwnershipReadImpl(db, d)
+}
+
+func resourcePostgreSQLSchemaOwnershipUpdate(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+	if err := setSchemaOwnership(db, d, database); err != nil {
+		return err
+	}
+
+	d.SetId(generateSchemaOwnershipID(d, database))
+
+	return resourcePostgreSQLSchemaOwnershipReadImpl(db, d)
+}
+
+// generateSchemaOwnershipID returns the ID of the resource: its database, schema, pattern and, if they are set,
+// its sorted object types (e.g.: app.public.app_%.sequence,table) so resources matching the same objects
+// with different object types have different IDs.
+func generateSchemaOwnershipID(d *schema.ResourceData, database string) string {
+	parts := []string{
+		database,
+		d.Get(schemaOwnershipSchemaAttr).(string),
+		d.Get(schemaOwnershipPatternAttr).(string),
+	}
+	if objectTypes := setToSortedSlice(d.Get(schemaOwnershipObjectTypesAttr).(*schema.Set)); len(objectTypes) > 0 {
+		parts = append(parts, strings.Join(objectTypes, ","))
+	}
+	return generateObjectID(parts...)
+}
+
+func resourcePostgreSQLSchemaOwnershipDelete(db *DBConnection, d *schema.ResourceData) error {
+	// The objects keep their current owner, there is no previous owner to restore.
+	d.SetId("")
+
+	return nil
+}
+
+func resourcePostgreSQLSchemaOwnershipRead(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLSchemaOwnershipReadImpl(db, d)
+}
+
+func resourcePostgreSQLSchemaOwnershipReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	parts, err := parseObjectID(d.Id())
+	if err != nil {
+		return err
+	}
+	if len(parts) != 3 && len(parts) != 4 {
+		return fmt.Errorf("schema ownership ID %s has not the expected format 'database.schema.pattern[.object_types]'", d.Id())
+	}
+	database, schemaName, pattern := parts[0], parts[1], parts[2]
+	// When importing, the object types are read from the ID.
+	if len(parts) == 4 && d.Get(schemaOwnershipObjectTypesAttr).(*schema.Set).Len() == 0 {
+		d.Set(schemaOwnershipObjectTypesAttr, strings.Split(parts[3], ","))
+	}
+
+	exists, err := dbExists(db, database)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		log.Printf("[WARN] PostgreSQL database (%s) not found, removing schema ownership from state", database)
+		d.SetId("")
+		return nil
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	var schemaExists bool
+	if err := txn.QueryRow("SELECT EXISTS (SELECT 1 FROM pg_namespace WHERE nspname = $1)", schemaName).Scan(&schemaExists); err != nil {
+		return fmt.Errorf("could not check if schema %s exists: %w", schemaName, err)
+	}
+	if !schemaExists {
+		log.Printf("[WARN] PostgreSQL schema (%s) not found in database %s, removing schema ownership from state", schemaName, database)
+		d.SetId("")
+		return nil
+	}
+
+	objects, err := getSchemaOwnershipObjects(txn, schemaName, pattern, getSchemaOwnershipObjectTypes(d))
+	if err != nil {
+		return err
+	}
+
+	// If an object is owned by another role, its owner is set in the state so the plan shows the drift.
+	owner := d.Get(schemaOwnershipOwnerAttr).(string)
+	names := make([]string, 0, len(objects))
+	for _, object := range objects {
+		if object.owner != owner {
+			owner = object.owner
+		}
+		names = append(names, fmt.Sprintf("%s %s", object.objectType, object.String()))
+	}
+
+	d.Set(schemaOwnershipDatabaseAttr, database)
+	d.Set(schemaOwnershipSchemaAttr, schemaName)
+	d.Set(schemaOwnershipPatternAttr, pattern)
+	d.Set(schemaOwnershipOwnerAttr, owner)
+	d.Set(schemaOwnershipObjectsAttr, names)
+	// The IDs of the resources created before the object types were part of it are replaced.
+	d.SetId(generateSchemaOwnershipID(d, database))
+
+	return nil
+}
+
+// setSchemaOwnership changes the owner of the matched objects which are not already owned by the expected role.
+func setSchemaOwnership(db *DBConnection, d *schema.ResourceData, database string) error {
+	schemaName := d.Get(schemaOwnershipSchemaAttr).(string)
+	owner := d.Get(schemaOwnershipOwnerAttr).(string)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	objects, err := getSchemaOwnershipObjects(
+		txn, schemaName, d.Get(schemaOwnershipPatternAttr).(string), getSchemaOwnershipObjectTypes(d),
+	)
+	if err != nil {
+		return err
+	}
+
+	// The connected user needs to be a member of the current owners and of the new one.
+	rolesToGrant := []string{owner}
+	var queries []string
+	for _, object := range objects {
+		if object.owner == owner {
+			continue
+		}
+		if !sliceContainsStr(rolesToGrant, object.owner) {
+			rolesToGrant = append(rolesToGrant, object.owner)
+		}
+		queries = append(queries, fmt.Sprintf(
+			"ALTER %s %s.%s OWNER TO %s",
+			schemaOwnershipAlterKeyword(db, object.objectType), pq.QuoteIdentifier(schemaName), object, pq.QuoteIdentifier(owner),
+		))
+	}
+	if len(queries) == 0 {
+		return nil
+	}
+
+	if err := withRolesGranted(txn, rolesToGrant, func() error {
+		return execBatch(txn, queries...)
+	}); err != nil {
+		return fmt.Errorf("could not change owner of objects in schema %s: %w", schemaName, err)
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func schemaOwnershipAlterKeyword(db *DBConnection, objectType string) string {
+	switch objectType {
+	case "sequence":
+		return "SEQUENCE"
+	case "function":
+		// ALTER ROUTINE also handles the procedures.
+		if db.featureSupported(featureRoutine) {
+			return "ROUTINE"
+		}
+		return "FUNCTION"
+	}
+	return "TABLE"
+}
+
+func getSchemaOwnershipObjectTypes(d *schema.ResourceData) []string {
+	objectTypes := setToSortedSlice(d.Get(schemaOwnershipObjectTypesAttr).(*schema.Set))
+	if len(objectTypes) == 0 {
+		return schemaOwnershipObjectTypes
+	}
+	return objectTypes
+}
+
+// getSchemaOwnershipObjects returns the objects of the schema matching the pattern with their owner.
+// The objects which can't be altered directly are excluded: the members of extensions,
+// the sequences owned by a column (they follow the owner of their table) and the aggregates.
+func getSchemaOwnershipObjects(txn *sql.Tx, schemaName, pattern string, objectTypes []string) ([]ownedObject, error) {
+	rows, err := txn.Query(`
+SELECT 'table', c.relname, '', pg_get_userbyid(c.relowner)
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE n.nspname = $1 AND c.relname LIKE $2 AND 'table' = ANY($3)
+	AND c.relkind IN ('r', 'p')
+	AND NOT EXISTS (
+		SELECT 1 FROM pg_depend dep
+		WHERE dep.classid = 'pg_class'::regclass AND dep.objid = c.oid AND dep.deptype = 'e'
+	)
+UNION ALL
+SELECT 'sequence', c.relname, '', pg_get_userbyid(c.relowner)
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE n.nspname = $1 AND c.relname LIKE $2 AND 'sequence' = ANY($3)
+	AND c.relkind = 'S'
+	AND NOT EXISTS (
+		SELECT 1 FROM pg_depend dep
+		WHERE dep.classid = 'pg_class'::regclass AND dep.objid = c.oid AND dep.deptype IN ('a', 'i', 'e')
+	)
+UNION ALL
+SELECT 'function', p.proname, pg_get_function_identity_arguments(p.oid), pg_get_userbyid(p.proowner)
+FROM pg_proc p
+JOIN pg_namespace n ON n.oid = p.pronamespace
+WHERE n.nspname = $1 AND p.proname LIKE $2 AND 'function' = ANY($3)
+	AND NOT EXISTS (SELECT 1 FROM pg_aggregate WHERE aggfnoid = p.oid)
+	AND NOT EXISTS (
+		SELECT 1 FROM pg_depend dep
+		WHERE dep.classid = 'pg_proc'::regclass AND dep.objid = p.oid AND dep.deptype = 'e'
+	)
+ORDER BY 1, 2, 3
+`, schemaName, pattern, pq.Array(objectTypes))
+	if err != nil {
+		return nil, fmt.Errorf("could not read objects of schema %s: %w", schemaName, err)
+	}
+	defer rows.Close()
+
+	var objects []ownedObject
+	for rows.Next() {
+		var object ownedObject
+		if err := rows.Scan(&object.objectType, &object.name, &object.arguments, &object.owner); err != nil {
+			return nil, fmt.Errorf("could not scan objects of schema %s: %w", schemaName, err)
+		}
+		objects = append(objects, object)
+	}
+
+	return objects, rows.Err()
+}
@@ -0,0 +1,134 @@
+package postgresql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOwnedObjectString(t *testing.T) {
+	assert.Equal(t, `"my.table"`, ownedObject{objectType: "table", name: "my.table"}.String())
+	assert.Equal(t, `"seq"`, ownedObject{objectType: "sequence", name: "seq"}.String())
+	assert.Equal(t, `"fn"(a integer, b text)`, ownedObject{objectType: "function", name: "fn", arguments: "a integer, b text"}.String())
+}
+
+func TestGetSchemaOwnershipObjectTypes(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourcePostgreSQLSchemaOwnership().Schema, map[string]interface{}{})
+	assert.Equal(t, []string{"table", "sequence", "function"}, getSchemaOwnershipObjectTypes(d))
+
+	d = schema.TestResourceDataRaw(t, resourcePostgreSQLSchemaOwnership().Schema, map[string]interface{}{
+		"object_types": []interface{}{"table", "function"},
+	})
+	assert.Equal(t, []string{"function", "table"}, getSchemaOwnershipObjectTypes(d))
+}
+
+func TestGenerateSchemaOwnershipID(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourcePostgreSQLSchemaOwnership().Schema, map[string]interface{}{
+		"schema": "public",
+		"owner":  "app",
+	})
+	assert.Equal(t, "app.public.%", generateSchemaOwnershipID(d, "app"))
+
+	d = schema.TestResourceDataRaw(t, resourcePostgreSQLSchemaOwnership().Schema, map[string]interface{}{
+		"schema":       "public",
+		"owner":        "app",
+		"pattern":      "app_%",
+		"object_types": []interface{}{"table", "sequence"},
+	})
+	assert.Equal(t, "app.public.app_%.sequence,table", generateSchemaOwnershipID(d, "app"))
+}
+
+func TestAccPostgresqlSchemaOwnership(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+	config := getTestConfig(t)
+	dsn := config.connStr(dbName)
+	dbExecute(t, dsn, "CREATE TABLE test_schema.app_orders (id serial PRIMARY KEY)")
+	dbExecute(t, dsn, "CREATE TABLE test_schema.other (id int)")
+	dbExecute(t, dsn, "CREATE SEQUENCE test_schema.app_seq")
+	dbExecute(t, dsn, "CREATE FUNCTION test_schema.app_fn(a int) RETURNS int LANGUAGE sql AS 'SELECT a'")
+
+	testConfig := fmt.Sprintf(`
+resource "postgresql_schema_ownership" "test" {
+	database = "%s"
+	schema   = "test_schema"
+	pattern  = "app_%%"
+	owner    = "%s"
+}
+`, dbName, roleName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_schema_ownership.test", "id", dbName+".test_schema.app_%"),
+					resource.TestCheckResourceAttr("postgresql_schema_ownership.test", "owner", roleName),
+					// The sequence of the serial column follows its table.
+					resource.TestCheckResourceAttr("postgresql_schema_ownership.test", "objects.#", "3"),
+					resource.TestCheckResourceAttr("postgresql_schema_ownership.test", "objects.0", `function "app_fn"(a integer)`),
+					resource.TestCheckResourceAttr("postgresql_schema_ownership.test", "objects.1", `sequence "app_seq"`),
+					resource.TestCheckResourceAttr("postgresql_schema_ownership.test", "objects.2", `table "app_orders"`),
+					testCheckSchemaObjectOwner(t, dbName, "app_orders", roleName),
+					testCheckSchemaObjectOwner(t, dbName, "app_orders_id_seq", roleName),
+					testCheckSchemaObjectOwner(t, dbName, "app_seq", roleName),
+				),
+			},
+			{
+				ResourceName:      "postgresql_schema_ownership.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: fmt.Sprintf(`
+resource "postgresql_schema_ownership" "test" {
+	database     = "%s"
+	schema       = "test_schema"
+	pattern      = "app_%%"
+	owner        = "%s"
+	object_types = ["table", "sequence"]
+}
+`, dbName, roleName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_schema_ownership.test", "id", dbName+".test_schema.app_%.sequence,table"),
+					resource.TestCheckResourceAttr("postgresql_schema_ownership.test", "objects.#", "2"),
+				),
+			},
+			{
+				ResourceName:      "postgresql_schema_ownership.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testCheckSchemaObjectOwner(t *testing.T, dbName, relation, expectedOwner string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		db := connectAsTestRole(t, expectedOwner, dbName)
+		defer db.Close()
+
+		var owner string
+		if err := db.QueryRow(
+			"SELECT pg_get_userbyid(relowner) FROM pg_class WHERE oid = $1::regclass", "test_schema."+relation,
+		).Scan(&owner); err != nil {
+			return fmt.Errorf("could not read owner of %s: %w", relation, err)
+		}
+		if owner != expectedOwner {
+			return fmt.Errorf("expected %s to be owned by %s, got %s", relation, expectedOwner, owner)
+		}
+		return nil
+	}
+}
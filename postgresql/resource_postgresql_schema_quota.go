@@ -0,0 +1,212 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	schemaQuotaDatabaseAttr  = "database"
+	schemaQuotaSchemaAttr    = "schema"
+	schemaQuotaRoleAttr      = "role"
+	schemaQuotaQuotaAttr     = "quota_mb"
+	schemaQuotaUsedBytesAttr = "used_bytes"
+)
+
+func resourcePostgreSQLSchemaQuota() *schema.Resource {
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLSchemaQuotaCreate),
+		Read:   PGReadResourceFunc(resourcePostgreSQLSchemaQuotaRead),
+		Update: PGResourceFunc(resourcePostgreSQLSchemaQuotaUpdate),
+		Delete: PGResourceFunc(resourcePostgreSQLSchemaQuotaDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			schemaQuotaDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The database in which the diskquota extension is installed. If not specified, the provider default database is used.",
+			},
+			schemaQuotaSchemaAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{schemaQuotaSchemaAttr, schemaQuotaRoleAttr},
+				Description:  "The schema to set the quota on",
+			},
+			schemaQuotaRoleAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{schemaQuotaSchemaAttr, schemaQuotaRoleAttr},
+				Description:  "The role to set the quota on",
+			},
+			schemaQuotaQuotaAttr: {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "The disk quota in MB",
+			},
+			schemaQuotaUsedBytesAttr: {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The disk space currently used in bytes, as computed by diskquota",
+			},
+		},
+	}
+}
+
+func resourcePostgreSQLSchemaQuotaCreate(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+
+	if err := setDiskQuota(db, d, fmt.Sprintf("%d MB", d.Get(schemaQuotaQuotaAttr).(int))); err != nil {
+		return err
+	}
+
+	d.SetId(generateSchemaQuotaID(d, database))
+
+	return resourcePostgreSQLSchemaQuotaReadImpl(db, d)
+}
+
+func resourcePostgreSQLSchemaQuotaUpdate(db *DBConnection, d *schema.ResourceData) error {
+	if err := setDiskQuota(db, d, fmt.Sprintf("%d MB", d.Get(schemaQuotaQuotaAttr).(int))); err != nil {
+		return err
+	}
+
+	return resourcePostgreSQLSchemaQuotaReadImpl(db, d)
+}
+
+func resourcePostgreSQLSchemaQuotaDelete(db *DBConnection, d *schema.ResourceData) error {
+	// A negative quota removes it.
+	if err := setDiskQuota(db, d, "-1"); err != nil {
+		return err
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+func resourcePostgreSQLSchemaQuotaRead(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLSchemaQuotaReadImpl(db, d)
+}
+
+func resourcePostgreSQLSchemaQuotaReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	database, schemaName, role, err := getSchemaQuotaTarget(d, db.client.databaseName)
+	if err != nil {
+		return err
+	}
+
+	exists, err := dbExists(db, database)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		log.Printf("[WARN] PostgreSQL database (%s) not found, removing disk quota from state", database)
+		d.SetId("")
+		return nil
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	query := "SELECT quota_in_mb, nspsize_in_bytes FROM diskquota.show_fast_schema_quota_view WHERE schema_name = $1"
+	name := schemaName
+	if role != "" {
+		query = "SELECT quota_in_mb, rolsize_in_bytes FROM diskquota.show_fast_role_quota_view WHERE role_name = $1"
+		name = role
+	}
+
+	var quota, usedBytes int64
+	err = txn.QueryRow(query, name).Scan(&quota, &usedBytes)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] disk quota for %s not found in database %s, removing it from state", name, database)
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("could not read disk quota of %s: %w", name, err)
+	}
+
+	d.Set(schemaQuotaDatabaseAttr, database)
+	d.Set(schemaQuotaSchemaAttr, schemaName)
+	d.Set(schemaQuotaRoleAttr, role)
+	d.Set(schemaQuotaQuotaAttr, quota)
+	d.Set(schemaQuotaUsedBytesAttr, usedBytes)
+	d.SetId(generateSchemaQuotaID(d, database))
+
+	return nil
+}
+
+// setDiskQuota sets the quota of the schema or the role with the diskquota functions.
+// The quota is a size understood by pg_size_bytes (e.g.: "10 MB").
+func setDiskQuota(db *DBConnection, d *schema.ResourceData, quota string) error {
+	database := getDatabase(d, db.client.databaseName)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	query := "SELECT diskquota.set_schema_quota($1, $2)"
+	name := d.Get(schemaQuotaSchemaAttr).(string)
+	if role := d.Get(schemaQuotaRoleAttr).(string); role != "" {
+		query = "SELECT diskquota.set_role_quota($1, $2)"
+		name = role
+	}
+
+	if _, err := txn.Exec(query, name, quota); err != nil {
+		return fmt.Errorf("could not set disk quota of %s: %w", name, err)
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func generateSchemaQuotaID(d *schema.ResourceData, databaseName string) string {
+	kind, name := schemaQuotaSchemaAttr, d.Get(schemaQuotaSchemaAttr).(string)
+	if role := d.Get(schemaQuotaRoleAttr).(string); role != "" {
+		kind, name = schemaQuotaRoleAttr, role
+	}
+
+	return strings.Join([]string{getDatabase(d, databaseName), kind, name}, ".")
+}
+
+// getSchemaQuotaTarget returns the database and the schema or the role of the quota.
+// When importing, they are parsed from the ID (database.schema.name or database.role.name).
+func getSchemaQuotaTarget(d *schema.ResourceData, databaseName string) (string, string, string, error) {
+	database := getDatabase(d, databaseName)
+	schemaName := d.Get(schemaQuotaSchemaAttr).(string)
+	role := d.Get(schemaQuotaRoleAttr).(string)
+
+	if schemaName == "" && role == "" {
+		parsed := strings.SplitN(d.Id(), ".", 3)
+		if len(parsed) != 3 || (parsed[1] != schemaQuotaSchemaAttr && parsed[1] != schemaQuotaRoleAttr) {
+			return "", "", "", fmt.Errorf("disk quota ID %s has not the expected format 'database.schema.name' or 'database.role.name'", d.Id())
+		}
+		database = parsed[0]
+		if parsed[1] == schemaQuotaSchemaAttr {
+			schemaName = parsed[2]
+		} else {
+			role = parsed[2]
+		}
+	}
+
+	return database, schemaName, role, nil
+}
@@ -0,0 +1,120 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSchemaQuotaTarget(t *testing.T) {
+	cases := []struct {
+		id       string
+		database string
+		schema   string
+		role     string
+		err      bool
+	}{
+		{id: "mydb.schema.myschema", database: "mydb", schema: "myschema"},
+		{id: "mydb.role.myrole", database: "mydb", role: "myrole"},
+		{id: "mydb.role.my.role", database: "mydb", role: "my.role"},
+		{id: "mydb.table.foo", err: true},
+		{id: "mydb.myschema", err: true},
+	}
+
+	for _, c := range cases {
+		d := schema.TestResourceDataRaw(t, resourcePostgreSQLSchemaQuota().Schema, map[string]interface{}{})
+		d.SetId(c.id)
+
+		database, schemaName, role, err := getSchemaQuotaTarget(d, "postgres")
+		if c.err {
+			assert.Error(t, err, c.id)
+			continue
+		}
+		assert.NoError(t, err, c.id)
+		assert.Equal(t, c.database, database, c.id)
+		assert.Equal(t, c.schema, schemaName, c.id)
+		assert.Equal(t, c.role, role, c.id)
+	}
+}
+
+func TestAccPostgresqlSchemaQuota_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+	testCheckDiskquotaAvailable(t, dbName)
+
+	testConfig := `
+resource "postgresql_schema_quota" "schema" {
+	database = "%[1]s"
+	schema   = "test_schema"
+	quota_mb = %[3]d
+}
+
+resource "postgresql_schema_quota" "role" {
+	database = "%[1]s"
+	role     = "%[2]s"
+	quota_mb = %[3]d
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testSuperuserPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testConfig, dbName, roleName, 10),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_schema_quota.schema", "id", dbName+".schema.test_schema"),
+					resource.TestCheckResourceAttr("postgresql_schema_quota.schema", "quota_mb", "10"),
+					resource.TestCheckResourceAttrSet("postgresql_schema_quota.schema", "used_bytes"),
+					resource.TestCheckResourceAttr("postgresql_schema_quota.role", "id", fmt.Sprintf("%s.role.%s", dbName, roleName)),
+					resource.TestCheckResourceAttr("postgresql_schema_quota.role", "quota_mb", "10"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testConfig, dbName, roleName, 20),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_schema_quota.schema", "quota_mb", "20"),
+					resource.TestCheckResourceAttr("postgresql_schema_quota.role", "quota_mb", "20"),
+				),
+			},
+			{
+				ResourceName:      "postgresql_schema_quota.schema",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// testCheckDiskquotaAvailable skips the test if the diskquota extension can't be created in the database.
+func testCheckDiskquotaAvailable(t *testing.T, dbName string) {
+	config := getTestConfig(t)
+	db, err := sql.Open("postgres", config.connStr(dbName))
+	if err != nil {
+		t.Fatalf("could not open connection pool for db %s: %v", dbName, err)
+	}
+	defer db.Close()
+
+	var available bool
+	if err := db.QueryRow("SELECT count(*) > 0 FROM pg_available_extensions WHERE name = 'diskquota'").Scan(&available); err != nil {
+		t.Fatalf("could not check if diskquota is available: %v", err)
+	}
+	if !available {
+		t.Skip("Skip test: the diskquota extension is not available")
+	}
+
+	if _, err := db.Exec("CREATE EXTENSION IF NOT EXISTS diskquota"); err != nil {
+		t.Skipf("Skip test: could not create the diskquota extension: %v", err)
+	}
+}
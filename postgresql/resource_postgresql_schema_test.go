@@ -282,6 +282,43 @@ resource "postgresql_schema" "public" {
 	})
 }
 
+func TestAccPostgresqlSchema_ExecuteAs(t *testing.T) {
+	skipIfNotAcc(t)
+
+	config := getTestConfig(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+
+	// The role creates the schema itself so it needs the CREATE privilege on the database.
+	dbExecute(t, config.connStr(dbName), fmt.Sprintf("GRANT CREATE ON DATABASE %s TO %s", dbName, roleName))
+
+	var testAccPostgresqlSchemaConfig = fmt.Sprintf(`
+resource "postgresql_schema" "test_execute_as" {
+  name       = "test_execute_as"
+  database   = "%s"
+  execute_as = "%s"
+}
+`, dbName, roleName)
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlSchemaDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPostgresqlSchemaConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlSchemaExists("postgresql_schema.test_execute_as", "test_execute_as"),
+					resource.TestCheckResourceAttr("postgresql_schema.test_execute_as", "owner", roleName),
+					testAccCheckSchemaOwner(dbName, "test_execute_as", roleName),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckPostgresqlSchemaDestroy(s *terraform.State) error {
 	client := testAccProvider.Meta().(*Client)
 
@@ -597,3 +634,108 @@ resource "postgresql_schema" "test4" {
   }
 }
 `
+
+func TestAccPostgresqlSchema_CurrentUserOwner(t *testing.T) {
+	skipIfNotAcc(t)
+
+	config := getTestConfig(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+
+	testAccPostgresqlSchemaConfig := `
+resource "postgresql_schema" "test_current_user" {
+  name     = "test_current_user"
+  database = "%s"
+  owner    = "%s"
+}
+`
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlSchemaDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccPostgresqlSchemaConfig, dbName, "CURRENT_USER"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_schema.test_current_user", "owner", config.Username),
+					testAccCheckSchemaOwner(dbName, "test_current_user", config.Username),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccPostgresqlSchemaConfig, dbName, roleName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_schema.test_current_user", "owner", roleName),
+					testAccCheckSchemaOwner(dbName, "test_current_user", roleName),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccPostgresqlSchemaConfig, dbName, "session_user"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_schema.test_current_user", "owner", config.Username),
+					testAccCheckSchemaOwner(dbName, "test_current_user", config.Username),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccPostgresqlSchemaConfig, dbName, roleName),
+			},
+			{
+				Config: fmt.Sprintf(testAccPostgresqlSchemaConfig, dbName, "CURRENT_ROLE"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_schema.test_current_user", "owner", config.Username),
+					testAccCheckSchemaOwner(dbName, "test_current_user", config.Username),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlSchema_DatabaseOwnerRole(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, _ := getTestDBNames(dbSuffix)
+
+	testAccPostgresqlSchemaConfig := `
+resource "postgresql_schema" "public" {
+  name     = "public"
+  database = "%s"
+  %s
+}
+`
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			// The public schema is owned by pg_database_owner since PostgreSQL 15.
+			testCheckCompatibleVersion(t, featureDatabaseOwnerRole)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlSchemaDestroy,
+		Steps: []resource.TestStep{
+			// The provider user owns the test database, so it's the member of pg_database_owner.
+			{
+				Config: fmt.Sprintf(testAccPostgresqlSchemaConfig, dbName, `owner = "CURRENT_USER"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_schema.public", "owner", "pg_database_owner"),
+					testAccCheckSchemaOwner(dbName, "public", "pg_database_owner"),
+				),
+			},
+			{
+				Config:   fmt.Sprintf(testAccPostgresqlSchemaConfig, dbName, `owner = "CURRENT_ROLE"`),
+				PlanOnly: true,
+			},
+			// Without owner, the owner is read without diff.
+			{
+				Config: fmt.Sprintf(testAccPostgresqlSchemaConfig, dbName, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_schema.public", "owner", "pg_database_owner"),
+					testAccCheckSchemaOwner(dbName, "public", "pg_database_owner"),
+				),
+			},
+		},
+	})
+}
@@ -22,7 +22,7 @@ const (
 func resourcePostgreSQLSecurityLabel() *schema.Resource {
 	return &schema.Resource{
 		Create: PGResourceFunc(resourcePostgreSQLSecurityLabelCreate),
-		Read:   PGResourceFunc(resourcePostgreSQLSecurityLabelRead),
+		Read:   PGReadResourceFunc(resourcePostgreSQLSecurityLabelRead),
 		Update: PGResourceFunc(resourcePostgreSQLSecurityLabelUpdate),
 		Delete: PGResourceFunc(resourcePostgreSQLSecurityLabelDelete),
 		Importer: &schema.ResourceImporter{
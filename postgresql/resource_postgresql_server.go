@@ -24,7 +24,7 @@ const (
 func resourcePostgreSQLServer() *schema.Resource {
 	return &schema.Resource{
 		Create: PGResourceFunc(resourcePostgreSQLServerCreate),
-		Read:   PGResourceFunc(resourcePostgreSQLServerRead),
+		Read:   PGReadResourceFunc(resourcePostgreSQLServerRead),
 		Update: PGResourceFunc(resourcePostgreSQLServerUpdate),
 		Delete: PGResourceFunc(resourcePostgreSQLServerDelete),
 		Importer: &schema.ResourceImporter{
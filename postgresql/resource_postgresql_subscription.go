@@ -14,7 +14,7 @@ import (
 func resourcePostgreSQLSubscription() *schema.Resource {
 	return &schema.Resource{
 		Create:   PGResourceFunc(resourcePostgreSQLSubscriptionCreate),
-		Read:     PGResourceFunc(resourcePostgreSQLSubscriptionRead),
+		Read:     PGReadResourceFunc(resourcePostgreSQLSubscriptionRead),
 		Delete:   PGResourceFunc(resourcePostgreSQLSubscriptionDelete),
 		Exists:   PGResourceExistsFunc(resourcePostgreSQLSubscriptionExists),
 		Importer: &schema.ResourceImporter{StateContext: schema.ImportStatePassthroughContext},
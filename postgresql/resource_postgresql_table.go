@@ -0,0 +1,571 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lib/pq"
+)
+
+const (
+	tableNameAttr        = "name"
+	tableSchemaAttr      = "schema"
+	tableDatabaseAttr    = "database"
+	tableOwnerAttr       = "owner"
+	tableTablespaceAttr  = "tablespace"
+	tableIfNotExistsAttr = "if_not_exists"
+	tableDropCascadeAttr = "drop_cascade"
+	tableColumnAttr      = "column"
+	tablePrimaryKeyAttr  = "primary_key"
+
+	tableColumnNameAttr     = "name"
+	tableColumnTypeAttr     = "type"
+	tableColumnNullableAttr = "nullable"
+	tableColumnDefaultAttr  = "default"
+)
+
+// columnTypeAliases maps the type aliases accepted by PostgreSQL to the name returned by format_type.
+var columnTypeAliases = map[string]string{
+	"int":         "integer",
+	"int4":        "integer",
+	"int2":        "smallint",
+	"int8":        "bigint",
+	"serial":      "integer",
+	"serial4":     "integer",
+	"smallserial": "smallint",
+	"serial2":     "smallint",
+	"bigserial":   "bigint",
+	"serial8":     "bigint",
+	"float":       "double precision",
+	"float8":      "double precision",
+	"float4":      "real",
+	"bool":        "boolean",
+	"decimal":     "numeric",
+	"varchar":     "character varying",
+	"char":        "character",
+	"varbit":      "bit varying",
+	"timestamp":   "timestamp without time zone",
+	"timestamptz": "timestamp with time zone",
+	"time":        "time without time zone",
+	"timetz":      "time with time zone",
+}
+
+var columnTypeRegexp = regexp.MustCompile(`^([a-z0-9_ ]+?)\s*(\(.*\))?(\[\])?$`)
+
+func resourcePostgreSQLTable() *schema.Resource {
+	return &schema.Resource{
+		Create: PGResourceFunc(resourcePostgreSQLTableCreate),
+		Read:   PGReadResourceFunc(resourcePostgreSQLTableRead),
+		Update: PGResourceFunc(resourcePostgreSQLTableUpdate),
+		Delete: PGResourceFunc(resourcePostgreSQLTableDelete),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		CustomizeDiff: resourcePostgreSQLTableCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			tableNameAttr: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the table",
+			},
+			tableSchemaAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "public",
+				Description: "The schema in which the table is created",
+			},
+			tableDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The database in which the table is created. If not specified, the provider default database is used.",
+			},
+			tableOwnerAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The ROLE name who owns the table",
+			},
+			tableTablespaceAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The tablespace in which the table is stored (the database default tablespace if not set)",
+			},
+			tableIfNotExistsAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "When true, use the existing table if it exists",
+			},
+			tableDropCascadeAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "When true, will also drop all the objects that depend on the table",
+			},
+			tableColumnAttr: {
+				Type:        schema.TypeList,
+				Required:    true,
+				Description: "The columns of the table. Columns appended at the end of the list are added in place, renaming, retyping or removing a column recreates the table.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						tableColumnNameAttr: {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The name of the column",
+						},
+						tableColumnTypeAttr: {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The data type of the column",
+
+							DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+								return normalizeColumnType(old) == normalizeColumnType(new)
+							},
+						},
+						tableColumnNullableAttr: {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     true,
+							Description: "Whether the column accepts NULL values",
+						},
+						tableColumnDefaultAttr: {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The default value expression of the column",
+						},
+					},
+				},
+			},
+			tablePrimaryKeyAttr: {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The columns of the primary key",
+			},
+		},
+	}
+}
+
+// normalizeColumnType returns the type name as returned by format_type so the types
+// written with an alias (e.g.: int, varchar(10)) don't show a diff.
+func normalizeColumnType(columnType string) string {
+	columnType = strings.ToLower(strings.TrimSpace(columnType))
+
+	matches := columnTypeRegexp.FindStringSubmatch(columnType)
+	if matches == nil {
+		return columnType
+	}
+
+	name := strings.Join(strings.Fields(matches[1]), " ")
+	if alias, ok := columnTypeAliases[name]; ok {
+		name = alias
+	}
+
+	modifier := strings.ReplaceAll(matches[2], " ", "")
+	// The precision of time types is written before "with(out) time zone"
+	if modifier != "" {
+		for _, timeType := range []string{"timestamp", "time"} {
+			for _, suffix := range []string{" without time zone", " with time zone"} {
+				if name == timeType+suffix {
+					return timeType + modifier + suffix + matches[3]
+				}
+			}
+		}
+	}
+
+	return name + modifier + matches[3]
+}
+
+// resourcePostgreSQLTableCustomizeDiff forces the recreation of the table if the existing
+// columns are renamed, retyped or removed, only new columns at the end of the list can be added in place.
+func resourcePostgreSQLTableCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Id() == "" || !d.HasChange(tableColumnAttr) || !d.NewValueKnown(tableColumnAttr) {
+		return nil
+	}
+
+	oldRaw, newRaw := d.GetChange(tableColumnAttr)
+	oldColumns := oldRaw.([]interface{})
+	newColumns := newRaw.([]interface{})
+
+	if len(newColumns) < len(oldColumns) {
+		return d.ForceNew(tableColumnAttr)
+	}
+
+	for i, oldColumn := range oldColumns {
+		if !tableColumnsEqual(oldColumn.(map[string]interface{}), newColumns[i].(map[string]interface{})) {
+			return d.ForceNew(tableColumnAttr)
+		}
+	}
+
+	return nil
+}
+
+// tableColumnsEqual returns true if the columns have the same name and type,
+// the nullability and the default value can be changed in place.
+func tableColumnsEqual(a, b map[string]interface{}) bool {
+	return a[tableColumnNameAttr].(string) == b[tableColumnNameAttr].(string) &&
+		normalizeColumnType(a[tableColumnTypeAttr].(string)) == normalizeColumnType(b[tableColumnTypeAttr].(string))
+}
+
+func resourcePostgreSQLTableCreate(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+	schemaName := d.Get(tableSchemaAttr).(string)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	columns := []string{}
+	for _, column := range d.Get(tableColumnAttr).([]interface{}) {
+		columns = append(columns, tableColumnDefinition(column.(map[string]interface{})))
+	}
+	if primaryKey := d.Get(tablePrimaryKeyAttr).([]interface{}); len(primaryKey) > 0 {
+		columns = append(columns, fmt.Sprintf("PRIMARY KEY (%s)", quoteIdentifierList(primaryKey)))
+	}
+
+	b := &strings.Builder{}
+	b.WriteString("CREATE TABLE ")
+	if d.Get(tableIfNotExistsAttr).(bool) {
+		b.WriteString("IF NOT EXISTS ")
+	}
+	fmt.Fprintf(b, "%s.%s (%s)", pq.QuoteIdentifier(schemaName), pq.QuoteIdentifier(d.Get(tableNameAttr).(string)), strings.Join(columns, ", "))
+	if tablespace := d.Get(tableTablespaceAttr).(string); tablespace != "" {
+		fmt.Fprint(b, " TABLESPACE ", pq.QuoteIdentifier(tablespace))
+	}
+
+	if _, err := txn.Exec(b.String()); err != nil {
+		return fmt.Errorf("could not create table: %w", err)
+	}
+
+	if err := setTableOwner(txn, d); err != nil {
+		return err
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId(generateTableID(d, database))
+
+	return resourcePostgreSQLTableReadImpl(db, d)
+}
+
+func resourcePostgreSQLTableRead(db *DBConnection, d *schema.ResourceData) error {
+	return resourcePostgreSQLTableReadImpl(db, d)
+}
+
+func resourcePostgreSQLTableReadImpl(db *DBConnection, d *schema.ResourceData) error {
+	database, schemaName, tableName, err := getDBTableName(d, db.client.databaseName)
+	if err != nil {
+		return err
+	}
+
+	exists, err := dbExists(db, database)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		log.Printf("[WARN] PostgreSQL database (%s) not found, removing table from state", database)
+		d.SetId("")
+		return nil
+	}
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	var tableOID uint32
+	var owner, tablespace string
+	err = txn.QueryRow(`
+SELECT c.oid, pg_catalog.pg_get_userbyid(c.relowner), COALESCE(t.spcname, '')
+FROM pg_catalog.pg_class c
+JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+LEFT JOIN pg_catalog.pg_tablespace t ON t.oid = c.reltablespace
+WHERE n.nspname = $1 AND c.relname = $2 AND c.relkind IN ('r', 'p')
+`, schemaName, tableName).Scan(&tableOID, &owner, &tablespace)
+	switch {
+	case err == sql.ErrNoRows:
+		log.Printf("[WARN] PostgreSQL table (%s.%s) not found in database %s", schemaName, tableName, database)
+		d.SetId("")
+		return nil
+	case err != nil:
+		return fmt.Errorf("could not read table: %w", err)
+	}
+
+	// The default expressions are kept as written in the configuration as PostgreSQL
+	// returns them in a normalized form (e.g.: 'foo'::text).
+	defaults := map[string]string{}
+	for _, column := range d.Get(tableColumnAttr).([]interface{}) {
+		column := column.(map[string]interface{})
+		defaults[column[tableColumnNameAttr].(string)] = column[tableColumnDefaultAttr].(string)
+	}
+
+	rows, err := txn.Query(`
+SELECT a.attname, pg_catalog.format_type(a.atttypid, a.atttypmod), NOT a.attnotnull, d.adbin IS NOT NULL
+FROM pg_catalog.pg_attribute a
+LEFT JOIN pg_catalog.pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+WHERE a.attrelid = $1 AND a.attnum > 0 AND NOT a.attisdropped
+ORDER BY a.attnum
+`, tableOID)
+	if err != nil {
+		return fmt.Errorf("could not read table columns: %w", err)
+	}
+	defer rows.Close()
+
+	columns := []interface{}{}
+	for rows.Next() {
+		var name, columnType string
+		var nullable, hasDefault bool
+		if err := rows.Scan(&name, &columnType, &nullable, &hasDefault); err != nil {
+			return fmt.Errorf("could not scan table column: %w", err)
+		}
+
+		columnDefault := ""
+		if hasDefault {
+			columnDefault = defaults[name]
+		}
+
+		columns = append(columns, map[string]interface{}{
+			tableColumnNameAttr:     name,
+			tableColumnTypeAttr:     columnType,
+			tableColumnNullableAttr: nullable,
+			tableColumnDefaultAttr:  columnDefault,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("could not read table columns: %w", err)
+	}
+
+	var primaryKey []string
+	err = txn.QueryRow(`
+SELECT array_agg(a.attname ORDER BY k.ord)
+FROM pg_catalog.pg_constraint c
+CROSS JOIN LATERAL unnest(c.conkey) WITH ORDINALITY AS k(attnum, ord)
+JOIN pg_catalog.pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = k.attnum
+WHERE c.conrelid = $1 AND c.contype = 'p'
+`, tableOID).Scan(pq.Array(&primaryKey))
+	if err != nil {
+		return fmt.Errorf("could not read table primary key: %w", err)
+	}
+
+	d.Set(tableNameAttr, tableName)
+	d.Set(tableSchemaAttr, schemaName)
+	d.Set(tableDatabaseAttr, database)
+	d.Set(tableOwnerAttr, owner)
+	d.Set(tableTablespaceAttr, tablespace)
+	d.Set(tableColumnAttr, columns)
+	d.Set(tablePrimaryKeyAttr, primaryKey)
+	d.SetId(generateTableID(d, database))
+
+	return nil
+}
+
+func resourcePostgreSQLTableUpdate(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if err := alterTableColumns(txn, d); err != nil {
+		return err
+	}
+
+	if err := setTableTablespace(txn, d); err != nil {
+		return err
+	}
+
+	if err := setTableOwner(txn, d); err != nil {
+		return err
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return resourcePostgreSQLTableReadImpl(db, d)
+}
+
+func resourcePostgreSQLTableDelete(db *DBConnection, d *schema.ResourceData) error {
+	database := getDatabase(d, db.client.databaseName)
+
+	txn, err := startTransaction(db.client, database)
+	if err != nil {
+		return err
+	}
+	defer deferredRollback(txn)
+
+	if err := withRolesGranted(txn, []string{d.Get(tableOwnerAttr).(string)}, func() error {
+		dropMode := "RESTRICT"
+		if d.Get(tableDropCascadeAttr).(bool) {
+			dropMode = "CASCADE"
+		}
+
+		sql := fmt.Sprintf("DROP TABLE IF EXISTS %s %s", tableIdentifier(d), dropMode)
+		if _, err := txn.Exec(sql); err != nil {
+			return fmt.Errorf("could not drop table: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if err := txn.Commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// alterTableColumns updates the nullability and the default value of the existing columns
+// and adds the columns appended to the list,
+// the other changes recreate the table (see resourcePostgreSQLTableCustomizeDiff).
+func alterTableColumns(txn *sql.Tx, d *schema.ResourceData) error {
+	if !d.HasChange(tableColumnAttr) {
+		return nil
+	}
+
+	oldRaw, newRaw := d.GetChange(tableColumnAttr)
+	oldColumns := oldRaw.([]interface{})
+	newColumns := newRaw.([]interface{})
+
+	for i, oldColumn := range oldColumns {
+		oldColumn := oldColumn.(map[string]interface{})
+		newColumn := newColumns[i].(map[string]interface{})
+		alterColumn := fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s", tableIdentifier(d), pq.QuoteIdentifier(newColumn[tableColumnNameAttr].(string)))
+
+		statements := []string{}
+		if nullable := newColumn[tableColumnNullableAttr].(bool); nullable != oldColumn[tableColumnNullableAttr].(bool) {
+			if nullable {
+				statements = append(statements, alterColumn+" DROP NOT NULL")
+			} else {
+				statements = append(statements, alterColumn+" SET NOT NULL")
+			}
+		}
+		if columnDefault := newColumn[tableColumnDefaultAttr].(string); columnDefault != oldColumn[tableColumnDefaultAttr].(string) {
+			if columnDefault == "" {
+				statements = append(statements, alterColumn+" DROP DEFAULT")
+			} else {
+				statements = append(statements, alterColumn+" SET DEFAULT "+columnDefault)
+			}
+		}
+
+		for _, sql := range statements {
+			if _, err := txn.Exec(sql); err != nil {
+				return fmt.Errorf("could not alter table column: %w", err)
+			}
+		}
+	}
+
+	for _, column := range newColumns[len(oldColumns):] {
+		sql := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", tableIdentifier(d), tableColumnDefinition(column.(map[string]interface{})))
+		if _, err := txn.Exec(sql); err != nil {
+			return fmt.Errorf("could not add column to table: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func setTableTablespace(txn *sql.Tx, d *schema.ResourceData) error {
+	if !d.HasChange(tableTablespaceAttr) {
+		return nil
+	}
+
+	tablespace := d.Get(tableTablespaceAttr).(string)
+	if tablespace == "" {
+		tablespace = "pg_default"
+	}
+
+	sql := fmt.Sprintf("ALTER TABLE %s SET TABLESPACE %s", tableIdentifier(d), pq.QuoteIdentifier(tablespace))
+	if _, err := txn.Exec(sql); err != nil {
+		return fmt.Errorf("could not set table tablespace: %w", err)
+	}
+
+	return nil
+}
+
+func setTableOwner(txn *sql.Tx, d *schema.ResourceData) error {
+	owner := d.Get(tableOwnerAttr).(string)
+	if owner == "" || !d.HasChange(tableOwnerAttr) {
+		return nil
+	}
+
+	// If the authenticated user is not a superuser, it needs to be member of the new owner.
+	return withRolesGranted(txn, []string{owner}, func() error {
+		sql := fmt.Sprintf("ALTER TABLE %s OWNER TO %s", tableIdentifier(d), pq.QuoteIdentifier(owner))
+		if _, err := txn.Exec(sql); err != nil {
+			return fmt.Errorf("could not set table owner: %w", err)
+		}
+		return nil
+	})
+}
+
+func tableColumnDefinition(column map[string]interface{}) string {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "%s %s", pq.QuoteIdentifier(column[tableColumnNameAttr].(string)), column[tableColumnTypeAttr].(string))
+	if !column[tableColumnNullableAttr].(bool) {
+		b.WriteString(" NOT NULL")
+	}
+	if columnDefault := column[tableColumnDefaultAttr].(string); columnDefault != "" {
+		fmt.Fprint(b, " DEFAULT ", columnDefault)
+	}
+	return b.String()
+}
+
+func quoteIdentifierList(idents []interface{}) string {
+	quoted := []string{}
+	for _, ident := range idents {
+		quoted = append(quoted, pq.QuoteIdentifier(ident.(string)))
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func tableIdentifier(d *schema.ResourceData) string {
+	return fmt.Sprintf("%s.%s", pq.QuoteIdentifier(d.Get(tableSchemaAttr).(string)), pq.QuoteIdentifier(d.Get(tableNameAttr).(string)))
+}
+
+func generateTableID(d *schema.ResourceData, databaseName string) string {
+	return strings.Join([]string{
+		getDatabase(d, databaseName),
+		d.Get(tableSchemaAttr).(string),
+		d.Get(tableNameAttr).(string),
+	}, ".")
+}
+
+func getDBTableName(d *schema.ResourceData, databaseName string) (string, string, string, error) {
+	database := getDatabase(d, databaseName)
+	tableName := d.Get(tableNameAttr).(string)
+	schemaName := d.Get(tableSchemaAttr).(string)
+
+	// When importing, we have to parse the ID to find database, schema and table names.
+	if tableName == "" {
+		parsed := strings.Split(d.Id(), ".")
+		if len(parsed) != 3 {
+			return "", "", "", fmt.Errorf("table ID %s has not the expected format 'database.schema.table': %v", d.Id(), parsed)
+		}
+		database = parsed[0]
+		schemaName = parsed[1]
+		tableName = parsed[2]
+	}
+	return database, schemaName, tableName, nil
+}
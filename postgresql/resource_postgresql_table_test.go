@@ -0,0 +1,150 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeColumnType(t *testing.T) {
+	cases := map[string]string{
+		"int":                            "integer",
+		"INTEGER":                        "integer",
+		"int[]":                          "integer[]",
+		"varchar(10)":                    "character varying(10)",
+		"character varying ( 10 )":       "character varying(10)",
+		"numeric(10, 2)":                 "numeric(10,2)",
+		"timestamptz":                    "timestamp with time zone",
+		"timestamp(3)":                   "timestamp(3) without time zone",
+		"timestamp(3) without time zone": "timestamp(3) without time zone",
+		"bigserial":                      "bigint",
+		"text":                           "text",
+		"public.my_type":                 "public.my_type",
+	}
+
+	for columnType, expected := range cases {
+		assert.Equal(t, expected, normalizeColumnType(columnType), columnType)
+	}
+}
+
+func TestAccPostgresqlTable_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+
+	testConfig := `
+resource "postgresql_table" "test" {
+	database    = "%s"
+	schema      = "test_schema"
+	name        = "test_table"
+	owner       = "%s"
+	primary_key = ["id"]
+
+	column {
+		name     = "id"
+		type     = "bigserial"
+		nullable = false
+	}
+	column {
+		name    = "label"
+		type    = "varchar(64)"
+		default = "'none'"
+	}
+	%s
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testSuperuserPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPostgresqlTableDestroy(t, dbName),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testConfig, dbName, roleName, ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlTableColumns(t, dbName, 2),
+					resource.TestCheckResourceAttr("postgresql_table.test", "id", dbName+".test_schema.test_table"),
+					resource.TestCheckResourceAttr("postgresql_table.test", "owner", roleName),
+					resource.TestCheckResourceAttr("postgresql_table.test", "column.#", "2"),
+					resource.TestCheckResourceAttr("postgresql_table.test", "column.0.type", "bigint"),
+					resource.TestCheckResourceAttr("postgresql_table.test", "column.0.nullable", "false"),
+					resource.TestCheckResourceAttr("postgresql_table.test", "column.1.type", "character varying(64)"),
+					resource.TestCheckResourceAttr("postgresql_table.test", "column.1.default", "'none'"),
+					resource.TestCheckResourceAttr("postgresql_table.test", "primary_key.#", "1"),
+					resource.TestCheckResourceAttr("postgresql_table.test", "primary_key.0", "id"),
+				),
+			},
+			{
+				// The new column is added in place
+				Config: fmt.Sprintf(testConfig, dbName, roleName, `
+	column {
+		name = "created_at"
+		type = "timestamptz"
+	}`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPostgresqlTableColumns(t, dbName, 3),
+					resource.TestCheckResourceAttr("postgresql_table.test", "column.#", "3"),
+					resource.TestCheckResourceAttr("postgresql_table.test", "column.2.type", "timestamp with time zone"),
+				),
+			},
+			{
+				ResourceName:            "postgresql_table.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"column.1.default"},
+			},
+		},
+	})
+}
+
+func testAccCheckPostgresqlTableColumns(t *testing.T, dbName string, expected int) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		config := getTestConfig(t)
+		db, err := sql.Open("postgres", config.connStr(dbName))
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		var count int
+		if err := db.QueryRow(
+			"SELECT count(*) FROM information_schema.columns WHERE table_schema = 'test_schema' AND table_name = 'test_table'",
+		).Scan(&count); err != nil {
+			return fmt.Errorf("could not count table columns: %w", err)
+		}
+		if count != expected {
+			return fmt.Errorf("expected %d columns in test_schema.test_table, got %d", expected, count)
+		}
+		return nil
+	}
+}
+
+func testAccCheckPostgresqlTableDestroy(t *testing.T, dbName string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		config := getTestConfig(t)
+		db, err := sql.Open("postgres", config.connStr(dbName))
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		var exists bool
+		if err := db.QueryRow("SELECT to_regclass('test_schema.test_table') IS NOT NULL").Scan(&exists); err != nil {
+			return fmt.Errorf("could not check if table exists: %w", err)
+		}
+		if exists {
+			return fmt.Errorf("table test_schema.test_table still exists after destroy")
+		}
+		return nil
+	}
+}
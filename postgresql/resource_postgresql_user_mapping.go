@@ -20,7 +20,7 @@ const (
 func resourcePostgreSQLUserMapping() *schema.Resource {
 	return &schema.Resource{
 		Create: PGResourceFunc(resourcePostgreSQLUserMappingCreate),
-		Read:   PGResourceFunc(resourcePostgreSQLUserMappingRead),
+		Read:   PGReadResourceFunc(resourcePostgreSQLUserMappingRead),
 		Update: PGResourceFunc(resourcePostgreSQLUserMappingUpdate),
 		Delete: PGResourceFunc(resourcePostgreSQLUserMappingDelete),
 		Importer: &schema.ResourceImporter{
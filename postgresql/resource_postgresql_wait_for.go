@@ -0,0 +1,159 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	waitForDatabaseAttr     = "database"
+	waitForRoleAttr         = "role"
+	waitForExtensionAttr    = "extension"
+	waitForPollIntervalAttr = "poll_interval"
+)
+
+func resourcePostgreSQLWaitFor() *schema.Resource {
+	return &schema.Resource{
+		// Create doesn't use PGResourceFunc as the server is not expected to accept connections yet.
+		Create: resourcePostgreSQLWaitForCreate,
+		Read:   resourcePostgreSQLWaitForRead,
+		Delete: resourcePostgreSQLWaitForDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			waitForDatabaseAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Wait until this database exists",
+			},
+			waitForRoleAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Wait until this role exists",
+			},
+			waitForExtensionAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Wait until this extension exists (in the database specified by `database` or the provider database)",
+			},
+			waitForPollIntervalAttr: {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      5,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Number of seconds to wait between two checks",
+			},
+		},
+	}
+}
+
+func resourcePostgreSQLWaitForCreate(d *schema.ResourceData, meta interface{}) error {
+	client, err := resourceClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	interval := time.Duration(d.Get(waitForPollIntervalAttr).(int)) * time.Second
+	deadline := time.Now().Add(d.Timeout(schema.TimeoutCreate))
+
+	for {
+		err := checkServerReady(client, d)
+		if err == nil {
+			break
+		}
+
+		if time.Now().Add(interval).After(deadline) {
+			return fmt.Errorf("timeout while waiting for PostgreSQL server: %w", err)
+		}
+
+		log.Printf("[DEBUG] PostgreSQL server is not ready yet: %v", err)
+		time.Sleep(interval)
+	}
+
+	d.SetId(generateWaitForID(d, client))
+
+	return nil
+}
+
+// checkServerReady returns nil if the server accepts connections
+// and all the objects we are waiting for exist.
+func checkServerReady(client *Client, d *schema.ResourceData) error {
+	db, err := client.Connect()
+	if err != nil {
+		return err
+	}
+
+	if role := d.Get(waitForRoleAttr).(string); role != "" {
+		var unused int
+		err := db.QueryRow("SELECT 1 FROM pg_roles WHERE rolname = $1", role).Scan(&unused)
+		switch {
+		case err == sql.ErrNoRows:
+			return fmt.Errorf("role %s does not exist", role)
+		case err != nil:
+			return fmt.Errorf("could not check if role %s exists: %w", role, err)
+		}
+	}
+
+	database := d.Get(waitForDatabaseAttr).(string)
+	if database != "" {
+		exists, err := dbExists(db, database)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("database %s does not exist", database)
+		}
+	}
+
+	if extension := d.Get(waitForExtensionAttr).(string); extension != "" {
+		txn, err := startTransaction(client, database)
+		if err != nil {
+			return err
+		}
+		defer deferredRollback(txn)
+
+		var unused int
+		err = txn.QueryRow("SELECT 1 FROM pg_catalog.pg_extension WHERE extname = $1", extension).Scan(&unused)
+		switch {
+		case err == sql.ErrNoRows:
+			return fmt.Errorf("extension %s does not exist", extension)
+		case err != nil:
+			return fmt.Errorf("could not check if extension %s exists: %w", extension, err)
+		}
+	}
+
+	return nil
+}
+
+func resourcePostgreSQLWaitForRead(d *schema.ResourceData, meta interface{}) error {
+	// Nothing to refresh, this resource only exists to wait during its creation.
+	return nil
+}
+
+func resourcePostgreSQLWaitForDelete(d *schema.ResourceData, meta interface{}) error {
+	d.SetId("")
+	return nil
+}
+
+func generateWaitForID(d *schema.ResourceData, client *Client) string {
+	parts := []string{fmt.Sprintf("%s:%d", client.config.Host, client.config.Port)}
+	for _, attr := range []string{waitForDatabaseAttr, waitForRoleAttr, waitForExtensionAttr} {
+		if v := d.Get(attr).(string); v != "" {
+			parts = append(parts, v)
+		}
+	}
+	return strings.Join(parts, "_")
+}
@@ -0,0 +1,69 @@
+package postgresql
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccPostgresqlWaitFor_Basic(t *testing.T) {
+	skipIfNotAcc(t)
+
+	dbSuffix, teardown := setupTestDatabase(t, true, true)
+	defer teardown()
+
+	dbName, roleName := getTestDBNames(dbSuffix)
+
+	config := fmt.Sprintf(`
+resource "postgresql_wait_for" "test" {
+	database      = "%s"
+	role          = "%s"
+	poll_interval = 1
+}
+`, dbName, roleName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("postgresql_wait_for.test", "database", dbName),
+					resource.TestCheckResourceAttr("postgresql_wait_for.test", "role", roleName),
+					resource.TestCheckResourceAttrSet("postgresql_wait_for.test", "id"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPostgresqlWaitFor_Timeout(t *testing.T) {
+	config := `
+resource "postgresql_wait_for" "test" {
+	role          = "tf_tests_unknown_role"
+	poll_interval = 1
+
+	timeouts {
+		create = "3s"
+	}
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      config,
+				ExpectError: regexp.MustCompile("timeout while waiting for PostgreSQL server: role tf_tests_unknown_role does not exist"),
+			},
+		},
+	})
+}
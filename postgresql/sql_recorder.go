@@ -0,0 +1,156 @@
+package postgresql
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// executedStatementRegexp matches the statements which are still executed by the SQLRecorder:
+// the session and transaction settings and the advisory locks of the provider.
+var executedStatementRegexp = regexp.MustCompile(`(?is)^\s*(SET|RESET)\s|^\s*SELECT\s+(pg_advisory_xact_lock|set_config)\s*\(`)
+
+// roleStatementRegexp matches the statements switching role, they are recorded as the changed objects depend on them.
+var roleStatementRegexp = regexp.MustCompile(`(?is)^\s*(SET\s+((LOCAL|SESSION)\s+)?ROLE|RESET\s+ROLE)\b`)
+
+// SQLRecorder writes the statements changing the objects in a file instead of executing them (see generate_sql_only).
+// The statements reading the database are still executed so the resources can compute the statements they would run.
+// The operations which recorded statements fail, so Terraform doesn't save a state for changes which were not made.
+type SQLRecorder struct {
+	path string
+
+	// operationLock serializes the operations so the statements recorded during an operation are its own.
+	operationLock sync.Mutex
+
+	lock sync.Mutex
+	file *os.File
+	// database is the database of the last statement written in the file.
+	database string
+	// statements are the statements recorded by the current operation.
+	statements []string
+}
+
+// newSQLRecorder returns a recorder writing the statements in the file, which is truncated.
+func newSQLRecorder(path string) (*SQLRecorder, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("could not open generated SQL file %s: %w", path, err)
+	}
+	return &SQLRecorder{path: path, file: file}, nil
+}
+
+// run executes the operation and returns an error with the statements it recorded, if any.
+// It executes fn directly if the recorder is nil.
+func (r *SQLRecorder) run(fn func() error) error {
+	if r == nil {
+		return fn()
+	}
+
+	r.operationLock.Lock()
+	defer r.operationLock.Unlock()
+
+	err := fn()
+
+	r.lock.Lock()
+	statements := r.statements
+	r.statements = nil
+	r.lock.Unlock()
+
+	if len(statements) == 0 {
+		return err
+	}
+	if err != nil {
+		// The operation can fail because the recorded statements were not executed, e.g.: reading a role which is not created.
+		log.Printf("[DEBUG] operation failed after recording statements in generate_sql_only mode: %v", err)
+	}
+	return fmt.Errorf(
+		"generate_sql_only is set, the following statements were written to %s instead of being executed:\n%s",
+		r.path, strings.Join(statements, "\n"),
+	)
+}
+
+// write writes the statement in the file, connecting to the database first if it's not the database of the previous one.
+// The statement is added to the statements of the current operation if it's not a transaction statement.
+func (r *SQLRecorder) write(database, statement string, transaction bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var b strings.Builder
+	if database != r.database {
+		fmt.Fprintf(&b, "\\connect %s\n", pq.QuoteLiteral(database))
+		r.database = database
+	}
+	fmt.Fprintf(&b, "%s;\n", statement)
+
+	if _, err := r.file.WriteString(b.String()); err != nil {
+		log.Printf("[WARN] could not write generated SQL file %s: %v", r.path, err)
+	}
+	if !transaction {
+		r.statements = append(r.statements, statement+";")
+	}
+}
+
+// connector returns a connector recording the statements of the connections of the connector.
+// It returns the connector itself if the recorder is nil.
+func (r *SQLRecorder) connector(connector driver.Connector, database string) driver.Connector {
+	if r == nil {
+		return connector
+	}
+	return &hookConnector{Connector: connector, newHook: func() statementHook {
+		// The transaction is written in the file only if it contains recorded statements.
+		var inTransaction, transactionWritten bool
+
+		return func(query string, args []driver.NamedValue, exec bool) bool {
+			switch {
+			case strings.HasPrefix(query, "BEGIN"):
+				inTransaction, transactionWritten = true, false
+				return true
+			case query == "COMMIT" || query == "ROLLBACK":
+				if transactionWritten {
+					r.write(database, query, true)
+				}
+				inTransaction, transactionWritten = false, false
+				return true
+			case !exec || (executedStatementRegexp.MatchString(query) && !roleStatementRegexp.MatchString(query)):
+				return true
+			}
+
+			if inTransaction && !transactionWritten {
+				r.write(database, "BEGIN", true)
+				transactionWritten = true
+			}
+			r.write(database, redactStatement(inlineStatementArgs(query, args)), false)
+			return false
+		}
+	}}
+}
+
+// inlineStatementArgs replaces the placeholders of the statement ($1, $2, ...) by the literal values of the arguments.
+func inlineStatementArgs(query string, args []driver.NamedValue) string {
+	// The last arguments are replaced first so $1 doesn't replace the beginning of $10.
+	for i := len(args) - 1; i >= 0; i-- {
+		query = strings.ReplaceAll(query, fmt.Sprintf("$%d", args[i].Ordinal), statementLiteral(args[i].Value))
+	}
+	return query
+}
+
+func statementLiteral(value driver.Value) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return pq.QuoteLiteral(v)
+	case []byte:
+		return pq.QuoteLiteral(string(v))
+	case time.Time:
+		return pq.QuoteLiteral(v.Format(time.RFC3339Nano))
+	}
+	return fmt.Sprint(value)
+}
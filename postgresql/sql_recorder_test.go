@@ -0,0 +1,95 @@
+package postgresql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInlineStatementArgs(t *testing.T) {
+	args := make([]driver.NamedValue, 0, 11)
+	for i := 1; i <= 10; i++ {
+		args = append(args, driver.NamedValue{Ordinal: i, Value: int64(i)})
+	}
+	args = append(args, driver.NamedValue{Ordinal: 11, Value: "it's"})
+
+	assert.Equal(
+		t,
+		"SELECT 1, 10, 'it''s', NULL",
+		inlineStatementArgs("SELECT $1, $10, $11, $12", append(args, driver.NamedValue{Ordinal: 12, Value: nil})),
+	)
+	assert.Equal(t, "SELECT 1", inlineStatementArgs("SELECT 1", nil))
+}
+
+func TestSQLRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.sql")
+	recorder, err := newSQLRecorder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db := sql.OpenDB(recorder.connector(testStatementConnector{}, "mydb"))
+	defer db.Close()
+
+	// An operation which only reads or changes its session doesn't fail.
+	err = recorder.run(func() error {
+		txn, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := txn.Exec("SET LOCAL statement_timeout = 1000"); err != nil {
+			return err
+		}
+		return txn.Commit()
+	})
+	assert.NoError(t, err)
+
+	err = recorder.run(func() error {
+		txn, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := txn.Exec("SELECT pg_advisory_xact_lock(oid::bigint) FROM pg_roles WHERE rolname = $1", "r"); err != nil {
+			return err
+		}
+		if _, err := txn.Exec(`CREATE ROLE "r" PASSWORD 'secret'`); err != nil {
+			return err
+		}
+		if _, err := txn.Exec(`SET LOCAL ROLE "r"`); err != nil {
+			return err
+		}
+		if _, err := txn.Exec("COMMENT ON ROLE r IS $1", "my role"); err != nil {
+			return err
+		}
+		return txn.Commit()
+	})
+	assert.EqualError(t, err, "generate_sql_only is set, the following statements were written to "+path+
+		` instead of being executed:
+CREATE ROLE "r" PASSWORD 'XXXX';
+SET LOCAL ROLE "r";
+COMMENT ON ROLE r IS 'my role';`)
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, strings.Join([]string{
+		`\connect 'mydb'`,
+		"BEGIN;",
+		`CREATE ROLE "r" PASSWORD 'XXXX';`,
+		`SET LOCAL ROLE "r";`,
+		"COMMENT ON ROLE r IS 'my role';",
+		"COMMIT;",
+		"",
+	}, "\n"), string(content))
+
+	// Without recorder, the operation and the connector are not wrapped.
+	var noRecorder *SQLRecorder
+	assert.NoError(t, noRecorder.run(func() error { return nil }))
+	assert.Equal(t, testStatementConnector{}, noRecorder.connector(testStatementConnector{}, "mydb"))
+}
@@ -0,0 +1,219 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// SSHTunnelConfig is the SSH bastion through which the connections to PostgreSQL are opened.
+// It implements pq.Dialer so it can be used as the dialer of the pq connector.
+type SSHTunnelConfig struct {
+	Host                  string
+	Port                  int
+	User                  string
+	PrivateKey            string
+	PrivateKeyPassphrase  string
+	UseAgent              bool
+	KnownHostsFile        string
+	InsecureIgnoreHostKey bool
+	// JumpHosts are the hosts to go through, in order, to reach the bastion.
+	JumpHosts []SSHJumpHost
+	// ConnectTimeout is the timeout to open the TCP connection to the first host.
+	ConnectTimeout time.Duration
+
+	// client is the SSH connection to the bastion shared by all the connection pools.
+	// It's opened on the first dial and opened again if it's broken.
+	lock   sync.Mutex
+	client *ssh.Client
+}
+
+// SSHJumpHost is a host to go through to reach the bastion (i.e.: ssh -J).
+type SSHJumpHost struct {
+	Host string
+	Port int
+	// User defaults to the user of the tunnel.
+	User string
+}
+
+// Dial opens a connection to the address from the bastion.
+func (t *SSHTunnelConfig) Dial(network, address string) (net.Conn, error) {
+	return t.DialTimeout(network, address, 0)
+}
+
+// DialTimeout opens a connection to the address from the bastion.
+// If the SSH connection is broken, it's opened again once.
+func (t *SSHTunnelConfig) DialTimeout(network, address string, timeout time.Duration) (net.Conn, error) {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	client, err := t.sshClient(false)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := client.DialContext(ctx, network, address)
+	if err == nil || ctx.Err() != nil {
+		return conn, err
+	}
+
+	log.Printf("[WARN] could not dial %s through SSH tunnel %s, reconnecting: %v", address, t.Host, err)
+	if client, err = t.sshClient(true); err != nil {
+		return nil, err
+	}
+	return client.DialContext(ctx, network, address)
+}
+
+// sshClient returns the SSH connection to the bastion, opening it if needed (or if reconnect is set).
+func (t *SSHTunnelConfig) sshClient(reconnect bool) (*ssh.Client, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.client != nil && !reconnect {
+		return t.client, nil
+	}
+	if t.client != nil {
+		t.client.Close()
+		t.client = nil
+	}
+
+	client, err := t.connect()
+	if err != nil {
+		return nil, err
+	}
+	t.client = client
+
+	return client, nil
+}
+
+// connect opens the SSH connection to the bastion through the jump hosts.
+func (t *SSHTunnelConfig) connect() (*ssh.Client, error) {
+	auth, err := t.authMethods()
+	if err != nil {
+		return nil, err
+	}
+
+	hostKeyCallback, err := t.hostKeyCallback()
+	if err != nil {
+		return nil, err
+	}
+
+	hops := make([]SSHJumpHost, 0, len(t.JumpHosts)+1)
+	hops = append(hops, t.JumpHosts...)
+	hops = append(hops, SSHJumpHost{Host: t.Host, Port: t.Port, User: t.User})
+
+	var client *ssh.Client
+	for _, hop := range hops {
+		user := hop.User
+		if user == "" {
+			user = t.User
+		}
+		address := net.JoinHostPort(hop.Host, strconv.Itoa(hop.Port))
+		sshConfig := &ssh.ClientConfig{
+			User:            user,
+			Auth:            auth,
+			HostKeyCallback: hostKeyCallback,
+			Timeout:         t.ConnectTimeout,
+		}
+
+		next, err := dialSSH(client, address, sshConfig)
+		if err != nil {
+			if client != nil {
+				client.Close()
+			}
+			return nil, fmt.Errorf("could not open SSH connection to %s: %w", address, err)
+		}
+		client = next
+	}
+
+	return client, nil
+}
+
+// dialSSH opens an SSH connection to the address, directly or through the previous hop if it's set.
+func dialSSH(previous *ssh.Client, address string, sshConfig *ssh.ClientConfig) (*ssh.Client, error) {
+	if previous == nil {
+		return ssh.Dial("tcp", address, sshConfig)
+	}
+
+	conn, err := previous.Dial("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, address, sshConfig)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return ssh.NewClient(sshConn, chans, reqs), nil
+}
+
+func (t *SSHTunnelConfig) authMethods() ([]ssh.AuthMethod, error) {
+	var methods []ssh.AuthMethod
+
+	if t.PrivateKey != "" {
+		var signer ssh.Signer
+		var err error
+		if t.PrivateKeyPassphrase != "" {
+			signer, err = ssh.ParsePrivateKeyWithPassphrase([]byte(t.PrivateKey), []byte(t.PrivateKeyPassphrase))
+		} else {
+			signer, err = ssh.ParsePrivateKey([]byte(t.PrivateKey))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not parse SSH private key: %w", err)
+		}
+		methods = append(methods, ssh.PublicKeys(signer))
+	}
+
+	if t.UseAgent {
+		socket := os.Getenv("SSH_AUTH_SOCK")
+		if socket == "" {
+			return nil, fmt.Errorf("use_agent is set but SSH_AUTH_SOCK is not defined")
+		}
+		conn, err := net.Dial("unix", socket)
+		if err != nil {
+			return nil, fmt.Errorf("could not connect to SSH agent: %w", err)
+		}
+		methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+	}
+
+	if len(methods) == 0 {
+		return nil, fmt.Errorf("ssh_tunnel requires private_key or use_agent to authenticate")
+	}
+
+	return methods, nil
+}
+
+func (t *SSHTunnelConfig) hostKeyCallback() (ssh.HostKeyCallback, error) {
+	if t.InsecureIgnoreHostKey {
+		return ssh.InsecureIgnoreHostKey(), nil
+	}
+
+	path := t.KnownHostsFile
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("could not find the default known_hosts file: %w", err)
+		}
+		path = filepath.Join(home, ".ssh", "known_hosts")
+	}
+
+	callback, err := knownhosts.New(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read known_hosts file %s: %w", path, err)
+	}
+	return callback, nil
+}
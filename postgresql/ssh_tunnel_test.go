@@ -0,0 +1,257 @@
+package postgresql
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+func generateTestSSHKey(t *testing.T, passphrase string) (string, ssh.Signer) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var block *pem.Block
+	if passphrase != "" {
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(key, "", []byte(passphrase))
+	} else {
+		block, err = ssh.MarshalPrivateKey(key, "")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(pem.EncodeToMemory(block)), signer
+}
+
+func TestSSHTunnelAuthMethods(t *testing.T) {
+	privateKey, _ := generateTestSSHKey(t, "")
+	encryptedKey, _ := generateTestSSHKey(t, "secret")
+
+	methods, err := (&SSHTunnelConfig{PrivateKey: privateKey}).authMethods()
+	assert.NoError(t, err)
+	assert.Len(t, methods, 1)
+
+	methods, err = (&SSHTunnelConfig{PrivateKey: encryptedKey, PrivateKeyPassphrase: "secret"}).authMethods()
+	assert.NoError(t, err)
+	assert.Len(t, methods, 1)
+
+	_, err = (&SSHTunnelConfig{PrivateKey: encryptedKey}).authMethods()
+	assert.Error(t, err)
+
+	_, err = (&SSHTunnelConfig{PrivateKey: "not a key"}).authMethods()
+	assert.Error(t, err)
+
+	_, err = (&SSHTunnelConfig{}).authMethods()
+	assert.Error(t, err)
+
+	t.Setenv("SSH_AUTH_SOCK", "")
+	_, err = (&SSHTunnelConfig{UseAgent: true}).authMethods()
+	assert.Error(t, err)
+}
+
+func TestSSHTunnelHostKeyCallback(t *testing.T) {
+	_, hostKey := generateTestSSHKey(t, "")
+	_, otherKey := generateTestSSHKey(t, "")
+	addr := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 2222}
+
+	knownHostsFile := filepath.Join(t.TempDir(), "known_hosts")
+	line := knownhosts.Line([]string{knownhosts.Normalize(addr.String())}, hostKey.PublicKey())
+	if err := os.WriteFile(knownHostsFile, []byte(line+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	callback, err := (&SSHTunnelConfig{KnownHostsFile: knownHostsFile}).hostKeyCallback()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, callback(addr.String(), addr, hostKey.PublicKey()))
+	assert.Error(t, callback(addr.String(), addr, otherKey.PublicKey()))
+
+	callback, err = (&SSHTunnelConfig{InsecureIgnoreHostKey: true}).hostKeyCallback()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, callback(addr.String(), addr, otherKey.PublicKey()))
+
+	_, err = (&SSHTunnelConfig{KnownHostsFile: filepath.Join(t.TempDir(), "missing")}).hostKeyCallback()
+	assert.Error(t, err)
+}
+
+func TestExpandSSHTunnel(t *testing.T) {
+	tunnel := expandSSHTunnel(map[string]interface{}{
+		"host":                     "bastion",
+		"port":                     2222,
+		"user":                     "ops",
+		"private_key":              "key",
+		"private_key_passphrase":   "",
+		"use_agent":                true,
+		"known_hosts_file":         "",
+		"insecure_ignore_host_key": false,
+		"jump_host": []interface{}{
+			map[string]interface{}{"host": "jump1", "port": 22, "user": ""},
+			map[string]interface{}{"host": "jump2", "port": 2200, "user": "jumper"},
+		},
+	})
+
+	assert.Equal(t, "bastion", tunnel.Host)
+	assert.Equal(t, 2222, tunnel.Port)
+	assert.Equal(t, "ops", tunnel.User)
+	assert.True(t, tunnel.UseAgent)
+	assert.Equal(t, []SSHJumpHost{
+		{Host: "jump1", Port: 22},
+		{Host: "jump2", Port: 2200, User: "jumper"},
+	}, tunnel.JumpHosts)
+}
+
+// TestSSHTunnelDial checks that the connections are forwarded by a bastion reached through a jump host.
+func TestSSHTunnelDial(t *testing.T) {
+	privateKey, clientKey := generateTestSSHKey(t, "")
+
+	// The target echoes what it receives.
+	target, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer target.Close()
+	go func() {
+		for {
+			conn, err := target.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	jumpHost := startTestSSHServer(t, clientKey.PublicKey())
+	bastion := startTestSSHServer(t, clientKey.PublicKey())
+
+	tunnel := &SSHTunnelConfig{
+		Host:                  "127.0.0.1",
+		Port:                  bastion,
+		User:                  "ops",
+		PrivateKey:            privateKey,
+		InsecureIgnoreHostKey: true,
+		JumpHosts:             []SSHJumpHost{{Host: "127.0.0.1", Port: jumpHost}},
+	}
+
+	for i := 0; i < 2; i++ {
+		conn, err := tunnel.Dial("tcp", target.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = conn.Write([]byte("ping"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		reply := make([]byte, 4)
+		_, err = io.ReadFull(conn, reply)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, "ping", string(reply))
+		conn.Close()
+	}
+
+	// The connection is opened again if it's broken.
+	tunnel.client.Close()
+	conn, err := tunnel.Dial("tcp", target.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+}
+
+// startTestSSHServer starts an SSH server accepting the key and forwarding the direct-tcpip channels.
+// It returns the port it listens on.
+func startTestSSHServer(t *testing.T, authorizedKey ssh.PublicKey) int {
+	_, hostKey := generateTestSSHKey(t, "")
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if string(key.Marshal()) != string(authorizedKey.Marshal()) {
+				return nil, assert.AnError
+			}
+			return nil, nil
+		},
+	}
+	config.AddHostKey(hostKey)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveTestSSHConn(conn, config)
+		}
+	}()
+
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func serveTestSSHConn(conn net.Conn, config *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	for newChannel := range chans {
+		var payload struct {
+			Host       string
+			Port       uint32
+			OriginHost string
+			OriginPort uint32
+		}
+		if newChannel.ChannelType() != "direct-tcpip" || ssh.Unmarshal(newChannel.ExtraData(), &payload) != nil {
+			newChannel.Reject(ssh.UnknownChannelType, "unsupported channel")
+			continue
+		}
+
+		remote, err := net.Dial("tcp", net.JoinHostPort(payload.Host, strconv.Itoa(int(payload.Port))))
+		if err != nil {
+			newChannel.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+		channel, channelReqs, err := newChannel.Accept()
+		if err != nil {
+			remote.Close()
+			continue
+		}
+		go ssh.DiscardRequests(channelReqs)
+		go func() {
+			defer channel.Close()
+			defer remote.Close()
+			go io.Copy(remote, channel)
+			io.Copy(channel, remote)
+		}()
+	}
+}
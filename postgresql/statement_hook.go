@@ -0,0 +1,127 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// statementHook is called with each statement and its arguments before it's sent to the server,
+// exec is true if the statement is executed with Exec (i.e.: its result is not read). If the hook returns false, the statement is not sent
+// and its result is empty.
+// The transaction statements (BEGIN, COMMIT and ROLLBACK) are passed to the hook but they are always sent.
+type statementHook func(query string, args []driver.NamedValue, exec bool) bool
+
+// hookConnector calls a hook with the statements executed by each connection of the connector
+// (see StatementLogger and SQLRecorder). newHook is called for each new connection.
+type hookConnector struct {
+	driver.Connector
+
+	newHook func() statementHook
+}
+
+func (c *hookConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &hookConn{Conn: conn, hook: c.newHook()}, nil
+}
+
+// hookConn calls the hook before sending the statements to the connection.
+// database/sql uses ExecContext and QueryContext directly, so the connection of the driver must implement them.
+type hookConn struct {
+	driver.Conn
+
+	hook statementHook
+}
+
+func (c *hookConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	if !c.hook(query, args, true) {
+		return driver.RowsAffected(0), nil
+	}
+	return execer.ExecContext(ctx, query, args)
+}
+
+func (c *hookConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	if !c.hook(query, args, false) {
+		return nil, fmt.Errorf("statement was not sent to the server: %s", query)
+	}
+	return queryer.QueryContext(ctx, query, args)
+}
+
+func (c *hookConn) Prepare(query string) (driver.Stmt, error) {
+	if !c.hook(query, nil, false) {
+		return nil, fmt.Errorf("statement was not sent to the server: %s", query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+func (c *hookConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	beginner, ok := c.Conn.(driver.ConnBeginTx)
+	if !ok {
+		return nil, fmt.Errorf("driver does not support transaction options")
+	}
+
+	statement := "BEGIN"
+	if level := sql.IsolationLevel(opts.Isolation); level != sql.LevelDefault {
+		statement += " ISOLATION LEVEL " + strings.ToUpper(level.String())
+	}
+	if opts.ReadOnly {
+		statement += " READ ONLY"
+	}
+	c.hook(statement, nil, false)
+
+	tx, err := beginner.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &hookTx{Tx: tx, conn: c}, nil
+}
+
+func (c *hookConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *hookConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *hookConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+type hookTx struct {
+	driver.Tx
+
+	conn *hookConn
+}
+
+func (t *hookTx) Commit() error {
+	t.conn.hook("COMMIT", nil, false)
+	return t.Tx.Commit()
+}
+
+func (t *hookTx) Rollback() error {
+	t.conn.hook("ROLLBACK", nil, false)
+	return t.Tx.Rollback()
+}
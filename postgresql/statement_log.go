@@ -0,0 +1,88 @@
+package postgresql
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// statementLogLevels are the log levels of the executed statements, as filtered by TF_LOG.
+var statementLogLevels = []string{"TRACE", "DEBUG", "INFO", "WARN"}
+
+// StatementLogger logs the statements executed by the provider (see statement_log).
+// The statement parameters are not logged and the passwords are redacted from the statements.
+type StatementLogger struct {
+	level string
+
+	// file is the file to which the statements are also appended, if set.
+	lock sync.Mutex
+	file *os.File
+}
+
+// newStatementLogger returns a logger at the level, appending the statements to the file if path is set.
+func newStatementLogger(level, path string) (*StatementLogger, error) {
+	logger := &StatementLogger{level: level}
+	if path == "" {
+		return logger, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("could not open statement log file %s: %w", path, err)
+	}
+	logger.file = file
+
+	return logger, nil
+}
+
+var statementRedactions = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	// CREATE/ALTER ROLE ... PASSWORD '...' and the password option of the user mappings, whose key can be quoted
+	// and whose value can be an escape string (pq.QuoteLiteral returns E'...' if the value contains a backslash).
+	{regexp.MustCompile(`(?i)((?:\bpassword|"password")\s+)(?:E'(?:[^'\\]|''|\\.)*'|'(?:[^']|'')*')`), "${1}'XXXX'"},
+	// The password of the connection strings, e.g.: CREATE SUBSCRIPTION ... CONNECTION '... password=...'.
+	{regexp.MustCompile(`(?i)(\bpassword\s*=\s*)(?:'(?:[^'\\]|\\.)*'|[^\s']+)`), "${1}XXXX"},
+}
+
+// redactStatement replaces the passwords of the statement by XXXX.
+func redactStatement(query string) string {
+	for _, redaction := range statementRedactions {
+		query = redaction.pattern.ReplaceAllString(query, redaction.replacement)
+	}
+	return query
+}
+
+// log logs the statement executed on the database.
+func (l *StatementLogger) log(database, query string) {
+	query = redactStatement(query)
+	log.Printf("[%s] PostgreSQL statement on database %s: %s", l.level, database, query)
+
+	if l.file == nil {
+		return
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if _, err := fmt.Fprintf(l.file, "%s %s: %s;\n", time.Now().UTC().Format(time.RFC3339), database, query); err != nil {
+		log.Printf("[WARN] could not write statement log file %s: %v", l.file.Name(), err)
+	}
+}
+
+// connector returns a connector logging the statements executed by the connections of the connector.
+// It returns the connector itself if the logger is nil.
+func (l *StatementLogger) connector(connector driver.Connector, database string) driver.Connector {
+	if l == nil {
+		return connector
+	}
+	hook := func(query string, _ []driver.NamedValue, _ bool) bool {
+		l.log(database, query)
+		return true
+	}
+	return &hookConnector{Connector: connector, newHook: func() statementHook { return hook }}
+}
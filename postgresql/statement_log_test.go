@@ -0,0 +1,116 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactStatement(t *testing.T) {
+	var tests = []struct {
+		query    string
+		expected string
+	}{
+		{`CREATE ROLE "r" WITH LOGIN PASSWORD 'secret'`, `CREATE ROLE "r" WITH LOGIN PASSWORD 'XXXX'`},
+		{`ALTER ROLE "r" ENCRYPTED PASSWORD 'it''s secret'`, `ALTER ROLE "r" ENCRYPTED PASSWORD 'XXXX'`},
+		{`CREATE USER MAPPING FOR "r" SERVER "s" OPTIONS (user 'u', password 'secret')`, `CREATE USER MAPPING FOR "r" SERVER "s" OPTIONS (user 'u', password 'XXXX')`},
+		{`ALTER USER MAPPING FOR "r" SERVER "s" OPTIONS (SET "password" 'secret', "user" 'u')`, `ALTER USER MAPPING FOR "r" SERVER "s" OPTIONS (SET "password" 'XXXX', "user" 'u')`},
+		{`CREATE USER MAPPING FOR "r" SERVER "s" OPTIONS ("password"  E'se\\cr''et', "user" 'u')`, `CREATE USER MAPPING FOR "r" SERVER "s" OPTIONS ("password"  'XXXX', "user" 'u')`},
+		{`CREATE USER MAPPING FOR "r" SERVER "s" OPTIONS ("password" ` + pq.QuoteLiteral(`a\b'c`) + `)`, `CREATE USER MAPPING FOR "r" SERVER "s" OPTIONS ("password"  'XXXX')`},
+		{`CREATE SUBSCRIPTION "s" CONNECTION 'host=h password=secret dbname=d' PUBLICATION "p"`, `CREATE SUBSCRIPTION "s" CONNECTION 'host=h password=XXXX dbname=d' PUBLICATION "p"`},
+		{`SELECT rolname FROM pg_roles WHERE rolname = $1`, `SELECT rolname FROM pg_roles WHERE rolname = $1`},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, redactStatement(test.query))
+	}
+}
+
+// testStatementConnector is a connector whose connections accept every statement.
+type testStatementConnector struct{}
+
+func (testStatementConnector) Connect(context.Context) (driver.Conn, error) {
+	return testStatementConn{}, nil
+}
+
+func (testStatementConnector) Driver() driver.Driver {
+	return nil
+}
+
+type testStatementConn struct{}
+
+func (testStatementConn) Prepare(string) (driver.Stmt, error) {
+	return nil, driver.ErrSkip
+}
+
+func (testStatementConn) Close() error {
+	return nil
+}
+
+func (testStatementConn) Begin() (driver.Tx, error) {
+	return testStatementConn{}, nil
+}
+
+func (testStatementConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	return testStatementConn{}, nil
+}
+
+func (testStatementConn) Commit() error {
+	return nil
+}
+
+func (testStatementConn) Rollback() error {
+	return nil
+}
+
+func (testStatementConn) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	return driver.RowsAffected(0), nil
+}
+
+func TestStatementLogConnector(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "statements.log")
+	logger, err := newStatementLogger("INFO", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db := sql.OpenDB(logger.connector(testStatementConnector{}, "mydb"))
+	defer db.Close()
+
+	txn, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := txn.Exec(`ALTER ROLE "r" PASSWORD 'secret'`); err != nil {
+		t.Fatal(err)
+	}
+	if err := txn.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var statements []string
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		// Remove the timestamp
+		statements = append(statements, strings.SplitN(line, " ", 2)[1])
+	}
+	assert.Equal(t, []string{
+		"mydb: BEGIN;",
+		`mydb: ALTER ROLE "r" PASSWORD 'XXXX';`,
+		"mydb: COMMIT;",
+	}, statements)
+
+	// Without logger, the connector is not wrapped.
+	var noLogger *StatementLogger
+	assert.Equal(t, testStatementConnector{}, noLogger.connector(testStatementConnector{}, "mydb"))
+}